@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/BerryBytes/awsctl/internal/bastion"
+	connection "github.com/BerryBytes/awsctl/internal/common"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/spf13/cobra"
 )
@@ -18,24 +19,79 @@ func NewBastionCmd(deps BastionDependencies) *cobra.Command {
 		Use:   "bastion",
 		Short: "Interactive bastion host connection manager",
 		Long: `Interactive menu for managing bastion host connections.
-Choose between SSH access, SOCKS proxy, or port forwarding.`,
+Choose between SSH access, SOCKS proxy, or port forwarding.
+
+The ssh, socks and forward subcommands take the same choices as flags, so they
+can be scripted. Values that are not given are prompted for, or are an error
+when stdin is not a terminal. The list subcommand prints the bastion instances
+found in AWS.`,
+		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, cancel := context.WithCancel(context.Background())
-			defer cancel()
-
-			go func() {
-				<-cmd.Context().Done()
-				cancel()
-			}()
-
-			err := deps.Service.Run(ctx)
-			if errors.Is(err, promptutils.ErrInterrupted) {
-				return nil
-			}
-			return err
+			return run(cmd, deps.Service.Run)
 		},
 	}
 
+	cmd.AddCommand(
+		sshCmd(deps.Service),
+		socksCmd(deps.Service),
+		forwardCmd(deps.Service),
+		listCmd(deps.Service),
+	)
+
 	return cmd
 }
+
+// run calls fn with the command's context, treating an interrupted prompt as
+// success.
+func run(cmd *cobra.Command, fn func(ctx context.Context) error) error {
+	return promptutils.IgnoreInterrupt(fn(cmd.Context()))
+}
+
+type connectFlags struct {
+	method   string
+	instance string
+	host     string
+	user     string
+	keyPath  string
+}
+
+func addConnectFlags(cmd *cobra.Command, f *connectFlags) {
+	cmd.Flags().StringVar(&f.method, "method", "", "Connection method: ssh or ssm")
+	cmd.Flags().StringVar(&f.instance, "instance", "", "Bastion instance ID")
+	cmd.Flags().StringVar(&f.host, "host", "", "Bastion host name or IP (ssh only)")
+	cmd.Flags().StringVar(&f.user, "user", "ec2-user", "SSH user")
+	cmd.Flags().StringVar(&f.keyPath, "key", "~/.ssh/id_ed25519", "SSH private key (ssh to a host only)")
+	cmd.MarkFlagsMutuallyExclusive("instance", "host")
+}
+
+// options validates the connection flags and returns them as
+// ConnectOptions.
+func (f *connectFlags) options(cmd *cobra.Command) (connection.ConnectOptions, error) {
+	if err := promptutils.RequireFlags(cmd, "method"); err != nil {
+		return connection.ConnectOptions{}, err
+	}
+	if !promptutils.IsInteractive() && f.instance == "" && f.host == "" {
+		return connection.ConnectOptions{}, errors.New("--instance or --host is required when stdin is not a terminal")
+	}
+
+	opts := connection.ConnectOptions{
+		Host:    f.instance,
+		User:    f.user,
+		KeyPath: f.keyPath,
+	}
+	if f.host != "" {
+		opts.Host = f.host
+	}
+	if f.method != "" {
+		method, err := connection.ParseMethod(f.method)
+		if err != nil {
+			return connection.ConnectOptions{}, err
+		}
+		opts.Method = method
+	}
+	if opts.Method == connection.MethodSSM && f.host != "" {
+		return connection.ConnectOptions{}, errors.New("--host cannot be used with --method ssm; use --instance")
+	}
+	return opts, nil
+}
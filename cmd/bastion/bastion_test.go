@@ -1,6 +1,7 @@
 package bastion_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -63,3 +64,40 @@ func TestBastionCmd_Run_Error(t *testing.T) {
 	err := executeCommand(cmd)
 	assert.EqualError(t, err, "unexpected error")
 }
+
+func TestBastionCmd_RejectsArgs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+
+	cmd := bastion.NewBastionCmd(bastion.BastionDependencies{
+		Service: mockService,
+	})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := executeCommand(cmd, "extra")
+	assert.Error(t, err)
+}
+
+func TestBastionCmd_Run_UsesCommandContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+	mockService.EXPECT().Run(gomock.Any()).DoAndReturn(func(got context.Context) error {
+		assert.Equal(t, "value", got.Value(key{}))
+		return nil
+	})
+
+	cmd := bastion.NewBastionCmd(bastion.BastionDependencies{
+		Service: mockService,
+	})
+	cmd.SetArgs([]string{})
+
+	assert.NoError(t, cmd.ExecuteContext(ctx))
+}
@@ -0,0 +1,81 @@
+package bastion
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/BerryBytes/awsctl/internal/bastion"
+	"github.com/BerryBytes/awsctl/utils/common"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/spf13/cobra"
+)
+
+func forwardCmd(service bastion.BastionServiceInterface) *cobra.Command {
+	var flags connectFlags
+	var localPort int
+	var remote string
+
+	cmd := &cobra.Command{
+		Use:   "forward",
+		Short: "Forward a local port to a remote host through a bastion host",
+		Long: `Forward a local port to a host and port reachable from a bastion host until
+Ctrl+C is pressed.`,
+		Example:      `  awsctl bastion forward --instance i-0123456789abcdef0 --method ssm --local 5432 --remote db.internal:5432`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := promptutils.RequireFlags(cmd, "local", "remote"); err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("local") {
+				if err := common.ValidatePort(localPort); err != nil {
+					return fmt.Errorf("invalid --local: %w", err)
+				}
+			}
+			var remoteHost string
+			var remotePort int
+			if remote != "" {
+				var err error
+				remoteHost, remotePort, err = parseHostPort(remote)
+				if err != nil {
+					return fmt.Errorf("invalid --remote: %w", err)
+				}
+			}
+
+			connect, err := flags.options(cmd)
+			if err != nil {
+				return err
+			}
+
+			return run(cmd, func(ctx context.Context) error {
+				return service.StartPortForwarding(ctx, connect, localPort, remoteHost, remotePort)
+			})
+		},
+	}
+
+	addConnectFlags(cmd, &flags)
+	cmd.Flags().IntVar(&localPort, "local", 0, "Local port to listen on")
+	cmd.Flags().StringVar(&remote, "remote", "", "Remote host:port to forward to")
+
+	return cmd
+}
+
+func parseHostPort(value string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(value)
+	if err != nil {
+		return "", 0, err
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("missing host in %q", value)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q", portStr)
+	}
+	if err := common.ValidatePort(port); err != nil {
+		return "", 0, err
+	}
+	return host, port, nil
+}
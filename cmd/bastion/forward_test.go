@@ -0,0 +1,91 @@
+package bastion_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/bastion"
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardCmd(t *testing.T) {
+	connect := connection.ConnectOptions{
+		Method:  connection.MethodSSM,
+		Host:    "i-0123456789abcdef0",
+		User:    "ec2-user",
+		KeyPath: "~/.ssh/id_ed25519",
+	}
+	target := []string{"forward", "--instance", "i-0123456789abcdef0", "--method", "ssm"}
+
+	tests := []struct {
+		name        string
+		interactive bool
+		args        []string
+		expect      func(*mock_awsctl.MockBastionServiceInterface)
+		wantErr     string
+	}{
+		{
+			name: "local and remote",
+			args: []string{"--local", "15432", "--remote", "db.internal:5432"},
+			expect: func(m *mock_awsctl.MockBastionServiceInterface) {
+				m.EXPECT().StartPortForwarding(gomock.Any(), connect, 15432, "db.internal", 5432).Return(nil)
+			},
+		},
+		{
+			name:        "missing values are prompted for on a terminal",
+			interactive: true,
+			args:        []string{"--remote", "db.internal:5432"},
+			expect: func(m *mock_awsctl.MockBastionServiceInterface) {
+				m.EXPECT().StartPortForwarding(gomock.Any(), connect, 0, "db.internal", 5432).Return(nil)
+			},
+		},
+		{
+			name:    "missing local without a terminal",
+			args:    []string{"--remote", "db.internal:5432"},
+			wantErr: "--local is required when stdin is not a terminal",
+		},
+		{
+			name:    "missing remote without a terminal",
+			args:    []string{"--local", "15432"},
+			wantErr: "--remote is required when stdin is not a terminal",
+		},
+		{
+			name:    "remote without port",
+			args:    []string{"--local", "15432", "--remote", "db.internal"},
+			wantErr: "invalid --remote: address db.internal: missing port in address",
+		},
+		{
+			name:    "remote without host",
+			args:    []string{"--local", "15432", "--remote", ":5432"},
+			wantErr: `invalid --remote: missing host in ":5432"`,
+		},
+		{
+			name:    "invalid local port",
+			args:    []string{"--local", "0", "--remote", "db.internal:5432"},
+			wantErr: "invalid --local: invalid port number: 0 (must be between 1 and 65535)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(promptutils.SetInteractive(tt.interactive))
+			ctrl := gomock.NewController(t)
+			mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+			if tt.expect != nil {
+				tt.expect(mockService)
+			}
+
+			cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+			cmd.SilenceErrors = true
+			err := executeCommand(cmd, append(target, tt.args...)...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package bastion
+
+import (
+	"context"
+
+	"github.com/BerryBytes/awsctl/internal/bastion"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
+	"github.com/spf13/cobra"
+)
+
+var instanceColumns = []output.Column[models.EC2Instance]{
+	{Header: "INSTANCE ID", Value: func(i models.EC2Instance) string { return i.InstanceID }},
+	{Header: "NAME", Value: func(i models.EC2Instance) string { return i.Name }},
+	{Header: "STATE", Value: func(i models.EC2Instance) string { return i.State }},
+	{Header: "TYPE", Value: func(i models.EC2Instance) string { return i.InstanceType }},
+	{Header: "PRIVATE IP", Value: func(i models.EC2Instance) string { return i.PrivateIPAddress }},
+	{Header: "PUBLIC IP", Value: func(i models.EC2Instance) string { return i.PublicIPAddress }},
+	{Header: "AZ", Value: func(i models.EC2Instance) string { return i.AZ }},
+}
+
+func listCmd(service bastion.BastionServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List bastion instances",
+		Long: `List the bastion instances of the region given with --region, or of the
+configured region. Use -o json, yaml, text or template=<template> for output
+that scripts can read.`,
+		Example: `  awsctl bastion list --region eu-west-1
+  awsctl bastion list -o json | jq -r '.[].instanceId'
+  awsctl bastion list -o 'template={{.instanceId}} {{.privateIpAddress}}'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd, func(ctx context.Context) error {
+				instances, err := service.ListInstances(ctx)
+				if err != nil {
+					return err
+				}
+				return output.Print(cmd.OutOrStdout(), output.Format(cmd), instances, instanceColumns)
+			})
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package bastion_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/bastion"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCmd(t *testing.T) {
+	instances := []models.EC2Instance{
+		{InstanceID: "i-0123456789abcdef0", Name: "bastion", State: "running", InstanceType: "t3.micro", PrivateIPAddress: "10.0.0.5", AZ: "eu-west-1a"},
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		err     error
+		want    string
+		wantErr string
+	}{
+		{
+			name: "table",
+			args: []string{"list"},
+			want: "INSTANCE ID          NAME     STATE    TYPE      PRIVATE IP  PUBLIC IP  AZ\n" +
+				"i-0123456789abcdef0  bastion  running  t3.micro  10.0.0.5    -          eu-west-1a\n",
+		},
+		{
+			name: "template",
+			args: []string{"list", "-o", "template={{.instanceId}} {{.privateIpAddress}}"},
+			want: "i-0123456789abcdef0 10.0.0.5\n",
+		},
+		{
+			name:    "lookup error",
+			args:    []string{"list"},
+			err:     errors.New("AWS lookup failed: denied"),
+			wantErr: "AWS lookup failed: denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+			if tt.err != nil {
+				mockService.EXPECT().ListInstances(gomock.Any()).Return(nil, tt.err)
+			} else {
+				mockService.EXPECT().ListInstances(gomock.Any()).Return(instances, nil)
+			}
+
+			cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+			cmd.PersistentFlags().StringP("output", "o", "", "")
+			cmd.SilenceErrors = true
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
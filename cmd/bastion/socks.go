@@ -0,0 +1,41 @@
+package bastion
+
+import (
+	"context"
+
+	"github.com/BerryBytes/awsctl/internal/bastion"
+	"github.com/BerryBytes/awsctl/utils/common"
+	"github.com/spf13/cobra"
+)
+
+func socksCmd(service bastion.BastionServiceInterface) *cobra.Command {
+	var flags connectFlags
+	var port int
+
+	cmd := &cobra.Command{
+		Use:   "socks",
+		Short: "Run a SOCKS proxy through a bastion host",
+		Long: `Run a SOCKS proxy on a local port through a bastion host until Ctrl+C is
+pressed.`,
+		Example:      `  awsctl bastion socks --instance i-0123456789abcdef0 --method ssh --port 1080`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := common.ValidatePort(port); err != nil {
+				return err
+			}
+			connect, err := flags.options(cmd)
+			if err != nil {
+				return err
+			}
+			return run(cmd, func(ctx context.Context) error {
+				return service.StartSOCKSProxy(ctx, connect, port)
+			})
+		},
+	}
+
+	addConnectFlags(cmd, &flags)
+	cmd.Flags().IntVar(&port, "port", 1080, "Local SOCKS proxy port")
+
+	return cmd
+}
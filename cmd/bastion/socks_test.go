@@ -0,0 +1,50 @@
+package bastion_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/bastion"
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSOCKSCmd(t *testing.T) {
+	t.Cleanup(promptutils.SetInteractive(false))
+	connect := connection.ConnectOptions{
+		Method:  connection.MethodSSH,
+		Host:    "i-0123456789abcdef0",
+		User:    "ec2-user",
+		KeyPath: "~/.ssh/id_ed25519",
+	}
+
+	t.Run("default port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+		mockService.EXPECT().StartSOCKSProxy(gomock.Any(), connect, 1080).Return(nil)
+
+		cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+		assert.NoError(t, executeCommand(cmd, "socks", "--instance", "i-0123456789abcdef0", "--method", "ssh"))
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+		mockService.EXPECT().StartSOCKSProxy(gomock.Any(), connect, 1081).Return(promptutils.ErrInterrupted)
+
+		cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+		assert.NoError(t, executeCommand(cmd, "socks", "--instance", "i-0123456789abcdef0", "--method", "ssh", "--port", "1081"))
+	})
+
+	t.Run("invalid port", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+
+		cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		err := executeCommand(cmd, "socks", "--instance", "i-0123456789abcdef0", "--method", "ssh", "--port", "70000")
+		assert.EqualError(t, err, "invalid port number: 70000 (must be between 1 and 65535)")
+	})
+}
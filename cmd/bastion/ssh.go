@@ -0,0 +1,38 @@
+package bastion
+
+import (
+	"context"
+
+	"github.com/BerryBytes/awsctl/internal/bastion"
+	"github.com/spf13/cobra"
+)
+
+func sshCmd(service bastion.BastionServiceInterface) *cobra.Command {
+	var flags connectFlags
+
+	cmd := &cobra.Command{
+		Use:   "ssh",
+		Short: "Open a shell on a bastion host",
+		Long: `Open a shell on a bastion host over SSH or an SSM session.
+
+With --method ssh and an --instance ID, the connection uses EC2 Instance
+Connect and no SSH key is needed.`,
+		Example: `  awsctl bastion ssh --instance i-0123456789abcdef0 --method ssm
+  awsctl bastion ssh --host bastion.example.com --method ssh --key ~/.ssh/bastion`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			connect, err := flags.options(cmd)
+			if err != nil {
+				return err
+			}
+			return run(cmd, func(ctx context.Context) error {
+				return service.SSHIntoBastion(ctx, connect)
+			})
+		},
+	}
+
+	addConnectFlags(cmd, &flags)
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package bastion_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/bastion"
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHCmd(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		args        []string
+		want        *connection.ConnectOptions
+		wantErr     string
+	}{
+		{
+			name: "ssm instance",
+			args: []string{"--instance", "i-0123456789abcdef0", "--method", "ssm"},
+			want: &connection.ConnectOptions{
+				Method:  connection.MethodSSM,
+				Host:    "i-0123456789abcdef0",
+				User:    "ec2-user",
+				KeyPath: "~/.ssh/id_ed25519",
+			},
+		},
+		{
+			name: "ssh host",
+			args: []string{"--host", "bastion.example.com", "--method", "ssh", "--user", "ubuntu", "--key", "~/.ssh/bastion"},
+			want: &connection.ConnectOptions{
+				Method:  connection.MethodSSH,
+				Host:    "bastion.example.com",
+				User:    "ubuntu",
+				KeyPath: "~/.ssh/bastion",
+			},
+		},
+		{
+			name:        "missing values are prompted for on a terminal",
+			interactive: true,
+			args:        []string{},
+			want: &connection.ConnectOptions{
+				User:    "ec2-user",
+				KeyPath: "~/.ssh/id_ed25519",
+			},
+		},
+		{
+			name:    "missing method without a terminal",
+			args:    []string{"--instance", "i-0123456789abcdef0"},
+			wantErr: "--method is required when stdin is not a terminal",
+		},
+		{
+			name:    "missing target without a terminal",
+			args:    []string{"--method", "ssm"},
+			wantErr: "--instance or --host is required when stdin is not a terminal",
+		},
+		{
+			name:    "invalid method",
+			args:    []string{"--instance", "i-0123456789abcdef0", "--method", "telnet"},
+			wantErr: `invalid method "telnet": must be ssh or ssm`,
+		},
+		{
+			name:    "ssm with host",
+			args:    []string{"--host", "bastion.example.com", "--method", "ssm"},
+			wantErr: "--host cannot be used with --method ssm; use --instance",
+		},
+		{
+			name:    "instance and host",
+			args:    []string{"--instance", "i-0123456789abcdef0", "--host", "bastion.example.com", "--method", "ssh"},
+			wantErr: "if any flags in the group [instance host] are set none of the others can be; [host instance] were all set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(promptutils.SetInteractive(tt.interactive))
+			ctrl := gomock.NewController(t)
+			mockService := mock_awsctl.NewMockBastionServiceInterface(ctrl)
+			if tt.want != nil {
+				mockService.EXPECT().SSHIntoBastion(gomock.Any(), *tt.want).Return(nil)
+			}
+
+			cmd := bastion.NewBastionCmd(bastion.BastionDependencies{Service: mockService})
+			cmd.SilenceErrors = true
+			err := executeCommand(cmd, append([]string{"ssh"}, tt.args...)...)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/console"
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/utils/common"
+
+	"github.com/spf13/cobra"
+)
+
+// signinTimeout bounds the getSigninToken request and, with --logout-first,
+// the time the browser has to load the local redirect page.
+const signinTimeout = 2 * time.Minute
+
+type ConsoleDependencies struct {
+	SSOClient sso.SSOClient
+	// HTTPClient calls the federation endpoint. It defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+	// OpenURL opens a URL in the browser. It defaults to common.OpenURL.
+	OpenURL func(url string) error
+}
+
+func NewConsoleCmd(deps ConsoleDependencies) *cobra.Command {
+	if deps.OpenURL == nil {
+		deps.OpenURL = common.OpenURL
+	}
+
+	var (
+		profile     string
+		service     string
+		region      string
+		endpoint    string
+		open        bool
+		logoutFirst bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "console",
+		Short: "Print a sign-in URL for the AWS web console",
+		Long: `Sign in to the AWS web console as the role of a profile.
+
+The role credentials of the profile, an SSO profile or an assume-role profile,
+are exchanged for a sign-in token at the federation endpoint and a login URL is
+printed. The URL is valid for 15 minutes and opens the console of --service in
+the region of the profile, or --region.
+
+--open opens the URL in the browser. --logout-first first signs out of the
+current console session through a local page that is served only once, so the
+console switches accounts instead of keeping the old session.`,
+		Example: `  awsctl console --profile dev-admin
+  awsctl console --profile prod-deploy --service ec2 --region eu-west-1 --open
+  awsctl console --profile dev-admin --logout-first`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if profile == "" {
+				profile = os.Getenv("AWS_PROFILE")
+			}
+			if profile == "" {
+				return errors.New("no profile given: pass --profile or set AWS_PROFILE")
+			}
+
+			creds, err := deps.SSOClient.ProfileCredentials(profile)
+			if errors.Is(err, sso.ErrLoginRequired) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "SSO session for profile %s has expired. Logging in...\n", profile)
+				if err := deps.SSOClient.SSOLogin(profile, false, false); err != nil {
+					return fmt.Errorf("failed to login: %w", err)
+				}
+				creds, err = deps.SSOClient.ProfileCredentials(profile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for profile %s: %w", profile, err)
+			}
+
+			if region == "" {
+				region, _ = deps.SSOClient.GetAWSRegion(profile)
+			}
+			if region == "" {
+				region = "us-east-1"
+			}
+			if endpoint == "" {
+				endpoint = console.SigninEndpoint(region)
+			}
+
+			federation := &console.Federation{Endpoint: endpoint, HTTPClient: deps.HTTPClient}
+			ctx, cancel := context.WithTimeout(cmd.Context(), signinTimeout)
+			defer cancel()
+
+			loginURL, err := federation.LoginURL(ctx, creds, console.DestinationURL(region, service))
+			if err != nil {
+				return fmt.Errorf("failed to create console sign-in URL for profile %s: %w", profile, err)
+			}
+
+			switch {
+			case logoutFirst:
+				if err := federation.LogoutFirst(ctx, loginURL, deps.OpenURL); err != nil {
+					return err
+				}
+			case open:
+				if err := deps.OpenURL(loginURL); err != nil {
+					return err
+				}
+			default:
+				_, err := fmt.Fprintln(cmd.OutOrStdout(), loginURL)
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Opened the AWS console for profile %s\n", profile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile whose role is used (defaults to AWS_PROFILE)")
+	cmd.Flags().StringVar(&service, "service", "", "Console of the service to open, e.g. ec2 or s3 (default the console home page)")
+	cmd.Flags().StringVar(&region, "region", "", "Region to open the console in (defaults to the profile's region)")
+	cmd.Flags().StringVar(&endpoint, "signin-endpoint", "", "Federation endpoint (default https://signin.aws.amazon.com, or the endpoint of the region's partition)")
+	cmd.Flags().BoolVar(&open, "open", false, "Open the URL in the browser instead of printing it")
+	cmd.Flags().BoolVar(&logoutFirst, "logout-first", false, "Sign out of the current console session before signing in; implies --open")
+
+	return cmd
+}
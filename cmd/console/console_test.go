@@ -0,0 +1,138 @@
+package console
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleCmd(t *testing.T) {
+	creds := &models.AWSCredentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "token"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"SigninToken":"signin-token"}`))
+	}))
+	defer server.Close()
+	loginPrefix := server.URL + "/federation?Action=login&Destination="
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expectedURL   string
+		opened        bool
+	}{
+		{
+			name: "prints login URL",
+			args: []string{"--profile", "dev", "--service", "ec2"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("eu-west-1", nil)
+			},
+			expectedURL: loginPrefix + "https%3A%2F%2Fconsole.aws.amazon.com%2Fec2%2Fhome%3Fregion%3Deu-west-1&Issuer=awsctl&SigninToken=signin-token",
+		},
+		{
+			name: "region flag and AWS_PROFILE",
+			args: []string{"--region", "ap-south-1"},
+			env:  "dev",
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+			},
+			expectedURL: loginPrefix + "https%3A%2F%2Fconsole.aws.amazon.com%2Fconsole%2Fhome%3Fregion%3Dap-south-1&Issuer=awsctl&SigninToken=signin-token",
+		},
+		{
+			name: "opens login URL",
+			args: []string{"--profile", "dev", "--open"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("", errors.New("no region"))
+			},
+			expectedURL: loginPrefix + "https%3A%2F%2Fconsole.aws.amazon.com%2Fconsole%2Fhome%3Fregion%3Dus-east-1&Issuer=awsctl&SigninToken=signin-token",
+			opened:      true,
+		},
+		{
+			name: "logs in when the SSO session expired",
+			args: []string{"--profile", "dev", "--region", "eu-west-1"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				gomock.InOrder(
+					m.EXPECT().ProfileCredentials("dev").Return(nil, sso.ErrLoginRequired),
+					m.EXPECT().SSOLogin("dev", false, false).Return(nil),
+					m.EXPECT().ProfileCredentials("dev").Return(creds, nil),
+				)
+			},
+			expectedURL: loginPrefix + "https%3A%2F%2Fconsole.aws.amazon.com%2Fconsole%2Fhome%3Fregion%3Deu-west-1&Issuer=awsctl&SigninToken=signin-token",
+		},
+		{
+			name: "credentials error",
+			args: []string{"--profile", "dev"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(nil, errors.New("profile dev not found"))
+			},
+			expectedError: "failed to get credentials for profile dev: profile dev not found",
+		},
+		{
+			name: "long-term credentials",
+			args: []string{"--profile", "dev", "--region", "eu-west-1"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(&models.AWSCredentials{AccessKeyID: "AKIA", SecretAccessKey: "secret"}, nil)
+			},
+			expectedError: "failed to create console sign-in URL for profile dev: console sign-in needs temporary credentials with a session token",
+		},
+		{
+			name:          "no profile",
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "no profile given",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", tt.env)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var opened []string
+			cmd := NewConsoleCmd(ConsoleDependencies{
+				SSOClient: mockSSOClient,
+				OpenURL: func(url string) error {
+					opened = append(opened, url)
+					return nil
+				},
+			})
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SetArgs(append(tt.args, "--signin-endpoint", server.URL))
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.opened {
+				assert.Equal(t, []string{tt.expectedURL}, opened)
+				assert.Empty(t, stdout.String())
+			} else {
+				assert.Empty(t, opened)
+				assert.Equal(t, tt.expectedURL, strings.TrimSpace(stdout.String()))
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package ecr
 
 import (
-	"errors"
+	"time"
 
 	"github.com/BerryBytes/awsctl/internal/ecr"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/spf13/cobra"
 )
@@ -17,16 +19,84 @@ func NewECRCmd(deps ECRDependencies) *cobra.Command {
 		Use:   "ecr",
 		Short: "Interactive AWS ECR login manager",
 		Long: `Interactive menu for logging into AWS Elastic Container Registry (ECR).
-Supports authentication to ECR repositories.`,
+Supports authentication to ECR repositories.
+
+The login subcommand logs in without the menu, so it can be scripted. The repos
+subcommand prints the repositories of the registry.`,
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := deps.Service.Run()
-			if errors.Is(err, promptutils.ErrInterrupted) {
-				return nil
+			return promptutils.IgnoreInterrupt(deps.Service.Run())
+		},
+	}
+
+	cmd.AddCommand(
+		loginCmd(deps.Service),
+		reposCmd(deps.Service),
+	)
+
+	return cmd
+}
+
+func loginCmd(service ecr.ECRServiceInterface) *cobra.Command {
+	var opts ecr.Options
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log docker in to an ECR registry",
+		Long: `Log docker in to an ECR registry. Without --registry, the registry of the
+account of the current credentials is used.
+
+The region defaults to the region of --registry, then to the configured
+region.`,
+		Example: `  awsctl ecr login
+  awsctl ecr login --registry 123456789012.dkr.ecr.eu-west-1.amazonaws.com --profile prod-admin`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Registry != "" {
+				if _, err := ecr.RegistryRegion(opts.Registry); err != nil {
+					return err
+				}
 			}
-			return err
+			return promptutils.IgnoreInterrupt(service.Login(opts))
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.Registry, "registry", "", "ECR registry host")
+
 	return cmd
 }
+
+var repositoryColumns = []output.Column[models.ECRRepository]{
+	{Header: "NAME", Value: func(r models.ECRRepository) string { return r.RepositoryName }},
+	{Header: "URI", Value: func(r models.ECRRepository) string { return r.RepositoryURI }},
+	{Header: "TAG MUTABILITY", Value: func(r models.ECRRepository) string { return r.ImageTagMutability }},
+	{Header: "CREATED", Value: func(r models.ECRRepository) string {
+		if r.CreatedAt.IsZero() {
+			return ""
+		}
+		return r.CreatedAt.Format(time.RFC3339)
+	}},
+}
+
+func reposCmd(service ecr.ECRServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repos",
+		Short: "List ECR repositories",
+		Long: `List the repositories of the registry of the account of the current
+credentials, in the region given with --region or the configured region. Use
+-o json, yaml, text or template=<template> for output that scripts can read.`,
+		Example: `  awsctl ecr repos --profile prod-admin
+  awsctl ecr repos -o json | jq -r '.[].repositoryUri'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repositories, err := service.ListRepositories()
+			if err != nil {
+				return promptutils.IgnoreInterrupt(err)
+			}
+			return output.Print(cmd.OutOrStdout(), output.Format(cmd), repositories, repositoryColumns)
+		},
+	}
+}
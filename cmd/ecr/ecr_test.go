@@ -1,10 +1,14 @@
 package ecr_test
 
 import (
+	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/BerryBytes/awsctl/cmd/ecr"
+	internalecr "github.com/BerryBytes/awsctl/internal/ecr"
+	"github.com/BerryBytes/awsctl/models"
 	mock_ecr "github.com/BerryBytes/awsctl/tests/mock/ecr"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/golang/mock/gomock"
@@ -114,3 +118,89 @@ func TestECRCmdArgsHandling(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestLoginCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    *internalecr.Options
+		wantErr string
+	}{
+		{
+			name: "account registry",
+			args: []string{"login"},
+			want: &internalecr.Options{},
+		},
+		{
+			name: "registry",
+			args: []string{"login", "--registry", "123456789012.dkr.ecr.eu-west-1.amazonaws.com"},
+			want: &internalecr.Options{Registry: "123456789012.dkr.ecr.eu-west-1.amazonaws.com"},
+		},
+		{
+			name:    "invalid registry",
+			args:    []string{"login", "--registry", "docker.io"},
+			wantErr: `invalid ECR registry "docker.io": expected <account>.dkr.ecr.<region>.amazonaws.com`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mock_ecr.NewMockECRServiceInterface(ctrl)
+			if tt.want != nil {
+				mockService.EXPECT().Login(*tt.want).Return(nil)
+			}
+
+			cmd := ecr.NewECRCmd(ecr.ECRDependencies{Service: mockService})
+			cmd.SilenceErrors = true
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReposCmd(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_ecr.NewMockECRServiceInterface(ctrl)
+		mockService.EXPECT().ListRepositories().Return([]models.ECRRepository{
+			{
+				RepositoryName:     "api",
+				RepositoryURI:      "123456789012.dkr.ecr.eu-west-1.amazonaws.com/api",
+				RegistryID:         "123456789012",
+				ImageTagMutability: "IMMUTABLE",
+				CreatedAt:          time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+			},
+		}, nil)
+
+		cmd := ecr.NewECRCmd(ecr.ECRDependencies{Service: mockService})
+		cmd.PersistentFlags().StringP("output", "o", "", "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"repos", "-o", "yaml"})
+
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, `- repositoryName: api
+  repositoryUri: 123456789012.dkr.ecr.eu-west-1.amazonaws.com/api
+  registryId: "123456789012"
+  imageTagMutability: IMMUTABLE
+  createdAt: 2024-05-01T12:00:00Z
+`, out.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_ecr.NewMockECRServiceInterface(ctrl)
+		mockService.EXPECT().ListRepositories().Return(nil, errors.New("AWS configuration not found"))
+
+		cmd := ecr.NewECRCmd(ecr.ECRDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"repos"})
+		assert.EqualError(t, cmd.Execute(), "AWS configuration not found")
+	})
+}
@@ -1,9 +1,9 @@
 package eks
 
 import (
-	"errors"
-
 	"github.com/BerryBytes/awsctl/internal/eks"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/spf13/cobra"
 )
@@ -17,16 +17,72 @@ func NewEKSCmd(deps EKSDependencies) *cobra.Command {
 		Use:   "eks",
 		Short: "Interactive EKS cluster manager",
 		Long: `Interactive menu for managing EKS cluster configurations.
-Supports updating kubeconfig for EKS clusters.`,
+Supports updating kubeconfig for EKS clusters.
+
+The update-kubeconfig subcommand takes the cluster as a flag, so it can be
+scripted. The list subcommand prints the EKS clusters found in AWS.`,
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := deps.Service.Run()
-			if errors.Is(err, promptutils.ErrInterrupted) {
-				return nil
+			return promptutils.IgnoreInterrupt(deps.Service.Run())
+		},
+	}
+
+	cmd.AddCommand(
+		updateKubeconfigCmd(deps.Service),
+		listCmd(deps.Service),
+	)
+
+	return cmd
+}
+
+func updateKubeconfigCmd(service eks.EKSServiceInterface) *cobra.Command {
+	var opts eks.Options
+
+	cmd := &cobra.Command{
+		Use:   "update-kubeconfig",
+		Short: "Add an EKS cluster to the kubeconfig",
+		Long: `Add an EKS cluster to the kubeconfig. The cluster is prompted for when
+--cluster is not given, or is an error when stdin is not a terminal.`,
+		Example:      `  awsctl eks update-kubeconfig --cluster prod --profile prod-admin --region eu-west-1`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := promptutils.RequireFlags(cmd, "cluster"); err != nil {
+				return err
 			}
-			return err
+			return promptutils.IgnoreInterrupt(service.UpdateKubeconfig(opts))
 		},
 	}
 
+	cmd.Flags().StringVar(&opts.Cluster, "cluster", "", "EKS cluster name")
+
 	return cmd
 }
+
+var clusterColumns = []output.Column[models.EKSCluster]{
+	{Header: "NAME", Value: func(c models.EKSCluster) string { return c.ClusterName }},
+	{Header: "REGION", Value: func(c models.EKSCluster) string { return c.Region }},
+	{Header: "ENDPOINT", Value: func(c models.EKSCluster) string { return c.Endpoint }},
+}
+
+func listCmd(service eks.EKSServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List EKS clusters",
+		Long: `List the EKS clusters of the region given with --region, or of the
+configured region. Use -o json, yaml, text or template=<template> for output
+that scripts can read.`,
+		Example: `  awsctl eks list --region eu-west-1
+  awsctl eks list -o 'template={{.clusterName}}' | xargs -n1 awsctl eks update-kubeconfig --cluster`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusters, err := service.ListClusters()
+			if err != nil {
+				return promptutils.IgnoreInterrupt(err)
+			}
+			return output.Print(cmd.OutOrStdout(), output.Format(cmd), clusters, clusterColumns)
+		},
+	}
+}
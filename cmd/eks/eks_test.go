@@ -1,10 +1,13 @@
 package eks_test
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
 	"github.com/BerryBytes/awsctl/cmd/eks"
+	internaleks "github.com/BerryBytes/awsctl/internal/eks"
+	"github.com/BerryBytes/awsctl/models"
 	mock_eks "github.com/BerryBytes/awsctl/tests/mock/eks"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/golang/mock/gomock"
@@ -159,3 +162,57 @@ func TestEKSCmdArgsHandling(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestUpdateKubeconfigCmd(t *testing.T) {
+	t.Run("cluster", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(false))
+		ctrl := gomock.NewController(t)
+		mockService := mock_eks.NewMockEKSServiceInterface(ctrl)
+		mockService.EXPECT().UpdateKubeconfig(internaleks.Options{Cluster: "prod"}).Return(nil)
+
+		cmd := eks.NewEKSCmd(eks.EKSDependencies{Service: mockService})
+		cmd.SetArgs([]string{"update-kubeconfig", "--cluster", "prod"})
+		assert.NoError(t, cmd.Execute())
+	})
+
+	t.Run("missing cluster without a terminal", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(false))
+		ctrl := gomock.NewController(t)
+		mockService := mock_eks.NewMockEKSServiceInterface(ctrl)
+
+		cmd := eks.NewEKSCmd(eks.EKSDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"update-kubeconfig"})
+		assert.EqualError(t, cmd.Execute(), "--cluster is required when stdin is not a terminal")
+	})
+
+	t.Run("missing cluster is prompted for on a terminal", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(true))
+		ctrl := gomock.NewController(t)
+		mockService := mock_eks.NewMockEKSServiceInterface(ctrl)
+		mockService.EXPECT().UpdateKubeconfig(internaleks.Options{}).Return(errors.New("failed to update kubeconfig"))
+
+		cmd := eks.NewEKSCmd(eks.EKSDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"update-kubeconfig"})
+		assert.EqualError(t, cmd.Execute(), "failed to update kubeconfig")
+	})
+}
+
+func TestListCmd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockService := mock_eks.NewMockEKSServiceInterface(ctrl)
+	mockService.EXPECT().ListClusters().Return([]models.EKSCluster{
+		{ClusterName: "prod", Region: "eu-west-1", Endpoint: "https://prod.eks.amazonaws.com"},
+		{ClusterName: "staging", Region: "eu-west-1", Endpoint: "https://staging.eks.amazonaws.com"},
+	}, nil)
+
+	cmd := eks.NewEKSCmd(eks.EKSDependencies{Service: mockService})
+	cmd.PersistentFlags().StringP("output", "o", "", "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "-o", "text"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "prod\teu-west-1\thttps://prod.eks.amazonaws.com\nstaging\teu-west-1\thttps://staging.eks.amazonaws.com\n", out.String())
+}
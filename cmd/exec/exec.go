@@ -0,0 +1,106 @@
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/utils/common"
+
+	"github.com/spf13/cobra"
+)
+
+// NestedEnvVar is set in the environment of processes started by awsctl exec
+// to the name of the profile whose credentials they received.
+const NestedEnvVar = "AWSCTL_EXEC_PROFILE"
+
+type ExecDependencies struct {
+	SSOClient sso.SSOClient
+	// Run starts the child process and returns its exit code. It defaults to
+	// common.RunProcess.
+	Run func(name string, args []string, env []string) (int, error)
+	// Environ returns the environment the child inherits. It defaults to
+	// os.Environ.
+	Environ func() []string
+}
+
+func NewExecCmd(deps ExecDependencies) *cobra.Command {
+	if deps.Run == nil {
+		deps.Run = common.RunProcess
+	}
+	if deps.Environ == nil {
+		deps.Environ = os.Environ
+	}
+
+	var profile string
+
+	cmd := &cobra.Command{
+		Use:   "exec --profile <profile> -- <command> [args...]",
+		Short: "Run a command with the role credentials of an SSO profile",
+		Long: `Run a command with the role credentials of an SSO profile injected into its
+environment as AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and
+AWS_REGION. AWS_PROFILE is removed so the credentials take precedence.
+
+The cached SSO token is refreshed when needed, and a browser login is started
+if it has expired. Signals are forwarded to the command and awsctl exits with
+its exit code.`,
+		Example: `  awsctl exec --profile dev-admin -- terraform plan
+  awsctl exec --profile dev-admin -- aws s3 ls`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			environ := deps.Environ()
+			if active := lookupEnv(environ, NestedEnvVar); active != "" {
+				return fmt.Errorf("already running inside awsctl exec for profile %s; nested exec is not supported", active)
+			}
+
+			if profile == "" {
+				profile = lookupEnv(environ, "AWS_PROFILE")
+			}
+			if profile == "" {
+				return errors.New("no profile given: pass --profile or set AWS_PROFILE")
+			}
+
+			creds, err := deps.SSOClient.ProfileCredentials(profile)
+			if errors.Is(err, sso.ErrLoginRequired) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "SSO session for profile %s has expired. Logging in...\n", profile)
+				if err := deps.SSOClient.SSOLogin(profile, false, false); err != nil {
+					return fmt.Errorf("failed to login: %w", err)
+				}
+				creds, err = deps.SSOClient.ProfileCredentials(profile)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for profile %s: %w", profile, err)
+			}
+
+			region, _ := deps.SSOClient.GetAWSRegion(profile)
+
+			vars := append(sso.CredentialEnv(creds, region), sso.EnvVar{Name: NestedEnvVar, Value: profile})
+			code, err := deps.Run(args[0], args[1:], sso.WithCredentialEnv(environ, vars))
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				cmd.SilenceErrors = true
+				return &common.ExitCodeError{Code: code}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "SSO profile whose credentials are injected (defaults to AWS_PROFILE)")
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+func lookupEnv(environ []string, name string) string {
+	for i := len(environ) - 1; i >= 0; i-- {
+		if value, ok := strings.CutPrefix(environ[i], name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
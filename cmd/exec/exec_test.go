@@ -0,0 +1,198 @@
+package exec
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/BerryBytes/awsctl/utils/common"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type runCall struct {
+	name string
+	args []string
+	env  []string
+}
+
+func TestExecCmd(t *testing.T) {
+	creds := &models.AWSCredentials{
+		AccessKeyID:     "AKIA",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      "2030-01-01T00:00:00Z",
+	}
+	baseEnv := []string{"PATH=/usr/bin", "AWS_PROFILE=other", "AWS_ACCESS_KEY_ID=OLD"}
+
+	tests := []struct {
+		name          string
+		args          []string
+		environ       []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		exitCode      int
+		expectedError string
+		check         func(t *testing.T, call *runCall)
+	}{
+		{
+			name:    "runs command with injected credentials",
+			args:    []string{"--profile", "dev", "--", "terraform", "plan", "-out", "plan.tfplan"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("eu-west-1", nil)
+			},
+			check: func(t *testing.T, call *runCall) {
+				assert.Equal(t, "terraform", call.name)
+				assert.Equal(t, []string{"plan", "-out", "plan.tfplan"}, call.args)
+				assert.Equal(t, []string{
+					"PATH=/usr/bin",
+					"AWS_ACCESS_KEY_ID=AKIA",
+					"AWS_SECRET_ACCESS_KEY=secret",
+					"AWS_SESSION_TOKEN=token",
+					"AWS_CREDENTIAL_EXPIRATION=2030-01-01T00:00:00Z",
+					"AWS_REGION=eu-west-1",
+					"AWS_DEFAULT_REGION=eu-west-1",
+					"AWSCTL_EXEC_PROFILE=dev",
+				}, call.env)
+			},
+		},
+		{
+			name:    "flags after the command belong to the command",
+			args:    []string{"--profile", "dev", "aws", "--profile", "x", "s3", "ls"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("", errors.New("no region"))
+			},
+			check: func(t *testing.T, call *runCall) {
+				assert.Equal(t, "aws", call.name)
+				assert.Equal(t, []string{"--profile", "x", "s3", "ls"}, call.args)
+				assert.NotContains(t, call.env, "AWS_REGION=")
+			},
+		},
+		{
+			name:    "defaults to AWS_PROFILE",
+			args:    []string{"--", "env"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("other").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("other").Return("us-east-1", nil)
+			},
+			check: func(t *testing.T, call *runCall) {
+				assert.Contains(t, call.env, "AWSCTL_EXEC_PROFILE=other")
+				assert.NotContains(t, call.env, "AWS_PROFILE=other")
+			},
+		},
+		{
+			name:    "logs in when the SSO session expired",
+			args:    []string{"--profile", "dev", "--", "env"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				gomock.InOrder(
+					m.EXPECT().ProfileCredentials("dev").Return(nil, sso.ErrLoginRequired),
+					m.EXPECT().SSOLogin("dev", false, false).Return(nil),
+					m.EXPECT().ProfileCredentials("dev").Return(creds, nil),
+				)
+				m.EXPECT().GetAWSRegion("dev").Return("us-east-1", nil)
+			},
+			check: func(t *testing.T, call *runCall) {
+				assert.Contains(t, call.env, "AWS_ACCESS_KEY_ID=AKIA")
+			},
+		},
+		{
+			name:    "passes the exit code through",
+			args:    []string{"--profile", "dev", "--", "false"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("us-east-1", nil)
+			},
+			exitCode:      3,
+			expectedError: "exit status 3",
+		},
+		{
+			name:          "refuses to nest",
+			args:          []string{"--profile", "dev", "--", "env"},
+			environ:       append([]string{"AWSCTL_EXEC_PROFILE=prod"}, baseEnv...),
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "already running inside awsctl exec for profile prod",
+		},
+		{
+			name:          "requires a profile",
+			args:          []string{"--", "env"},
+			environ:       []string{"PATH=/usr/bin"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "no profile given",
+		},
+		{
+			name:          "requires a command",
+			args:          []string{"--profile", "dev"},
+			environ:       baseEnv,
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "requires at least 1 arg(s)",
+		},
+		{
+			name:    "login failure",
+			args:    []string{"--profile", "dev", "--", "env"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(nil, sso.ErrLoginRequired)
+				m.EXPECT().SSOLogin("dev", false, false).Return(errors.New("timed out"))
+			},
+			expectedError: "failed to login: timed out",
+		},
+		{
+			name:    "credentials error",
+			args:    []string{"--profile", "static", "--", "env"},
+			environ: baseEnv,
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("static").Return(nil, errors.New("profile static is not an SSO profile"))
+			},
+			expectedError: "failed to get credentials for profile static: profile static is not an SSO profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var call *runCall
+			cmd := NewExecCmd(ExecDependencies{
+				SSOClient: mockSSOClient,
+				Run: func(name string, args []string, env []string) (int, error) {
+					call = &runCall{name: name, args: args, env: env}
+					return tt.exitCode, nil
+				},
+				Environ: func() []string { return tt.environ },
+			})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				if tt.exitCode != 0 {
+					var exitErr *common.ExitCodeError
+					require.ErrorAs(t, err, &exitErr)
+					assert.Equal(t, tt.exitCode, exitErr.Code)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, call)
+			tt.check(t, call)
+		})
+	}
+}
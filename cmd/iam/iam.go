@@ -0,0 +1,82 @@
+package iam
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
+
+	"github.com/spf13/cobra"
+)
+
+type IAMDependencies struct {
+	SSOClient sso.SSOClient
+}
+
+func NewIAMCmd(deps IAMDependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam",
+		Short: "Manage credentials of IAM user profiles",
+		Long:  "Commands for profiles that authenticate as IAM users instead of through AWS SSO.",
+	}
+
+	cmd.AddCommand(sessionCmd(deps))
+	return cmd
+}
+
+func sessionCmd(deps IAMDependencies) *cobra.Command {
+	var opts sso.MFASessionOptions
+
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Start an MFA session for an IAM user profile",
+		Long: `Prompt for the MFA code of an IAM user profile with mfa_serial and get
+temporary credentials for it with STS GetSessionToken.
+
+The credentials are cached and exposed as a separate profile, <profile>-mfa by
+default, which gets them through credential_process. Use that profile with
+awsctl bastion, rds, eks, ecr and exec, or any other AWS tool, until the
+session expires. A still valid session is reused unless --force is passed.`,
+		Example: `  awsctl iam session --profile customer
+  awsctl iam session --profile customer --code 123456 --duration-seconds 3600
+  AWS_PROFILE=customer-mfa awsctl rds`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Profile == "" {
+				opts.Profile = os.Getenv("AWS_PROFILE")
+			}
+			if opts.Profile == "" {
+				return errors.New("no profile given: pass --profile or set AWS_PROFILE")
+			}
+			if opts.SessionProfile == "" {
+				opts.SessionProfile = opts.Profile + "-mfa"
+			}
+			if opts.DurationSeconds != 0 && (opts.DurationSeconds < 900 || opts.DurationSeconds > 129600) {
+				return fmt.Errorf("invalid --duration-seconds %d: must be between 900 and 129600", opts.DurationSeconds)
+			}
+
+			creds, err := deps.SSOClient.MFASession(opts)
+			if err != nil {
+				if errors.Is(err, promptUtils.ErrInterrupted) {
+					return nil
+				}
+				return fmt.Errorf("failed to start MFA session for profile %s: %w", opts.Profile, err)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "MFA session for profile %s is valid until %s\n", opts.Profile, creds.Expiration)
+			fmt.Fprintf(out, "Use profile %s, e.g.: eval \"$(awsctl sso use %s)\"\n", opts.SessionProfile, opts.SessionProfile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Profile, "profile", "", "IAM user profile with mfa_serial (defaults to AWS_PROFILE)")
+	cmd.Flags().StringVar(&opts.SessionProfile, "session-profile", "", "Profile that exposes the session (default <profile>-mfa)")
+	cmd.Flags().StringVar(&opts.TokenCode, "code", "", "MFA code; prompted for when not given")
+	cmd.Flags().Int32Var(&opts.DurationSeconds, "duration-seconds", 0, "Lifetime of the session in seconds (900-129600, default 43200)")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Start a new session even if the cached one is still valid")
+
+	return cmd
+}
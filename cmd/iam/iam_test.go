@@ -0,0 +1,121 @@
+package iam
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionCmd(t *testing.T) {
+	creds := &models.AWSCredentials{AccessKeyID: "ASIA", Expiration: "2030-01-01T00:00:00Z"}
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expected      string
+	}{
+		{
+			name: "default session profile",
+			args: []string{"session", "--profile", "customer"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().MFASession(sso.MFASessionOptions{Profile: "customer", SessionProfile: "customer-mfa"}).Return(creds, nil)
+			},
+			expected: `MFA session for profile customer is valid until 2030-01-01T00:00:00Z
+Use profile customer-mfa, e.g.: eval "$(awsctl sso use customer-mfa)"
+`,
+		},
+		{
+			name: "all flags",
+			args: []string{"session", "--profile", "customer", "--session-profile", "cust", "--code", "123456", "--duration-seconds", "3600", "--force"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().MFASession(sso.MFASessionOptions{
+					Profile:         "customer",
+					SessionProfile:  "cust",
+					TokenCode:       "123456",
+					DurationSeconds: 3600,
+					Force:           true,
+				}).Return(creds, nil)
+			},
+			expected: `MFA session for profile customer is valid until 2030-01-01T00:00:00Z
+Use profile cust, e.g.: eval "$(awsctl sso use cust)"
+`,
+		},
+		{
+			name: "profile from AWS_PROFILE",
+			args: []string{"session"},
+			env:  "customer",
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().MFASession(sso.MFASessionOptions{Profile: "customer", SessionProfile: "customer-mfa"}).Return(creds, nil)
+			},
+			expected: `MFA session for profile customer is valid until 2030-01-01T00:00:00Z
+Use profile customer-mfa, e.g.: eval "$(awsctl sso use customer-mfa)"
+`,
+		},
+		{
+			name: "interrupted",
+			args: []string{"session", "--profile", "customer"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().MFASession(gomock.Any()).Return(nil, promptUtils.ErrInterrupted)
+			},
+		},
+		{
+			name: "error",
+			args: []string{"session", "--profile", "customer"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().MFASession(gomock.Any()).Return(nil, errors.New("invalid MFA code"))
+			},
+			expectedError: "failed to start MFA session for profile customer: invalid MFA code",
+		},
+		{
+			name:          "no profile",
+			args:          []string{"session"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "no profile given",
+		},
+		{
+			name:          "invalid duration",
+			args:          []string{"session", "--profile", "customer", "--duration-seconds", "200000"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "invalid --duration-seconds 200000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", tt.env)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout bytes.Buffer
+			cmd := NewIAMCmd(IAMDependencies{SSOClient: mockSSOClient})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SilenceErrors = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package promptinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+
+	"github.com/spf13/cobra"
+)
+
+// CommandName is checked by main to skip the AWS SDK setup that prompt-info
+// does not need.
+const CommandName = "prompt-info"
+
+// DefaultFormat prints the profile, the account alias or ID and the minutes
+// left on the SSO token, e.g. "dev-admin (dev) 42m", or the token status
+// when it is not valid.
+const DefaultFormat = `{{.Profile}}{{with or .Alias .AccountID}} ({{.}}){{end}}{{if .Valid}} {{.MinutesLeft}}m{{else if ne .Status "not sso"}} {{.Status}}{{end}}`
+
+type PromptInfoDependencies struct {
+	SSOClient sso.SSOClient
+}
+
+func NewPromptInfoCmd(deps PromptInfoDependencies) *cobra.Command {
+	var profile string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   CommandName,
+		Short: "Print the active profile for a shell prompt",
+		Long: `Print a segment describing the active profile for PS1, starship or other
+shell prompts.
+
+Only ~/.aws/config, the awsctl config and the token caches are read, so the
+command never prompts or calls AWS and returns in a few milliseconds. Nothing is
+printed when no profile is active and there is no default profile.
+
+--format is a Go template with the fields .Profile, .AccountID, .Alias, .Role,
+.Region, .SSOSession, .ExpiresAt, .MinutesLeft, .Status and .Valid. .Alias is
+the account alias from the accounts section of the awsctl config.`,
+		Example: `  PS1='$(awsctl prompt-info) \$ '
+  awsctl prompt-info --format '{{.Profile}}@{{.Region}}{{if not .Valid}} (login){{end}}'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := template.New("prompt").Parse(format)
+			if err != nil {
+				return fmt.Errorf("invalid --format: %w", err)
+			}
+
+			if profile == "" {
+				profile = os.Getenv("AWS_PROFILE")
+			}
+			if profile == "" {
+				profile = os.Getenv("AWS_DEFAULT_PROFILE")
+			}
+			implicit := profile == ""
+			if implicit {
+				profile = "default"
+			}
+
+			info, err := deps.SSOClient.PromptInfo(profile)
+			if errors.Is(err, sso.ErrProfileNotFound) {
+				if implicit {
+					return nil
+				}
+				// Profiles that only have keys in ~/.aws/credentials are
+				// still shown by name.
+				info, err = &models.PromptInfo{Profile: profile, Status: sso.StatusNotSSO}, nil
+			}
+			if err != nil {
+				return err
+			}
+
+			var segment strings.Builder
+			if err := tmpl.Execute(&segment, info); err != nil {
+				return fmt.Errorf("invalid --format: %w", err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), segment.String())
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "Profile to describe (defaults to AWS_PROFILE, then default)")
+	cmd.Flags().StringVar(&format, "format", DefaultFormat, "Go template for the segment")
+
+	return cmd
+}
@@ -0,0 +1,139 @@
+package promptinfo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptInfoCmd(t *testing.T) {
+	valid := &models.PromptInfo{
+		Profile:     "dev-admin",
+		AccountID:   "111111111111",
+		Alias:       "dev",
+		Role:        "Admin",
+		Region:      "eu-west-1",
+		MinutesLeft: 42,
+		Status:      sso.StatusValid,
+		Valid:       true,
+	}
+	expired := &models.PromptInfo{Profile: "prod", AccountID: "222222222222", Status: sso.StatusExpired}
+	notFound := fmt.Errorf("%w: missing is not in ~/.aws/config", sso.ErrProfileNotFound)
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           map[string]string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "default format",
+			env:  map[string]string{"AWS_PROFILE": "dev-admin"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("dev-admin").Return(valid, nil)
+			},
+			expected: "dev-admin (dev) 42m\n",
+		},
+		{
+			name: "expired token",
+			args: []string{"--profile", "prod"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("prod").Return(expired, nil)
+			},
+			expected: "prod (222222222222) expired\n",
+		},
+		{
+			name: "custom format",
+			args: []string{"--profile", "dev-admin", "--format", "{{.Role}}@{{.Region}}"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("dev-admin").Return(valid, nil)
+			},
+			expected: "Admin@eu-west-1\n",
+		},
+		{
+			name: "AWS_DEFAULT_PROFILE",
+			env:  map[string]string{"AWS_DEFAULT_PROFILE": "prod"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("prod").Return(expired, nil)
+			},
+			expected: "prod (222222222222) expired\n",
+		},
+		{
+			name: "no active profile and no default",
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("default").Return(nil, notFound)
+			},
+		},
+		{
+			name: "profile only in the credentials file",
+			args: []string{"--profile", "missing"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("missing").Return(nil, notFound)
+			},
+			expected: "missing\n",
+		},
+		{
+			name: "read error",
+			args: []string{"--profile", "dev-admin"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("dev-admin").Return(nil, errors.New("failed to read ~/.aws/config"))
+			},
+			expectedError: "failed to read ~/.aws/config",
+		},
+		{
+			name:          "invalid format",
+			args:          []string{"--format", "{{.Profile"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "invalid --format",
+		},
+		{
+			name: "unknown field",
+			args: []string{"--profile", "dev-admin", "--format", "{{.Account}}"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().PromptInfo("dev-admin").Return(valid, nil)
+			},
+			expectedError: "invalid --format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_DEFAULT_PROFILE", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout bytes.Buffer
+			cmd := NewPromptInfoCmd(PromptInfoDependencies{SSOClient: mockSSOClient})
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
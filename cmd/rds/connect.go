@@ -0,0 +1,30 @@
+package rds
+
+import (
+	"github.com/BerryBytes/awsctl/internal/rds"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/spf13/cobra"
+)
+
+func connectCmd(service rds.RDSServiceInterface) *cobra.Command {
+	var opts rds.Options
+
+	cmd := &cobra.Command{
+		Use:          "connect",
+		Short:        "Print the endpoint and an IAM auth token for an RDS database",
+		Example:      `  awsctl rds connect --db orders --user app`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := promptutils.RequireFlags(cmd, "db", "user"); err != nil {
+				return err
+			}
+			return promptutils.IgnoreInterrupt(service.Connect(opts))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.DB, "db", "", "RDS instance or cluster identifier")
+	cmd.Flags().StringVar(&opts.User, "user", "", "Database user")
+
+	return cmd
+}
@@ -0,0 +1,47 @@
+package rds_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/rds"
+	internalrds "github.com/BerryBytes/awsctl/internal/rds"
+	mock_rds "github.com/BerryBytes/awsctl/tests/mock/rds"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectCmd(t *testing.T) {
+	t.Run("db and user", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(false))
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+		mockService.EXPECT().Connect(internalrds.Options{DB: "orders", User: "app"}).Return(nil)
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		cmd.SetArgs([]string{"connect", "--db", "orders", "--user", "app"})
+		assert.NoError(t, cmd.Execute())
+	})
+
+	t.Run("missing user without a terminal", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(false))
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"connect", "--db", "orders"})
+		assert.EqualError(t, cmd.Execute(), "--user is required when stdin is not a terminal")
+	})
+
+	t.Run("missing values are prompted for on a terminal", func(t *testing.T) {
+		t.Cleanup(promptutils.SetInteractive(true))
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+		mockService.EXPECT().Connect(internalrds.Options{}).Return(promptutils.ErrInterrupted)
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		cmd.SetArgs([]string{"connect"})
+		assert.NoError(t, cmd.Execute())
+	})
+}
@@ -0,0 +1,36 @@
+package rds
+
+import (
+	"github.com/BerryBytes/awsctl/internal/rds"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/spf13/cobra"
+)
+
+var instanceColumns = []output.Column[models.RDSInstance]{
+	{Header: "IDENTIFIER", Value: func(i models.RDSInstance) string { return i.DBInstanceIdentifier }},
+	{Header: "ENGINE", Value: func(i models.RDSInstance) string { return i.Engine }},
+	{Header: "ENDPOINT", Value: func(i models.RDSInstance) string { return i.Endpoint }},
+}
+
+func listCmd(service rds.RDSServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List RDS instances and clusters",
+		Long: `List the RDS instances and Aurora clusters of the region given with --region,
+or of the configured region. Use -o json, yaml, text or template=<template>
+for output that scripts can read.`,
+		Example: `  awsctl rds list --profile prod-admin
+  awsctl rds list -o json | jq -r '.[] | select(.engine == "postgres") | .endpoint'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instances, err := service.ListInstances()
+			if err != nil {
+				return promptutils.IgnoreInterrupt(err)
+			}
+			return output.Print(cmd.OutOrStdout(), output.Format(cmd), instances, instanceColumns)
+		},
+	}
+}
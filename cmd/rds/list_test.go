@@ -0,0 +1,57 @@
+package rds_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/rds"
+	"github.com/BerryBytes/awsctl/models"
+	mock_rds "github.com/BerryBytes/awsctl/tests/mock/rds"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCmd(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+		mockService.EXPECT().ListInstances().Return([]models.RDSInstance{
+			{DBInstanceIdentifier: "orders", Engine: "postgres", Endpoint: "orders.abc.eu-west-1.rds.amazonaws.com:5432"},
+		}, nil)
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		cmd.PersistentFlags().StringP("output", "o", "", "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"list", "-o", "json"})
+
+		assert.NoError(t, cmd.Execute())
+		assert.JSONEq(t, `[{"dbInstanceIdentifier":"orders","engine":"postgres","endpoint":"orders.abc.eu-west-1.rds.amazonaws.com:5432"}]`, out.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+		mockService.EXPECT().ListInstances().Return(nil, errors.New("failed to list RDS instances: denied"))
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		cmd.SilenceErrors = true
+		cmd.SetArgs([]string{"list"})
+		assert.EqualError(t, cmd.Execute(), "failed to list RDS instances: denied")
+	})
+
+	t.Run("interrupted prompt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+		mockService.EXPECT().ListInstances().Return(nil, promptutils.ErrInterrupted)
+
+		cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"list"})
+		assert.NoError(t, cmd.Execute())
+		assert.Empty(t, out.String())
+	})
+}
@@ -1,8 +1,6 @@
 package rds
 
 import (
-	"errors"
-
 	"github.com/BerryBytes/awsctl/internal/rds"
 	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/spf13/cobra"
@@ -17,16 +15,24 @@ func NewRDSCmd(deps RDSDependencies) *cobra.Command {
 		Use:   "rds",
 		Short: "Interactive RDS connection manager",
 		Long: `Interactive menu for managing RDS database connections.
-Choose between direct connection, SSH tunnel, or SOCKS proxy.`,
+Choose between direct connection, SSH tunnel, or SOCKS proxy.
+
+The connect and tunnel subcommands take the same choices as flags, so they can
+be scripted. Values that are not given are prompted for, or are an error when
+stdin is not a terminal. The list subcommand prints the RDS instances and
+clusters found in AWS.`,
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := deps.Service.Run()
-			if errors.Is(err, promptutils.ErrInterrupted) {
-				return nil
-			}
-			return err
+			return promptutils.IgnoreInterrupt(deps.Service.Run())
 		},
 	}
 
+	cmd.AddCommand(
+		connectCmd(deps.Service),
+		tunnelCmd(deps.Service),
+		listCmd(deps.Service),
+	)
+
 	return cmd
 }
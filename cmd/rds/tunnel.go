@@ -0,0 +1,70 @@
+package rds
+
+import (
+	"fmt"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/rds"
+	"github.com/BerryBytes/awsctl/utils/common"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/spf13/cobra"
+)
+
+func tunnelCmd(service rds.RDSServiceInterface) *cobra.Command {
+	var opts rds.Options
+	var auth, method string
+
+	cmd := &cobra.Command{
+		Use:   "tunnel",
+		Short: "Forward a local port to an RDS database through a bastion host",
+		Long: `Forward a local port to an RDS database through a bastion host until Ctrl+C
+is pressed.
+
+With --auth token, a MySQL options file with an IAM auth token is written for
+the session. The RDS CA bundle is downloaded unless --ca-cert is given.`,
+		Example: `  awsctl rds tunnel --db orders --user app --auth token --bastion i-0123456789abcdef0 --method ssm
+  awsctl rds tunnel --db orders --user app --auth password --local 13306 --bastion bastion.example.com --method ssh`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := promptutils.RequireFlags(cmd, "db", "user", "auth", "bastion", "method"); err != nil {
+				return err
+			}
+			if err := common.ValidatePort(opts.LocalPort); err != nil {
+				return fmt.Errorf("invalid --local: %w", err)
+			}
+
+			switch auth {
+			case "":
+			case "token":
+				opts.Auth = rds.AuthToken
+			case "password":
+				opts.Auth = rds.AuthPassword
+			default:
+				return fmt.Errorf("invalid --auth %q: must be token or password", auth)
+			}
+			if method != "" {
+				m, err := connection.ParseMethod(method)
+				if err != nil {
+					return err
+				}
+				opts.Bastion.Method = m
+			}
+			opts.DownloadCACert = opts.CACert == "" && !promptutils.IsInteractive()
+
+			return promptutils.IgnoreInterrupt(service.Tunnel(opts))
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.DB, "db", "", "RDS instance or cluster identifier")
+	cmd.Flags().StringVar(&opts.User, "user", "", "Database user")
+	cmd.Flags().StringVar(&auth, "auth", "", "Authentication method: token or password")
+	cmd.Flags().IntVar(&opts.LocalPort, "local", 3306, "Local port to listen on")
+	cmd.Flags().StringVar(&opts.CACert, "ca-cert", "", "RDS CA bundle for token auth")
+	cmd.Flags().StringVar(&opts.Bastion.Host, "bastion", "", "Bastion instance ID, or host name or IP")
+	cmd.Flags().StringVar(&method, "method", "", "Bastion connection method: ssh or ssm")
+	cmd.Flags().StringVar(&opts.Bastion.User, "ssh-user", "ec2-user", "SSH user on the bastion host")
+	cmd.Flags().StringVar(&opts.Bastion.KeyPath, "ssh-key", "~/.ssh/id_ed25519", "SSH private key for the bastion host")
+
+	return cmd
+}
@@ -0,0 +1,97 @@
+package rds_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/cmd/rds"
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	internalrds "github.com/BerryBytes/awsctl/internal/rds"
+	mock_rds "github.com/BerryBytes/awsctl/tests/mock/rds"
+	promptutils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTunnelCmd(t *testing.T) {
+	required := []string{"tunnel", "--db", "orders", "--user", "app", "--bastion", "i-0123456789abcdef0", "--method", "ssm"}
+	bastion := connection.ConnectOptions{
+		Method:  connection.MethodSSM,
+		Host:    "i-0123456789abcdef0",
+		User:    "ec2-user",
+		KeyPath: "~/.ssh/id_ed25519",
+	}
+
+	tests := []struct {
+		name        string
+		interactive bool
+		args        []string
+		want        *internalrds.Options
+		serviceErr  error
+		wantErr     string
+	}{
+		{
+			name: "token auth downloads the CA bundle without a terminal",
+			args: append(required, "--auth", "token"),
+			want: &internalrds.Options{
+				DB: "orders", User: "app", Auth: internalrds.AuthToken, LocalPort: 3306,
+				DownloadCACert: true, Bastion: bastion,
+			},
+		},
+		{
+			name: "password auth with local port and CA bundle",
+			args: append(required, "--auth", "password", "--local", "13306", "--ca-cert", "/tmp/ca.pem"),
+			want: &internalrds.Options{
+				DB: "orders", User: "app", Auth: internalrds.AuthPassword, LocalPort: 13306,
+				CACert: "/tmp/ca.pem", Bastion: bastion,
+			},
+		},
+		{
+			name:        "missing values are prompted for on a terminal",
+			interactive: true,
+			args:        []string{"tunnel"},
+			want: &internalrds.Options{
+				LocalPort: 3306,
+				Bastion:   connection.ConnectOptions{User: "ec2-user", KeyPath: "~/.ssh/id_ed25519"},
+			},
+			serviceErr: errors.New("tunnel connection failed"),
+			wantErr:    "tunnel connection failed",
+		},
+		{
+			name:    "missing auth without a terminal",
+			args:    required,
+			wantErr: "--auth is required when stdin is not a terminal",
+		},
+		{
+			name:    "invalid auth",
+			args:    append(required, "--auth", "kerberos"),
+			wantErr: `invalid --auth "kerberos": must be token or password`,
+		},
+		{
+			name:    "invalid local port",
+			args:    append(required, "--auth", "token", "--local", "0"),
+			wantErr: "invalid --local: invalid port number: 0 (must be between 1 and 65535)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(promptutils.SetInteractive(tt.interactive))
+			ctrl := gomock.NewController(t)
+			mockService := mock_rds.NewMockRDSServiceInterface(ctrl)
+			if tt.want != nil {
+				mockService.EXPECT().Tunnel(*tt.want).Return(tt.serviceErr)
+			}
+
+			cmd := rds.NewRDSCmd(rds.RDSDependencies{Service: mockService})
+			cmd.SilenceErrors = true
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,20 +1,31 @@
 package root
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/BerryBytes/awsctl/internal/ecr"
 	"github.com/BerryBytes/awsctl/internal/eks"
 	"github.com/BerryBytes/awsctl/internal/rds"
 	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/internal/tunnel"
 	"github.com/BerryBytes/awsctl/utils/common"
 	generalUtils "github.com/BerryBytes/awsctl/utils/general"
+	outputUtils "github.com/BerryBytes/awsctl/utils/output"
 
 	bastionCmd "github.com/BerryBytes/awsctl/cmd/bastion"
+	consoleCmd "github.com/BerryBytes/awsctl/cmd/console"
 	ecrCmd "github.com/BerryBytes/awsctl/cmd/ecr"
 	eksCmd "github.com/BerryBytes/awsctl/cmd/eks"
+	execCmd "github.com/BerryBytes/awsctl/cmd/exec"
+	iamCmd "github.com/BerryBytes/awsctl/cmd/iam"
+	promptInfoCmd "github.com/BerryBytes/awsctl/cmd/promptinfo"
 	rdsCmd "github.com/BerryBytes/awsctl/cmd/rds"
+	tunnelCmd "github.com/BerryBytes/awsctl/cmd/tunnel"
 
 	cmdSSO "github.com/BerryBytes/awsctl/cmd/sso"
 	"github.com/BerryBytes/awsctl/internal/bastion"
+	connection "github.com/BerryBytes/awsctl/internal/common"
 
 	"github.com/spf13/cobra"
 )
@@ -27,24 +38,59 @@ type RootDependencies struct {
 	RDSService     rds.RDSServiceInterface
 	EKSService     eks.EKSServiceInterface
 	ECRService     ecr.ECRServiceInterface
+	TunnelService  tunnel.TunnelServiceInterface
 	Version        string
+	// ConfigureAWS applies the global --profile and --region flags to the
+	// services before a command runs. It is only called when one is given.
+	ConfigureAWS func(opts connection.AWSOptions) error
 }
 
 func NewRootCmd(deps RootDependencies) *cobra.Command {
+	var opts connection.AWSOptions
+	var output string
+
 	rootCmd := &cobra.Command{
 		Use:   "awsctl",
 		Short: "AWS CLI Tool",
-		Long:  `A CLI tool for managing AWS services and configurations.`,
+		Long: `A CLI tool for managing AWS services and configurations.
+
+The global --profile and --region flags select the AWS profile and region for
+bastion, rds, eks, ecr and tunnel, which then skip the matching prompts.
+Without them AWS_PROFILE, AWS_REGION and the shared config are used as before.
+
+The global --output flag selects how list commands print their results: an
+aligned table (the default), json, yaml, tab-separated text, or a Go template
+given as template=<template> that is run for each item.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputUtils.Validate(output); err != nil {
+				return err
+			}
+			if opts.Region != "" && !generalUtils.IsValidRegionFormat(opts.Region) {
+				return fmt.Errorf("invalid --region %q", opts.Region)
+			}
+			if (opts.Profile == "" && opts.Region == "") || deps.ConfigureAWS == nil {
+				return nil
+			}
+			if err := deps.ConfigureAWS(opts); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		},
 		Version: deps.Version,
 	}
 	rootCmd.SetVersionTemplate(`{{printf "%s version %s\n" .Name .Version}}`)
 
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&opts.Profile, "profile", "", "AWS profile to use (defaults to AWS_PROFILE)")
+	flags.StringVar(&opts.Region, "region", "", "AWS region to use (defaults to AWS_REGION or the profile's region)")
+	flags.StringVarP(&output, "output", "o", "", "Output format of commands that print data: "+strings.Join(outputUtils.Formats, ", "))
+
 	rootCmd.AddCommand(cmdSSO.NewSSOCommands(cmdSSO.SSODependencies{
-		SetupClient:    deps.SSOSetupClient,
-		GeneralManager: deps.GeneralManager,
+		SetupClient: deps.SSOSetupClient,
 	}))
 
 	rootCmd.AddCommand(bastionCmd.NewBastionCmd(bastionCmd.BastionDependencies{
@@ -63,5 +109,25 @@ func NewRootCmd(deps RootDependencies) *cobra.Command {
 		Service: deps.ECRService,
 	}))
 
+	rootCmd.AddCommand(tunnelCmd.NewTunnelCmd(tunnelCmd.TunnelDependencies{
+		Service: deps.TunnelService,
+	}))
+
+	rootCmd.AddCommand(execCmd.NewExecCmd(execCmd.ExecDependencies{
+		SSOClient: deps.SSOSetupClient,
+	}))
+
+	rootCmd.AddCommand(iamCmd.NewIAMCmd(iamCmd.IAMDependencies{
+		SSOClient: deps.SSOSetupClient,
+	}))
+
+	rootCmd.AddCommand(consoleCmd.NewConsoleCmd(consoleCmd.ConsoleDependencies{
+		SSOClient: deps.SSOSetupClient,
+	}))
+
+	rootCmd.AddCommand(promptInfoCmd.NewPromptInfoCmd(promptInfoCmd.PromptInfoDependencies{
+		SSOClient: deps.SSOSetupClient,
+	}))
+
 	return rootCmd
 }
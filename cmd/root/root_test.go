@@ -1,8 +1,13 @@
 package root
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"testing"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_ecr "github.com/BerryBytes/awsctl/tests/mock/ecr"
 	mock_eks "github.com/BerryBytes/awsctl/tests/mock/eks"
@@ -39,12 +44,17 @@ func TestNewRootCmd(t *testing.T) {
 				assert.Equal(t, "AWS CLI Tool", cmd.Short)
 				assert.NotEmpty(t, cmd.Long)
 
-				assert.Len(t, cmd.Commands(), 5)
+				assert.Len(t, cmd.Commands(), 10)
 				assert.IsType(t, &cobra.Command{}, cmd.Commands()[0])
 				assert.IsType(t, &cobra.Command{}, cmd.Commands()[1])
 				assert.IsType(t, &cobra.Command{}, cmd.Commands()[2])
 				assert.IsType(t, &cobra.Command{}, cmd.Commands()[3])
 				assert.IsType(t, &cobra.Command{}, cmd.Commands()[4])
+				assert.IsType(t, &cobra.Command{}, cmd.Commands()[5])
+				assert.IsType(t, &cobra.Command{}, cmd.Commands()[6])
+				assert.IsType(t, &cobra.Command{}, cmd.Commands()[7])
+				assert.IsType(t, &cobra.Command{}, cmd.Commands()[8])
+				assert.IsType(t, &cobra.Command{}, cmd.Commands()[9])
 			},
 		},
 		{
@@ -61,7 +71,7 @@ func TestNewRootCmd(t *testing.T) {
 			},
 			validateFunc: func(t *testing.T, cmd *cobra.Command) {
 				assert.NotNil(t, cmd)
-				assert.Len(t, cmd.Commands(), 5)
+				assert.Len(t, cmd.Commands(), 10)
 			},
 		},
 	}
@@ -184,3 +194,137 @@ func TestSubcommandInitialization(t *testing.T) {
 		assert.Equal(t, "eks", eksCmd.Name())
 	})
 }
+
+func TestRootCmdGlobalFlags(t *testing.T) {
+	t.Run("flags are persistent", func(t *testing.T) {
+		cmd := NewRootCmd(RootDependencies{})
+		for _, name := range []string{"profile", "region", "output"} {
+			assert.NotNil(t, cmd.PersistentFlags().Lookup(name), name)
+		}
+		assert.Equal(t, "o", cmd.PersistentFlags().Lookup("output").Shorthand)
+	})
+
+	t.Run("profile and region configure AWS", func(t *testing.T) {
+		var got []connection.AWSOptions
+		cmd := NewRootCmd(RootDependencies{
+			ConfigureAWS: func(opts connection.AWSOptions) error {
+				got = append(got, opts)
+				return nil
+			},
+		})
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"--profile", "dev-admin", "--region", "eu-west-1"})
+
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, []connection.AWSOptions{{Profile: "dev-admin", Region: "eu-west-1"}}, got)
+	})
+
+	t.Run("AWS is not reconfigured without flags", func(t *testing.T) {
+		called := false
+		cmd := NewRootCmd(RootDependencies{
+			ConfigureAWS: func(opts connection.AWSOptions) error {
+				called = true
+				return nil
+			},
+		})
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"--output", "json"})
+
+		assert.NoError(t, cmd.Execute())
+		assert.False(t, called)
+	})
+
+	t.Run("configure error is returned", func(t *testing.T) {
+		cmd := NewRootCmd(RootDependencies{
+			ConfigureAWS: func(opts connection.AWSOptions) error {
+				return errors.New("failed to load AWS config: profile not found")
+			},
+		})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"--profile", "missing"})
+
+		err := cmd.Execute()
+		assert.EqualError(t, err, "failed to load AWS config: profile not found")
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"invalid region", []string{"--region", "europe"}, `invalid --region "europe"`},
+		{"invalid output", []string{"-o", "xml"}, `invalid --output "xml": must be one of table, json, yaml, text, template=<template>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRootCmd(RootDependencies{
+				ConfigureAWS: func(opts connection.AWSOptions) error {
+					t.Fatal("ConfigureAWS called")
+					return nil
+				},
+			})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tt.args)
+
+			assert.EqualError(t, cmd.Execute(), tt.wantErr)
+		})
+	}
+}
+
+func TestRootCmd_SSOCredentialsWithoutAWSCLI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No CheckAWSCLI call is expected: credential_process must work on hosts
+	// without the AWS CLI.
+	mockGeneral := mock_awsctl.NewMockGeneralUtilsInterface(ctrl)
+	mockSSO := mock_sso.NewMockSSOClient(ctrl)
+	mockSSO.EXPECT().ProfileCredentials("dev-admin").Return(&models.AWSCredentials{
+		AccessKeyID:     "AKIAEXAMPLE",
+		SecretAccessKey: "secret",
+	}, nil)
+
+	cmd := NewRootCmd(RootDependencies{
+		SSOSetupClient: mockSSO,
+		GeneralManager: mockGeneral,
+	})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"sso", "credentials", "--profile", "dev-admin"})
+
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), `"AccessKeyId":"AKIAEXAMPLE"`)
+}
+
+func TestRootCmd_SSOGlobalFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"profile rejected", []string{"--profile", "dev", "sso", "init"}, "awsctl sso init does not take the global --profile flag"},
+		{"region rejected", []string{"sso", "status", "--region", "eu-west-1"}, "awsctl sso status does not take the global --region flag"},
+		{"output validated", []string{"sso", "status", "-o", "xml"}, `invalid --output "xml": must be one of table, json, yaml, text, template=<template>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cmd := NewRootCmd(RootDependencies{
+				SSOSetupClient: mock_sso.NewMockSSOClient(ctrl),
+				ConfigureAWS: func(opts connection.AWSOptions) error {
+					t.Fatal("ConfigureAWS called")
+					return nil
+				},
+			})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tt.args)
+
+			assert.EqualError(t, cmd.Execute(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+
+	"github.com/spf13/cobra"
+)
+
+func AssumeCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var opts sso.AssumeRoleOptions
+	var shell string
+	var saveAs string
+
+	cmd := &cobra.Command{
+		Use:   "assume <role-arn>",
+		Short: "Assume a role with the credentials of an SSO profile",
+		Long: `Assume an IAM role with the credentials of a source profile and print the
+statements that export the role's temporary credentials in the given shell.
+
+The source profile can be an SSO profile or another assume-role profile, so
+roles that are only reachable from an SSO "hub" role can be chained. Role
+credentials are cached until shortly before they expire.
+
+Pass --save-as to also write the role as a profile with role_arn and
+source_profile to ~/.aws/config; awsctl exec, sso export and sso credentials
+resolve such profiles the same way.`,
+		Example: `  eval "$(awsctl sso assume arn:aws:iam::123456789012:role/deploy --source-profile hub-admin)"
+  awsctl sso assume arn:aws:iam::123456789012:role/deploy --source-profile hub-admin --save-as prod-deploy`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.RoleARN = args[0]
+			if !sso.IsValidRoleARN(opts.RoleARN) {
+				return fmt.Errorf("invalid role ARN %q", opts.RoleARN)
+			}
+			if opts.SourceProfile == "" {
+				opts.SourceProfile = os.Getenv("AWS_PROFILE")
+			}
+			if opts.SourceProfile == "" {
+				return errors.New("no source profile given: pass --source-profile or set AWS_PROFILE")
+			}
+			if err := validateDuration(opts.DurationSeconds); err != nil {
+				return err
+			}
+			if shell == "" {
+				shell = detectShell()
+			}
+			if !slices.Contains(sso.Shells, shell) {
+				return fmt.Errorf("invalid shell %q: must be one of %s", shell, strings.Join(sso.Shells, ", "))
+			}
+
+			if saveAs != "" {
+				if err := ssoClient.ConfigureAssumeRoleProfile(saveAs, opts); err != nil {
+					return fmt.Errorf("failed to save profile %s: %w", saveAs, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Saved profile %s\n", saveAs)
+			}
+
+			creds, err := ssoClient.AssumeRole(opts)
+			if err != nil {
+				return fmt.Errorf("failed to assume role: %w", err)
+			}
+			region, _ := ssoClient.GetAWSRegion(opts.SourceProfile)
+
+			statements, err := sso.FormatEnv(shell, sso.CredentialEnv(creds, region))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), statements)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.SourceProfile, "source-profile", "", "Profile whose credentials assume the role (defaults to AWS_PROFILE)")
+	addAssumeRoleFlags(cmd, &opts)
+	cmd.Flags().StringVar(&shell, "shell", "", "Output format: "+strings.Join(sso.Shells, ", "))
+	cmd.Flags().StringVar(&saveAs, "save-as", "", "Also save the role as a profile with this name")
+
+	return cmd
+}
+
+// addAssumeRoleFlags adds the optional AssumeRole parameters shared by sso
+// assume and sso setup --role-arn.
+func addAssumeRoleFlags(cmd *cobra.Command, opts *sso.AssumeRoleOptions) {
+	cmd.Flags().StringVar(&opts.ExternalID, "external-id", "", "External ID required by the role's trust policy")
+	cmd.Flags().Int32Var(&opts.DurationSeconds, "duration-seconds", 0, "Lifetime of the role credentials in seconds (900-43200)")
+	cmd.Flags().StringVar(&opts.RoleSessionName, "role-session-name", "", "Session name shown in CloudTrail (default awsctl-<timestamp>)")
+}
+
+func validateDuration(seconds int32) error {
+	if seconds != 0 && (seconds < 900 || seconds > 43200) {
+		return fmt.Errorf("invalid --duration-seconds %d: must be between 900 and 43200", seconds)
+	}
+	return nil
+}
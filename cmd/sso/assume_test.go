@@ -0,0 +1,129 @@
+package sso
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssumeCmd(t *testing.T) {
+	const roleARN = "arn:aws:iam::123456789012:role/deploy"
+	creds := &models.AWSCredentials{
+		AccessKeyID:     "ASIA",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      "2030-01-01T00:00:00Z",
+	}
+	dotenv := `AWS_ACCESS_KEY_ID=ASIA
+AWS_SECRET_ACCESS_KEY=secret
+AWS_SESSION_TOKEN=token
+AWS_CREDENTIAL_EXPIRATION=2030-01-01T00:00:00Z
+AWS_REGION=eu-west-1
+AWS_DEFAULT_REGION=eu-west-1
+`
+
+	tests := []struct {
+		name          string
+		args          []string
+		env           string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expected      string
+		expectedErr   string
+	}{
+		{
+			name: "assume",
+			args: []string{roleARN, "--source-profile", "hub", "--external-id", "ext", "--role-session-name", "ci", "--shell", "dotenv"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().AssumeRole(sso.AssumeRoleOptions{RoleARN: roleARN, SourceProfile: "hub", ExternalID: "ext", RoleSessionName: "ci"}).Return(creds, nil)
+				m.EXPECT().GetAWSRegion("hub").Return("eu-west-1", nil)
+			},
+			expected: dotenv,
+		},
+		{
+			name: "source profile from AWS_PROFILE",
+			args: []string{roleARN, "--shell", "dotenv"},
+			env:  "hub",
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().AssumeRole(sso.AssumeRoleOptions{RoleARN: roleARN, SourceProfile: "hub"}).Return(creds, nil)
+				m.EXPECT().GetAWSRegion("hub").Return("eu-west-1", nil)
+			},
+			expected: dotenv,
+		},
+		{
+			name: "save as profile",
+			args: []string{roleARN, "--source-profile", "hub", "--duration-seconds", "3600", "--save-as", "prod-deploy", "--shell", "dotenv"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				opts := sso.AssumeRoleOptions{RoleARN: roleARN, SourceProfile: "hub", DurationSeconds: 3600}
+				m.EXPECT().ConfigureAssumeRoleProfile("prod-deploy", opts).Return(nil)
+				m.EXPECT().AssumeRole(opts).Return(creds, nil)
+				m.EXPECT().GetAWSRegion("hub").Return("eu-west-1", nil)
+			},
+			expected:    dotenv,
+			expectedErr: "Saved profile prod-deploy",
+		},
+		{
+			name: "assume error",
+			args: []string{roleARN, "--source-profile", "hub"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().AssumeRole(gomock.Any()).Return(nil, errors.New("access denied"))
+			},
+			expectedError: "failed to assume role: access denied",
+		},
+		{
+			name:          "no source profile",
+			args:          []string{roleARN},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "no source profile given",
+		},
+		{
+			name:          "invalid role ARN",
+			args:          []string{"deploy", "--source-profile", "hub"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: `invalid role ARN "deploy"`,
+		},
+		{
+			name:          "invalid duration",
+			args:          []string{roleARN, "--source-profile", "hub", "--duration-seconds", "60"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "invalid --duration-seconds 60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", tt.env)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout, stderr bytes.Buffer
+			cmd := AssumeCmd(mockSSOClient)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+			assert.Contains(t, stderr.String(), tt.expectedErr)
+		})
+	}
+}
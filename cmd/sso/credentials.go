@@ -0,0 +1,61 @@
+package sso
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+
+	"github.com/spf13/cobra"
+)
+
+func CredentialsCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var profile string
+	var configure bool
+
+	cmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Print SSO role credentials for use as a credential_process",
+		Long: `Print the role credentials of an SSO profile in the credential_process
+JSON format. Credentials are cached until shortly before they expire.
+
+Pass --configure to add a credential_process line for the profile to
+~/.aws/config, so SDKs and tools without SSO support can use it.`,
+		Example: `  awsctl sso credentials --profile dev-admin
+  awsctl sso credentials --profile dev-admin --configure`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configure {
+				if err := ssoClient.ConfigureCredentialProcess(profile); err != nil {
+					return fmt.Errorf("failed to configure credential_process: %w", err)
+				}
+				cmd.Printf("Added credential_process = %s to profile %s\n", sso.CredentialProcessCommand(profile), profile)
+				return nil
+			}
+
+			creds, err := ssoClient.ProfileCredentials(profile)
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for profile %s: %w", profile, err)
+			}
+
+			data, err := json.Marshal(models.CredentialProcessOutput{
+				Version:         1,
+				AccessKeyID:     creds.AccessKeyID,
+				SecretAccessKey: creds.SecretAccessKey,
+				SessionToken:    creds.SessionToken,
+				Expiration:      creds.Expiration,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to encode credentials: %w", err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "SSO profile to get credentials for")
+	cmd.Flags().BoolVar(&configure, "configure", false, "Add a credential_process line for the profile to ~/.aws/config")
+	_ = cmd.MarkFlagRequired("profile")
+
+	return cmd
+}
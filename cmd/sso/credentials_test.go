@@ -0,0 +1,112 @@
+package sso
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsCmd(t *testing.T) {
+	creds := &models.AWSCredentials{
+		AccessKeyID:     "AKIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      "2030-01-01T00:00:00Z",
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		check         func(t *testing.T, stdout, stderr string)
+	}{
+		{
+			name: "prints credential_process JSON",
+			args: []string{"--profile", "dev-admin"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev-admin").Return(creds, nil)
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				var decoded map[string]any
+				require.NoError(t, json.Unmarshal([]byte(stdout), &decoded))
+				assert.Equal(t, map[string]any{
+					"Version":         float64(1),
+					"AccessKeyId":     "AKIAEXAMPLE",
+					"SecretAccessKey": "secret",
+					"SessionToken":    "token",
+					"Expiration":      "2030-01-01T00:00:00Z",
+				}, decoded)
+				assert.Empty(t, stderr)
+			},
+		},
+		{
+			name: "configures credential_process",
+			args: []string{"--profile", "dev-admin", "--configure"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ConfigureCredentialProcess("dev-admin").Return(nil)
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				assert.Contains(t, stdout, "credential_process = "+sso.CredentialProcessCommand("dev-admin"))
+			},
+		},
+		{
+			name:          "requires profile",
+			args:          []string{},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: `required flag(s) "profile" not set`,
+		},
+		{
+			name: "credentials error",
+			args: []string{"--profile", "dev-admin"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev-admin").Return(nil, errors.New("token expired"))
+			},
+			expectedError: "failed to get credentials for profile dev-admin: token expired",
+		},
+		{
+			name: "configure error",
+			args: []string{"--profile", "static", "--configure"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ConfigureCredentialProcess("static").Return(errors.New("profile static is not an SSO profile"))
+			},
+			expectedError: "failed to configure credential_process: profile static is not an SSO profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout, stderr bytes.Buffer
+			cmd := CredentialsCmd(mockSSOClient)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, stdout.String(), stderr.String())
+		})
+	}
+}
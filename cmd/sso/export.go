@@ -0,0 +1,72 @@
+package sso
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+
+	"github.com/spf13/cobra"
+)
+
+func ExportCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var profile string
+	var shell string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print shell statements that export SSO role credentials",
+		Long: `Print the statements that set the temporary role credentials, region and
+credential expiry (AWS_CREDENTIAL_EXPIRATION) of an SSO profile in the given
+shell. The dotenv format can be used as an env_file for docker compose.
+
+The shell is detected from $SHELL when --shell is not given.`,
+		Example: `  eval "$(awsctl sso export --profile dev-admin)"
+  awsctl sso export --profile dev-admin --shell fish | source
+  awsctl sso export --profile dev-admin --shell powershell | Invoke-Expression
+  awsctl sso export --profile dev-admin --shell dotenv > .env.aws`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if shell == "" {
+				shell = detectShell()
+			}
+			if !slices.Contains(sso.Shells, shell) {
+				return fmt.Errorf("invalid shell %q: must be one of %s", shell, strings.Join(sso.Shells, ", "))
+			}
+
+			creds, err := ssoClient.ProfileCredentials(profile)
+			if err != nil {
+				return fmt.Errorf("failed to get credentials for profile %s: %w", profile, err)
+			}
+			region, _ := ssoClient.GetAWSRegion(profile)
+
+			statements, err := sso.FormatEnv(shell, sso.CredentialEnv(creds, region))
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), statements)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&profile, "profile", "", "SSO profile to export credentials for")
+	cmd.Flags().StringVar(&shell, "shell", "", "Output format: "+strings.Join(sso.Shells, ", "))
+	_ = cmd.MarkFlagRequired("profile")
+
+	return cmd
+}
+
+// detectShell guesses the format from $SHELL, falling back to powershell on
+// Windows and bash elsewhere.
+func detectShell() string {
+	if name := filepath.Base(os.Getenv("SHELL")); slices.Contains([]string{"bash", "zsh", "fish"}, name) {
+		return name
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+	return "bash"
+}
@@ -0,0 +1,152 @@
+package sso
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCmd(t *testing.T) {
+	creds := &models.AWSCredentials{
+		AccessKeyID:     "AKIA",
+		SecretAccessKey: "se'cret",
+		SessionToken:    "token",
+		Expiration:      "2030-01-01T00:00:00Z",
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		shellEnv      string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expected      string
+	}{
+		{
+			name: "bash",
+			args: []string{"--profile", "dev", "--shell", "bash"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("eu-west-1", nil)
+			},
+			expected: `export AWS_ACCESS_KEY_ID='AKIA'
+export AWS_SECRET_ACCESS_KEY='se'\''cret'
+export AWS_SESSION_TOKEN='token'
+export AWS_CREDENTIAL_EXPIRATION='2030-01-01T00:00:00Z'
+export AWS_REGION='eu-west-1'
+export AWS_DEFAULT_REGION='eu-west-1'
+`,
+		},
+		{
+			name: "fish",
+			args: []string{"--profile", "dev", "--shell", "fish"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("", errors.New("no region"))
+			},
+			expected: `set -gx AWS_ACCESS_KEY_ID 'AKIA';
+set -gx AWS_SECRET_ACCESS_KEY 'se\'cret';
+set -gx AWS_SESSION_TOKEN 'token';
+set -gx AWS_CREDENTIAL_EXPIRATION '2030-01-01T00:00:00Z';
+`,
+		},
+		{
+			name: "powershell",
+			args: []string{"--profile", "dev", "--shell", "powershell"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("eu-west-1", nil)
+			},
+			expected: `$Env:AWS_ACCESS_KEY_ID = 'AKIA'
+$Env:AWS_SECRET_ACCESS_KEY = 'se''cret'
+$Env:AWS_SESSION_TOKEN = 'token'
+$Env:AWS_CREDENTIAL_EXPIRATION = '2030-01-01T00:00:00Z'
+$Env:AWS_REGION = 'eu-west-1'
+$Env:AWS_DEFAULT_REGION = 'eu-west-1'
+`,
+		},
+		{
+			name: "dotenv",
+			args: []string{"--profile", "dev", "--shell", "dotenv"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(creds, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("eu-west-1", nil)
+			},
+			expected: `AWS_ACCESS_KEY_ID=AKIA
+AWS_SECRET_ACCESS_KEY=se'cret
+AWS_SESSION_TOKEN=token
+AWS_CREDENTIAL_EXPIRATION=2030-01-01T00:00:00Z
+AWS_REGION=eu-west-1
+AWS_DEFAULT_REGION=eu-west-1
+`,
+		},
+		{
+			name:     "shell detected from SHELL",
+			args:     []string{"--profile", "dev"},
+			shellEnv: "/usr/bin/zsh",
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(&models.AWSCredentials{AccessKeyID: "AKIA"}, nil)
+				m.EXPECT().GetAWSRegion("dev").Return("", nil)
+			},
+			expected: `export AWS_ACCESS_KEY_ID='AKIA'
+export AWS_SECRET_ACCESS_KEY=''
+export AWS_SESSION_TOKEN=''
+`,
+		},
+		{
+			name:          "invalid shell",
+			args:          []string{"--profile", "dev", "--shell", "tcsh"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: `invalid shell "tcsh"`,
+		},
+		{
+			name:          "requires profile",
+			args:          []string{"--shell", "bash"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: `required flag(s) "profile" not set`,
+		},
+		{
+			name: "credentials error",
+			args: []string{"--profile", "dev", "--shell", "bash"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileCredentials("dev").Return(nil, errors.New("SSO login required"))
+			},
+			expectedError: "failed to get credentials for profile dev: SSO login required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			t.Setenv("SHELL", tt.shellEnv)
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout bytes.Buffer
+			cmd := ExportCmd(mockSSOClient)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
@@ -12,16 +12,23 @@ import (
 
 var refresh bool
 var noBrowser bool
+var setDefault bool
 
 func InitCmd(ssoClient sso.SSOClient) *cobra.Command {
 	initCmd := &cobra.Command{
 		Use:   "init",
 		Short: "Authenticate with AWS SSO",
+		Long: `Authenticate with AWS SSO using one of the configured profiles.
+
+The default profile is left unchanged, so other shells keep their profile.
+Activate the profile in the current shell with ` + "`awsctl sso use`" + `, or pass
+--set-default to write it to [default].`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			refresh, _ := cmd.Flags().GetBool("refresh")
 			noBrowser, _ := cmd.Flags().GetBool("no-browser")
+			setDefault, _ := cmd.Flags().GetBool("set-default")
 
-			err := ssoClient.InitSSO(refresh, noBrowser)
+			err := ssoClient.InitSSO(refresh, noBrowser, setDefault)
 			if err != nil {
 				if errors.Is(err, promptUtils.ErrInterrupted) {
 					return nil
@@ -36,5 +43,6 @@ func InitCmd(ssoClient sso.SSOClient) *cobra.Command {
 
 	initCmd.Flags().BoolVarP(&refresh, "refresh", "r", false, "Force SSO re-login")
 	initCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Disable the browser-based login flow")
+	initCmd.Flags().BoolVar(&setDefault, "set-default", false, "Also make the profile the default for every shell")
 	return initCmd
 }
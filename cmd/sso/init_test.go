@@ -32,7 +32,7 @@ func TestInitCmd(t *testing.T) {
 			refreshFlag:   false,
 			noBrowserFlag: false,
 			mockSetup: func() {
-				mockSSOClient.EXPECT().InitSSO(false, false).Return(nil)
+				mockSSOClient.EXPECT().InitSSO(false, false, false).Return(nil)
 			},
 			expectedError: "",
 		},
@@ -42,7 +42,7 @@ func TestInitCmd(t *testing.T) {
 			refreshFlag:   true,
 			noBrowserFlag: false,
 			mockSetup: func() {
-				mockSSOClient.EXPECT().InitSSO(true, false).Return(nil)
+				mockSSOClient.EXPECT().InitSSO(true, false, false).Return(nil)
 			},
 			expectedError: "",
 		},
@@ -52,7 +52,15 @@ func TestInitCmd(t *testing.T) {
 			refreshFlag:   false,
 			noBrowserFlag: true,
 			mockSetup: func() {
-				mockSSOClient.EXPECT().InitSSO(false, true).Return(nil)
+				mockSSOClient.EXPECT().InitSSO(false, true, false).Return(nil)
+			},
+			expectedError: "",
+		},
+		{
+			name: "successful initialization with set-default flag",
+			args: []string{"--set-default"},
+			mockSetup: func() {
+				mockSSOClient.EXPECT().InitSSO(false, false, true).Return(nil)
 			},
 			expectedError: "",
 		},
@@ -62,7 +70,7 @@ func TestInitCmd(t *testing.T) {
 			refreshFlag:   false,
 			noBrowserFlag: false,
 			mockSetup: func() {
-				mockSSOClient.EXPECT().InitSSO(false, false).Return(errors.New("initialization error"))
+				mockSSOClient.EXPECT().InitSSO(false, false, false).Return(errors.New("initialization error"))
 			},
 			expectedError: "SSO initialization failed: initialization error",
 		},
@@ -72,7 +80,7 @@ func TestInitCmd(t *testing.T) {
 			refreshFlag:   false,
 			noBrowserFlag: false,
 			mockSetup: func() {
-				mockSSOClient.EXPECT().InitSSO(false, false).Return(promptUtils.ErrInterrupted)
+				mockSSOClient.EXPECT().InitSSO(false, false, false).Return(promptUtils.ErrInterrupted)
 			},
 			expectedError: "",
 		},
@@ -110,7 +118,7 @@ func TestInitCmd_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
-	mockSSOClient.EXPECT().InitSSO(false, false).Return(nil)
+	mockSSOClient.EXPECT().InitSSO(false, false, false).Return(nil)
 
 	cmd := InitCmd(mockSSOClient)
 
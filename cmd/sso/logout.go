@@ -0,0 +1,39 @@
+package sso
+
+import (
+	"fmt"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+
+	"github.com/spf13/cobra"
+)
+
+func LogoutCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var opts sso.LogoutOptions
+
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Log out of AWS SSO sessions",
+		Long: `Revoke the cached SSO access token of a session and delete it from
+~/.aws/sso/cache, together with the cached role credentials of its profiles
+and of the assume-role profiles chained from them. --all also ends every MFA
+session started with awsctl iam session.`,
+		Example: `  awsctl sso logout --session my-sso
+  awsctl sso logout --profile dev-admin
+  awsctl sso logout --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ssoClient.Logout(opts); err != nil {
+				return fmt.Errorf("SSO logout failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Session, "session", "", "SSO session name to log out of")
+	cmd.Flags().StringVar(&opts.Profile, "profile", "", "Log out of the SSO session used by this profile")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Log out of all cached SSO sessions")
+	cmd.MarkFlagsMutuallyExclusive("session", "profile", "all")
+	cmd.MarkFlagsOneRequired("session", "profile", "all")
+
+	return cmd
+}
@@ -0,0 +1,86 @@
+package sso
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogoutCmd(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+	}{
+		{
+			name: "logout by session",
+			args: []string{"--session", "dev"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().Logout(sso.LogoutOptions{Session: "dev"}).Return(nil)
+			},
+		},
+		{
+			name: "logout by profile",
+			args: []string{"--profile", "dev-admin"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().Logout(sso.LogoutOptions{Profile: "dev-admin"}).Return(nil)
+			},
+		},
+		{
+			name: "logout all",
+			args: []string{"--all"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().Logout(sso.LogoutOptions{All: true}).Return(nil)
+			},
+		},
+		{
+			name:          "no selector",
+			args:          []string{},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "at least one of the flags in the group [session profile all] is required",
+		},
+		{
+			name:          "conflicting selectors",
+			args:          []string{"--session", "dev", "--all"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "if any flags in the group [session profile all] are set none of the others can be",
+		},
+		{
+			name: "logout error",
+			args: []string{"--session", "dev"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().Logout(sso.LogoutOptions{Session: "dev"}).Return(errors.New("boom"))
+			},
+			expectedError: "SSO logout failed: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			cmd := LogoutCmd(mockSSOClient)
+			cmd.SetArgs(tt.args)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package sso
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	generalutils "github.com/BerryBytes/awsctl/utils/general"
+	"github.com/BerryBytes/awsctl/utils/output"
+
+	"github.com/spf13/cobra"
+)
+
+func SessionsCmd(ssoClient sso.SSOClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage SSO sessions",
+		Long: `List, inspect, rename and remove the SSO sessions of the awsctl config file
+and ~/.aws/config.
+
+Renaming or removing a session also updates or removes the profiles that
+reference it.`,
+	}
+
+	cmd.AddCommand(sessionsListCmd(ssoClient))
+	cmd.AddCommand(sessionsShowCmd(ssoClient))
+	cmd.AddCommand(sessionsRenameCmd(ssoClient))
+	cmd.AddCommand(sessionsRemoveCmd(ssoClient))
+	cmd.AddCommand(sessionsImportCmd(ssoClient))
+	return cmd
+}
+
+var sessionColumns = []output.Column[models.SSOSessionInfo]{
+	{Header: "NAME", Value: func(s models.SSOSessionInfo) string { return s.Name }},
+	{Header: "START URL", Value: func(s models.SSOSessionInfo) string { return s.StartURL }},
+	{Header: "REGION", Value: func(s models.SSOSessionInfo) string { return s.Region }},
+	{Header: "PROFILES", Value: func(s models.SSOSessionInfo) string { return strconv.Itoa(len(s.Profiles)) }},
+	{Header: "SOURCE", Value: func(s models.SSOSessionInfo) string { return sessionSource(&s) }},
+}
+
+func sessionsListCmd(ssoClient sso.SSOClient) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List SSO sessions and the profiles using them",
+		Long: `List the SSO sessions of the awsctl config file and ~/.aws/config. Use
+-o json, yaml, text or template=<template> for output that scripts can read.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := ssoClient.SSOSessions()
+			if err != nil {
+				return fmt.Errorf("failed to list SSO sessions: %w", err)
+			}
+
+			format := output.Format(cmd)
+			if len(sessions) == 0 && (format == "" || format == "table") {
+				cmd.Println("No SSO sessions found.")
+				return nil
+			}
+			return output.Print(cmd.OutOrStdout(), format, sessions, sessionColumns)
+		},
+	}
+}
+
+func sessionsShowCmd(ssoClient sso.SSOClient) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show an SSO session",
+		Long: `Show the start URL, region, scopes and profiles of an SSO session. With
+-o, the session is printed like a one-row sessions list.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion(ssoClient),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			session, err := findSession(ssoClient, args[0])
+			if err != nil {
+				return err
+			}
+
+			if format := output.Format(cmd); format != "" {
+				return output.Print(cmd.OutOrStdout(), format, []models.SSOSessionInfo{*session}, sessionColumns)
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintf(w, "Name:\t%s\n", session.Name)
+			_, _ = fmt.Fprintf(w, "Start URL:\t%s\n", dash(session.StartURL))
+			_, _ = fmt.Fprintf(w, "Region:\t%s\n", dash(session.Region))
+			_, _ = fmt.Fprintf(w, "Scopes:\t%s\n", dash(session.Scopes))
+			_, _ = fmt.Fprintf(w, "Source:\t%s\n", sessionSource(session))
+			_, _ = fmt.Fprintf(w, "Profiles:\t%s\n", dash(strings.Join(session.Profiles, ", ")))
+			return w.Flush()
+		},
+	}
+}
+
+func sessionsRenameCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <old-name> <new-name>",
+		Short: "Rename an SSO session and update the profiles using it",
+		Long: `Rename an SSO session in the awsctl config file and ~/.aws/config.
+
+Profiles that reference the session are pointed at the new name and its
+cached SSO token is kept, so no new login is needed.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: sessionNameCompletion(ssoClient),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldName, newName := args[0], args[1]
+			if !generalutils.IsValidSessionName(newName) {
+				return fmt.Errorf("invalid session name: must only contain letters, numbers, dashes, or underscores, and cannot start or end with a dash/underscore")
+			}
+			if oldName == newName {
+				return fmt.Errorf("new session name must differ from the current one")
+			}
+			if err := ssoClient.RenameSSOSession(oldName, newName, yes); err != nil {
+				return fmt.Errorf("failed to rename SSO session: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
+
+func sessionsRemoveCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an SSO session and the profiles using it",
+		Long: `Remove an SSO session from the awsctl config file and ~/.aws/config,
+together with every profile that references it.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: sessionNameCompletion(ssoClient),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ssoClient.RemoveSSOSession(args[0], yes); err != nil {
+				return fmt.Errorf("failed to remove SSO session: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	return cmd
+}
+
+func sessionsImportCmd(ssoClient sso.SSOClient) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import",
+		Short: "Import [sso-session] blocks of ~/.aws/config into the awsctl config",
+		Long: `Copy the [sso-session] blocks of ~/.aws/config that the awsctl config file
+does not have yet into it, so they can be selected by awsctl sso setup.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			imported, err := ssoClient.ImportSSOSessions()
+			if err != nil {
+				return fmt.Errorf("failed to import SSO sessions: %w", err)
+			}
+			if len(imported) == 0 {
+				cmd.Println("No new SSO sessions to import.")
+				return nil
+			}
+			cmd.Printf("Imported %d SSO sessions: %s\n", len(imported), strings.Join(imported, ", "))
+			return nil
+		},
+	}
+}
+
+func findSession(ssoClient sso.SSOClient, name string) (*models.SSOSessionInfo, error) {
+	sessions, err := ssoClient.SSOSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSO sessions: %w", err)
+	}
+	for i := range sessions {
+		if sessions[i].Name == name {
+			return &sessions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("SSO session %s not found", name)
+}
+
+func sessionNameCompletion(ssoClient sso.SSOClient) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		sessions, _ := ssoClient.SSOSessions()
+		names := make([]string, 0, len(sessions))
+		for _, session := range sessions {
+			names = append(names, session.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// sessionSource names the files a session is defined in.
+func sessionSource(s *models.SSOSessionInfo) string {
+	switch {
+	case s.InConfig && s.InAWSConfig:
+		return "both"
+	case s.InConfig:
+		return "awsctl"
+	default:
+		return "aws"
+	}
+}
@@ -0,0 +1,175 @@
+package sso
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionsCmd(t *testing.T) {
+	sessions := []models.SSOSessionInfo{
+		{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1", InConfig: true, InAWSConfig: true, Profiles: []string{"corp-dev", "default"}},
+		{Name: "legacy", StartURL: "https://legacy.awsapps.com/start", Region: "eu-west-1", InAWSConfig: true, Profiles: []string{}},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expected      string
+	}{
+		{
+			name: "list",
+			args: []string{"list"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(sessions, nil)
+			},
+			expected: `NAME    START URL                         REGION     PROFILES  SOURCE
+corp    https://corp.awsapps.com/start    us-east-1  2         both
+legacy  https://legacy.awsapps.com/start  eu-west-1  0         aws
+`,
+		},
+		{
+			name: "list json",
+			args: []string{"list", "-o", "json"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(sessions[1:], nil)
+			},
+			expected: `[
+  {
+    "name": "legacy",
+    "startUrl": "https://legacy.awsapps.com/start",
+    "region": "eu-west-1",
+    "inConfig": false,
+    "inAwsConfig": true,
+    "profiles": []
+  }
+]
+`,
+		},
+		{
+			name: "list error",
+			args: []string{"list"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(nil, errors.New("boom"))
+			},
+			expectedError: "failed to list SSO sessions: boom",
+		},
+		{
+			name: "show",
+			args: []string{"show", "corp"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(sessions, nil)
+			},
+			expected: `Name:       corp
+Start URL:  https://corp.awsapps.com/start
+Region:     us-east-1
+Scopes:     -
+Source:     both
+Profiles:   corp-dev, default
+`,
+		},
+		{
+			name: "show text",
+			args: []string{"show", "legacy", "-o", "text"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(sessions, nil)
+			},
+			expected: "legacy\thttps://legacy.awsapps.com/start\teu-west-1\t0\taws\n",
+		},
+		{
+			name: "show unknown",
+			args: []string{"show", "missing"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().SSOSessions().Return(sessions, nil)
+			},
+			expectedError: "SSO session missing not found",
+		},
+		{
+			name: "rename",
+			args: []string{"rename", "corp", "acme", "--yes"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().RenameSSOSession("corp", "acme", true).Return(nil)
+			},
+		},
+		{
+			name:          "rename invalid name",
+			args:          []string{"rename", "corp", "acme_"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "invalid session name",
+		},
+		{
+			name: "rename error",
+			args: []string{"rename", "corp", "acme"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().RenameSSOSession("corp", "acme", false).Return(errors.New("boom"))
+			},
+			expectedError: "failed to rename SSO session: boom",
+		},
+		{
+			name: "remove",
+			args: []string{"remove", "corp", "-y"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().RemoveSSOSession("corp", true).Return(nil)
+			},
+		},
+		{
+			name:          "remove requires name",
+			args:          []string{"remove"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "accepts 1 arg(s), received 0",
+		},
+		{
+			name: "import",
+			args: []string{"import"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ImportSSOSessions().Return([]string{"legacy", "team"}, nil)
+			},
+			expected: "Imported 2 SSO sessions: legacy, team\n",
+		},
+		{
+			name: "import error",
+			args: []string{"import"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ImportSSOSessions().Return(nil, errors.New("boom"))
+			},
+			expectedError: "failed to import SSO sessions: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout bytes.Buffer
+			cmd := SessionsCmd(mockSSOClient)
+			cmd.PersistentFlags().StringP("output", "o", "", "")
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+		})
+	}
+}
@@ -3,6 +3,7 @@ package sso
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/BerryBytes/awsctl/internal/sso"
@@ -16,11 +17,55 @@ func SetupCmd(ssoClient sso.SSOClient) *cobra.Command {
 	var startURL string
 	var region string
 	var name string
+	var all bool
+	var includeAccount, excludeAccount string
+	var includeRole, excludeRole string
+	var dryRun bool
+	var setDefault bool
+	var assumeRole sso.AssumeRoleOptions
+	var profileName string
 
 	cmd := &cobra.Command{
 		Use:   "setup",
 		Short: "Setup AWS SSO configuration",
+		Long: `Setup AWS SSO configuration.
+
+With --all, a profile is written for every account and role visible to the
+SSO session. Re-running it updates changed profiles and reports profiles that
+no longer have access.
+
+The default profile is only changed with --set-default.
+
+With --role-arn, an assume-role profile is written instead: it has role_arn
+and source_profile settings and gets its credentials by assuming the role with
+the credentials of the source profile, e.g. an SSO "hub" role.`,
+		Example: `  awsctl sso setup --name corp --start-url https://corp.awsapps.com/start --region us-east-1
+  awsctl sso setup --all --include-account '^prod-'
+  awsctl sso setup --role-arn arn:aws:iam::123456789012:role/deploy --source-profile hub-admin --profile-name prod-deploy`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if assumeRole.RoleARN != "" {
+				if all || startURL != "" || region != "" || name != "" || setDefault {
+					return fmt.Errorf("--role-arn cannot be used with --all, --start-url, --region, --name or --set-default")
+				}
+				if !sso.IsValidRoleARN(assumeRole.RoleARN) {
+					return fmt.Errorf("invalid role ARN %q", assumeRole.RoleARN)
+				}
+				if err := validateDuration(assumeRole.DurationSeconds); err != nil {
+					return err
+				}
+				err := ssoClient.SetupAssumeRoleProfile(profileName, assumeRole)
+				if err != nil {
+					if errors.Is(err, promptUtils.ErrInterrupted) {
+						return nil
+					}
+					return fmt.Errorf("failed to set up assume-role profile: %w", err)
+				}
+				return nil
+			}
+			if assumeRole.SourceProfile != "" || assumeRole.ExternalID != "" || assumeRole.DurationSeconds != 0 || assumeRole.RoleSessionName != "" || profileName != "" {
+				return fmt.Errorf("--source-profile, --external-id, --duration-seconds, --role-session-name and --profile-name require --role-arn")
+			}
+
 			if startURL != "" && !strings.HasPrefix(startURL, "https://") {
 				return fmt.Errorf("invalid start URL: must begin with https://")
 			}
@@ -35,10 +80,36 @@ func SetupCmd(ssoClient sso.SSOClient) *cobra.Command {
 				return fmt.Errorf("invalid session name: must only contain letters, numbers, dashes, or underscores, and cannot start or end with a dash/underscore")
 			}
 
+			if all && setDefault {
+				return fmt.Errorf("--set-default cannot be used with --all")
+			}
+
+			if !all && (includeAccount != "" || excludeAccount != "" || includeRole != "" || excludeRole != "" || dryRun) {
+				return fmt.Errorf("--include-account, --exclude-account, --include-role, --exclude-role and --dry-run require --all")
+			}
+
+			for flag, pattern := range map[string]string{
+				"--include-account": includeAccount,
+				"--exclude-account": excludeAccount,
+				"--include-role":    includeRole,
+				"--exclude-role":    excludeRole,
+			} {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("invalid %s pattern: %w", flag, err)
+				}
+			}
+
 			opts := sso.SSOFlagOptions{
-				StartURL: startURL,
-				Region:   region,
-				Name:     name,
+				StartURL:       startURL,
+				Region:         region,
+				Name:           name,
+				All:            all,
+				IncludeAccount: includeAccount,
+				ExcludeAccount: excludeAccount,
+				IncludeRole:    includeRole,
+				ExcludeRole:    excludeRole,
+				DryRun:         dryRun,
+				SetDefault:     setDefault,
 			}
 
 			err := ssoClient.SetupSSO(opts)
@@ -55,6 +126,17 @@ func SetupCmd(ssoClient sso.SSOClient) *cobra.Command {
 	cmd.Flags().StringVar(&name, "name", "", "SSO session name")
 	cmd.Flags().StringVar(&startURL, "start-url", "", "AWS SSO Start URL")
 	cmd.Flags().StringVar(&region, "region", "", "AWS SSO Region")
+	cmd.Flags().BoolVar(&all, "all", false, "Create a profile for every account and role in the SSO session")
+	cmd.Flags().StringVar(&includeAccount, "include-account", "", "With --all, only include accounts whose name matches this regex")
+	cmd.Flags().StringVar(&excludeAccount, "exclude-account", "", "With --all, skip accounts whose name matches this regex")
+	cmd.Flags().StringVar(&includeRole, "include-role", "", "With --all, only include roles matching this regex")
+	cmd.Flags().StringVar(&excludeRole, "exclude-role", "", "With --all, skip roles matching this regex")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --all, preview the profiles without writing them")
+	cmd.Flags().BoolVar(&setDefault, "set-default", false, "Also make the new profile the default for every shell")
+	cmd.Flags().StringVar(&assumeRole.RoleARN, "role-arn", "", "Write an assume-role profile for this role instead of an SSO profile")
+	cmd.Flags().StringVar(&assumeRole.SourceProfile, "source-profile", "", "With --role-arn, the profile whose credentials assume the role")
+	cmd.Flags().StringVar(&profileName, "profile-name", "", "With --role-arn, the name of the new profile")
+	addAssumeRoleFlags(cmd, &assumeRole)
 
 	return cmd
 }
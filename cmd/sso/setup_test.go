@@ -81,6 +81,81 @@ func TestSetupCmd(t *testing.T) {
 			},
 			expectedError: "invalid session name: must only contain letters, numbers, dashes, or underscores, and cannot start or end with a dash/underscore",
 		},
+		{
+			name: "bulk setup with filters",
+			args: []string{"--all", "--include-account=^prod", "--exclude-role=Billing", "--dry-run"},
+			mockSetup: func() {
+				mockSSOClient.EXPECT().SetupSSO(sso.SSOFlagOptions{
+					All:            true,
+					IncludeAccount: "^prod",
+					ExcludeRole:    "Billing",
+					DryRun:         true,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "set default",
+			args: []string{"--set-default"},
+			mockSetup: func() {
+				mockSSOClient.EXPECT().SetupSSO(sso.SSOFlagOptions{SetDefault: true}).Return(nil)
+			},
+		},
+		{
+			name:          "set default with --all",
+			args:          []string{"--all", "--set-default"},
+			mockSetup:     func() {},
+			expectedError: "--set-default cannot be used with --all",
+		},
+		{
+			name:          "filters without --all",
+			args:          []string{"--include-role=Admin"},
+			mockSetup:     func() {},
+			expectedError: "require --all",
+		},
+		{
+			name:          "invalid filter pattern",
+			args:          []string{"--all", "--exclude-account=("},
+			mockSetup:     func() {},
+			expectedError: "invalid --exclude-account pattern",
+		},
+		{
+			name: "assume-role profile",
+			args: []string{"--role-arn=arn:aws:iam::123456789012:role/deploy", "--source-profile=hub", "--profile-name=prod-deploy", "--external-id=ext", "--duration-seconds=1800"},
+			mockSetup: func() {
+				mockSSOClient.EXPECT().SetupAssumeRoleProfile("prod-deploy", sso.AssumeRoleOptions{
+					RoleARN:         "arn:aws:iam::123456789012:role/deploy",
+					SourceProfile:   "hub",
+					ExternalID:      "ext",
+					DurationSeconds: 1800,
+				}).Return(nil)
+			},
+		},
+		{
+			name: "assume-role profile error",
+			args: []string{"--role-arn=arn:aws:iam::123456789012:role/deploy"},
+			mockSetup: func() {
+				mockSSOClient.EXPECT().SetupAssumeRoleProfile("", sso.AssumeRoleOptions{RoleARN: "arn:aws:iam::123456789012:role/deploy"}).Return(errors.New("boom"))
+			},
+			expectedError: "failed to set up assume-role profile: boom",
+		},
+		{
+			name:          "assume-role profile with --all",
+			args:          []string{"--role-arn=arn:aws:iam::123456789012:role/deploy", "--all"},
+			mockSetup:     func() {},
+			expectedError: "--role-arn cannot be used with --all",
+		},
+		{
+			name:          "invalid role ARN",
+			args:          []string{"--role-arn=deploy"},
+			mockSetup:     func() {},
+			expectedError: `invalid role ARN "deploy"`,
+		},
+		{
+			name:          "source profile without role ARN",
+			args:          []string{"--source-profile=hub"},
+			mockSetup:     func() {},
+			expectedError: "require --role-arn",
+		},
 		{
 			name: "partial flags - only name provided",
 			args: []string{"--name=valid-name"},
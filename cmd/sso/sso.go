@@ -1,34 +1,50 @@
 package sso
 
 import (
+	"fmt"
+
 	"github.com/BerryBytes/awsctl/internal/sso"
-	generalUtils "github.com/BerryBytes/awsctl/utils/general"
 
 	"github.com/spf13/cobra"
 )
 
 type SSODependencies struct {
-	SetupClient    sso.SSOClient
-	GeneralManager generalUtils.GeneralUtilsInterface
+	SetupClient sso.SSOClient
 }
 
 func NewSSOCommands(deps SSODependencies) *cobra.Command {
 	ssoCmd := &cobra.Command{
 		Use:   "sso",
 		Short: "Manage AWS SSO configurations",
-		Long:  "A set of commands to manage and configure AWS SSO profiles.",
-		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			cmd.SilenceUsage = true
-			if err := deps.GeneralManager.CheckAWSCLI(); err != nil {
-				cmd.Println("Please install AWS CLI first: https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html")
-				return err
+		Long: `A set of commands to manage and configure AWS SSO profiles.
+
+The global --profile and --region flags are not used by sso commands; commands
+that work on one profile take it as an argument or as their own flag.`,
+	}
+	// Cobra only runs the nearest PersistentPreRunE, so the root one is called
+	// from here.
+	ssoCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		for _, name := range []string{"profile", "region"} {
+			if f := cmd.InheritedFlags().Lookup(name); f != nil && f.Changed {
+				return fmt.Errorf("%s does not take the global --%s flag", cmd.CommandPath(), name)
 			}
-			return nil
-		},
+		}
+		if parent := ssoCmd.Parent(); parent != nil && parent.PersistentPreRunE != nil {
+			return parent.PersistentPreRunE(cmd, args)
+		}
+		return nil
 	}
 
 	ssoCmd.AddCommand(InitCmd(deps.SetupClient))
 	ssoCmd.AddCommand(SetupCmd(deps.SetupClient))
+	ssoCmd.AddCommand(StatusCmd(deps.SetupClient))
+	ssoCmd.AddCommand(LogoutCmd(deps.SetupClient))
+	ssoCmd.AddCommand(CredentialsCmd(deps.SetupClient))
+	ssoCmd.AddCommand(ExportCmd(deps.SetupClient))
+	ssoCmd.AddCommand(UseCmd(deps.SetupClient))
+	ssoCmd.AddCommand(SessionsCmd(deps.SetupClient))
+	ssoCmd.AddCommand(AssumeCmd(deps.SetupClient))
 
 	return ssoCmd
 }
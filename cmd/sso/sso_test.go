@@ -1,11 +1,9 @@
 package sso_test
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/BerryBytes/awsctl/cmd/sso"
-	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
 
 	"github.com/golang/mock/gomock"
@@ -22,50 +20,25 @@ func TestSSOCmd_PersistentPreRun_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGeneral := mock_awsctl.NewMockGeneralUtilsInterface(ctrl)
-	mockGeneral.EXPECT().CheckAWSCLI().Return(nil)
-
 	mockSSO := mock_sso.NewMockSSOClient(ctrl)
 
 	cmd := sso.NewSSOCommands(sso.SSODependencies{
-		SetupClient:    mockSSO,
-		GeneralManager: mockGeneral,
+		SetupClient: mockSSO,
 	})
 
 	err := executePersistentPreRunE(cmd)
 	assert.NoError(t, err)
-}
-
-func TestSSOCmd_PersistentPreRun_Error(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockGeneral := mock_awsctl.NewMockGeneralUtilsInterface(ctrl)
-	mockGeneral.EXPECT().CheckAWSCLI().Return(errors.New("missing aws cli"))
-
-	mockSSO := mock_sso.NewMockSSOClient(ctrl)
-
-	cmd := sso.NewSSOCommands(sso.SSODependencies{
-		SetupClient:    mockSSO,
-		GeneralManager: mockGeneral,
-	})
-
-	err := executePersistentPreRunE(cmd)
-	assert.EqualError(t, err, "missing aws cli")
+	assert.True(t, cmd.SilenceUsage)
 }
 
 func TestSSOCmd_HasSubcommands(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGeneral := mock_awsctl.NewMockGeneralUtilsInterface(ctrl)
-	mockGeneral.EXPECT().CheckAWSCLI().Return(nil).AnyTimes()
-
 	mockSSO := mock_sso.NewMockSSOClient(ctrl)
 
 	cmd := sso.NewSSOCommands(sso.SSODependencies{
-		SetupClient:    mockSSO,
-		GeneralManager: mockGeneral,
+		SetupClient: mockSSO,
 	})
 
 	subcommands := cmd.Commands()
@@ -76,4 +49,11 @@ func TestSSOCmd_HasSubcommands(t *testing.T) {
 
 	assert.Contains(t, names, "init")
 	assert.Contains(t, names, "setup")
+	assert.Contains(t, names, "status")
+	assert.Contains(t, names, "logout")
+	assert.Contains(t, names, "credentials")
+	assert.Contains(t, names, "export")
+	assert.Contains(t, names, "use")
+	assert.Contains(t, names, "sessions")
+	assert.Contains(t, names, "assume")
 }
@@ -0,0 +1,90 @@
+package sso
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
+
+	"github.com/spf13/cobra"
+)
+
+var statusColumns = []output.Column[models.ProfileStatus]{
+	{Header: "PROFILE", Value: func(s models.ProfileStatus) string { return s.Profile }},
+	{Header: "SESSION", Value: func(s models.ProfileStatus) string { return s.SSOSession }},
+	{Header: "ACCOUNT", Value: func(s models.ProfileStatus) string { return s.AccountID }},
+	{Header: "ROLE", Value: func(s models.ProfileStatus) string { return s.Role }},
+	{Header: "REGION", Value: func(s models.ProfileStatus) string { return s.Region }},
+	{Header: "EXPIRES IN", Value: expiresIn},
+	{Header: "STATUS", Value: func(s models.ProfileStatus) string { return s.Status }},
+}
+
+var identityColumn = output.Column[models.ProfileStatus]{
+	Header: "IDENTITY",
+	Value: func(s models.ProfileStatus) string {
+		if s.IdentityError != "" {
+			return "error: " + s.IdentityError
+		}
+		return s.Identity
+	},
+}
+
+func StatusCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var verify bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show SSO token status of all AWS profiles",
+		Long: `Show the SSO session, account, role, region and token expiry of every AWS profile.
+
+Only local configuration and the SSO token cache are read. Pass --verify to
+also check each profile's identity with STS. Use -o json, yaml, text or
+template=<template> for output that scripts can read.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := ssoClient.ProfileStatuses(verify)
+			if err != nil {
+				return fmt.Errorf("failed to get SSO status: %w", err)
+			}
+
+			format := output.Format(cmd)
+			if len(statuses) == 0 && (format == "" || format == "table") {
+				cmd.Println("No AWS profiles found.")
+				return nil
+			}
+			columns := statusColumns
+			if verify {
+				columns = append(slices.Clip(columns), identityColumn)
+			}
+			return output.Print(cmd.OutOrStdout(), format, statuses, columns)
+		},
+	}
+
+	cmd.Flags().BoolVar(&verify, "verify", false, "Verify each profile's identity with STS (requires network)")
+	return cmd
+}
+
+func expiresIn(s models.ProfileStatus) string {
+	if s.ExpiresAt == "" {
+		return ""
+	}
+	expiresAt, err := time.Parse(time.RFC3339, s.ExpiresAt)
+	if err != nil {
+		return ""
+	}
+	remaining := time.Until(expiresAt).Round(time.Minute)
+	if remaining <= 0 {
+		return "expired"
+	}
+	return strings.TrimSuffix(remaining.String(), "0s")
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
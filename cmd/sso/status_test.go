@@ -0,0 +1,144 @@
+package sso
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCmd(t *testing.T) {
+	statuses := []models.ProfileStatus{
+		{
+			Profile:    "dev-admin",
+			SSOSession: "dev",
+			AccountID:  "123456789012",
+			Role:       "Admin",
+			Region:     "us-east-1",
+			ExpiresAt:  time.Now().Add(2*time.Hour + 30*time.Minute).UTC().Format(time.RFC3339),
+			Status:     "valid",
+			Valid:      true,
+			Identity:   "arn:aws:sts::123456789012:assumed-role/Admin/user",
+		},
+		{
+			Profile: "static",
+			Status:  "not sso",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		check         func(t *testing.T, out string)
+	}{
+		{
+			name: "table output",
+			args: []string{},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "PROFILE")
+				assert.Contains(t, out, "dev-admin")
+				assert.Contains(t, out, "2h30m")
+				assert.Contains(t, out, "not sso")
+				assert.NotContains(t, out, "IDENTITY")
+			},
+		},
+		{
+			name: "table output with verify",
+			args: []string{"--verify"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(true).Return(statuses, nil)
+			},
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "IDENTITY")
+				assert.Contains(t, out, "assumed-role/Admin/user")
+			},
+		},
+		{
+			name: "json output",
+			args: []string{"-o", "json"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			check: func(t *testing.T, out string) {
+				var decoded []models.ProfileStatus
+				require.NoError(t, json.Unmarshal([]byte(out), &decoded))
+				assert.Equal(t, statuses, decoded)
+			},
+		},
+		{
+			name: "no profiles",
+			args: []string{},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(nil, nil)
+			},
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "No AWS profiles found.")
+			},
+		},
+		{
+			name: "text output",
+			args: []string{"-o", "text"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses[1:], nil)
+			},
+			check: func(t *testing.T, out string) {
+				assert.Equal(t, "static\t\t\t\t\t\tnot sso\n", out)
+			},
+		},
+		{
+			name: "invalid output format",
+			args: []string{"-o", "xml"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expectedError: "invalid --output",
+		},
+		{
+			name: "status error",
+			args: []string{},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(nil, errors.New("boom"))
+			},
+			expectedError: "failed to get SSO status: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			cmd := StatusCmd(mockSSOClient)
+			cmd.PersistentFlags().StringP("output", "o", "", "")
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, buf.String())
+		})
+	}
+}
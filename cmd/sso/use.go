@@ -0,0 +1,85 @@
+package sso
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+
+	"github.com/spf13/cobra"
+)
+
+func UseCmd(ssoClient sso.SSOClient) *cobra.Command {
+	var shell string
+	var setDefault bool
+
+	cmd := &cobra.Command{
+		Use:   "use <profile>",
+		Short: "Activate an AWS profile in the current shell",
+		Long: `Print the statements that set AWS_PROFILE in the current shell, so switching
+profiles in one terminal does not affect other terminals or running tools.
+Credential and region variables exported earlier are unset, as they would take
+precedence over the profile.
+
+The default profile is only changed with --set-default. The shell is detected
+from $SHELL when --shell is not given.`,
+		Example: `  eval "$(awsctl sso use dev-admin)"
+  awsctl sso use dev-admin --shell fish | source
+  awsctl sso use dev-admin --shell powershell | Invoke-Expression
+  eval "$(awsctl sso use dev-admin --set-default)"`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			profiles, _ := ssoClient.ValidProfiles()
+			return profiles, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := args[0]
+			if shell == "" {
+				shell = detectShell()
+			}
+			if !slices.Contains(sso.Shells, shell) {
+				return fmt.Errorf("invalid shell %q: must be one of %s", shell, strings.Join(sso.Shells, ", "))
+			}
+
+			statuses, err := ssoClient.ProfileStatuses(false)
+			if err != nil {
+				return fmt.Errorf("failed to list profiles: %w", err)
+			}
+			index := slices.IndexFunc(statuses, func(s models.ProfileStatus) bool { return s.Profile == profile })
+			if index < 0 && profile != "default" {
+				return fmt.Errorf("profile %s not found in ~/.aws/config", profile)
+			}
+
+			if setDefault && profile != "default" {
+				if err := ssoClient.SetDefaultProfile(profile); err != nil {
+					return fmt.Errorf("failed to set default profile: %w", err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Profile %s is now the default profile\n", profile)
+			}
+
+			if index >= 0 {
+				status := statuses[index]
+				if status.Status != sso.StatusValid && status.Status != sso.StatusNotSSO {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: SSO token for profile %s is %s; run `awsctl sso init` after activating it to log in\n", profile, status.Status)
+				}
+			}
+
+			statements, err := sso.FormatProfileEnv(shell, profile)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), statements)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&shell, "shell", "", "Output format: "+strings.Join(sso.Shells, ", "))
+	cmd.Flags().BoolVar(&setDefault, "set-default", false, "Also make the profile the default for every shell")
+
+	return cmd
+}
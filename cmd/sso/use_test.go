@@ -0,0 +1,155 @@
+package sso
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseCmd(t *testing.T) {
+	statuses := []models.ProfileStatus{
+		{Profile: "dev", Status: sso.StatusValid},
+		{Profile: "prod", Status: sso.StatusExpired},
+		{Profile: "static", Status: sso.StatusNotSSO},
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		mockSetup     func(m *mock_sso.MockSSOClient)
+		expectedError string
+		expected      string
+		expectedErr   string
+	}{
+		{
+			name: "bash",
+			args: []string{"dev", "--shell", "bash"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expected: `unset AWS_DEFAULT_PROFILE
+unset AWS_ACCESS_KEY_ID
+unset AWS_SECRET_ACCESS_KEY
+unset AWS_SESSION_TOKEN
+unset AWS_SECURITY_TOKEN
+unset AWS_CREDENTIAL_EXPIRATION
+unset AWS_REGION
+unset AWS_DEFAULT_REGION
+export AWS_PROFILE='dev'
+`,
+		},
+		{
+			name: "fish",
+			args: []string{"static", "--shell", "fish"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expected: `set -e AWS_DEFAULT_PROFILE;
+set -e AWS_ACCESS_KEY_ID;
+set -e AWS_SECRET_ACCESS_KEY;
+set -e AWS_SESSION_TOKEN;
+set -e AWS_SECURITY_TOKEN;
+set -e AWS_CREDENTIAL_EXPIRATION;
+set -e AWS_REGION;
+set -e AWS_DEFAULT_REGION;
+set -gx AWS_PROFILE 'static';
+`,
+		},
+		{
+			name: "dotenv",
+			args: []string{"dev", "--shell", "dotenv"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expected: "AWS_PROFILE=dev\n",
+		},
+		{
+			name: "expired token warns on stderr",
+			args: []string{"prod", "--shell", "dotenv"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expected:    "AWS_PROFILE=prod\n",
+			expectedErr: "Warning: SSO token for profile prod is expired",
+		},
+		{
+			name: "set default",
+			args: []string{"dev", "--shell", "dotenv", "--set-default"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+				m.EXPECT().SetDefaultProfile("dev").Return(nil)
+			},
+			expected:    "AWS_PROFILE=dev\n",
+			expectedErr: "Profile dev is now the default profile",
+		},
+		{
+			name: "set default error",
+			args: []string{"dev", "--set-default"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+				m.EXPECT().SetDefaultProfile("dev").Return(errors.New("boom"))
+			},
+			expectedError: "failed to set default profile: boom",
+		},
+		{
+			name: "unknown profile",
+			args: []string{"missing"},
+			mockSetup: func(m *mock_sso.MockSSOClient) {
+				m.EXPECT().ProfileStatuses(false).Return(statuses, nil)
+			},
+			expectedError: "profile missing not found in ~/.aws/config",
+		},
+		{
+			name:          "invalid shell",
+			args:          []string{"dev", "--shell", "tcsh"},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: `invalid shell "tcsh"`,
+		},
+		{
+			name:          "requires profile",
+			args:          []string{},
+			mockSetup:     func(m *mock_sso.MockSSOClient) {},
+			expectedError: "accepts 1 arg(s), received 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSSOClient := mock_sso.NewMockSSOClient(ctrl)
+			tt.mockSetup(mockSSOClient)
+
+			var stdout, stderr bytes.Buffer
+			cmd := UseCmd(mockSSOClient)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stdout.String())
+			if tt.expectedErr != "" {
+				assert.Contains(t, stderr.String(), tt.expectedErr)
+			} else {
+				assert.Empty(t, stderr.String())
+			}
+		})
+	}
+}
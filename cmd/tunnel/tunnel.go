@@ -0,0 +1,194 @@
+package tunnel
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/tunnel"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/output"
+	"github.com/spf13/cobra"
+)
+
+type TunnelDependencies struct {
+	Service tunnel.TunnelServiceInterface
+}
+
+func NewTunnelCmd(deps TunnelDependencies) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tunnel",
+		Short: "Bring up the tunnel sets defined in the awsctl config",
+		Long: `Bring up named sets of port forwards through a bastion host, as defined
+under tunnels in the awsctl config file.
+
+A tunnel set names its bastion (an instance ID, a host, or name:<Name tag>),
+the method (ssm, ssh, or eic for SSH through EC2 Instance Connect), and its
+forwards. A forward's remote is host:port, rds:<instance or cluster> or
+eks:<cluster>. The set's profile and region are used unless --profile or
+--region is given.
+
+A tunnel set is identified by its name. The list, stop and logs subcommands
+work on the sets that are up, whether they run in a terminal or were started
+in the background with --detach.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		upCmd(deps.Service),
+		listCmd(deps.Service),
+		stopCmd(deps.Service),
+		logsCmd(deps.Service),
+	)
+
+	return cmd
+}
+
+func upCmd(service tunnel.TunnelServiceInterface) *cobra.Command {
+	var detach bool
+
+	cmd := &cobra.Command{
+		Use:   "up <name>",
+		Short: "Bring up a tunnel set",
+		Long: `Bring up a tunnel set until Ctrl+C is pressed or it is stopped with
+awsctl tunnel stop.
+
+With --detach, the set runs in the background and the command returns once
+its local ports are listening. Its output goes to a log file that
+awsctl tunnel logs prints.`,
+		Example: `  awsctl tunnel up prod-db
+  awsctl tunnel up prod-db --detach`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := connection.AWSOptions{
+				Profile: flagValue(cmd, "profile"),
+				Region:  flagValue(cmd, "region"),
+			}
+			if detach {
+				return service.Detach(args[0], opts)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			err := service.Up(ctx, args[0], opts)
+			if ctx.Err() != nil {
+				// Stopped by Ctrl+C or tunnel stop.
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVarP(&detach, "detach", "d", false, "Run the tunnel set in the background")
+
+	return cmd
+}
+
+var tunnelColumns = []output.Column[models.TunnelProcess]{
+	{Header: "ID", Value: func(t models.TunnelProcess) string { return t.ID }},
+	{Header: "PID", Value: func(t models.TunnelProcess) string { return strconv.Itoa(t.PID) }},
+	{Header: "METHOD", Value: func(t models.TunnelProcess) string { return t.Method }},
+	{Header: "BASTION", Value: func(t models.TunnelProcess) string { return t.Bastion }},
+	{Header: "PORTS", Value: func(t models.TunnelProcess) string {
+		ports := make([]string, len(t.Forwards))
+		for i, f := range t.Forwards {
+			ports[i] = strconv.Itoa(f.Local) + "->" + f.Remote
+		}
+		return strings.Join(ports, ",")
+	}},
+	{Header: "PROFILE", Value: func(t models.TunnelProcess) string { return t.Profile }},
+	{Header: "STARTED", Value: func(t models.TunnelProcess) string { return t.StartedAt.Local().Format(time.DateTime) }},
+}
+
+func listCmd(service tunnel.TunnelServiceInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the tunnel sets that are up",
+		Long: `List the tunnel sets that are up. Use -o json, yaml, text or
+template=<template> for output that scripts can read.`,
+		Example: `  awsctl tunnel list
+  awsctl tunnel list -o json | jq -r '.[].id'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tunnels, err := service.List()
+			if err != nil {
+				return err
+			}
+			return output.Print(cmd.OutOrStdout(), output.Format(cmd), tunnels, tunnelColumns)
+		},
+	}
+}
+
+func stopCmd(service tunnel.TunnelServiceInterface) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:     "stop <id>",
+		Aliases: []string{"down"},
+		Short:   "Stop a tunnel set, or all of them with --all",
+		Long: `Stop a tunnel set that is up, in the background or in another terminal.
+Only the processes recorded when the set came up are stopped.`,
+		Example: `  awsctl tunnel stop prod-db
+  awsctl tunnel stop --all`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case all && len(args) > 0:
+				return errors.New("give a tunnel set or --all, not both")
+			case all:
+				return service.StopAll()
+			case len(args) == 0:
+				return errors.New("a tunnel set or --all is required")
+			}
+			return service.Stop(args[0])
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Stop every tunnel set that is up")
+
+	return cmd
+}
+
+func logsCmd(service tunnel.TunnelServiceInterface) *cobra.Command {
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:   "logs <id>",
+		Short: "Print the output of a tunnel set started with --detach",
+		Example: `  awsctl tunnel logs prod-db
+  awsctl tunnel logs prod-db -f`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return service.Logs(ctx, args[0], cmd.OutOrStdout(), follow)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new output until the tunnel set stops")
+
+	return cmd
+}
+
+// flagValue returns the value of an inherited global flag, or "" when the
+// command is run without the root command.
+func flagValue(cmd *cobra.Command, name string) string {
+	if f := cmd.Flag(name); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
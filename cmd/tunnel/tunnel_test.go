@@ -0,0 +1,145 @@
+package tunnel_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/cmd/tunnel"
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
+	mock_tunnel "github.com/BerryBytes/awsctl/tests/mock/tunnel"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCmd(t *testing.T) (*cobra.Command, *mock_tunnel.MockTunnelServiceInterface) {
+	ctrl := gomock.NewController(t)
+	service := mock_tunnel.NewMockTunnelServiceInterface(ctrl)
+
+	// Stand in for the root command's global flags.
+	root := &cobra.Command{Use: "awsctl"}
+	root.PersistentFlags().String("profile", "", "")
+	root.PersistentFlags().String("region", "", "")
+	root.AddCommand(tunnel.NewTunnelCmd(tunnel.TunnelDependencies{Service: service}))
+	root.SilenceErrors = true
+	return root, service
+}
+
+func TestTunnelUpCmd(t *testing.T) {
+	root, service := newCmd(t)
+	service.EXPECT().Up(gomock.Any(), "prod-db", connection.AWSOptions{Profile: "prod"}).Return(nil)
+
+	root.SetArgs([]string{"tunnel", "up", "prod-db", "--profile", "prod"})
+	assert.NoError(t, root.Execute())
+}
+
+func TestTunnelUpCmd_Error(t *testing.T) {
+	root, service := newCmd(t)
+	service.EXPECT().Up(gomock.Any(), "prod-db", connection.AWSOptions{}).Return(errors.New("tunnel set \"prod-db\" not found"))
+
+	root.SetArgs([]string{"tunnel", "up", "prod-db"})
+	assert.EqualError(t, root.Execute(), "tunnel set \"prod-db\" not found")
+}
+
+func TestTunnelUpCmd_Detach(t *testing.T) {
+	root, service := newCmd(t)
+	service.EXPECT().Detach("prod-db", connection.AWSOptions{Region: "eu-west-1"}).Return(nil)
+
+	root.SetArgs([]string{"tunnel", "up", "prod-db", "--detach", "--region", "eu-west-1"})
+	assert.NoError(t, root.Execute())
+}
+
+func TestTunnelListCmd(t *testing.T) {
+	root, service := newCmd(t)
+	root.PersistentFlags().StringP("output", "o", "", "")
+	service.EXPECT().List().Return([]models.TunnelProcess{{
+		ID:        "prod-db",
+		PID:       4242,
+		Method:    "ssm",
+		Bastion:   "i-0abc",
+		Forwards:  []models.TunnelForward{{Local: 15432, Remote: "orders.rds.amazonaws.com:5432"}},
+		StartedAt: time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+	}}, nil)
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"tunnel", "list", "-o", "text"})
+	assert.NoError(t, root.Execute())
+	assert.True(t, strings.HasPrefix(out.String(), "prod-db\t4242\tssm\ti-0abc\t15432->orders.rds.amazonaws.com:5432\t\t"), out.String())
+}
+
+func TestTunnelStopCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		setup   func(*mock_tunnel.MockTunnelServiceInterface)
+		wantErr string
+	}{
+		{
+			name:  "stop a set",
+			args:  []string{"tunnel", "stop", "prod-db"},
+			setup: func(s *mock_tunnel.MockTunnelServiceInterface) { s.EXPECT().Stop("prod-db").Return(nil) },
+		},
+		{
+			name:  "down is an alias",
+			args:  []string{"tunnel", "down", "prod-db"},
+			setup: func(s *mock_tunnel.MockTunnelServiceInterface) { s.EXPECT().Stop("prod-db").Return(nil) },
+		},
+		{
+			name:  "all",
+			args:  []string{"tunnel", "stop", "--all"},
+			setup: func(s *mock_tunnel.MockTunnelServiceInterface) { s.EXPECT().StopAll().Return(nil) },
+		},
+		{
+			name:    "set and all",
+			args:    []string{"tunnel", "stop", "prod-db", "--all"},
+			wantErr: "give a tunnel set or --all, not both",
+		},
+		{
+			name:    "neither",
+			args:    []string{"tunnel", "stop"},
+			wantErr: "a tunnel set or --all is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, service := newCmd(t)
+			if tt.setup != nil {
+				tt.setup(service)
+			}
+
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTunnelLogsCmd(t *testing.T) {
+	root, service := newCmd(t)
+	service.EXPECT().Logs(gomock.Any(), "prod-db", gomock.Any(), true).Return(nil)
+
+	root.SetArgs([]string{"tunnel", "logs", "prod-db", "-f"})
+	assert.NoError(t, root.Execute())
+}
+
+func TestTunnelCmd_Args(t *testing.T) {
+	for _, args := range [][]string{
+		{"tunnel", "up"},
+		{"tunnel", "stop", "a", "b"},
+		{"tunnel", "logs"},
+	} {
+		root, _ := newCmd(t)
+		root.SetArgs(args)
+		assert.Error(t, root.Execute(), args)
+	}
+}
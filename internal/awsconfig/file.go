@@ -0,0 +1,319 @@
+// Package awsconfig reads and edits the AWS shared config and credentials
+// files. Edits keep comments, blank lines, ordering and formatting of every
+// line they do not change.
+package awsconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entry is one line of a section. Key is empty for comments, blank lines and
+// anything else that is not a key/value pair. Nested holds the indented
+// sub-property lines that follow a key, e.g. in [services x] sections.
+type entry struct {
+	raw    string
+	key    string
+	value  string
+	nested []string
+}
+
+// Section is a [name] block of the file. Name is normalized, e.g.
+// "profile dev", "sso-session corp", "services local" or "default".
+type Section struct {
+	Name    string
+	header  string
+	entries []*entry
+}
+
+// File is a parsed AWS config or credentials file.
+type File struct {
+	preamble []string
+	sections []*Section
+}
+
+// Parse parses the contents of an AWS config or credentials file.
+func Parse(data []byte) *File {
+	f := &File{}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return f
+	}
+
+	var current *Section
+	var lastKey *entry
+	for _, raw := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(raw)
+
+		if name, ok := parseHeader(trimmed); ok {
+			current = &Section{Name: name, header: raw}
+			f.sections = append(f.sections, current)
+			lastKey = nil
+			continue
+		}
+		if current == nil {
+			f.preamble = append(f.preamble, raw)
+			continue
+		}
+
+		indented := trimmed != "" && (raw[0] == ' ' || raw[0] == '\t')
+		if indented && lastKey != nil {
+			lastKey.nested = append(lastKey.nested, raw)
+			continue
+		}
+
+		e := &entry{raw: raw}
+		if !isComment(trimmed) {
+			if key, value, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(key) != "" {
+				e.key = strings.TrimSpace(key)
+				e.value = strings.TrimSpace(value)
+			}
+		}
+		current.entries = append(current.entries, e)
+		lastKey = nil
+		if e.key != "" {
+			lastKey = e
+		}
+	}
+	return f
+}
+
+func parseHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", false
+	}
+	end := strings.Index(line, "]")
+	if end < 0 {
+		return "", false
+	}
+	if rest := strings.TrimSpace(line[end+1:]); rest != "" && !isComment(rest) {
+		return "", false
+	}
+	return normalizeName(line[1:end]), true
+}
+
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";")
+}
+
+// Bytes renders the file. Unchanged files render byte for byte as parsed,
+// apart from line endings which are normalized to \n.
+func (f *File) Bytes() []byte {
+	var lines []string
+	lines = append(lines, f.preamble...)
+	for _, s := range f.sections {
+		lines = append(lines, s.header)
+		for _, e := range s.entries {
+			lines = append(lines, e.raw)
+			lines = append(lines, e.nested...)
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// SectionNames returns the names of all sections in file order.
+func (f *File) SectionNames() []string {
+	names := make([]string, 0, len(f.sections))
+	for _, s := range f.sections {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func (f *File) section(name string) *Section {
+	name = normalizeName(name)
+	for _, s := range f.sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// HasSection reports whether the section exists.
+func (f *File) HasSection(name string) bool {
+	return f.section(name) != nil
+}
+
+// Get returns the value of key in section. Like the AWS CLI, the last
+// occurrence of a key wins.
+func (f *File) Get(section, key string) (string, bool) {
+	value, found := "", false
+	name := normalizeName(section)
+	for _, s := range f.sections {
+		if s.Name != name {
+			continue
+		}
+		for _, e := range s.entries {
+			if e.key == key {
+				value, found = e.value, true
+			}
+		}
+	}
+	return value, found
+}
+
+// Values returns the key/value pairs of section, or nil if it does not exist.
+func (f *File) Values(section string) map[string]string {
+	if !f.HasSection(section) {
+		return nil
+	}
+	return f.Map()[normalizeName(section)]
+}
+
+// Map returns every section's key/value pairs keyed by section name.
+func (f *File) Map() map[string]map[string]string {
+	result := make(map[string]map[string]string, len(f.sections))
+	for _, s := range f.sections {
+		values := result[s.Name]
+		if values == nil {
+			values = make(map[string]string)
+			result[s.Name] = values
+		}
+		for _, e := range s.entries {
+			if e.key != "" {
+				values[e.key] = e.value
+			}
+		}
+	}
+	return result
+}
+
+// Set sets key to value in section, creating the section at the end of the
+// file if needed. An existing key is rewritten in place; a new key is added
+// after the last key of the section.
+func (f *File) Set(section, key, value string) {
+	s := f.section(section)
+	if s == nil {
+		s = f.addSection(section)
+	}
+
+	line := fmt.Sprintf("%s = %s", key, value)
+	last := -1
+	for i, e := range s.entries {
+		if e.key == key {
+			last = i
+		}
+	}
+	if last >= 0 {
+		e := s.entries[last]
+		e.raw, e.value, e.nested = line, value, nil
+		return
+	}
+
+	insertAt := 0
+	for i, e := range s.entries {
+		if e.key != "" {
+			insertAt = i + 1
+		}
+	}
+	s.entries = append(s.entries, nil)
+	copy(s.entries[insertAt+1:], s.entries[insertAt:])
+	s.entries[insertAt] = &entry{raw: line, key: key, value: value}
+}
+
+// addSection appends an empty section, separated from the previous one by a
+// blank line.
+func (f *File) addSection(name string) *Section {
+	name = normalizeName(name)
+	if n := len(f.sections); n > 0 {
+		prev := f.sections[n-1]
+		if len(prev.entries) == 0 || strings.TrimSpace(prev.entries[len(prev.entries)-1].raw) != "" {
+			prev.entries = append(prev.entries, &entry{raw: ""})
+		}
+	} else if n := len(f.preamble); n > 0 && strings.TrimSpace(f.preamble[n-1]) != "" {
+		f.preamble = append(f.preamble, "")
+	}
+
+	s := &Section{Name: name, header: "[" + name + "]"}
+	f.sections = append(f.sections, s)
+	return s
+}
+
+// Delete removes every occurrence of key from section and reports whether
+// anything was removed.
+func (f *File) Delete(section, key string) bool {
+	removed := false
+	name := normalizeName(section)
+	for _, s := range f.sections {
+		if s.Name != name {
+			continue
+		}
+		kept := s.entries[:0]
+		for _, e := range s.entries {
+			if e.key == key {
+				removed = true
+				continue
+			}
+			kept = append(kept, e)
+		}
+		s.entries = kept
+	}
+	return removed
+}
+
+// DeleteSection removes the section with all its lines and reports whether
+// it existed.
+func (f *File) DeleteSection(name string) bool {
+	name = normalizeName(name)
+	kept := f.sections[:0]
+	removed := false
+	for _, s := range f.sections {
+		if s.Name == name {
+			removed = true
+			continue
+		}
+		kept = append(kept, s)
+	}
+	f.sections = kept
+	return removed
+}
+
+// RenameSection renames a section in place, keeping its contents.
+func (f *File) RenameSection(oldName, newName string) error {
+	s := f.section(oldName)
+	if s == nil {
+		return fmt.Errorf("section [%s] not found", oldName)
+	}
+	if f.HasSection(newName) {
+		return fmt.Errorf("section [%s] already exists", newName)
+	}
+	s.Name = normalizeName(newName)
+	s.header = "[" + s.Name + "]"
+	return nil
+}
+
+// ProfileSection returns the config file section name of a profile.
+func ProfileSection(profile string) string {
+	if profile == "default" {
+		return "default"
+	}
+	return "profile " + profile
+}
+
+// Profiles returns the profile names defined in a config file, in file order.
+func (f *File) Profiles() []string {
+	var profiles []string
+	seen := make(map[string]bool)
+	for _, s := range f.sections {
+		name := s.Name
+		if name != "default" {
+			var ok bool
+			if name, ok = strings.CutPrefix(name, "profile "); !ok {
+				continue
+			}
+		}
+		if !seen[name] {
+			seen[name] = true
+			profiles = append(profiles, name)
+		}
+	}
+	return profiles
+}
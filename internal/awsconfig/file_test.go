@@ -0,0 +1,146 @@
+package awsconfig_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleConfig = `# managed by hand
+[default]
+region = us-east-1 ; trailing text is part of the value
+output = json
+
+# production account
+[profile prod]
+sso_session = corp
+sso_account_id = 111111111111
+; legacy role
+sso_role_name = Admin
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = us-east-1
+
+[services local]
+s3 =
+  endpoint_url = http://localhost:4566
+  addressing_style = path
+
+[profile  spaced ]
+region = eu-west-1
+`
+
+func TestParse_RoundTrip(t *testing.T) {
+	f := awsconfig.Parse([]byte(sampleConfig))
+	assert.Equal(t, sampleConfig, string(f.Bytes()))
+
+	assert.Equal(t, []string{"default", "profile prod", "sso-session corp", "services local", "profile spaced"}, f.SectionNames())
+	assert.Equal(t, []string{"default", "prod", "spaced"}, f.Profiles())
+
+	value, ok := f.Get("default", "region")
+	assert.True(t, ok)
+	assert.Equal(t, "us-east-1 ; trailing text is part of the value", value)
+
+	value, ok = f.Get("profile prod", "sso_role_name")
+	assert.True(t, ok)
+	assert.Equal(t, "Admin", value)
+
+	_, ok = f.Get("profile prod", "region")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]string{"s3": ""}, f.Values("services local"))
+	assert.Nil(t, f.Values("profile missing"))
+}
+
+func TestParse_Empty(t *testing.T) {
+	f := awsconfig.Parse(nil)
+	assert.Empty(t, f.SectionNames())
+	assert.Nil(t, f.Bytes())
+}
+
+func TestFile_Set(t *testing.T) {
+	f := awsconfig.Parse([]byte(sampleConfig))
+
+	f.Set("profile prod", "sso_role_name", "ReadOnly")
+	f.Set("profile prod", "region", "us-west-2")
+	f.Set("profile new", "region", "ap-south-1")
+	f.Set("profile new", "output", "json")
+
+	expected := `# managed by hand
+[default]
+region = us-east-1 ; trailing text is part of the value
+output = json
+
+# production account
+[profile prod]
+sso_session = corp
+sso_account_id = 111111111111
+; legacy role
+sso_role_name = ReadOnly
+region = us-west-2
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = us-east-1
+
+[services local]
+s3 =
+  endpoint_url = http://localhost:4566
+  addressing_style = path
+
+[profile  spaced ]
+region = eu-west-1
+
+[profile new]
+region = ap-south-1
+output = json
+`
+	assert.Equal(t, expected, string(f.Bytes()))
+}
+
+func TestFile_SetInEmptyFile(t *testing.T) {
+	f := awsconfig.Parse(nil)
+	f.Set("default", "region", "us-east-1")
+	f.Set("sso-session corp", "sso_region", "us-east-1")
+
+	assert.Equal(t, "[default]\nregion = us-east-1\n\n[sso-session corp]\nsso_region = us-east-1\n", string(f.Bytes()))
+}
+
+func TestFile_SetReplacesNestedValue(t *testing.T) {
+	f := awsconfig.Parse([]byte("[services local]\ns3 =\n  endpoint_url = http://localhost:4566\nsts =\n  endpoint_url = http://localhost:4566\n"))
+	f.Set("services local", "s3", "")
+
+	assert.Equal(t, "[services local]\ns3 = \nsts =\n  endpoint_url = http://localhost:4566\n", string(f.Bytes()))
+}
+
+func TestFile_Delete(t *testing.T) {
+	f := awsconfig.Parse([]byte(sampleConfig))
+
+	assert.True(t, f.Delete("services local", "s3"))
+	assert.False(t, f.Delete("profile prod", "missing"))
+	assert.True(t, f.DeleteSection("profile prod"))
+	assert.False(t, f.DeleteSection("profile prod"))
+
+	assert.Equal(t, []string{"default", "sso-session corp", "services local", "profile spaced"}, f.SectionNames())
+	assert.NotContains(t, string(f.Bytes()), "endpoint_url")
+	assert.Contains(t, string(f.Bytes()), "# production account")
+}
+
+func TestFile_RenameSection(t *testing.T) {
+	f := awsconfig.Parse([]byte(sampleConfig))
+
+	require.NoError(t, f.RenameSection("sso-session corp", "sso-session main"))
+	assert.Contains(t, string(f.Bytes()), "[sso-session main]\nsso_start_url = https://corp.awsapps.com/start\n")
+	assert.False(t, f.HasSection("sso-session corp"))
+
+	assert.EqualError(t, f.RenameSection("sso-session corp", "sso-session x"), "section [sso-session corp] not found")
+	assert.EqualError(t, f.RenameSection("profile prod", "default"), "section [default] already exists")
+}
+
+func TestProfileSection(t *testing.T) {
+	assert.Equal(t, "default", awsconfig.ProfileSection("default"))
+	assert.Equal(t, "profile dev", awsconfig.ProfileSection("dev"))
+}
@@ -0,0 +1,167 @@
+package awsconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultLockTimeout = 10 * time.Second
+	lockRetryInterval  = 50 * time.Millisecond
+	// staleLockAge is how old a lock file must be before it is considered
+	// left behind by a crashed process and removed.
+	staleLockAge = 30 * time.Second
+)
+
+// Store reads and atomically updates one AWS config or credentials file.
+type Store struct {
+	Fs          afero.Fs
+	Path        string
+	LockTimeout time.Duration
+}
+
+// NewStore returns a Store for the file at path on fs.
+func NewStore(fs afero.Fs, path string) *Store {
+	return &Store{Fs: fs, Path: path, LockTimeout: defaultLockTimeout}
+}
+
+// ConfigFilePath returns the shared config file path, honoring
+// AWS_CONFIG_FILE like the AWS CLI and SDKs.
+func ConfigFilePath() (string, error) {
+	return sharedFilePath("AWS_CONFIG_FILE", "config")
+}
+
+// CredentialsFilePath returns the shared credentials file path, honoring
+// AWS_SHARED_CREDENTIALS_FILE.
+func CredentialsFilePath() (string, error) {
+	return sharedFilePath("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+}
+
+func sharedFilePath(envVar, name string) (string, error) {
+	if path := os.Getenv(envVar); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".aws", name), nil
+}
+
+// Load parses the file. A missing file loads as an empty File.
+func (s *Store) Load() (*File, error) {
+	data, err := afero.ReadFile(s.Fs, s.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &File{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", s.Path, err)
+	}
+	return Parse(data), nil
+}
+
+// Update loads the file, applies fn and writes the result back atomically
+// while holding the file's lock. Nothing is written if fn returns an error or
+// leaves the contents unchanged.
+func (s *Store) Update(fn func(f *File) error) error {
+	dir := filepath.Dir(s.Path)
+	if err := s.Fs.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := s.Load()
+	if err != nil {
+		return err
+	}
+	before := f.Bytes()
+	if err := fn(f); err != nil {
+		return err
+	}
+	after := f.Bytes()
+	if string(before) == string(after) {
+		return nil
+	}
+	return s.write(after)
+}
+
+// write replaces the file through a temporary file in the same directory, so
+// readers never observe a partially written file.
+func (s *Store) write(data []byte) error {
+	mode := os.FileMode(0600)
+	if info, err := s.Fs.Stat(s.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := afero.TempFile(s.Fs, filepath.Dir(s.Path), "."+filepath.Base(s.Path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = s.Fs.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := s.Fs.Chmod(tmpName, mode); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := s.Fs.Rename(tmpName, s.Path); err != nil {
+		return fmt.Errorf("failed to update %s: %w", s.Path, err)
+	}
+	return nil
+}
+
+// lock acquires an exclusive lock file next to the config file. Lock files
+// older than staleLockAge are assumed to be left behind and are taken over.
+func (s *Store) lock() (func(), error) {
+	lockPath := s.Path + ".lock"
+	timeout := s.LockTimeout
+	if timeout <= 0 {
+		timeout = defaultLockTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		lockFile, err := s.Fs.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = lockFile.WriteString(strconv.Itoa(os.Getpid()))
+			_ = lockFile.Close()
+			return func() { _ = s.Fs.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", s.Path, err)
+		}
+
+		if info, statErr := s.Fs.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = s.Fs.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock on %s; remove %s if no other awsctl process is running", s.Path, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,161 @@
+package awsconfig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configPath = "/home/user/.aws/config"
+
+func TestStore_LoadMissingFile(t *testing.T) {
+	store := awsconfig.NewStore(afero.NewMemMapFs(), configPath)
+
+	f, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, f.SectionNames())
+}
+
+func TestStore_Update(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, configPath, []byte("# keep me\n[default]\nregion = us-east-1\n"), 0640))
+	store := awsconfig.NewStore(fs, configPath)
+
+	err := store.Update(func(f *awsconfig.File) error {
+		f.Set("default", "output", "json")
+		return nil
+	})
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# keep me\n[default]\nregion = us-east-1\noutput = json\n", string(data))
+
+	info, err := fs.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	entries, err := afero.ReadDir(fs, filepath.Dir(configPath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp and lock files must be cleaned up")
+}
+
+func TestStore_UpdateCreatesFile(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	store := awsconfig.NewStore(fs, configPath)
+
+	require.NoError(t, store.Update(func(f *awsconfig.File) error {
+		f.Set("profile dev", "region", "eu-west-1")
+		return nil
+	}))
+
+	data, err := afero.ReadFile(fs, configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "[profile dev]\nregion = eu-west-1\n", string(data))
+
+	info, err := fs.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestStore_UpdateErrorWritesNothing(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	store := awsconfig.NewStore(fs, configPath)
+
+	err := store.Update(func(f *awsconfig.File) error {
+		f.Set("default", "region", "us-east-1")
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	exists, err := afero.Exists(fs, configPath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestStore_UpdateConcurrent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	store := awsconfig.NewStore(fs, configPath)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, store.Update(func(f *awsconfig.File) error {
+				f.Set("profile p", "key"+string(rune('a'+i)), "v")
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	f, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, f.Values("profile p"), 20)
+}
+
+func TestStore_LockTimeout(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, configPath+".lock", []byte("1"), 0600))
+	store := awsconfig.NewStore(fs, configPath)
+	store.LockTimeout = 100 * time.Millisecond
+
+	err := store.Update(func(f *awsconfig.File) error { return nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out waiting for lock")
+}
+
+func TestStore_StaleLockIsTakenOver(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	lockPath := configPath + ".lock"
+	require.NoError(t, afero.WriteFile(fs, lockPath, []byte("1"), 0600))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, fs.Chtimes(lockPath, old, old))
+	store := awsconfig.NewStore(fs, configPath)
+
+	require.NoError(t, store.Update(func(f *awsconfig.File) error {
+		f.Set("default", "region", "us-east-1")
+		return nil
+	}))
+
+	exists, err := afero.Exists(fs, lockPath)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestStore_OsFs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config")
+	store := awsconfig.NewStore(afero.NewOsFs(), path)
+
+	require.NoError(t, store.Update(func(f *awsconfig.File) error {
+		f.Set("default", "region", "us-east-1")
+		return nil
+	}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "[default]\nregion = us-east-1\n", string(data))
+}
+
+func TestSharedFilePaths(t *testing.T) {
+	t.Setenv("HOME", "/home/user")
+	t.Setenv("AWS_CONFIG_FILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/tmp/creds")
+
+	path, err := awsconfig.ConfigFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/home/user", ".aws", "config"), path)
+
+	path, err = awsconfig.CredentialsFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/creds", path)
+}
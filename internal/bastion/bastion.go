@@ -9,6 +9,7 @@ import (
 	"syscall"
 
 	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 )
 
@@ -49,6 +50,38 @@ func (b *BastionService) Run(ctx context.Context) error {
 	}
 }
 
+// SSHIntoBastion opens a shell on the bastion described by connect.
+func (b *BastionService) SSHIntoBastion(ctx context.Context, connect connection.ConnectOptions) error {
+	b.services.UseConnectOptions(connect)
+	return b.handleSSHIntoBastion(ctx)
+}
+
+// StartSOCKSProxy runs a SOCKS proxy on port through the bastion described by
+// connect until it is interrupted. A zero port is prompted for.
+func (b *BastionService) StartSOCKSProxy(ctx context.Context, connect connection.ConnectOptions, port int) error {
+	b.services.UseConnectOptions(connect)
+	if port == 0 {
+		return b.handleStartSOCKSProxy(ctx)
+	}
+	return b.startSOCKSProxy(ctx, port)
+}
+
+// StartPortForwarding forwards localPort to remoteHost:remotePort through the
+// bastion described by connect until it is interrupted. Zero values are
+// prompted for.
+func (b *BastionService) StartPortForwarding(ctx context.Context, connect connection.ConnectOptions, localPort int, remoteHost string, remotePort int) error {
+	b.services.UseConnectOptions(connect)
+	return b.portForwarding(ctx, localPort, remoteHost, remotePort)
+}
+
+// ListInstances returns the bastion instances found in AWS.
+func (b *BastionService) ListInstances(ctx context.Context) ([]models.EC2Instance, error) {
+	if !b.services.IsAWSConfigured() {
+		return nil, errors.New("AWS configuration required to list bastion instances")
+	}
+	return b.services.ListBastionInstances(ctx)
+}
+
 func (b *BastionService) handleSelectionError(err error) error {
 	if errors.Is(err, promptUtils.ErrInterrupted) {
 		return nil
@@ -72,7 +105,10 @@ func (b *BastionService) handleStartSOCKSProxy(ctx context.Context) error {
 	if err != nil {
 		return b.handlePromptError(err, "port")
 	}
+	return b.startSOCKSProxy(ctx, port)
+}
 
+func (b *BastionService) startSOCKSProxy(ctx context.Context, port int) error {
 	if err := b.services.StartSOCKSProxy(ctx, port); err != nil {
 		return fmt.Errorf("SOCKS proxy error: %v", err)
 	}
@@ -81,28 +117,39 @@ func (b *BastionService) handleStartSOCKSProxy(ctx context.Context) error {
 }
 
 func (b *BastionService) handlePortForwarding(ctx context.Context) error {
-	localPort, err := b.prompter.PromptForLocalPort("forwarding", 8080)
-	if err != nil {
-		if errors.Is(err, promptUtils.ErrInterrupted) {
-			return nil
+	return b.portForwarding(ctx, 0, "", 0)
+}
+
+func (b *BastionService) portForwarding(ctx context.Context, localPort int, remoteHost string, remotePort int) error {
+	var err error
+	if localPort == 0 {
+		localPort, err = b.prompter.PromptForLocalPort("forwarding", 8080)
+		if err != nil {
+			if errors.Is(err, promptUtils.ErrInterrupted) {
+				return nil
+			}
+			return fmt.Errorf("failed to get local port: %v", err)
 		}
-		return fmt.Errorf("failed to get local port: %v", err)
 	}
 
-	remoteHost, err := b.prompter.PromptForRemoteHost()
-	if err != nil {
-		if errors.Is(err, promptUtils.ErrInterrupted) {
-			return nil
+	if remoteHost == "" {
+		remoteHost, err = b.prompter.PromptForRemoteHost()
+		if err != nil {
+			if errors.Is(err, promptUtils.ErrInterrupted) {
+				return nil
+			}
+			return fmt.Errorf("failed to get remote host: %v", err)
 		}
-		return fmt.Errorf("failed to get remote host: %v", err)
 	}
 
-	remotePort, err := b.prompter.PromptForRemotePort("remote service")
-	if err != nil {
-		if errors.Is(err, promptUtils.ErrInterrupted) {
-			return nil
+	if remotePort == 0 {
+		remotePort, err = b.prompter.PromptForRemotePort("remote service")
+		if err != nil {
+			if errors.Is(err, promptUtils.ErrInterrupted) {
+				return nil
+			}
+			return fmt.Errorf("failed to get remote port: %v", err)
 		}
-		return fmt.Errorf("failed to get remote port: %v", err)
 	}
 
 	cleanup, stopPortForwarding, err := b.services.StartPortForwarding(ctx, localPort, remoteHost, remotePort)
@@ -1,7 +1,16 @@
 package bastion
 
-import "context"
+import (
+	"context"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
+)
 
 type BastionServiceInterface interface {
 	Run(ctx context.Context) error
+	SSHIntoBastion(ctx context.Context, connect connection.ConnectOptions) error
+	StartSOCKSProxy(ctx context.Context, connect connection.ConnectOptions, port int) error
+	StartPortForwarding(ctx context.Context, connect connection.ConnectOptions, localPort int, remoteHost string, remotePort int) error
+	ListInstances(ctx context.Context) ([]models.EC2Instance, error)
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/golang/mock/gomock"
@@ -208,3 +209,91 @@ func TestBastionService_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestBastionService_Subcommands(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrompter := mock_awsctl.NewMockConnectionPrompter(ctrl)
+	mockServices := mock_awsctl.NewMockServicesInterface(ctrl)
+	service := NewBastionService(mockServices, mockPrompter)
+
+	connect := connection.ConnectOptions{Method: connection.MethodSSM, Host: "i-0123456789abcdef0"}
+
+	t.Run("SSHIntoBastion uses the connect options", func(t *testing.T) {
+		gomock.InOrder(
+			mockServices.EXPECT().UseConnectOptions(connect),
+			mockServices.EXPECT().SSHIntoBastion(gomock.Any()).Return(nil),
+		)
+
+		assert.NoError(t, service.SSHIntoBastion(context.Background(), connect))
+	})
+
+	t.Run("StartSOCKSProxy with a port does not prompt", func(t *testing.T) {
+		gomock.InOrder(
+			mockServices.EXPECT().UseConnectOptions(connect),
+			mockServices.EXPECT().StartSOCKSProxy(gomock.Any(), 1081).Return(errors.New("proxy error")),
+		)
+
+		err := service.StartSOCKSProxy(context.Background(), connect, 1081)
+		assert.EqualError(t, err, "SOCKS proxy error: proxy error")
+	})
+
+	t.Run("StartSOCKSProxy without a port prompts", func(t *testing.T) {
+		mockServices.EXPECT().UseConnectOptions(connect)
+		mockPrompter.EXPECT().PromptForSOCKSProxyPort(1080).Return(1080, nil)
+		mockServices.EXPECT().StartSOCKSProxy(gomock.Any(), 1080).Return(nil)
+
+		assert.NoError(t, service.StartSOCKSProxy(context.Background(), connect, 0))
+	})
+
+	t.Run("StartPortForwarding with all values does not prompt", func(t *testing.T) {
+		stopped := false
+		mockServices.EXPECT().UseConnectOptions(connect)
+		mockServices.EXPECT().StartPortForwarding(gomock.Any(), 5432, "db.internal", 5432).Return(
+			func() {},
+			func() { stopped = true },
+			nil,
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := service.StartPortForwarding(ctx, connect, 5432, "db.internal", 5432)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, stopped)
+	})
+
+	t.Run("StartPortForwarding prompts for missing values", func(t *testing.T) {
+		mockServices.EXPECT().UseConnectOptions(connect)
+		mockPrompter.EXPECT().PromptForRemotePort("remote service").Return(0, errors.New("remote port error"))
+
+		err := service.StartPortForwarding(context.Background(), connect, 5432, "db.internal", 0)
+		assert.EqualError(t, err, "failed to get remote port: remote port error")
+	})
+}
+
+func TestBastionService_ListInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrompter := mock_awsctl.NewMockConnectionPrompter(ctrl)
+	mockServices := mock_awsctl.NewMockServicesInterface(ctrl)
+	service := NewBastionService(mockServices, mockPrompter)
+
+	t.Run("returns the instances found in AWS", func(t *testing.T) {
+		instances := []models.EC2Instance{{InstanceID: "i-0123456789abcdef0", Name: "bastion"}}
+		mockServices.EXPECT().IsAWSConfigured().Return(true)
+		mockServices.EXPECT().ListBastionInstances(gomock.Any()).Return(instances, nil)
+
+		got, err := service.ListInstances(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, instances, got)
+	})
+
+	t.Run("requires AWS configuration", func(t *testing.T) {
+		mockServices.EXPECT().IsAWSConfigured().Return(false)
+
+		_, err := service.ListInstances(context.Background())
+		assert.EqualError(t, err, "AWS configuration required to list bastion instances")
+	})
+}
@@ -14,11 +14,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -48,6 +50,11 @@ type ConnectionProvider struct {
 	AwsConfigured bool
 	ConfigLoader  AWSConfigLoader
 	NewEC2Client  func(region string, loader AWSConfigLoader) (EC2ClientInterface, error)
+	// Options holds the profile and region given on the command line. A set
+	// region is used instead of prompting for one.
+	Options AWSOptions
+	// Connect holds the connection settings given on the command line.
+	Connect ConnectOptions
 }
 
 func NewConnectionProvider(
@@ -84,9 +91,13 @@ func NewConnectionProvider(
 }
 
 func (p *ConnectionProvider) GetConnectionDetails(ctx context.Context) (*ConnectionDetails, error) {
-	method, err := p.Prompter.ChooseConnectionMethod()
-	if err != nil {
-		return nil, fmt.Errorf("failed to select connection method: %w", err)
+	method := p.Connect.Method
+	if method == "" {
+		var err error
+		method, err = p.Prompter.ChooseConnectionMethod()
+		if err != nil {
+			return nil, fmt.Errorf("failed to select connection method: %w", err)
+		}
 	}
 
 	switch method {
@@ -100,14 +111,21 @@ func (p *ConnectionProvider) GetConnectionDetails(ctx context.Context) (*Connect
 }
 
 func (p *ConnectionProvider) getSSHDetails(ctx context.Context) (*ConnectionDetails, error) {
-	host, err := p.GetBastionHost(ctx)
-	if err != nil {
-		return nil, err
+	var err error
+	host := p.Connect.Host
+	if host == "" {
+		host, err = p.GetBastionHost(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	user, err := p.Prompter.PromptForSSHUser("ec2-user")
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+	user := p.Connect.User
+	if user == "" {
+		user, err = p.Prompter.PromptForSSHUser("ec2-user")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
 	}
 
 	keyPath := ""
@@ -136,9 +154,12 @@ func (p *ConnectionProvider) getSSHDetails(ctx context.Context) (*ConnectionDeta
 	}
 
 	if !useInstanceConnect && method == MethodSSH {
-		keyPath, err = p.Prompter.PromptForSSHKeyPath("~/.ssh/id_ed25519")
-		if err != nil {
-			return nil, fmt.Errorf("failed to get key path: %w", err)
+		keyPath = p.Connect.KeyPath
+		if keyPath == "" {
+			keyPath, err = p.Prompter.PromptForSSHKeyPath("~/.ssh/id_ed25519")
+			if err != nil {
+				return nil, fmt.Errorf("failed to get key path: %w", err)
+			}
 		}
 		if strings.HasPrefix(keyPath, "~/") {
 			homeDir, err := p.HomeDir()
@@ -195,6 +216,17 @@ func (p *ConnectionProvider) GetSSMDetails(ctx context.Context) (*ConnectionDeta
 		return nil, errors.New("AWS configuration required for SSM access")
 	}
 
+	if p.Connect.Host != "" {
+		if !strings.HasPrefix(p.Connect.Host, "i-") {
+			return nil, fmt.Errorf("invalid instance ID %q - should start with 'i-'", p.Connect.Host)
+		}
+		return &ConnectionDetails{
+			InstanceID: p.Connect.Host,
+			Method:     MethodSSM,
+			SSMClient:  p.SsmClient,
+		}, nil
+	}
+
 	instanceID, awsErr := p.GetBastionInstanceID(ctx, true)
 	if awsErr == nil {
 		return &ConnectionDetails{
@@ -224,33 +256,52 @@ func (p *ConnectionProvider) GetSSMDetails(ctx context.Context) (*ConnectionDeta
 }
 
 func (p *ConnectionProvider) GetBastionInstanceID(ctx context.Context, isSSM bool) (string, error) {
-	defaultRegion, err := p.GetDefaultRegion()
+	region, err := p.region()
 	if err != nil {
-		fmt.Printf("Failed to load default region: %v\n", err)
-		defaultRegion = ""
+		return "", fmt.Errorf("failed to get region: %w", err)
 	}
 
-	region, err := p.Prompter.PromptForRegion(defaultRegion)
+	instances, err := p.listBastionInstances(ctx, region)
 	if err != nil {
-		return "", fmt.Errorf("failed to get region: %w", err)
+		return "", err
 	}
 
-	loader := &DefaultAWSConfigLoader{}
+	if len(instances) == 0 {
+		return "", errors.New("no bastion hosts found in AWS")
+	}
+
+	return p.Prompter.PromptForBastionInstance(instances, isSSM)
+}
+
+// ListBastionInstances returns the bastion instances of the region given with
+// --region or configured, prompting for a region only when neither is set.
+func (p *ConnectionProvider) ListBastionInstances(ctx context.Context) ([]models.EC2Instance, error) {
+	region := p.Options.Region
+	if region == "" {
+		region, _ = p.GetDefaultRegion()
+	}
+	if region == "" {
+		var err error
+		region, err = p.Prompter.PromptForRegion("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get region: %w", err)
+		}
+	}
+	return p.listBastionInstances(ctx, region)
+}
+
+func (p *ConnectionProvider) listBastionInstances(ctx context.Context, region string) ([]models.EC2Instance, error) {
+	loader := &DefaultAWSConfigLoader{Options: p.Options}
 	ec2Client, err := p.NewEC2Client(region, loader)
 	if err != nil {
-		return "", fmt.Errorf("failed to initialize EC2 client: %w", err)
+		return nil, fmt.Errorf("failed to initialize EC2 client: %w", err)
 	}
 
 	instances, err := ec2Client.ListBastionInstances(ctx)
 	if err != nil {
-		return "", fmt.Errorf("AWS lookup failed: %w", err)
-	}
-
-	if len(instances) == 0 {
-		return "", errors.New("no bastion hosts found in AWS")
+		return nil, fmt.Errorf("AWS lookup failed: %w", err)
 	}
-
-	return p.Prompter.PromptForBastionInstance(instances, isSSM)
+	return instances, nil
 }
 
 func (p *ConnectionProvider) GetBastionHost(ctx context.Context) (string, error) {
@@ -264,19 +315,13 @@ func (p *ConnectionProvider) GetBastionHost(ctx context.Context) (string, error)
 		return p.Prompter.PromptForBastionHost()
 	}
 
-	defaultRegion, err := p.GetDefaultRegion()
-	if err != nil {
-		fmt.Printf("Failed to load default region: %v\n", err)
-		defaultRegion = ""
-	}
-
-	region, err := p.Prompter.PromptForRegion(defaultRegion)
+	region, err := p.region()
 	if err != nil {
 		fmt.Printf("Failed to get region: %v\n", err)
 		return p.Prompter.PromptForBastionHost()
 	}
 
-	loader := &DefaultAWSConfigLoader{}
+	loader := &DefaultAWSConfigLoader{Options: p.Options}
 	ec2Client, err := p.NewEC2Client(region, loader)
 	if err != nil {
 		log.Printf("Failed to initialize EC2 client: %v", err)
@@ -299,6 +344,41 @@ func (p *ConnectionProvider) GetBastionHost(ctx context.Context) (string, error)
 	return p.Prompter.PromptForBastionInstance(instances, false)
 }
 
+// region returns the region given with --region, or prompts for one with the
+// configured region as the default.
+func (p *ConnectionProvider) region() (string, error) {
+	if p.Options.Region != "" {
+		return p.Options.Region, nil
+	}
+
+	defaultRegion, err := p.GetDefaultRegion()
+	if err != nil {
+		fmt.Printf("Failed to load default region: %v\n", err)
+		defaultRegion = ""
+	}
+	return p.Prompter.PromptForRegion(defaultRegion)
+}
+
+// UseAWSOptions reloads the AWS config for the profile and region in opts and
+// rebuilds the EC2, SSM and EC2 Instance Connect clients from it, so that
+// connections use the chosen profile instead of the process default.
+func (p *ConnectionProvider) UseAWSOptions(ctx context.Context, opts AWSOptions) error {
+	loader := &DefaultAWSConfigLoader{Options: opts}
+	cfg, err := loader.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	p.Options = opts
+	p.ConfigLoader = loader
+	p.AwsConfig = cfg
+	p.AwsConfigured = isAWSConfigured(cfg)
+	p.Ec2Client = NewEC2Client(ec2.NewFromConfig(cfg))
+	p.SsmClient = ssm.NewFromConfig(cfg)
+	p.InstanceConn = NewEC2InstanceConnectAdapter(ec2instanceconnect.NewFromConfig(cfg))
+	return nil
+}
+
 func isAWSConfigured(cfg aws.Config) bool {
 	if cfg.Region == "" {
 		return false
@@ -1256,3 +1256,194 @@ func TestGetInstanceDetails_InstanceNotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "instance i-1234567890abcdef0 not found")
 }
+
+func TestGetBastionInstanceID_RegionOption(t *testing.T) {
+	m := setupMocks(t)
+	defer m.ctrl.Finish()
+	ctx := context.Background()
+
+	provider := connection.NewConnectionProvider(m.prompter, m.fs, aws.Config{}, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+	provider.Options = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+	provider.NewEC2Client = func(region string, loader connection.AWSConfigLoader) (connection.EC2ClientInterface, error) {
+		assert.Equal(t, "eu-west-1", region)
+		assert.Equal(t, &connection.DefaultAWSConfigLoader{Options: provider.Options}, loader)
+		return m.ec2Client, nil
+	}
+	m.ec2Client.EXPECT().ListBastionInstances(ctx).Return([]models.EC2Instance{
+		{InstanceID: "i-1234567890abcdef0", Name: "bastion-1"},
+	}, nil)
+	m.prompter.EXPECT().PromptForBastionInstance(gomock.Any(), true).Return("i-1234567890abcdef0", nil)
+
+	instanceID, err := provider.GetBastionInstanceID(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "i-1234567890abcdef0", instanceID)
+}
+
+func TestListBastionInstances(t *testing.T) {
+	ctx := context.Background()
+	instances := []models.EC2Instance{{InstanceID: "i-1234567890abcdef0", Name: "bastion-1"}}
+
+	t.Run("configured region is used without prompting", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+
+		cfg := aws.Config{
+			Region:      "us-west-2",
+			Credentials: credentials.NewStaticCredentialsProvider("mock-access-key", "mock-secret-key", ""),
+		}
+		provider := connection.NewConnectionProvider(m.prompter, m.fs, cfg, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+		provider.NewEC2Client = func(region string, loader connection.AWSConfigLoader) (connection.EC2ClientInterface, error) {
+			assert.Equal(t, "us-west-2", region)
+			return m.ec2Client, nil
+		}
+		m.ec2Client.EXPECT().ListBastionInstances(ctx).Return(instances, nil)
+
+		got, err := provider.ListBastionInstances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, instances, got)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+
+		provider := connection.NewConnectionProvider(m.prompter, m.fs, aws.Config{}, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+		provider.Options = connection.AWSOptions{Region: "eu-west-1"}
+		provider.NewEC2Client = func(region string, loader connection.AWSConfigLoader) (connection.EC2ClientInterface, error) {
+			return m.ec2Client, nil
+		}
+		m.ec2Client.EXPECT().ListBastionInstances(ctx).Return(nil, errors.New("unauthorized"))
+
+		_, err := provider.ListBastionInstances(ctx)
+		assert.EqualError(t, err, "AWS lookup failed: unauthorized")
+	})
+}
+
+func TestUseAWSOptions(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	credentialsFile := filepath.Join(dir, "credentials")
+	assert.NoError(t, os.WriteFile(configFile, []byte("[profile prod-admin]\nregion = ap-south-1\n"), 0600))
+	assert.NoError(t, os.WriteFile(credentialsFile, []byte("[prod-admin]\naws_access_key_id = AKIAPROD\naws_secret_access_key = secret\n"), 0600))
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	ctx := context.Background()
+
+	t.Run("profile and region", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+		provider := connection.NewConnectionProvider(m.prompter, m.fs, aws.Config{}, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+
+		opts := connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+		assert.NoError(t, provider.UseAWSOptions(ctx, opts))
+
+		assert.Equal(t, opts, provider.Options)
+		assert.Equal(t, "eu-west-1", provider.AwsConfig.Region)
+		assert.True(t, provider.AwsConfigured)
+		assert.NotSame(t, m.ec2Client, provider.Ec2Client)
+		assert.NotSame(t, m.ssmClient, provider.SsmClient)
+		assert.NotSame(t, m.instanceConn, provider.InstanceConn)
+
+		creds, err := provider.AwsConfig.Credentials.Retrieve(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "AKIAPROD", creds.AccessKeyID)
+	})
+
+	t.Run("profile region is used", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+		provider := connection.NewConnectionProvider(m.prompter, m.fs, aws.Config{}, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+
+		assert.NoError(t, provider.UseAWSOptions(ctx, connection.AWSOptions{Profile: "prod-admin"}))
+		assert.Equal(t, "ap-south-1", provider.AwsConfig.Region)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+		provider := connection.NewConnectionProvider(m.prompter, m.fs, aws.Config{}, m.ec2Client, m.ssmClient, m.instanceConn, m.configLoader)
+
+		err := provider.UseAWSOptions(ctx, connection.AWSOptions{Profile: "missing"})
+		assert.ErrorContains(t, err, "failed to load AWS config")
+		assert.Equal(t, connection.AWSOptions{}, provider.Options)
+	})
+}
+
+func TestGetConnectionDetails_ConnectOptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SSM with instance does not prompt", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+
+		provider := &connection.ConnectionProvider{
+			Prompter:      m.prompter,
+			SsmClient:     m.ssmClient,
+			AwsConfigured: true,
+			Connect:       connection.ConnectOptions{Method: connection.MethodSSM, Host: "i-0123456789abcdef0"},
+		}
+
+		details, err := provider.GetConnectionDetails(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &connection.ConnectionDetails{
+			InstanceID: "i-0123456789abcdef0",
+			Method:     connection.MethodSSM,
+			SSMClient:  m.ssmClient,
+		}, details)
+	})
+
+	t.Run("SSM with host", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+
+		provider := &connection.ConnectionProvider{
+			Prompter:      m.prompter,
+			AwsConfigured: true,
+			Connect:       connection.ConnectOptions{Method: connection.MethodSSM, Host: "bastion.example.com"},
+		}
+
+		_, err := provider.GetConnectionDetails(ctx)
+		assert.EqualError(t, err, `invalid instance ID "bastion.example.com" - should start with 'i-'`)
+	})
+
+	t.Run("SSH with host, user and key does not prompt", func(t *testing.T) {
+		m := setupMocks(t)
+		defer m.ctrl.Finish()
+
+		provider := &connection.ConnectionProvider{
+			Prompter: m.prompter,
+			Fs:       m.fs,
+			HomeDir:  func() (string, error) { return "/home/test", nil },
+			Connect: connection.ConnectOptions{
+				Method:  connection.MethodSSH,
+				Host:    "bastion.example.com",
+				User:    "ubuntu",
+				KeyPath: "~/.ssh/bastion",
+			},
+		}
+
+		m.fs.EXPECT().Stat("/home/test/.ssh/bastion").Return(nil, os.ErrNotExist)
+
+		_, err := provider.GetConnectionDetails(ctx)
+		assert.ErrorContains(t, err, "invalid SSH key")
+	})
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := connection.ParseMethod("ssh")
+	assert.NoError(t, err)
+	assert.Equal(t, connection.MethodSSH, method)
+
+	method, err = connection.ParseMethod("SSM")
+	assert.NoError(t, err)
+	assert.Equal(t, connection.MethodSSM, method)
+
+	_, err = connection.ParseMethod("eic")
+	assert.EqualError(t, err, `invalid method "eic": must be ssh or ssm`)
+}
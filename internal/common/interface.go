@@ -2,6 +2,8 @@ package connection
 
 import (
 	"context"
+	"io"
+	"net"
 
 	"github.com/BerryBytes/awsctl/models"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -61,19 +63,44 @@ type ServicesInterface interface {
 	SSHIntoBastion(ctx context.Context) error
 	StartSOCKSProxy(ctx context.Context, port int) error
 	StartPortForwarding(ctx context.Context, localPort int, remoteHost string, remotePort int) (cleanup func(), stop func(), err error)
+	StartTunnels(ctx context.Context, forwards []Forward) error
 	IsAWSConfigured() bool
+	UseConnectOptions(opts ConnectOptions)
+	ListBastionInstances(ctx context.Context) ([]models.EC2Instance, error)
 }
 
 var _ ServicesInterface = (*Services)(nil)
 
+// SSHClient is a bastion connection opened by the built-in SSH client.
+type SSHClient interface {
+	Shell(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error
+	Forward(ctx context.Context, ln net.Listener, remoteAddr string) error
+	SOCKS(ctx context.Context, ln net.Listener) error
+	Close() error
+}
+
+// SSMDataChannel is a Session Manager session opened by the built-in data
+// channel client.
+type SSMDataChannel interface {
+	Shell(ctx context.Context, stdin io.Reader, stdout io.Writer) error
+	Forward(ctx context.Context, conn net.Conn) error
+	Conn(ctx context.Context) (net.Conn, error)
+	Done() <-chan struct{}
+	// CustomerMessage is the notice the agent sent during the handshake.
+	CustomerMessage() string
+	Close() error
+}
+
 type AWSConfigLoader interface {
 	LoadDefaultConfig(ctx context.Context) (aws.Config, error)
 }
 
-type DefaultAWSConfigLoader struct{}
+type DefaultAWSConfigLoader struct {
+	Options AWSOptions
+}
 
 func (d *DefaultAWSConfigLoader) LoadDefaultConfig(ctx context.Context) (aws.Config, error) {
-	return config.LoadDefaultConfig(ctx)
+	return config.LoadDefaultConfig(ctx, d.Options.LoadOptions()...)
 }
 
 type SSMClientInterface interface {
@@ -0,0 +1,62 @@
+package connection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AWSOptions holds the profile and region given with the global --profile
+// and --region flags. Empty fields fall back to AWS_PROFILE, AWS_REGION and
+// the shared config, and to prompting where the services prompt.
+type AWSOptions struct {
+	Profile string
+	Region  string
+}
+
+// LoadOptions returns the options that make config.LoadDefaultConfig use the
+// profile and region of o.
+func (o AWSOptions) LoadOptions() []func(*config.LoadOptions) error {
+	var opts []func(*config.LoadOptions) error
+	if o.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(o.Profile))
+	}
+	if o.Region != "" {
+		opts = append(opts, config.WithRegion(o.Region))
+	}
+	return opts
+}
+
+// ConnectOptions holds the bastion connection settings given to the bastion
+// and rds subcommands. Set fields are used instead of prompting for them.
+type ConnectOptions struct {
+	// Method is MethodSSH or MethodSSM.
+	Method string
+	// Host is a bastion host name or IP, or an EC2 instance ID. SSM needs an
+	// instance ID; with SSH, an instance ID connects through EC2 Instance
+	// Connect.
+	Host    string
+	User    string
+	KeyPath string
+}
+
+// Forward is a local port forwarded to RemoteHost:RemotePort.
+type Forward struct {
+	LocalPort  int
+	RemoteHost string
+	RemotePort int
+}
+
+// ParseMethod returns the connection method for the value of a --method
+// flag, "ssh" or "ssm".
+func ParseMethod(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "ssh":
+		return MethodSSH, nil
+	case "ssm":
+		return MethodSSM, nil
+	default:
+		return "", fmt.Errorf("invalid method %q: must be ssh or ssm", value)
+	}
+}
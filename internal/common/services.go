@@ -3,33 +3,108 @@ package connection
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/BerryBytes/awsctl/internal/sshclient"
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	"github.com/spf13/afero"
 )
 
+// SSHBackendEnv selects how SSH connections are made. Setting it to
+// SSHBackendExec runs the system ssh binary instead of the built-in client.
+const (
+	SSHBackendEnv  = "AWSCTL_SSH_BACKEND"
+	SSHBackendExec = "exec"
+)
+
 type Services struct {
 	Provider        *ConnectionProvider
 	Executor        common.SSHExecutorInterface
 	OsDetector      common.OSDetector
 	SsmStarter      SSMStarterInterface
 	CommandExecutor common.CommandExecutor
+	// DialSSH opens connections with the built-in SSH client. When it is nil
+	// the system ssh binary is run through Executor.
+	DialSSH func(ctx context.Context, cfg sshclient.Config) (SSHClient, error)
 }
 
 func NewServices(
 	provider *ConnectionProvider,
 ) *Services {
-	return &Services{
+	s := &Services{
 		Provider:        provider,
 		Executor:        &common.RealSSHExecutor{},
 		OsDetector:      common.RuntimeOSDetector{},
 		SsmStarter:      NewRealSSMStarter(provider.SsmClient, provider.AwsConfig.Region),
 		CommandExecutor: &common.RealCommandExecutor{},
 	}
+	if os.Getenv(SSHBackendEnv) != SSHBackendExec {
+		s.DialSSH = func(ctx context.Context, cfg sshclient.Config) (SSHClient, error) {
+			return sshclient.Dial(ctx, cfg)
+		}
+	}
+	return s
+}
+
+// nativeSSHConfig returns the settings for the built-in SSH client, or false
+// when the system ssh binary has to be used instead. That is the case when
+// DialSSH is unset and for instances without a public address, which are
+// reached through `aws ec2-instance-connect open-tunnel`.
+func (s *Services) nativeSSHConfig(details *ConnectionDetails) (sshclient.Config, bool) {
+	if s.DialSSH == nil || strings.HasPrefix(details.Host, "i-") {
+		return sshclient.Config{}, false
+	}
+
+	cfg := sshclient.Config{
+		Host:    details.Host,
+		User:    details.User,
+		KeyPath: details.KeyPath,
+	}
+	if details.UseInstanceConnect {
+		// The temporary key pair is only valid for this session, so there is
+		// nothing to remember about the host.
+		cfg.HostKeyPolicy = sshclient.HostKeyInsecure
+		cfg.Timeout = 10 * time.Second
+		cfg.KeepAlive = 15 * time.Second
+	} else {
+		cfg.ConfirmHostKey = s.confirmHostKey
+	}
+	return cfg, true
+}
+
+func (s *Services) confirmHostKey(host, fingerprint string) (bool, error) {
+	fmt.Printf("The authenticity of host '%s' can't be established.\n", host)
+	fmt.Printf("Host key fingerprint is %s.\n", fingerprint)
+	return s.Provider.Prompter.PromptForConfirmation("Are you sure you want to continue connecting?")
+}
+
+// UseAWSOptions rebuilds the AWS clients of the provider and the SSM session
+// starter for the profile and region in opts.
+func (s *Services) UseAWSOptions(ctx context.Context, opts AWSOptions) error {
+	if err := s.Provider.UseAWSOptions(ctx, opts); err != nil {
+		return err
+	}
+	s.SsmStarter = NewRealSSMStarter(s.Provider.SsmClient, s.Provider.AwsConfig.Region)
+	return nil
+}
+
+// UseConnectOptions makes later connections use the settings in opts instead
+// of prompting for them.
+func (s *Services) UseConnectOptions(opts ConnectOptions) {
+	s.Provider.Connect = opts
+}
+
+// ListBastionInstances returns the bastion instances found in AWS.
+func (s *Services) ListBastionInstances(ctx context.Context) ([]models.EC2Instance, error) {
+	return s.Provider.ListBastionInstances(ctx)
 }
 
 func (s *Services) SSHIntoBastion(ctx context.Context) error {
@@ -71,10 +146,30 @@ func (s *Services) SSHIntoBastion(ctx context.Context) error {
 			"--instance-id", details.InstanceID,
 			"--connection-type", "eice",
 		}
+		if profile := s.Provider.Options.Profile; profile != "" {
+			args = append(args, "--profile", profile)
+		}
+		if region := s.Provider.Options.Region; region != "" {
+			args = append(args, "--region", region)
+		}
 
 		return s.CommandExecutor.RunInteractiveCommand(ctx, "aws", args...)
 	}
 
+	if cfg, ok := s.nativeSSHConfig(details); ok {
+		fmt.Println("Using traditional SSH authentication")
+		fmt.Printf("Connecting to %s@%s...\n", details.User, details.Host)
+
+		client, err := s.DialSSH(ctx, cfg)
+		if err != nil {
+			fmt.Printf("SSH session failed with error: %v\n", err)
+			return err
+		}
+		defer func() { _ = client.Close() }()
+
+		return client.Shell(ctx, os.Stdin, os.Stdout, os.Stderr)
+	}
+
 	builder := common.NewSSHCommandBuilder(
 		details.Host,
 		details.User,
@@ -126,6 +221,28 @@ func (s *Services) StartSOCKSProxy(ctx context.Context, localPort int) error {
 		return s.SsmStarter.StartSOCKSProxy(ctx, details.InstanceID, localPort)
 	}
 
+	via := details.Host
+	if details.UseInstanceConnect {
+		via = fmt.Sprintf("EC2 Instance Connect for instance %s", details.Host)
+	}
+
+	if cfg, ok := s.nativeSSHConfig(details); ok {
+		client, err := s.DialSSH(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = client.Close() }()
+
+		ln, err := listenLocal(localPort)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Setting up SOCKS proxy on localhost:%d via %s...\n", localPort, via)
+		fmt.Println("SOCKS proxy active. Press Ctrl+C to stop.")
+		return client.SOCKS(ctx, ln)
+	}
+
 	builder := common.NewSSHCommandBuilder(
 		details.Host,
 		details.User,
@@ -137,10 +254,6 @@ func (s *Services) StartSOCKSProxy(ctx context.Context, localPort int) error {
 		WithSOCKS(localPort).
 		Build()
 
-	via := details.Host
-	if details.UseInstanceConnect {
-		via = fmt.Sprintf("EC2 Instance Connect for instance %s", details.Host)
-	}
 	fmt.Printf("Setting up SOCKS proxy on localhost:%d via %s...\n", localPort, via)
 	fmt.Println("SOCKS proxy active. Press Ctrl+C to stop.")
 
@@ -174,6 +287,22 @@ func (s *Services) StartPortForwarding(ctx context.Context, localPort int, remot
 		return cleanup, func() {}, err
 	}
 
+	via := details.Host
+	if details.UseInstanceConnect {
+		via = fmt.Sprintf("EC2 Instance Connect for instance %s", details.Host)
+	}
+
+	if cfg, ok := s.nativeSSHConfig(details); ok {
+		stop, err := s.forwardNative(ctx, cfg, localPort, net.JoinHostPort(remoteHost, strconv.Itoa(remotePort)))
+		if err != nil {
+			return cleanup, func() {}, err
+		}
+		fmt.Printf("Setting up port forwarding from localhost:%d to %s:%d via %s...\n",
+			localPort, remoteHost, remotePort, via)
+		fmt.Println("Port forwarding active. Press Ctrl+C to stop.")
+		return cleanup, stop, nil
+	}
+
 	builder := common.NewSSHCommandBuilder(
 		details.Host,
 		details.User,
@@ -185,11 +314,6 @@ func (s *Services) StartPortForwarding(ctx context.Context, localPort int, remot
 		WithForwarding(localPort, remoteHost, remotePort).
 		Build()
 
-	via := details.Host
-	if details.UseInstanceConnect {
-		via = fmt.Sprintf("EC2 Instance Connect for instance %s", details.Host)
-	}
-
 	fmt.Printf("Setting up port forwarding from localhost:%d to %s:%d via %s...\n",
 		localPort, remoteHost, remotePort, via)
 	fmt.Println("Port forwarding active. Press Ctrl+C to stop.")
@@ -212,6 +336,143 @@ func (s *Services) StartPortForwarding(ctx context.Context, localPort int, remot
 	return cleanup, stop, nil
 }
 
+// StartTunnels forwards every port in forwards through a single bastion
+// connection until ctx is done or one of the forwards fails.
+func (s *Services) StartTunnels(ctx context.Context, forwards []Forward) error {
+	details, err := s.Provider.GetConnectionDetails(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection details: %w", err)
+	}
+
+	tempFiles := []common.TempFile{}
+	if details.KeyPath != "" && details.UseInstanceConnect {
+		tempFiles = append(tempFiles, common.TempFile{
+			Path: details.KeyPath,
+			Desc: "temporary SSH key",
+		})
+	}
+	defer common.SetupCleanup(afero.NewOsFs(), tempFiles)()
+
+	via := details.Host
+	if details.Method == MethodSSM {
+		via = fmt.Sprintf("SSM instance %s", details.InstanceID)
+	} else if details.UseInstanceConnect {
+		via = fmt.Sprintf("EC2 Instance Connect for instance %s", details.Host)
+	}
+	for _, f := range forwards {
+		fmt.Printf("Forwarding localhost:%d to %s:%d via %s\n", f.LocalPort, f.RemoteHost, f.RemotePort, via)
+	}
+
+	if details.Method == MethodSSM {
+		return runTunnels(ctx, len(forwards), func(ctx context.Context, i int) error {
+			f := forwards[i]
+			return s.SsmStarter.StartPortForwarding(ctx, details.InstanceID, f.LocalPort, f.RemoteHost, f.RemotePort)
+		})
+	}
+
+	if cfg, ok := s.nativeSSHConfig(details); ok {
+		listeners := make([]net.Listener, 0, len(forwards))
+		defer func() {
+			for _, ln := range listeners {
+				_ = ln.Close()
+			}
+		}()
+		for _, f := range forwards {
+			ln, err := listenLocal(f.LocalPort)
+			if err != nil {
+				return err
+			}
+			listeners = append(listeners, ln)
+		}
+
+		client, err := s.DialSSH(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = client.Close() }()
+
+		fmt.Println("Tunnels active. Press Ctrl+C to stop.")
+		return runTunnels(ctx, len(forwards), func(ctx context.Context, i int) error {
+			f := forwards[i]
+			return client.Forward(ctx, listeners[i], net.JoinHostPort(f.RemoteHost, strconv.Itoa(f.RemotePort)))
+		})
+	}
+
+	builder := common.NewSSHCommandBuilder(
+		details.Host,
+		details.User,
+		details.KeyPath,
+		details.UseInstanceConnect,
+	)
+	for _, f := range forwards {
+		builder.WithForwarding(f.LocalPort, f.RemoteHost, f.RemotePort)
+	}
+
+	fmt.Println("Tunnels active. Press Ctrl+C to stop.")
+	return common.ExecuteSSHCommand(s.Executor, builder.Build())
+}
+
+// runTunnels runs start for tunnels 0 to n-1 concurrently. When one fails the
+// others are stopped, and its error is returned once all have.
+func runTunnels(ctx context.Context, n int, start func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() { errs <- start(ctx, i) }()
+	}
+
+	var first error
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
+
+// forwardNative connects with the built-in SSH client and forwards localPort
+// to remoteAddr in the background until the returned stop func is called.
+func (s *Services) forwardNative(ctx context.Context, cfg sshclient.Config, localPort int, remoteAddr string) (func(), error) {
+	client, err := s.DialSSH(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := listenLocal(localPort)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { _ = client.Close() }()
+		if err := client.Forward(ctx, ln, remoteAddr); err != nil {
+			fmt.Fprintf(os.Stderr, "Port forwarding stopped: %v\n", err)
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		cancel()
+		<-done
+	}), nil
+}
+
+// listenLocal listens on localPort of the loopback interface, like ssh -L and
+// -D do by default.
+func listenLocal(localPort int) (net.Listener, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(localPort)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on localhost:%d: %w", localPort, err)
+	}
+	return ln, nil
+}
+
 func (s *Services) IsAWSConfigured() bool {
 	if s == nil || s.Provider == nil {
 		return false
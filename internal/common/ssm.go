@@ -4,26 +4,50 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"runtime"
 
+	"github.com/BerryBytes/awsctl/internal/proxy"
+	"github.com/BerryBytes/awsctl/internal/ssmsession"
 	"github.com/BerryBytes/awsctl/utils/common"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
+// SSMBackendEnv selects how Session Manager sessions are carried. Setting it
+// to SSMBackendPlugin runs session-manager-plugin instead of the built-in
+// data channel client.
+const (
+	SSMBackendEnv    = "AWSCTL_SSM_BACKEND"
+	SSMBackendPlugin = "plugin"
+)
+
 type RealSSMStarter struct {
 	Client          SSMClientInterface
 	Region          string
 	CommandExecutor common.CommandExecutor
+	// OpenDataChannel connects to sessions with the built-in data channel
+	// client. When it is nil session-manager-plugin is run instead.
+	OpenDataChannel func(ctx context.Context, streamURL, token string) (SSMDataChannel, error)
 }
 
 func NewRealSSMStarter(client SSMClientInterface, region string) *RealSSMStarter {
-	return &RealSSMStarter{
+	s := &RealSSMStarter{
 		Client:          client,
 		Region:          region,
 		CommandExecutor: &common.RealCommandExecutor{},
 	}
+	if os.Getenv(SSMBackendEnv) != SSMBackendPlugin {
+		s.OpenDataChannel = func(ctx context.Context, streamURL, token string) (SSMDataChannel, error) {
+			channel, err := ssmsession.Dial(ctx, streamURL, token)
+			if err != nil {
+				return nil, err
+			}
+			return channel, nil
+		}
+	}
+	return s
 }
 
 func (s *RealSSMStarter) StartSession(ctx context.Context, instanceID string) error {
@@ -36,12 +60,23 @@ func (s *RealSSMStarter) StartSession(ctx context.Context, instanceID string) er
 	defer s.TerminateSession(ctx, session.SessionId)
 
 	fmt.Printf("Starting SSM session with instance %s...\n", instanceID)
+	if s.OpenDataChannel != nil {
+		channel, err := s.OpenDataChannel(ctx, aws.ToString(session.StreamUrl), aws.ToString(session.TokenValue))
+		if err != nil {
+			return fmt.Errorf("SSM session failed: %w", err)
+		}
+		defer func() { _ = channel.Close() }()
+		if message := channel.CustomerMessage(); message != "" {
+			fmt.Fprintln(os.Stderr, message)
+		}
+		return channel.Shell(ctx, os.Stdin, os.Stdout)
+	}
 	return s.RunSessionManagerPlugin(ctx, session, instanceID, "StartSession")
 }
 
 func (s *RealSSMStarter) StartPortForwarding(ctx context.Context, instanceID string, localPort int, remoteHost string, remotePort int) error {
 	fmt.Printf("remote host %s", remoteHost)
-	session, err := s.Client.StartSession(ctx, &ssm.StartSessionInput{
+	input := &ssm.StartSessionInput{
 		Target:       aws.String(instanceID),
 		DocumentName: aws.String("AWS-StartPortForwardingSessionToRemoteHost"),
 		Parameters: map[string][]string{
@@ -49,7 +84,13 @@ func (s *RealSSMStarter) StartPortForwarding(ctx context.Context, instanceID str
 			"localPortNumber": {fmt.Sprintf("%d", localPort)},
 			"host":            {remoteHost},
 		},
-	})
+	}
+	if s.OpenDataChannel != nil {
+		fmt.Printf("Starting SSM port forwarding for instance %s (localhost:%d to %s:%d)...\n", instanceID, localPort, remoteHost, remotePort)
+		return s.forward(ctx, input, localPort, "SSM port forwarding failed")
+	}
+
+	session, err := s.Client.StartSession(ctx, input)
 	if err != nil {
 		return fmt.Errorf("SSM port forwarding failed: %w", err)
 	}
@@ -59,22 +100,107 @@ func (s *RealSSMStarter) StartPortForwarding(ctx context.Context, instanceID str
 	return s.RunSessionManagerPlugin(ctx, session, instanceID, "PortForwarding")
 }
 
+// StartSOCKSProxy serves a SOCKS5 and HTTP CONNECT proxy on localPort. Every
+// proxied connection gets a port forwarding session to the requested
+// destination, so anything instanceID can reach is reachable through the
+// proxy without a proxy server on the instance.
 func (s *RealSSMStarter) StartSOCKSProxy(ctx context.Context, instanceID string, localPort int) error {
-	session, err := s.Client.StartSession(ctx, &ssm.StartSessionInput{
-		Target:       aws.String(instanceID),
-		DocumentName: aws.String("AWS-StartPortForwardingSession"),
-		Parameters: map[string][]string{
-			"portNumber":      {"1080"},
-			"localPortNumber": {fmt.Sprintf("%d", localPort)},
-		},
-	})
+	if s.OpenDataChannel == nil {
+		return fmt.Errorf("SSM SOCKS proxy failed: not supported with %s=%s", SSMBackendEnv, SSMBackendPlugin)
+	}
+
+	ln, err := listenLocal(localPort)
 	if err != nil {
 		return fmt.Errorf("SSM SOCKS proxy failed: %w", err)
 	}
-	defer s.TerminateSession(ctx, session.SessionId)
 
 	fmt.Printf("Starting SSM SOCKS proxy for instance %s on localhost:%d...\n", instanceID, localPort)
-	return s.RunSessionManagerPlugin(ctx, session, instanceID, "SOCKSProxy")
+	return proxy.ServeDynamic(ctx, ln, s.dialRemoteHost(instanceID))
+}
+
+// forward listens on localPort and carries every accepted connection over a
+// session of its own, started with input, until ctx is done.
+func (s *RealSSMStarter) forward(ctx context.Context, input *ssm.StartSessionInput, localPort int, failure string) error {
+	ln, err := listenLocal(localPort)
+	if err != nil {
+		return fmt.Errorf("%s: %w", failure, err)
+	}
+
+	return proxy.Serve(ctx, ln, func(conn net.Conn) {
+		if err := s.forwardConn(ctx, input, conn); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %s for connection from %s: %v\n", failure, conn.RemoteAddr(), err)
+		}
+	})
+}
+
+func (s *RealSSMStarter) forwardConn(ctx context.Context, input *ssm.StartSessionInput, conn net.Conn) error {
+	channel, closeSession, err := s.openSession(ctx, input)
+	if err != nil {
+		return err
+	}
+	defer closeSession()
+
+	return channel.Forward(ctx, conn)
+}
+
+// dialRemoteHost returns a dial func that connects to addresses reachable
+// from instanceID, each through a port forwarding session of its own. It
+// fails when the instance cannot reach the address, and the session is
+// terminated once the returned connection is closed.
+func (s *RealSSMStarter) dialRemoteHost(instanceID string) proxy.DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		channel, closeSession, err := s.openSession(ctx, &ssm.StartSessionInput{
+			Target:       aws.String(instanceID),
+			DocumentName: aws.String("AWS-StartPortForwardingSessionToRemoteHost"),
+			Parameters: map[string][]string{
+				"portNumber": {port},
+				"host":       {host},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := channel.Conn(ctx)
+		if err != nil {
+			closeSession()
+			return nil, err
+		}
+		go func() {
+			select {
+			case <-channel.Done():
+			case <-ctx.Done():
+			}
+			closeSession()
+		}()
+		return conn, nil
+	}
+}
+
+// openSession starts a session with input and opens its data channel. The
+// returned func closes the channel and terminates the session.
+func (s *RealSSMStarter) openSession(ctx context.Context, input *ssm.StartSessionInput) (SSMDataChannel, func(), error) {
+	session, err := s.Client.StartSession(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	terminate := func() { s.TerminateSession(context.WithoutCancel(ctx), session.SessionId) }
+
+	channel, err := s.OpenDataChannel(ctx, aws.ToString(session.StreamUrl), aws.ToString(session.TokenValue))
+	if err != nil {
+		terminate()
+		return nil, nil, err
+	}
+
+	return channel, func() {
+		_ = channel.Close()
+		terminate()
+	}, nil
 }
 
 func (s *RealSSMStarter) TerminateSession(ctx context.Context, sessionID *string) {
@@ -1,16 +1,23 @@
 package connection_test
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"io"
+	"net"
+	"strconv"
 	"testing"
+	"time"
 
 	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/ssmsession"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRealSSMStarter(t *testing.T) {
@@ -145,58 +152,9 @@ func TestRealSSMStarter(t *testing.T) {
 	})
 
 	t.Run("StartSOCKSProxy", func(t *testing.T) {
-		tests := []struct {
-			name          string
-			setupMocks    func()
-			expectError   bool
-			errorContains string
-		}{
-			{
-				name: "successful SOCKS proxy",
-				setupMocks: func() {
-					mockSSMClient.EXPECT().StartSession(gomock.Any(), &ssm.StartSessionInput{
-						Target:       aws.String("i-1234567890"),
-						DocumentName: aws.String("AWS-StartPortForwardingSession"),
-						Parameters: map[string][]string{
-							"portNumber":      {"1080"},
-							"localPortNumber": {"8080"},
-						},
-					}).Return(&ssm.StartSessionOutput{
-						SessionId:  aws.String("test-session-id"),
-						StreamUrl:  aws.String("wss://test-stream"),
-						TokenValue: aws.String("test-token"),
-					}, nil)
-					mockCommandExecutor.EXPECT().LookPath(gomock.Any()).Return("/path/to/plugin", nil)
-					mockCommandExecutor.EXPECT().RunInteractiveCommand(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-					mockSSMClient.EXPECT().TerminateSession(gomock.Any(), gomock.Any()).Return(nil, nil)
-				},
-			},
-			{
-				name: "SOCKS proxy fails",
-				setupMocks: func() {
-					mockSSMClient.EXPECT().StartSession(gomock.Any(), gomock.Any()).Return(nil, errors.New("proxy error"))
-				},
-				expectError:   true,
-				errorContains: "SSM SOCKS proxy failed",
-			},
-		}
-
-		for _, tt := range tests {
-			t.Run(tt.name, func(t *testing.T) {
-				tt.setupMocks()
-				s := newTestSSMStarter()
-				err := s.StartSOCKSProxy(context.Background(), "i-1234567890", 8080)
-
-				if tt.expectError {
-					assert.Error(t, err)
-					if tt.errorContains != "" {
-						assert.Contains(t, err.Error(), tt.errorContains)
-					}
-				} else {
-					assert.NoError(t, err)
-				}
-			})
-		}
+		s := newTestSSMStarter()
+		err := s.StartSOCKSProxy(context.Background(), "i-1234567890", 8080)
+		assert.EqualError(t, err, "SSM SOCKS proxy failed: not supported with AWSCTL_SSM_BACKEND=plugin")
 	})
 
 	t.Run("TerminateSession", func(t *testing.T) {
@@ -281,3 +239,219 @@ func TestRealSSMStarter(t *testing.T) {
 		}
 	})
 }
+
+func TestRealSSMStarter_DataChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSSMClient := mock_awsctl.NewMockSSMClientInterface(ctrl)
+	mockChannel := mock_awsctl.NewMockSSMDataChannel(ctrl)
+	session := &ssm.StartSessionOutput{
+		SessionId:  aws.String("test-session-id"),
+		StreamUrl:  aws.String("wss://test-stream"),
+		TokenValue: aws.String("test-token"),
+	}
+
+	var openErr error
+	newTestSSMStarter := func() *connection.RealSSMStarter {
+		return &connection.RealSSMStarter{
+			Client: mockSSMClient,
+			Region: "us-west-2",
+			OpenDataChannel: func(ctx context.Context, streamURL, token string) (connection.SSMDataChannel, error) {
+				assert.Equal(t, "wss://test-stream", streamURL)
+				assert.Equal(t, "test-token", token)
+				if openErr != nil {
+					return nil, openErr
+				}
+				return mockChannel, nil
+			},
+		}
+	}
+
+	t.Run("StartSession", func(t *testing.T) {
+		openErr = nil
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), gomock.Any()).Return(session, nil)
+		mockChannel.EXPECT().CustomerMessage().Return("")
+		mockChannel.EXPECT().Shell(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		mockChannel.EXPECT().Close().Return(nil)
+		mockSSMClient.EXPECT().TerminateSession(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		err := newTestSSMStarter().StartSession(context.Background(), "i-1234567890")
+		assert.NoError(t, err)
+	})
+
+	t.Run("StartSession data channel fails", func(t *testing.T) {
+		openErr = errors.New("handshake failed")
+		defer func() { openErr = nil }()
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), gomock.Any()).Return(session, nil)
+		mockSSMClient.EXPECT().TerminateSession(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		err := newTestSSMStarter().StartSession(context.Background(), "i-1234567890")
+		assert.EqualError(t, err, "SSM session failed: handshake failed")
+	})
+
+	t.Run("StartPortForwarding", func(t *testing.T) {
+		port := freeLocalPort(t)
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), &ssm.StartSessionInput{
+			Target:       aws.String("i-1234567890"),
+			DocumentName: aws.String("AWS-StartPortForwardingSessionToRemoteHost"),
+			Parameters: map[string][]string{
+				"portNumber":      {"5432"},
+				"localPortNumber": {strconv.Itoa(port)},
+				"host":            {"db.internal"},
+			},
+		}).Return(session, nil)
+		mockChannel.EXPECT().Forward(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, conn net.Conn) error {
+			_, err := conn.Write([]byte("hello"))
+			return err
+		})
+		mockChannel.EXPECT().Close().Return(nil)
+		mockSSMClient.EXPECT().TerminateSession(gomock.Any(), &ssm.TerminateSessionInput{SessionId: aws.String("test-session-id")}).Return(nil, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- newTestSSMStarter().StartPortForwarding(ctx, "i-1234567890", port, "db.internal", 5432)
+		}()
+
+		conn := dialEventually(t, port)
+		buf := make([]byte, 5)
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err := io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(buf))
+		_ = conn.Close()
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("StartSOCKSProxy", func(t *testing.T) {
+		port := freeLocalPort(t)
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), &ssm.StartSessionInput{
+			Target:       aws.String("i-1234567890"),
+			DocumentName: aws.String("AWS-StartPortForwardingSessionToRemoteHost"),
+			Parameters: map[string][]string{
+				"portNumber": {"443"},
+				"host":       {"api.internal"},
+			},
+		}).Return(session, nil)
+		local, remote := net.Pipe()
+		sessionDone := make(chan struct{})
+		go func() {
+			defer close(sessionDone)
+			_, _ = remote.Write([]byte("hello"))
+			_, _ = io.Copy(io.Discard, remote)
+		}()
+		mockChannel.EXPECT().Conn(gomock.Any()).Return(local, nil)
+		mockChannel.EXPECT().Done().Return((<-chan struct{})(sessionDone))
+		mockChannel.EXPECT().Close().Return(nil)
+		terminated := make(chan struct{})
+		mockSSMClient.EXPECT().TerminateSession(gomock.Any(), &ssm.TerminateSessionInput{SessionId: aws.String("test-session-id")}).
+			DoAndReturn(func(context.Context, *ssm.TerminateSessionInput, ...func(*ssm.Options)) (*ssm.TerminateSessionOutput, error) {
+				close(terminated)
+				return nil, nil
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- newTestSSMStarter().StartSOCKSProxy(ctx, "i-1234567890", port) }()
+
+		conn := dialEventually(t, port)
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, err := conn.Write([]byte{5, 1, 0})
+		require.NoError(t, err)
+		_, err = conn.Write(append([]byte{5, 1, 0, 3, 12}, append([]byte("api.internal"), 0x01, 0xbb)...))
+		require.NoError(t, err)
+		reply := make([]byte, 12)
+		_, err = io.ReadFull(conn, reply)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{5, 0}, reply[:2], "method selection")
+		assert.Equal(t, byte(0), reply[3], "SOCKS reply code")
+
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(buf))
+		_ = conn.Close()
+		<-terminated
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("StartSOCKSProxy destination unreachable", func(t *testing.T) {
+		port := freeLocalPort(t)
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), gomock.Any()).Return(session, nil)
+		mockChannel.EXPECT().Conn(gomock.Any()).Return(nil, ssmsession.ErrConnectToPort)
+		mockChannel.EXPECT().Close().Return(nil)
+		mockSSMClient.EXPECT().TerminateSession(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- newTestSSMStarter().StartSOCKSProxy(ctx, "i-1234567890", port) }()
+
+		conn := dialEventually(t, port)
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, err := conn.Write([]byte("CONNECT 10.0.1.5:22 HTTP/1.1\r\nHost: 10.0.1.5:22\r\n\r\n"))
+		require.NoError(t, err)
+		status, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "HTTP/1.1 502 Bad Gateway\r\n", status)
+		_ = conn.Close()
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("StartSOCKSProxy session fails", func(t *testing.T) {
+		port := freeLocalPort(t)
+		mockSSMClient.EXPECT().StartSession(gomock.Any(), gomock.Any()).Return(nil, errors.New("TargetNotConnected"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- newTestSSMStarter().StartSOCKSProxy(ctx, "i-1234567890", port) }()
+
+		conn := dialEventually(t, port)
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		_, err := conn.Write([]byte("CONNECT 10.0.1.5:22 HTTP/1.1\r\nHost: 10.0.1.5:22\r\n\r\n"))
+		require.NoError(t, err)
+		status, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "HTTP/1.1 502 Bad Gateway\r\n", status)
+		_ = conn.Close()
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("StartPortForwarding port in use", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+		defer func() { _ = ln.Close() }()
+		port := ln.Addr().(*net.TCPAddr).Port
+
+		err = newTestSSMStarter().StartPortForwarding(context.Background(), "i-1234567890", port, "db.internal", 5432)
+		assert.ErrorContains(t, err, "SSM port forwarding failed")
+	})
+}
+
+func freeLocalPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+	return port
+}
+
+func dialEventually(t *testing.T, port int) net.Conn {
+	t.Helper()
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = net.Dial("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return conn
+}
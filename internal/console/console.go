@@ -0,0 +1,219 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BerryBytes/awsctl/models"
+)
+
+// DefaultIssuer is shown by the console as the origin of the sign-in link.
+const DefaultIssuer = "awsctl"
+
+// partition holds the sign-in and console hosts of an AWS partition.
+type partition struct {
+	signin  string
+	console string
+}
+
+var (
+	awsPartition   = partition{signin: "signin.aws.amazon.com", console: "console.aws.amazon.com"}
+	chinaPartition = partition{signin: "signin.amazonaws.cn", console: "console.amazonaws.cn"}
+	govPartition   = partition{signin: "signin.amazonaws-us-gov.com", console: "console.amazonaws-us-gov.com"}
+)
+
+func partitionFor(region string) partition {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return chinaPartition
+	case strings.HasPrefix(region, "us-gov-"):
+		return govPartition
+	default:
+		return awsPartition
+	}
+}
+
+// SigninEndpoint returns the federation endpoint of the partition of region,
+// e.g. https://signin.aws.amazon.com.
+func SigninEndpoint(region string) string {
+	return "https://" + partitionFor(region).signin
+}
+
+// DestinationURL returns the console page of a service in region, or the
+// console home page when service is empty.
+func DestinationURL(region, service string) string {
+	if service == "" {
+		service = "console"
+	}
+	u := url.URL{
+		Scheme:   "https",
+		Host:     partitionFor(region).console,
+		Path:     "/" + strings.Trim(service, "/") + "/home",
+		RawQuery: url.Values{"region": {region}}.Encode(),
+	}
+	return u.String()
+}
+
+// Federation turns temporary credentials into console sign-in URLs through
+// the federation endpoint's getSigninToken action.
+type Federation struct {
+	// Endpoint is the base URL of the federation endpoint, e.g.
+	// https://signin.aws.amazon.com.
+	Endpoint string
+	// Issuer defaults to DefaultIssuer.
+	Issuer string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// SigninToken exchanges temporary credentials for a sign-in token, which is
+// valid for 15 minutes. Long-term IAM user keys are rejected by the endpoint.
+func (f *Federation) SigninToken(ctx context.Context, creds *models.AWSCredentials) (string, error) {
+	if creds.SessionToken == "" {
+		return "", errors.New("console sign-in needs temporary credentials with a session token")
+	}
+	session, err := json.Marshal(map[string]string{
+		"sessionId":    creds.AccessKeyID,
+		"sessionKey":   creds.SecretAccessKey,
+		"sessionToken": creds.SessionToken,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	query := url.Values{
+		"Action":  {"getSigninToken"},
+		"Session": {string(session)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.federationURL(query), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create sign-in token request: %w", err)
+	}
+
+	resp, err := f.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get sign-in token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read sign-in token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get sign-in token: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		SigninToken string `json:"SigninToken"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("failed to parse sign-in token response: %w", err)
+	}
+	if token.SigninToken == "" {
+		return "", errors.New("failed to get sign-in token: empty response")
+	}
+	return token.SigninToken, nil
+}
+
+// LoginURL returns a URL that signs in to the console with the credentials
+// and opens destination.
+func (f *Federation) LoginURL(ctx context.Context, creds *models.AWSCredentials, destination string) (string, error) {
+	token, err := f.SigninToken(ctx, creds)
+	if err != nil {
+		return "", err
+	}
+	issuer := f.Issuer
+	if issuer == "" {
+		issuer = DefaultIssuer
+	}
+	return f.federationURL(url.Values{
+		"Action":      {"login"},
+		"Issuer":      {issuer},
+		"Destination": {destination},
+		"SigninToken": {token},
+	}), nil
+}
+
+// LogoutURL returns the URL that ends the current console session.
+func (f *Federation) LogoutURL() string {
+	return strings.TrimSuffix(f.Endpoint, "/") + "/oauth?Action=logout"
+}
+
+func (f *Federation) federationURL(query url.Values) string {
+	return strings.TrimSuffix(f.Endpoint, "/") + "/federation?" + query.Encode()
+}
+
+func (f *Federation) httpClient() *http.Client {
+	if f.HTTPClient != nil {
+		return f.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+var logoutFirstPage = template.Must(template.New("logout").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Signing in to the AWS console</title></head>
+<body>
+<p>Signing out of the current console session…</p>
+<iframe src="{{.Logout}}" style="display:none"></iframe>
+<script>setTimeout(function () { window.location.replace({{.Login}}); }, {{.DelayMillis}});</script>
+<noscript><a href="{{.Login}}">Continue to the AWS console</a></noscript>
+</body>
+</html>
+`))
+
+// LogoutFirst serves a page on a loopback port that ends the current console
+// session before redirecting to loginURL, and passes its address to open. The
+// server stops after the page has been served once, or when ctx is done.
+func (f *Federation) LogoutFirst(ctx context.Context, loginURL string, open func(string) error) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start local redirect server: %w", err)
+	}
+
+	served := make(chan struct{})
+	var once sync.Once
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = logoutFirstPage.Execute(w, struct {
+			Logout      string
+			Login       string
+			DelayMillis int
+		}{f.LogoutURL(), loginURL, 2000})
+		once.Do(func() { close(served) })
+	})
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := open("http://" + listener.Addr().String() + "/"); err != nil {
+		return err
+	}
+
+	select {
+	case <-served:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("the sign-in page was not opened: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,134 @@
+package console_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/console"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var roleCreds = &models.AWSCredentials{
+	AccessKeyID:     "ASIA",
+	SecretAccessKey: "secret",
+	SessionToken:    "token",
+}
+
+// federationServer stands in for the federation endpoint and records the
+// session of each getSigninToken request.
+func federationServer(t *testing.T, sessions *[]map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/federation" || r.URL.Query().Get("Action") != "getSigninToken" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var session map[string]string
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("Session")), &session); err != nil {
+			http.Error(w, "invalid session", http.StatusBadRequest)
+			return
+		}
+		*sessions = append(*sessions, session)
+		if session["sessionToken"] == "expired" {
+			http.Error(w, "Signin token could not be created", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"SigninToken":"signin-token"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLoginURL(t *testing.T) {
+	var sessions []map[string]string
+	server := federationServer(t, &sessions)
+	federation := &console.Federation{Endpoint: server.URL + "/"}
+
+	loginURL, err := federation.LoginURL(context.Background(), roleCreds, console.DestinationURL("eu-west-1", "ec2"))
+	require.NoError(t, err)
+
+	require.Len(t, sessions, 1)
+	assert.Equal(t, map[string]string{"sessionId": "ASIA", "sessionKey": "secret", "sessionToken": "token"}, sessions[0])
+
+	u, err := url.Parse(loginURL)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/federation", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, url.Values{
+		"Action":      {"login"},
+		"Issuer":      {"awsctl"},
+		"Destination": {"https://console.aws.amazon.com/ec2/home?region=eu-west-1"},
+		"SigninToken": {"signin-token"},
+	}, u.Query())
+}
+
+func TestSigninToken_Errors(t *testing.T) {
+	var sessions []map[string]string
+	server := federationServer(t, &sessions)
+	federation := &console.Federation{Endpoint: server.URL}
+
+	_, err := federation.SigninToken(context.Background(), &models.AWSCredentials{AccessKeyID: "AKIA", SecretAccessKey: "secret"})
+	assert.EqualError(t, err, "console sign-in needs temporary credentials with a session token")
+	assert.Empty(t, sessions)
+
+	_, err = federation.SigninToken(context.Background(), &models.AWSCredentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SessionToken: "expired"})
+	assert.EqualError(t, err, "failed to get sign-in token: HTTP 400: Signin token could not be created")
+}
+
+func TestDestinationURL(t *testing.T) {
+	tests := []struct {
+		region   string
+		service  string
+		expected string
+	}{
+		{"us-east-1", "", "https://console.aws.amazon.com/console/home?region=us-east-1"},
+		{"eu-west-1", "/s3/", "https://console.aws.amazon.com/s3/home?region=eu-west-1"},
+		{"cn-north-1", "ec2", "https://console.amazonaws.cn/ec2/home?region=cn-north-1"},
+		{"us-gov-west-1", "rds", "https://console.amazonaws-us-gov.com/rds/home?region=us-gov-west-1"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, console.DestinationURL(tt.region, tt.service))
+	}
+
+	assert.Equal(t, "https://signin.aws.amazon.com", console.SigninEndpoint("eu-west-1"))
+	assert.Equal(t, "https://signin.amazonaws.cn", console.SigninEndpoint("cn-northwest-1"))
+	assert.Equal(t, "https://signin.amazonaws-us-gov.com", console.SigninEndpoint("us-gov-east-1"))
+}
+
+func TestLogoutFirst(t *testing.T) {
+	federation := &console.Federation{Endpoint: "https://signin.example.com"}
+	loginURL := "https://signin.example.com/federation?Action=login&SigninToken=signin-token"
+
+	var page string
+	err := federation.LogoutFirst(context.Background(), loginURL, func(localURL string) error {
+		assert.True(t, strings.HasPrefix(localURL, "http://127.0.0.1:"), localURL)
+		resp, err := http.Get(localURL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		page = string(body)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Contains(t, page, `<iframe src="https://signin.example.com/oauth?Action=logout"`)
+	assert.Contains(t, page, `window.location.replace("https://signin.example.com/federation?Action=login\u0026SigninToken=signin-token")`)
+}
+
+func TestLogoutFirst_Timeout(t *testing.T) {
+	federation := &console.Federation{Endpoint: "https://signin.example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := federation.LogoutFirst(ctx, "https://signin.example.com/federation", func(string) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
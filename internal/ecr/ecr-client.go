@@ -5,7 +5,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"path/filepath"
+	"sort"
 
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
@@ -28,6 +30,12 @@ func NewECRClient(cfg aws.Config, fs common.FileSystemInterface, executor common
 }
 
 func (c *AwsECRAdapter) Login(ctx context.Context) error {
+	return c.LoginRegistry(ctx, "")
+}
+
+// LoginRegistry logs docker in to registry, or to the registry of the
+// caller's account when registry is empty.
+func (c *AwsECRAdapter) LoginRegistry(ctx context.Context, registry string) error {
 	if _, err := c.Executor.LookPath("docker"); err != nil {
 		return fmt.Errorf("docker command not found: %w", err)
 	}
@@ -52,7 +60,9 @@ func (c *AwsECRAdapter) Login(ctx context.Context) error {
 	username := "AWS"
 	password := usernamePassword[len(username)+1:]
 
-	registry := *auth.ProxyEndpoint
+	if registry == "" {
+		registry = *auth.ProxyEndpoint
+	}
 	args := []string{"login", "--username", username, "--password-stdin", registry}
 	output, err := c.Executor.RunCommandWithInput("docker", password, args...)
 	if err != nil {
@@ -70,3 +80,32 @@ func (c *AwsECRAdapter) Login(ctx context.Context) error {
 
 	return nil
 }
+
+// ListRepositories returns the repositories of the caller's registry sorted by
+// name.
+func (c *AwsECRAdapter) ListRepositories(ctx context.Context) ([]models.ECRRepository, error) {
+	var repositories []models.ECRRepository
+
+	paginator := ecr.NewDescribeRepositoriesPaginator(c.Client, &ecr.DescribeRepositoriesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe ECR repositories: %w", err)
+		}
+		for _, repo := range page.Repositories {
+			repositories = append(repositories, models.ECRRepository{
+				RepositoryName:     aws.ToString(repo.RepositoryName),
+				RepositoryURI:      aws.ToString(repo.RepositoryUri),
+				RegistryID:         aws.ToString(repo.RegistryId),
+				ImageTagMutability: string(repo.ImageTagMutability),
+				CreatedAt:          aws.ToTime(repo.CreatedAt),
+			})
+		}
+	}
+
+	sort.Slice(repositories, func(i, j int) bool {
+		return repositories[i].RepositoryName < repositories[j].RepositoryName
+	})
+
+	return repositories, nil
+}
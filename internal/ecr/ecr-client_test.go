@@ -5,8 +5,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"testing"
+	"time"
 
 	internalECR "github.com/BerryBytes/awsctl/internal/ecr"
+	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_ecr "github.com/BerryBytes/awsctl/tests/mock/ecr"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -215,3 +217,65 @@ func TestAwsECRAdapter_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAwsECRAdapter_ListRepositories(t *testing.T) {
+	t.Run("pages are merged and sorted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockECRAPI := mock_ecr.NewMockECRAPI(ctrl)
+		created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		gomock.InOrder(
+			mockECRAPI.EXPECT().DescribeRepositories(gomock.Any(), &ecr.DescribeRepositoriesInput{}, gomock.Any()).Return(&ecr.DescribeRepositoriesOutput{
+				Repositories: []types.Repository{{
+					RepositoryName:     aws.String("worker"),
+					RepositoryUri:      aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/worker"),
+					RegistryId:         aws.String("123456789012"),
+					ImageTagMutability: types.ImageTagMutabilityMutable,
+					CreatedAt:          &created,
+				}},
+				NextToken: aws.String("next"),
+			}, nil),
+			mockECRAPI.EXPECT().DescribeRepositories(gomock.Any(), &ecr.DescribeRepositoriesInput{NextToken: aws.String("next")}, gomock.Any()).Return(&ecr.DescribeRepositoriesOutput{
+				Repositories: []types.Repository{{
+					RepositoryName: aws.String("api"),
+					RepositoryUri:  aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/api"),
+					RegistryId:     aws.String("123456789012"),
+				}},
+			}, nil),
+		)
+
+		adapter := &internalECR.AwsECRAdapter{Client: mockECRAPI}
+		repositories, err := adapter.ListRepositories(context.TODO())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.ECRRepository{
+			{
+				RepositoryName: "api",
+				RepositoryURI:  "123456789012.dkr.ecr.us-east-1.amazonaws.com/api",
+				RegistryID:     "123456789012",
+			},
+			{
+				RepositoryName:     "worker",
+				RepositoryURI:      "123456789012.dkr.ecr.us-east-1.amazonaws.com/worker",
+				RegistryID:         "123456789012",
+				ImageTagMutability: "MUTABLE",
+				CreatedAt:          created,
+			},
+		}, repositories)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockECRAPI := mock_ecr.NewMockECRAPI(ctrl)
+		mockECRAPI.EXPECT().DescribeRepositories(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("access denied"))
+
+		adapter := &internalECR.AwsECRAdapter{Client: mockECRAPI}
+		repositories, err := adapter.ListRepositories(context.TODO())
+
+		assert.EqualError(t, err, "failed to describe ECR repositories: access denied")
+		assert.Nil(t, repositories)
+	})
+}
@@ -4,15 +4,36 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 
 	connection "github.com/BerryBytes/awsctl/internal/common"
 	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 )
 
+// Options holds the values given to the ecr subcommands.
+type Options struct {
+	// Registry is the registry host to log in to, e.g.
+	// 123456789012.dkr.ecr.us-east-1.amazonaws.com. When it is empty, the
+	// registry of the caller's account is used.
+	Registry string
+}
+
+var registryRegex = regexp.MustCompile(`^\d{12}\.dkr\.ecr(-fips)?\.([a-z0-9-]+)\.amazonaws\.com(\.cn)?$`)
+
+// RegistryRegion returns the region of an ECR registry host.
+func RegistryRegion(registry string) (string, error) {
+	m := registryRegex.FindStringSubmatch(registry)
+	if m == nil {
+		return "", fmt.Errorf("invalid ECR registry %q: expected <account>.dkr.ecr.<region>.amazonaws.com", registry)
+	}
+	return m[2], nil
+}
+
 type ECRService struct {
 	EPrompter        ECRPromptInterface
 	CPrompter        connection.ConnectionPrompter
@@ -25,6 +46,9 @@ type ECRService struct {
 	ECRClientFactory ECRClientFactory
 	FileSystem       common.FileSystemInterface
 	Executor         common.CommandExecutor
+	// AWSOptions holds the profile and region given on the command line,
+	// which are used instead of prompting for them.
+	AWSOptions connection.AWSOptions
 }
 
 func NewECRService(
@@ -86,6 +110,30 @@ func (s *ECRService) Run() error {
 	}
 }
 
+// Login logs docker in to the registry in opts without asking for
+// confirmation. The region is taken from --region, the registry host or the
+// configuration before it is prompted for.
+func (s *ECRService) Login(opts Options) error {
+	if !s.ConnServices.IsAWSConfigured() {
+		return fmt.Errorf("AWS configuration not found")
+	}
+
+	region := s.AWSOptions.Region
+	if opts.Registry != "" {
+		registryRegion, err := RegistryRegion(opts.Registry)
+		if err != nil {
+			return err
+		}
+		if region == "" {
+			region = registryRegion
+		}
+	}
+	if region == "" && s.ConnProvider != nil {
+		region, _ = s.ConnProvider.GetDefaultRegion()
+	}
+	return s.login(region, opts.Registry)
+}
+
 func (s *ECRService) HandleECRLogin() error {
 	if !s.ConnServices.IsAWSConfigured() {
 		return fmt.Errorf("AWS configuration not found")
@@ -100,21 +148,80 @@ func (s *ECRService) HandleECRLogin() error {
 		return nil
 	}
 
-	defaultRegion := ""
-	if s.ConnProvider != nil {
-		defaultRegion, err = s.ConnProvider.GetDefaultRegion()
-		if err != nil {
-			fmt.Printf("Failed to load default region: %v\n", err)
-			defaultRegion = ""
-		}
+	return s.login(s.AWSOptions.Region, "")
+}
+
+// ListRepositories returns the repositories of the registry of the caller's
+// account. The region is taken from --region or the configuration before it
+// is prompted for.
+func (s *ECRService) ListRepositories() ([]models.ECRRepository, error) {
+	if !s.ConnServices.IsAWSConfigured() {
+		return nil, fmt.Errorf("AWS configuration not found")
+	}
+
+	region := s.AWSOptions.Region
+	if region == "" && s.ConnProvider != nil {
+		region, _ = s.ConnProvider.GetDefaultRegion()
+	}
+	if err := s.newClient(region); err != nil {
+		return nil, err
+	}
+
+	repositories, err := s.ECRClient.ListRepositories(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ECR repositories: %w", err)
+	}
+	return repositories, nil
+}
+
+// login logs docker in to registry, prompting for the region and profile
+// that are not known.
+func (s *ECRService) login(region, registry string) error {
+	if err := s.newClient(region); err != nil {
+		return err
 	}
 
-	region, err := s.CPrompter.PromptForRegion(defaultRegion)
+	var err error
+	if registry != "" {
+		err = s.ECRClient.LoginRegistry(context.TODO(), registry)
+	} else {
+		err = s.ECRClient.Login(context.TODO())
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get region: %w", err)
+		return fmt.Errorf("ECR login failed: %w", err)
 	}
 
-	profile := os.Getenv("AWS_PROFILE")
+	fmt.Println("Successfully logged in to AWS ECR")
+	return nil
+}
+
+// newClient creates the ECR client for region, prompting for the region and
+// profile that are not known.
+func (s *ECRService) newClient(region string) error {
+	var err error
+	if region == "" {
+		defaultRegion := ""
+		if s.ConnProvider != nil {
+			defaultRegion, err = s.ConnProvider.GetDefaultRegion()
+			if err != nil {
+				fmt.Printf("Failed to load default region: %v\n", err)
+				defaultRegion = ""
+			}
+		}
+
+		if defaultRegion == "" && !promptUtils.IsInteractive() {
+			return promptUtils.ErrRegionRequired
+		}
+		region, err = s.CPrompter.PromptForRegion(defaultRegion)
+		if err != nil {
+			return fmt.Errorf("failed to get region: %w", err)
+		}
+	}
+
+	profile := s.AWSOptions.Profile
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
 	if profile == "" {
 		profiles, err := s.AWSClient.ValidProfiles()
 		if err != nil {
@@ -125,6 +232,8 @@ func (s *ECRService) HandleECRLogin() error {
 		}
 		if len(profiles) == 1 {
 			profile = profiles[0]
+		} else if !promptUtils.IsInteractive() {
+			return promptUtils.ErrProfileRequired
 		} else {
 			profile, err = s.Prompt.PromptForSelection("Select AWS profile:", profiles)
 			if err != nil {
@@ -144,12 +253,5 @@ func (s *ECRService) HandleECRLogin() error {
 	if s.ECRClient == nil {
 		s.ECRClient = s.ECRClientFactory.NewECRClient(cfg, s.FileSystem, s.Executor)
 	}
-
-	err = s.ECRClient.Login(context.TODO())
-	if err != nil {
-		return fmt.Errorf("ECR login failed: %w", err)
-	}
-
-	fmt.Println("Successfully logged in to AWS ECR")
 	return nil
 }
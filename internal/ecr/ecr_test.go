@@ -6,9 +6,12 @@ import (
 	"os"
 	"testing"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
 	"github.com/BerryBytes/awsctl/internal/ecr"
+	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_ecr "github.com/BerryBytes/awsctl/tests/mock/ecr"
+	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +34,7 @@ func TestNewECRService(t *testing.T) {
 }
 
 func TestECRService_Run(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(true))
 	tests := []struct {
 		name          string
 		action        ecr.ECRAction
@@ -113,6 +117,7 @@ func TestECRService_Run(t *testing.T) {
 }
 
 func TestECRService_HandleECRLogin(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(true))
 	tests := []struct {
 		name               string
 		awsConfigured      bool
@@ -460,3 +465,102 @@ func TestRealECRClientFactory_NewECRClient(t *testing.T) {
 	assert.NotNil(t, client, "Client should be created")
 	assert.Implements(t, (*ecr.ECRAdapterInterface)(nil), client, "Client should implement ECRAdapterInterface")
 }
+
+func TestECRService_ListRepositories(t *testing.T) {
+	repositories := []models.ECRRepository{{RepositoryName: "api"}}
+
+	tests := []struct {
+		name          string
+		awsConfigured bool
+		listErr       error
+		want          []models.ECRRepository
+		expectedError string
+	}{
+		{
+			name:          "region and profile from options",
+			awsConfigured: true,
+			want:          repositories,
+		},
+		{
+			name:          "list error",
+			awsConfigured: true,
+			listErr:       errors.New("access denied"),
+			expectedError: "failed to list ECR repositories: access denied",
+		},
+		{
+			name:          "AWS not configured",
+			awsConfigured: false,
+			expectedError: "AWS configuration not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConnServices := mock_awsctl.NewMockServicesInterface(ctrl)
+			mockConfigLoader := mock_ecr.NewMockConfigLoader(ctrl)
+			mockECRClientFactory := mock_ecr.NewMockECRClientFactory(ctrl)
+			mockECRClient := mock_ecr.NewMockECRAdapterInterface(ctrl)
+
+			service := &ecr.ECRService{
+				ConnServices:     mockConnServices,
+				ConfigLoader:     mockConfigLoader,
+				ECRClientFactory: mockECRClientFactory,
+				AWSOptions:       connection.AWSOptions{Profile: "prod", Region: "eu-west-1"},
+			}
+
+			mockConnServices.EXPECT().IsAWSConfigured().Return(tt.awsConfigured)
+			if tt.awsConfigured {
+				mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{}, nil)
+				mockECRClientFactory.EXPECT().NewECRClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockECRClient)
+				mockECRClient.EXPECT().ListRepositories(gomock.Any()).Return(tt.want, tt.listErr)
+			}
+
+			got, err := service.ListRepositories()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestECRService_ListRepositories_NotInteractive(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(false))
+	t.Setenv("AWS_PROFILE", "")
+
+	tests := []struct {
+		name    string
+		options connection.AWSOptions
+		wantErr error
+	}{
+		{name: "no region", options: connection.AWSOptions{Profile: "prod"}, wantErr: promptUtils.ErrRegionRequired},
+		{name: "no profile", options: connection.AWSOptions{Region: "eu-west-1"}, wantErr: promptUtils.ErrProfileRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConnServices := mock_awsctl.NewMockServicesInterface(ctrl)
+			mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+			mockAWSClient := mock_ecr.NewMockProfileProvider(ctrl)
+			mockAWSClient.EXPECT().ValidProfiles().Return([]string{"dev", "prod"}, nil).AnyTimes()
+
+			service := &ecr.ECRService{
+				ConnServices: mockConnServices,
+				AWSClient:    mockAWSClient,
+				AWSOptions:   tt.options,
+			}
+
+			_, err := service.ListRepositories()
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package ecr
 import (
 	"context"
 
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -11,10 +12,13 @@ import (
 
 type ECRAPI interface {
 	GetAuthorizationToken(ctx context.Context, params *ecr.GetAuthorizationTokenInput, optFns ...func(*ecr.Options)) (*ecr.GetAuthorizationTokenOutput, error)
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
 }
 
 type ECRAdapterInterface interface {
 	Login(ctx context.Context) error
+	LoginRegistry(ctx context.Context, registry string) error
+	ListRepositories(ctx context.Context) ([]models.ECRRepository, error)
 }
 
 type ConfigLoader interface {
@@ -23,6 +27,8 @@ type ConfigLoader interface {
 
 type ECRServiceInterface interface {
 	Run() error
+	Login(opts Options) error
+	ListRepositories() ([]models.ECRRepository, error)
 }
 
 type ECRClientFactory interface {
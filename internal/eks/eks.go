@@ -15,6 +15,12 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 )
 
+// Options holds the values given to the eks subcommands. Set fields are used
+// instead of prompting for them.
+type Options struct {
+	Cluster string
+}
+
 type EKSService struct {
 	EPrompter        EKSPromptInterface
 	CPrompter        connection.ConnectionPrompter
@@ -24,6 +30,10 @@ type EKSService struct {
 	ConfigLoader     ConfigLoader
 	EKSClientFactory EKSClientFactory
 	FileSystem       common.FileSystemInterface
+	// AWSOptions holds the profile and region given on the command line,
+	// which are used instead of prompting for them.
+	AWSOptions connection.AWSOptions
+	Options    Options
 }
 type RealConfigLoader struct{}
 
@@ -70,6 +80,12 @@ func (s *EKSService) Run() error {
 	return s.HandleKubeconfigUpdate()
 }
 
+// UpdateKubeconfig adds the cluster in opts to the kubeconfig.
+func (s *EKSService) UpdateKubeconfig(opts Options) error {
+	s.Options = opts
+	return s.HandleKubeconfigUpdate()
+}
+
 func (s *EKSService) HandleKubeconfigUpdate() error {
 	cluster, profile, err := s.GetEKSClusterDetails()
 	if err != nil {
@@ -89,33 +105,43 @@ func (s *EKSService) HandleKubeconfigUpdate() error {
 }
 
 func (s *EKSService) GetEKSClusterDetails() (*models.EKSCluster, string, error) {
+	if s.Options.Cluster != "" {
+		return s.lookupCluster(s.Options.Cluster)
+	}
+
 	if !s.ConnServices.IsAWSConfigured() {
 		fmt.Println("AWS configuration not found - falling back to manual input")
 		return s.HandleManualCluster()
 	}
 
-	defaultRegion := ""
-	if s.ConnProvider != nil {
-		var err error
-		defaultRegion, err = s.ConnProvider.GetDefaultRegion()
-		if err != nil {
-			fmt.Printf("Failed to load default region: %v\n", err)
-			defaultRegion = ""
+	var err error
+	region := s.AWSOptions.Region
+	if region == "" {
+		defaultRegion := ""
+		if s.ConnProvider != nil {
+			defaultRegion, err = s.ConnProvider.GetDefaultRegion()
+			if err != nil {
+				fmt.Printf("Failed to load default region: %v\n", err)
+				defaultRegion = ""
+			}
 		}
-	}
 
-	region, err := s.CPrompter.PromptForRegion(defaultRegion)
-	if err != nil {
-		fmt.Printf("Failed to get region: %v\n", err)
-		fmt.Println("Proceeding with manual input")
-		return s.HandleManualCluster()
+		region, err = s.CPrompter.PromptForRegion(defaultRegion)
+		if err != nil {
+			fmt.Printf("Failed to get region: %v\n", err)
+			fmt.Println("Proceeding with manual input")
+			return s.HandleManualCluster()
+		}
 	}
 
-	profile, err := s.EPrompter.PromptForProfile()
-	if err != nil {
-		fmt.Printf("Failed to get AWS profile: %v\n", err)
-		fmt.Println("Proceeding with manual input")
-		return s.HandleManualCluster()
+	profile := s.AWSOptions.Profile
+	if profile == "" {
+		profile, err = s.EPrompter.PromptForProfile()
+		if err != nil {
+			fmt.Printf("Failed to get AWS profile: %v\n", err)
+			fmt.Println("Proceeding with manual input")
+			return s.HandleManualCluster()
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -166,6 +192,100 @@ func (s *EKSService) GetEKSClusterDetails() (*models.EKSCluster, string, error)
 	return nil, "", fmt.Errorf("selected cluster not found")
 }
 
+// lookupCluster returns the details of the EKS cluster name. Without
+// --region, the configured region is used when there is one.
+func (s *EKSService) lookupCluster(name string) (*models.EKSCluster, string, error) {
+	if !s.IsAWSConfigured() {
+		return nil, "", fmt.Errorf("AWS configuration required to look up EKS cluster %s", name)
+	}
+
+	region, profile, err := s.regionAndProfile()
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.newClient(ctx, region, profile); err != nil {
+		return nil, "", err
+	}
+
+	cluster, err := s.EKSClient.GetClusterDetails(ctx, name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get EKS cluster %s: %w", name, err)
+	}
+	return cluster, profile, nil
+}
+
+// ListClusters returns the EKS clusters of the region given with --region, or
+// of the configured region.
+func (s *EKSService) ListClusters() ([]models.EKSCluster, error) {
+	if !s.IsAWSConfigured() {
+		return nil, fmt.Errorf("AWS configuration required to list EKS clusters")
+	}
+
+	region, profile, err := s.regionAndProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.newClient(ctx, region, profile); err != nil {
+		return nil, err
+	}
+
+	clusters, err := s.EKSClient.ListEKSClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EKS clusters: %w", err)
+	}
+	return clusters, nil
+}
+
+// regionAndProfile returns the region and profile given on the command line,
+// falling back to the configuration and then to prompting. Without a terminal
+// to prompt on, a missing region or profile is an error.
+func (s *EKSService) regionAndProfile() (region, profile string, err error) {
+	region = s.AWSOptions.Region
+	if region == "" && s.ConnProvider != nil {
+		region, _ = s.ConnProvider.GetDefaultRegion()
+	}
+	if region == "" {
+		if !promptUtils.IsInteractive() {
+			return "", "", promptUtils.ErrRegionRequired
+		}
+		region, err = s.CPrompter.PromptForRegion("")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get region: %w", err)
+		}
+	}
+
+	profile = s.AWSOptions.Profile
+	if profile == "" {
+		profile, err = s.EPrompter.PromptForProfile()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get AWS profile: %w", err)
+		}
+	}
+	return region, profile, nil
+}
+
+// newClient creates the EKS client for region and profile.
+func (s *EKSService) newClient(ctx context.Context, region, profile string) error {
+	awsCfg, err := s.ConfigLoader.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithSharedConfigProfile(profile),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	s.EKSClient = s.EKSClientFactory.NewEKSClient(awsCfg, s.FileSystem)
+	return nil
+}
+
 func (s *EKSService) HandleManualCluster() (*models.EKSCluster, string, error) {
 	fmt.Println("Please enter EKS cluster details manually")
 	clusterName, endpoint, caData, region, err := s.EPrompter.PromptForManualCluster()
@@ -6,11 +6,13 @@ import (
 	"os"
 	"testing"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
 	"github.com/BerryBytes/awsctl/internal/eks"
 	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_eks "github.com/BerryBytes/awsctl/tests/mock/eks"
 	"github.com/BerryBytes/awsctl/utils/common"
+	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -589,3 +591,75 @@ func TestEKSService_GetEKSClusterDetails_NoClustersFound(t *testing.T) {
 	_, _, err := service.GetEKSClusterDetails()
 	assert.NoError(t, err)
 }
+
+func TestEKSService_ListClusters(t *testing.T) {
+	clusters := []models.EKSCluster{{ClusterName: "prod", Region: "eu-west-1"}}
+
+	tests := []struct {
+		name          string
+		awsConfigured bool
+		listErr       error
+		expectedError string
+	}{
+		{name: "success", awsConfigured: true},
+		{name: "list error", awsConfigured: true, listErr: errors.New("access denied"), expectedError: "failed to list EKS clusters: access denied"},
+		{name: "AWS not configured", expectedError: "AWS configuration required to list EKS clusters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConnServices := mock_awsctl.NewMockServicesInterface(ctrl)
+			mockConfigLoader := mock_eks.NewMockConfigLoader(ctrl)
+			mockEKSClientFactory := mock_eks.NewMockEKSClientFactory(ctrl)
+			mockEKSClient := mock_eks.NewMockEKSAdapterInterface(ctrl)
+
+			service := &eks.EKSService{
+				ConnServices:     mockConnServices,
+				ConfigLoader:     mockConfigLoader,
+				EKSClientFactory: mockEKSClientFactory,
+				AWSOptions:       connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"},
+			}
+
+			mockConnServices.EXPECT().IsAWSConfigured().Return(tt.awsConfigured)
+			if tt.awsConfigured {
+				mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{Region: "eu-west-1"}, nil)
+				mockEKSClientFactory.EXPECT().NewEKSClient(gomock.Any(), gomock.Any()).Return(mockEKSClient)
+				if tt.listErr != nil {
+					mockEKSClient.EXPECT().ListEKSClusters(gomock.Any()).Return(nil, tt.listErr)
+				} else {
+					mockEKSClient.EXPECT().ListEKSClusters(gomock.Any()).Return(clusters, nil)
+				}
+			}
+
+			got, err := service.ListClusters()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, clusters, got)
+		})
+	}
+}
+
+func TestEKSService_ListClusters_NotInteractive(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(false))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnServices := mock_awsctl.NewMockServicesInterface(ctrl)
+	mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+
+	service := &eks.EKSService{
+		ConnServices: mockConnServices,
+		AWSOptions:   connection.AWSOptions{Profile: "prod-admin"},
+	}
+
+	_, err := service.ListClusters()
+	assert.ErrorIs(t, err, promptUtils.ErrRegionRequired)
+}
@@ -12,6 +12,8 @@ import (
 
 type EKSServiceInterface interface {
 	Run() error
+	UpdateKubeconfig(opts Options) error
+	ListClusters() ([]models.EKSCluster, error)
 }
 
 type EKSAPI interface {
@@ -101,6 +101,9 @@ func (p *EPrompter) PromptForProfile() (string, error) {
 		return validProfiles[0], nil
 	}
 
+	if !promptUtils.IsInteractive() {
+		return "", promptUtils.ErrProfileRequired
+	}
 	selectedProfile, err := p.Prompt.PromptForSelection("Select an AWS profile:", validProfiles)
 	if err != nil {
 		return "", err
@@ -212,6 +212,7 @@ func TestPromptForProfile_SingleProfile(t *testing.T) {
 }
 
 func TestPromptForProfile_MultipleProfiles(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(true))
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -236,6 +237,28 @@ func TestPromptForProfile_MultipleProfiles(t *testing.T) {
 	assert.Equal(t, "profile2", profile)
 }
 
+func TestPromptForProfile_NotInteractive(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(false))
+	t.Setenv("AWS_PROFILE", "")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfigClient := mock_sso.NewMockSSOClient(ctrl)
+	mockConfigClient.EXPECT().
+		ValidProfiles().
+		Return([]string{"profile1", "profile2"}, nil)
+
+	prompter := &EPrompter{
+		Prompt:          mock_awsctl.NewMockPrompter(ctrl),
+		AWSConfigClient: mockConfigClient,
+	}
+
+	_, err := prompter.PromptForProfile()
+
+	assert.ErrorIs(t, err, promptUtils.ErrProfileRequired)
+}
+
 func TestPromptForProfile_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ServeDynamic is like ServeSOCKS5, but also accepts HTTP CONNECT requests on
+// the same listener for clients that only speak HTTP proxies. The protocol is
+// told apart by the first byte a client sends.
+func ServeDynamic(ctx context.Context, ln net.Listener, dial DialFunc) error {
+	return Serve(ctx, ln, func(conn net.Conn) {
+		_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+		bc := &bufferedConn{Conn: conn, r: bufio.NewReader(conn)}
+		first, err := bc.r.Peek(1)
+		if err != nil {
+			return
+		}
+
+		if first[0] == socksVersion {
+			err = handleSOCKS5(ctx, bc, dial)
+		} else {
+			err = handleHTTPConnect(ctx, bc, dial)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: proxy connection from %s failed: %v\n", conn.RemoteAddr(), err)
+		}
+	})
+}
+
+func handleHTTPConnect(ctx context.Context, conn *bufferedConn, dial DialFunc) error {
+	req, err := http.ReadRequest(conn.r)
+	if err != nil {
+		return fmt.Errorf("failed to read HTTP request: %w", err)
+	}
+	if req.Method != http.MethodConnect {
+		_ = writeHTTPStatus(conn, http.StatusMethodNotAllowed)
+		return fmt.Errorf("unsupported HTTP method %s", req.Method)
+	}
+
+	target := req.Host
+	remote, err := dial(ctx, "tcp", target)
+	if err != nil {
+		_ = writeHTTPStatus(conn, http.StatusBadGateway)
+		return fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer func() { _ = remote.Close() }()
+
+	if err := writeHTTPStatus(conn, http.StatusOK); err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	Pipe(conn, remote)
+	return nil
+}
+
+func writeHTTPStatus(conn net.Conn, code int) error {
+	_, err := fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\n\r\n", code, http.StatusText(code))
+	return err
+}
+
+// bufferedConn is a connection whose first bytes have been peeked at.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *bufferedConn) CloseWrite() error {
+	if cw, ok := c.Conn.(closeWriter); ok {
+		return cw.CloseWrite()
+	}
+	return c.Conn.Close()
+}
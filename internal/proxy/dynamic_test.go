@@ -0,0 +1,111 @@
+package proxy_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startDynamic(t *testing.T, dial proxy.DialFunc) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- proxy.ServeDynamic(ctx, ln, dial) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	return ln.Addr().String()
+}
+
+// echoDial connects every target to an in-memory echo server and records the
+// targets on dialed.
+func echoDial(dialed chan<- string) proxy.DialFunc {
+	return func(_ context.Context, _, target string) (net.Conn, error) {
+		dialed <- target
+		client, server := net.Pipe()
+		go func() {
+			defer func() { _ = server.Close() }()
+			_, _ = io.Copy(server, server)
+		}()
+		return client, nil
+	}
+}
+
+func httpConnect(t *testing.T, addr, request string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write([]byte(request))
+	require.NoError(t, err)
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	require.NoError(t, err)
+	return conn, r, resp
+}
+
+func TestServeDynamic_HTTPConnect(t *testing.T) {
+	dialed := make(chan string, 1)
+	addr := startDynamic(t, echoDial(dialed))
+
+	conn, r, resp := httpConnect(t, addr, "CONNECT db.internal:5432 HTTP/1.1\r\nHost: db.internal:5432\r\n\r\n")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "db.internal:5432", <-dialed)
+
+	_, err := conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+}
+
+func TestServeDynamic_HTTPErrors(t *testing.T) {
+	t.Run("not CONNECT", func(t *testing.T) {
+		addr := startDynamic(t, echoDial(make(chan string, 1)))
+		_, _, resp := httpConnect(t, addr, "GET http://example.com/ HTTP/1.1\r\nHost: example.com\r\n\r\n")
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+
+	t.Run("dial failure", func(t *testing.T) {
+		addr := startDynamic(t, func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("connect: connection refused")
+		})
+		_, _, resp := httpConnect(t, addr, "CONNECT 10.0.1.5:443 HTTP/1.1\r\nHost: 10.0.1.5:443\r\n\r\n")
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	})
+}
+
+func TestServeDynamic_SOCKS5(t *testing.T) {
+	dialed := make(chan string, 1)
+	addr := startDynamic(t, echoDial(dialed))
+
+	conn, reply := connect(t, addr, []byte{5, 1, 0}, []byte{5, 1, 0, 1, 10, 0, 1, 5, 0x00, 0x50})
+	assert.Equal(t, byte(0), reply[1])
+	assert.Equal(t, "10.0.1.5:80", <-dialed)
+
+	_, err := conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf))
+}
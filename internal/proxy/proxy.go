@@ -0,0 +1,68 @@
+// Package proxy serves local listeners whose connections are carried by a
+// tunnel, such as an SSH connection or an SSM session.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// DialFunc opens a connection to addr through a tunnel.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Serve runs handle for every connection accepted on ln until ctx is done or
+// ln is closed.
+func Serve(ctx context.Context, ln net.Listener, handle func(net.Conn)) error {
+	stop := context.AfterFunc(ctx, func() { _ = ln.Close() })
+	defer stop()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			handle(conn)
+		}()
+	}
+}
+
+// closeWriter is implemented by connections that can be half-closed, such as
+// *net.TCPConn and SSH channels.
+type closeWriter interface {
+	CloseWrite() error
+}
+
+// Pipe copies data between a and b in both directions until both sides are
+// done. When one side reaches EOF, the write half of the other is closed so
+// that the peer sees the end of the stream.
+func Pipe(a, b net.Conn) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		copyAndClose(a, b)
+	}()
+	go func() {
+		defer wg.Done()
+		copyAndClose(b, a)
+	}()
+	wg.Wait()
+}
+
+func copyAndClose(dst, src net.Conn) {
+	_, _ = io.Copy(dst, src)
+	if cw, ok := dst.(closeWriter); ok {
+		_ = cw.CloseWrite()
+		return
+	}
+	_ = dst.Close()
+}
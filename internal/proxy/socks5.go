@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	socksVersion = 0x05
+
+	socksMethodNoAuth       = 0x00
+	socksMethodNoAcceptable = 0xff
+
+	socksCmdConnect = 0x01
+
+	socksAddrIPv4   = 0x01
+	socksAddrDomain = 0x03
+	socksAddrIPv6   = 0x04
+
+	socksReplySucceeded           = 0x00
+	socksReplyGeneralFailure      = 0x01
+	socksReplyCommandNotSupported = 0x07
+	socksReplyAddrNotSupported    = 0x08
+)
+
+// handshakeTimeout bounds how long a client may take to send its SOCKS
+// greeting and request.
+const handshakeTimeout = 30 * time.Second
+
+// ServeSOCKS5 accepts SOCKS5 clients on ln until ctx is done or ln is closed,
+// and connects their CONNECT requests with dial. Only the no-authentication
+// method and the CONNECT command are supported, which is what browsers and
+// curl --socks5-hostname use.
+func ServeSOCKS5(ctx context.Context, ln net.Listener, dial DialFunc) error {
+	return Serve(ctx, ln, func(conn net.Conn) {
+		if err := handleSOCKS5(ctx, conn, dial); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: SOCKS connection from %s failed: %v\n", conn.RemoteAddr(), err)
+		}
+	})
+}
+
+func handleSOCKS5(ctx context.Context, conn net.Conn, dial DialFunc) error {
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	target, err := readSOCKS5Request(conn)
+	if err != nil {
+		return err
+	}
+
+	remote, err := dial(ctx, "tcp", target)
+	if err != nil {
+		_ = writeSOCKS5Reply(conn, socksReplyGeneralFailure)
+		return fmt.Errorf("failed to connect to %s: %w", target, err)
+	}
+	defer func() { _ = remote.Close() }()
+
+	if err := writeSOCKS5Reply(conn, socksReplySucceeded); err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	Pipe(conn, remote)
+	return nil
+}
+
+// readSOCKS5Request negotiates the authentication method and returns the
+// host:port of the client's CONNECT request.
+func readSOCKS5Request(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", fmt.Errorf("failed to read greeting: %w", err)
+	}
+	if header[0] != socksVersion {
+		return "", fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", fmt.Errorf("failed to read authentication methods: %w", err)
+	}
+
+	method := byte(socksMethodNoAcceptable)
+	for _, m := range methods {
+		if m == socksMethodNoAuth {
+			method = socksMethodNoAuth
+		}
+	}
+	if _, err := conn.Write([]byte{socksVersion, method}); err != nil {
+		return "", err
+	}
+	if method == socksMethodNoAcceptable {
+		return "", errors.New("client does not support the no-authentication method")
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", fmt.Errorf("failed to read request: %w", err)
+	}
+	if request[0] != socksVersion {
+		return "", fmt.Errorf("unsupported SOCKS version %d", request[0])
+	}
+	if request[1] != socksCmdConnect {
+		_ = writeSOCKS5Reply(conn, socksReplyCommandNotSupported)
+		return "", fmt.Errorf("unsupported SOCKS command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		size := net.IPv4len
+		if request[3] == socksAddrIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", fmt.Errorf("failed to read address: %w", err)
+		}
+		host = net.IP(ip).String()
+	case socksAddrDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", fmt.Errorf("failed to read address: %w", err)
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", fmt.Errorf("failed to read address: %w", err)
+		}
+		host = string(domain)
+	default:
+		_ = writeSOCKS5Reply(conn, socksReplyAddrNotSupported)
+		return "", fmt.Errorf("unsupported SOCKS address type %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", fmt.Errorf("failed to read port: %w", err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// writeSOCKS5Reply writes a reply with an unspecified bound address, which
+// clients of CONNECT requests ignore.
+func writeSOCKS5Reply(conn net.Conn, reply byte) error {
+	_, err := conn.Write([]byte{socksVersion, reply, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
@@ -0,0 +1,149 @@
+package proxy_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSOCKS5 serves SOCKS5 on a local port with dial and returns its address.
+func startSOCKS5(t *testing.T, dial proxy.DialFunc) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- proxy.ServeSOCKS5(ctx, ln, dial) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	return ln.Addr().String()
+}
+
+func connect(t *testing.T, addr string, greeting, request []byte) (net.Conn, []byte) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write(greeting)
+	require.NoError(t, err)
+	method := make([]byte, 2)
+	_, err = io.ReadFull(conn, method)
+	require.NoError(t, err)
+	if request == nil {
+		return conn, method
+	}
+
+	_, err = conn.Write(request)
+	require.NoError(t, err)
+	reply := make([]byte, 10)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	return conn, reply
+}
+
+func TestServeSOCKS5_Connect(t *testing.T) {
+	tests := []struct {
+		name    string
+		request []byte
+		want    string
+	}{
+		{
+			name:    "domain",
+			request: []byte{5, 1, 0, 3, 10, 'd', 'b', '.', 'i', 'n', 't', 'e', 'r', 'n', 'a', 0x0c, 0xea},
+			want:    "db.interna:3306",
+		},
+		{
+			name:    "ipv4",
+			request: []byte{5, 1, 0, 1, 10, 0, 1, 5, 0x00, 0x50},
+			want:    "10.0.1.5:80",
+		},
+		{
+			name:    "ipv6",
+			request: append(append([]byte{5, 1, 0, 4}, net.ParseIP("fd00::1")...), 0x01, 0xbb),
+			want:    "[fd00::1]:443",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialed := make(chan string, 1)
+			addr := startSOCKS5(t, func(_ context.Context, network, target string) (net.Conn, error) {
+				assert.Equal(t, "tcp", network)
+				dialed <- target
+				client, server := net.Pipe()
+				go func() {
+					defer func() { _ = server.Close() }()
+					_, _ = io.Copy(server, server)
+				}()
+				return client, nil
+			})
+
+			conn, reply := connect(t, addr, []byte{5, 2, 2, 0}, tt.request)
+			assert.Equal(t, byte(0), reply[1])
+			assert.Equal(t, tt.want, <-dialed)
+
+			_, err := conn.Write([]byte("hello"))
+			require.NoError(t, err)
+			buf := make([]byte, 5)
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+			assert.Equal(t, "hello", string(buf))
+		})
+	}
+}
+
+func TestServeSOCKS5_Errors(t *testing.T) {
+	failDial := func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("connect: connection refused")
+	}
+
+	t.Run("no acceptable method", func(t *testing.T) {
+		addr := startSOCKS5(t, failDial)
+		_, method := connect(t, addr, []byte{5, 1, 2}, nil)
+		assert.Equal(t, []byte{5, 0xff}, method)
+	})
+
+	t.Run("unsupported command", func(t *testing.T) {
+		addr := startSOCKS5(t, failDial)
+		_, reply := connect(t, addr, []byte{5, 1, 0}, []byte{5, 2, 0, 1, 127, 0, 0, 1, 0, 80})
+		assert.Equal(t, byte(7), reply[1])
+	})
+
+	t.Run("unsupported address type", func(t *testing.T) {
+		addr := startSOCKS5(t, failDial)
+		_, reply := connect(t, addr, []byte{5, 1, 0}, []byte{5, 1, 0, 9, 0, 0, 0, 0, 0, 0})
+		assert.Equal(t, byte(8), reply[1])
+	})
+
+	t.Run("dial failure", func(t *testing.T) {
+		addr := startSOCKS5(t, failDial)
+		_, reply := connect(t, addr, []byte{5, 1, 0}, []byte{5, 1, 0, 1, 127, 0, 0, 1, 0, 80})
+		assert.Equal(t, byte(1), reply[1])
+	})
+}
+
+func TestServe_StopsOnClose(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- proxy.Serve(context.Background(), ln, func(net.Conn) {}) }()
+
+	require.NoError(t, ln.Close())
+	assert.NoError(t, <-done)
+}
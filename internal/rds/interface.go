@@ -12,6 +12,9 @@ import (
 
 type RDSServiceInterface interface {
 	Run() error
+	Connect(opts Options) error
+	Tunnel(opts Options) error
+	ListInstances() ([]models.RDSInstance, error)
 }
 
 type RDSAPI interface {
@@ -100,6 +100,7 @@ func TestPromptForProfile_FromEnv(t *testing.T) {
 }
 
 func TestPromptForProfile_FromConfig(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(true))
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -124,6 +125,23 @@ func TestPromptForProfile_FromConfig(t *testing.T) {
 	assert.Equal(t, "profile2", profile)
 }
 
+func TestPromptForProfile_NotInteractive(t *testing.T) {
+	t.Cleanup(promptUtils.SetInteractive(false))
+	t.Setenv("AWS_PROFILE", "")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPrompter := mock_awsctl.NewMockPrompter(ctrl)
+	mockConfigClient := mock_sso.NewMockSSOClient(ctrl)
+	mockConfigClient.EXPECT().ValidProfiles().Return([]string{"profile1", "profile2"}, nil)
+
+	prompter := rds.NewRPrompter(mockPrompter, mockConfigClient)
+	_, err := prompter.PromptForProfile()
+
+	assert.ErrorIs(t, err, promptUtils.ErrProfileRequired)
+}
+
 func TestPromptForProfile_Error(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -16,6 +16,7 @@ import (
 
 	connection "github.com/BerryBytes/awsctl/internal/common"
 	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,6 +24,27 @@ import (
 	"github.com/spf13/afero"
 )
 
+const (
+	AuthToken    = "Token"
+	AuthPassword = "Native password"
+)
+
+// Options holds the values given to the rds subcommands. Set fields are used
+// instead of prompting for them.
+type Options struct {
+	// DB is the identifier of the RDS instance or cluster.
+	DB   string
+	User string
+	// Auth is AuthToken or AuthPassword.
+	Auth      string
+	LocalPort int
+	// CACert is the path of the RDS CA bundle used with token auth. When it
+	// is empty, DownloadCACert downloads the bundle instead of prompting.
+	CACert         string
+	DownloadCACert bool
+	Bastion        connection.ConnectOptions
+}
+
 type RDSService struct {
 	RPrompter           RDSPromptInterface
 	CPrompter           connection.ConnectionPrompter
@@ -37,6 +59,10 @@ type RDSService struct {
 	ConfigLoader        ConfigLoader
 	RDSClientFactory    RDSClientFactory
 	TerminateSOCKSProxy func(port int, protocol string) error
+	// AWSOptions holds the profile and region given on the command line,
+	// which are used instead of prompting for them.
+	AWSOptions connection.AWSOptions
+	Options    Options
 }
 
 type RealConfigLoader struct{}
@@ -105,6 +131,22 @@ func (s *RDSService) Run() error {
 	}
 }
 
+// Connect prints the endpoint and an IAM auth token for the database in opts.
+func (s *RDSService) Connect(opts Options) error {
+	s.Options = opts
+	return s.HandleDirectConnection()
+}
+
+// Tunnel forwards a local port to the database in opts through a bastion
+// host until it is interrupted.
+func (s *RDSService) Tunnel(opts Options) error {
+	s.Options = opts
+	if s.ConnServices != nil {
+		s.ConnServices.UseConnectOptions(opts.Bastion)
+	}
+	return s.HandleTunnelConnection()
+}
+
 func (s *RDSService) HandleDirectConnection() error {
 	endpoint, dbUser, region, err := s.GetRDSConnectionDetails()
 	if err != nil {
@@ -126,9 +168,13 @@ func (s *RDSService) HandleDirectConnection() error {
 }
 
 func (s *RDSService) HandleTunnelConnection() error {
-	authMethod, err := s.RPrompter.PromptForAuthMethod("Select authentication method for RDS:", []string{"Token", "Native password"})
-	if err != nil {
-		return fmt.Errorf("failed to get authentication method: %w", err)
+	authMethod := s.Options.Auth
+	if authMethod == "" {
+		var err error
+		authMethod, err = s.RPrompter.PromptForAuthMethod("Select authentication method for RDS:", []string{AuthToken, AuthPassword})
+		if err != nil {
+			return fmt.Errorf("failed to get authentication method: %w", err)
+		}
 	}
 	rdsEndpoint, dbUser, region, err := s.GetRDSConnectionDetails()
 	if err != nil {
@@ -145,16 +191,19 @@ func (s *RDSService) HandleTunnelConnection() error {
 		return fmt.Errorf("invalid port in RDS endpoint: %w", err)
 	}
 
-	localPort, err := s.CPrompter.PromptForLocalPort("RDS", 3306)
-	if err != nil {
-		return fmt.Errorf("failed to get local port: %w", err)
+	localPort := s.Options.LocalPort
+	if localPort == 0 {
+		localPort, err = s.CPrompter.PromptForLocalPort("RDS", 3306)
+		if err != nil {
+			return fmt.Errorf("failed to get local port: %w", err)
+		}
 	}
 
 	var tempFiles []common.TempFile
 	var rdsCleanup func()
 	var mysqlCommand string
 
-	if authMethod == "Token" {
+	if authMethod == AuthToken {
 		authToken, err := s.RDSClient.GenerateAuthToken(rdsEndpoint, dbUser, region)
 		if err != nil {
 			return fmt.Errorf("failed to generate RDS auth token: %w", err)
@@ -209,7 +258,7 @@ ssl-ca=%s
 	fmt.Printf(" - Port: %d\n", localPort)
 	fmt.Printf(" - User: %s\n", dbUser)
 
-	if authMethod == "Token" {
+	if authMethod == AuthToken {
 		fmt.Print(mysqlCommand)
 		fmt.Println("\nNote: This temporary configuration will be deleted when port forwarding ends.")
 	} else {
@@ -243,6 +292,10 @@ ssl-ca=%s
 }
 
 func (s *RDSService) GetRDSConnectionDetails() (endpoint, dbUser, region string, err error) {
+	if s.Options.DB != "" {
+		return s.lookupConnectionDetails(s.Options.DB)
+	}
+
 	if !s.isAWSConfigured() {
 		fmt.Println("AWS configuration not found - falling back to manual connection")
 		return s.handleManualConnection()
@@ -253,27 +306,33 @@ func (s *RDSService) GetRDSConnectionDetails() (endpoint, dbUser, region string,
 		return s.handleManualConnection()
 	}
 
-	defaultRegion := ""
-	if s.ConnProvider != nil {
-		defaultRegion, err = s.ConnProvider.GetDefaultRegion()
-		if err != nil {
-			fmt.Printf("Failed to load default region: %v\n", err)
-			defaultRegion = ""
+	region = s.AWSOptions.Region
+	if region == "" {
+		defaultRegion := ""
+		if s.ConnProvider != nil {
+			defaultRegion, err = s.ConnProvider.GetDefaultRegion()
+			if err != nil {
+				fmt.Printf("Failed to load default region: %v\n", err)
+				defaultRegion = ""
+			}
 		}
-	}
 
-	region, err = s.CPrompter.PromptForRegion(defaultRegion)
-	if err != nil {
-		fmt.Printf("Failed to get region: %v\n", err)
-		fmt.Println("Proceeding with manual connection")
-		return s.handleManualConnection()
+		region, err = s.CPrompter.PromptForRegion(defaultRegion)
+		if err != nil {
+			fmt.Printf("Failed to get region: %v\n", err)
+			fmt.Println("Proceeding with manual connection")
+			return s.handleManualConnection()
+		}
 	}
 
-	profile, err := s.RPrompter.PromptForProfile()
-	if err != nil {
-		fmt.Printf("Failed to get AWS profile: %v\n", err)
-		fmt.Println("Proceeding with manual connection")
-		return s.handleManualConnection()
+	profile := s.AWSOptions.Profile
+	if profile == "" {
+		profile, err = s.RPrompter.PromptForProfile()
+		if err != nil {
+			fmt.Printf("Failed to get AWS profile: %v\n", err)
+			fmt.Println("Proceeding with manual connection")
+			return s.handleManualConnection()
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -322,10 +381,113 @@ func (s *RDSService) GetRDSConnectionDetails() (endpoint, dbUser, region string,
 		return "", "", "", err
 	}
 
-	dbUser, err = s.GPrompter.PromptForInput("Enter database username:", "")
+	dbUser, err = s.dbUser()
+	return endpoint, dbUser, region, err
+}
+
+// lookupConnectionDetails returns the endpoint of the RDS instance or cluster
+// db. Without --region, the configured region is used when there is one.
+func (s *RDSService) lookupConnectionDetails(db string) (endpoint, dbUser, region string, err error) {
+	if !s.isAWSConfigured() {
+		return "", "", "", fmt.Errorf("AWS configuration required to look up RDS instance %s", db)
+	}
+
+	region, profile, err := s.regionAndProfile()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.newClient(ctx, region, profile); err != nil {
+		return "", "", "", err
+	}
+
+	endpoint, err = s.RDSClient.GetConnectionEndpoint(ctx, db)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get endpoint of %s: %w", db, err)
+	}
+
+	dbUser, err = s.dbUser()
 	return endpoint, dbUser, region, err
 }
 
+// ListInstances returns the RDS instances and clusters of the region given
+// with --region, or of the configured region.
+func (s *RDSService) ListInstances() ([]models.RDSInstance, error) {
+	if !s.isAWSConfigured() {
+		return nil, fmt.Errorf("AWS configuration required to list RDS instances")
+	}
+
+	region, profile, err := s.regionAndProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.newClient(ctx, region, profile); err != nil {
+		return nil, err
+	}
+
+	instances, err := s.RDSClient.ListRDSResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list RDS instances: %w", err)
+	}
+	return instances, nil
+}
+
+// regionAndProfile returns the region and profile given on the command line,
+// falling back to the configuration and then to prompting. Without a terminal
+// to prompt on, a missing region or profile is an error.
+func (s *RDSService) regionAndProfile() (region, profile string, err error) {
+	region = s.AWSOptions.Region
+	if region == "" && s.ConnProvider != nil {
+		region, _ = s.ConnProvider.GetDefaultRegion()
+	}
+	if region == "" {
+		if !promptUtils.IsInteractive() {
+			return "", "", promptUtils.ErrRegionRequired
+		}
+		region, err = s.CPrompter.PromptForRegion("")
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get region: %w", err)
+		}
+	}
+
+	profile = s.AWSOptions.Profile
+	if profile == "" {
+		profile, err = s.RPrompter.PromptForProfile()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get AWS profile: %w", err)
+		}
+	}
+	return region, profile, nil
+}
+
+// newClient creates the RDS client for region and profile.
+func (s *RDSService) newClient(ctx context.Context, region, profile string) error {
+	awsCfg, err := s.ConfigLoader.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithSharedConfigProfile(profile),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	s.RDSClient = s.RDSClientFactory.NewRDSClient(awsCfg, &common.RealCommandExecutor{})
+	return nil
+}
+
+func (s *RDSService) dbUser() (string, error) {
+	if s.Options.User != "" {
+		return s.Options.User, nil
+	}
+	return s.GPrompter.PromptForInput("Enter database username:", "")
+}
+
 func (s *RDSService) handleManualConnection() (string, string, string, error) {
 	fmt.Println("Please enter connection details manually")
 	// here endpoint will also have port(host:port)
@@ -349,6 +511,20 @@ func (s *RDSService) CleanupSOCKS() error {
 }
 
 func (s *RDSService) HandleSSLCertificate(region string) (string, error) {
+	if s.Options.CACert != "" {
+		if _, err := s.Fs.Stat(s.Options.CACert); err != nil {
+			return "", fmt.Errorf("certificate not found at %s: %w", s.Options.CACert, err)
+		}
+		return s.Options.CACert, nil
+	}
+	if s.Options.DownloadCACert {
+		certPath, err := DownloadSSLCertificate(s, region)
+		if err != nil {
+			return "", fmt.Errorf("failed to download certificate: %w", err)
+		}
+		return certPath, nil
+	}
+
 	downloadChoice, err := s.GPrompter.PromptForSelection(
 		"To connect securely, an RDS SSL certificate is required:",
 		[]string{"Download certificate automatically", "Provide custom certificate path"},
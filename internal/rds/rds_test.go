@@ -14,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
 	"github.com/BerryBytes/awsctl/internal/rds"
 	"github.com/BerryBytes/awsctl/models"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
@@ -693,6 +694,31 @@ func TestHandleSSLCertificate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, certPath, path)
 	})
+
+	t.Run("CACertOptionSkipsPrompt", func(t *testing.T) {
+		certPath := "/tmp/rds-cert.pem"
+		svc.Options = rds.Options{CACert: certPath}
+		defer func() { svc.Options = rds.Options{} }()
+
+		mockFs.EXPECT().Stat(certPath).Return(&mockFileInfo{name: "rds-cert.pem"}, nil)
+
+		path, err := svc.HandleSSLCertificate(region)
+		assert.NoError(t, err)
+		assert.Equal(t, certPath, path)
+	})
+
+	t.Run("CACertOptionNotFound", func(t *testing.T) {
+		certPath := "/tmp/nonexistent-cert.pem"
+		svc.Options = rds.Options{CACert: certPath}
+		defer func() { svc.Options = rds.Options{} }()
+
+		mockFs.EXPECT().Stat(certPath).Return(nil, os.ErrNotExist)
+
+		path, err := svc.HandleSSLCertificate(region)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("certificate not found at %s", certPath))
+		assert.Empty(t, path)
+	})
 }
 
 func TestDownloadSSLCertificate(t *testing.T) {
@@ -1077,6 +1103,123 @@ func TestRDSService_GetConnectionDetails(t *testing.T) {
 		assert.Equal(t, "us-east-1", region)
 	})
 
+	t.Run("AWSOptionsSkipPrompts", func(t *testing.T) {
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+		mockConnPrompter.EXPECT().PromptForConfirmation("Look for RDS instances in AWS?").Return(true, nil)
+
+		mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{
+			Region: "eu-west-1",
+			Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+				return aws.Credentials{AccessKeyID: "test"}, nil
+			}),
+		}, nil)
+		mockRDSClientFactory.EXPECT().NewRDSClient(gomock.Any(), gomock.Any()).Return(mockRDSClient)
+		mockRDSClient.EXPECT().ListRDSResources(gomock.Any()).Return([]models.RDSInstance{
+			{DBInstanceIdentifier: "test-rds"},
+		}, nil)
+		mockRPrompter.EXPECT().PromptForRDSInstance(gomock.Any()).Return("test-rds", nil)
+		mockRDSClient.EXPECT().GetConnectionEndpoint(gomock.Any(), "test-rds").Return("test-rds:5432", nil)
+		mockGPrompter.EXPECT().PromptForInput("Enter database username:", "").Return("test-user", nil)
+
+		endpoint, dbUser, region, err := svc.GetRDSConnectionDetails()
+		assert.NoError(t, err)
+		assert.Equal(t, "test-rds:5432", endpoint)
+		assert.Equal(t, "test-user", dbUser)
+		assert.Equal(t, "eu-west-1", region)
+	})
+
+	t.Run("DBOptionSkipsLookup", func(t *testing.T) {
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+		svc.Options = rds.Options{DB: "orders", User: "app"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+		mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{Region: "eu-west-1"}, nil)
+		mockRDSClientFactory.EXPECT().NewRDSClient(gomock.Any(), gomock.Any()).Return(mockRDSClient)
+		mockRDSClient.EXPECT().GetConnectionEndpoint(gomock.Any(), "orders").Return("orders.abc.eu-west-1.rds.amazonaws.com:3306", nil)
+
+		endpoint, dbUser, region, err := svc.GetRDSConnectionDetails()
+		assert.NoError(t, err)
+		assert.Equal(t, "orders.abc.eu-west-1.rds.amazonaws.com:3306", endpoint)
+		assert.Equal(t, "app", dbUser)
+		assert.Equal(t, "eu-west-1", region)
+	})
+
+	t.Run("DBOptionEndpointError", func(t *testing.T) {
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+		svc.Options = rds.Options{DB: "missing"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+		mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{Region: "eu-west-1"}, nil)
+		mockRDSClientFactory.EXPECT().NewRDSClient(gomock.Any(), gomock.Any()).Return(mockRDSClient)
+		mockRDSClient.EXPECT().GetConnectionEndpoint(gomock.Any(), "missing").Return("", errors.New("DBInstanceNotFound"))
+
+		_, _, _, err := svc.GetRDSConnectionDetails()
+		assert.EqualError(t, err, "failed to get endpoint of missing: DBInstanceNotFound")
+	})
+
+	t.Run("DBOptionAWSNotConfigured", func(t *testing.T) {
+		svc := newService(nil)
+		svc.Options = rds.Options{DB: "orders"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(false)
+
+		_, _, _, err := svc.GetRDSConnectionDetails()
+		assert.EqualError(t, err, "AWS configuration required to look up RDS instance orders")
+	})
+
+	t.Run("ListInstances", func(t *testing.T) {
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+		instances := []models.RDSInstance{{DBInstanceIdentifier: "orders", Engine: "postgres"}}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+		mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{Region: "eu-west-1"}, nil)
+		mockRDSClientFactory.EXPECT().NewRDSClient(gomock.Any(), gomock.Any()).Return(mockRDSClient)
+		mockRDSClient.EXPECT().ListRDSResources(gomock.Any()).Return(instances, nil)
+
+		got, err := svc.ListInstances()
+		assert.NoError(t, err)
+		assert.Equal(t, instances, got)
+	})
+
+	t.Run("ListInstancesError", func(t *testing.T) {
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin", Region: "eu-west-1"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+		mockConfigLoader.EXPECT().LoadDefaultConfig(gomock.Any(), gomock.Any()).Return(aws.Config{Region: "eu-west-1"}, nil)
+		mockRDSClientFactory.EXPECT().NewRDSClient(gomock.Any(), gomock.Any()).Return(mockRDSClient)
+		mockRDSClient.EXPECT().ListRDSResources(gomock.Any()).Return(nil, errors.New("access denied"))
+
+		_, err := svc.ListInstances()
+		assert.EqualError(t, err, "failed to list RDS instances: access denied")
+	})
+
+	t.Run("ListInstancesAWSNotConfigured", func(t *testing.T) {
+		svc := newService(nil)
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(false)
+
+		_, err := svc.ListInstances()
+		assert.EqualError(t, err, "AWS configuration required to list RDS instances")
+	})
+
+	t.Run("ListInstancesNotInteractive", func(t *testing.T) {
+		t.Cleanup(promptUtils.SetInteractive(false))
+		svc := newService(nil)
+		svc.AWSOptions = connection.AWSOptions{Profile: "prod-admin"}
+
+		mockConnServices.EXPECT().IsAWSConfigured().Return(true)
+
+		_, err := svc.ListInstances()
+		assert.ErrorIs(t, err, promptUtils.ErrRegionRequired)
+	})
+
 	t.Run("AWSNotConfigured", func(t *testing.T) {
 		svc := newService(nil)
 
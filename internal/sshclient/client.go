@@ -0,0 +1,278 @@
+// Package sshclient connects to bastion hosts with golang.org/x/crypto/ssh
+// instead of running the system ssh binary.
+package sshclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/proxy"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	defaultPort      = "22"
+	defaultTimeout   = 30 * time.Second
+	defaultKeepAlive = 60 * time.Second
+)
+
+// Config describes how to reach and authenticate to an SSH server.
+type Config struct {
+	// Host is a hostname or address, optionally with a port.
+	Host    string
+	User    string
+	KeyPath string
+
+	HostKeyPolicy HostKeyPolicy
+	// KnownHostsFile defaults to ~/.ssh/known_hosts.
+	KnownHostsFile string
+	// ConfirmHostKey is asked whether to trust a host that is not in
+	// KnownHostsFile. Unknown hosts are rejected when it is nil.
+	ConfirmHostKey func(host, fingerprint string) (bool, error)
+
+	// Timeout bounds connecting and the handshake. Defaults to 30s.
+	Timeout time.Duration
+	// KeepAlive is the interval between keepalive requests. Defaults to 60s;
+	// a negative value disables them.
+	KeepAlive time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.KeepAlive == 0 {
+		c.KeepAlive = defaultKeepAlive
+	}
+	if c.KnownHostsFile == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			c.KnownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+		}
+	}
+	return c
+}
+
+func (c Config) address() string {
+	if _, _, err := net.SplitHostPort(c.Host); err == nil {
+		return c.Host
+	}
+	return net.JoinHostPort(strings.Trim(c.Host, "[]"), defaultPort)
+}
+
+func (c Config) host() string {
+	if host, _, err := net.SplitHostPort(c.Host); err == nil {
+		return host
+	}
+	return c.Host
+}
+
+// Client is an open SSH connection.
+type Client struct {
+	client *ssh.Client
+	host   string
+	done   chan struct{}
+}
+
+// Dial connects and authenticates to the server in cfg with the key at
+// KeyPath and any identities held by ssh-agent. Failures are reported as
+// *AuthError, *HostKeyError or *NetworkError.
+func Dial(ctx context.Context, cfg Config) (*Client, error) {
+	cfg = cfg.withDefaults()
+	addr := cfg.address()
+
+	signers, closeAgent, err := cfg.signers()
+	if err != nil {
+		return nil, err
+	}
+	defer closeAgent()
+
+	var hostKeyErr error
+	hostKeyCallback, algorithms, err := cfg.hostKeyCallback(addr, &hostKeyErr)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfig := &ssh.ClientConfig{
+		User:              cfg.User,
+		Auth:              []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: algorithms,
+		Timeout:           cfg.Timeout,
+	}
+
+	dialer := net.Dialer{Timeout: cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, &NetworkError{Host: cfg.host(), Err: err}
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(cfg.Timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, clientConfig)
+	stop()
+	if err != nil {
+		_ = conn.Close()
+		switch {
+		case hostKeyErr != nil:
+			return nil, hostKeyErr
+		case strings.Contains(err.Error(), "unable to authenticate"):
+			return nil, &AuthError{User: cfg.User, KeyPath: cfg.KeyPath, Err: err}
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		default:
+			return nil, &NetworkError{Host: cfg.host(), Err: err}
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	client := &Client{
+		client: ssh.NewClient(sshConn, chans, reqs),
+		host:   cfg.host(),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		_ = client.client.Wait()
+		close(client.done)
+	}()
+	if cfg.KeepAlive > 0 {
+		go client.keepAlive(cfg.KeepAlive)
+	}
+
+	return client, nil
+}
+
+// signers loads the key at KeyPath and the identities of ssh-agent. The
+// returned func closes the agent connection.
+func (c Config) signers() ([]ssh.Signer, func(), error) {
+	var signers []ssh.Signer
+	var encrypted bool
+	closeAgent := func() {}
+
+	if c.KeyPath != "" {
+		pemBytes, err := os.ReadFile(c.KeyPath)
+		if err != nil {
+			return nil, closeAgent, &AuthError{User: c.User, KeyPath: c.KeyPath, Err: err}
+		}
+		signer, err := ssh.ParsePrivateKey(pemBytes)
+		var passphraseErr *ssh.PassphraseMissingError
+		switch {
+		case errors.As(err, &passphraseErr):
+			// Encrypted keys are only usable through ssh-agent.
+			encrypted = true
+		case err != nil:
+			return nil, closeAgent, &AuthError{User: c.User, KeyPath: c.KeyPath, Err: fmt.Errorf("failed to parse SSH key: %w", err)}
+		default:
+			signers = append(signers, signer)
+		}
+	}
+
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			closeAgent = func() { _ = conn.Close() }
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, agentSigners...)
+			}
+		}
+	}
+
+	if len(signers) == 0 {
+		closeAgent()
+		err := errors.New("no usable SSH key or ssh-agent identity")
+		if encrypted {
+			err = errors.New("key is passphrase protected; add it to ssh-agent")
+		}
+		return nil, func() {}, &AuthError{User: c.User, KeyPath: c.KeyPath, Err: err}
+	}
+	return signers, closeAgent, nil
+}
+
+func (c *Client) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if _, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				_ = c.client.Close()
+				return
+			}
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// DialContext opens a connection to addr from the server, like a single
+// -L or -D request.
+func (c *Client) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return c.client.DialContext(ctx, network, addr)
+}
+
+// Forward accepts connections on ln and forwards each one to remoteAddr
+// through the server, like ssh -L. It returns when ctx is done, ln is closed
+// or the connection to the server is lost.
+func (c *Client) Forward(ctx context.Context, ln net.Listener, remoteAddr string) error {
+	serveCtx, cancel := c.untilClosed(ctx)
+	defer cancel()
+
+	err := proxy.Serve(serveCtx, ln, func(conn net.Conn) {
+		remote, err := c.DialContext(serveCtx, "tcp", remoteAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to forward connection to %s: %v\n", remoteAddr, err)
+			return
+		}
+		defer func() { _ = remote.Close() }()
+		proxy.Pipe(conn, remote)
+	})
+	return c.checkLost(ctx, err)
+}
+
+// SOCKS runs a SOCKS5 proxy on ln that opens its connections through the
+// server, like ssh -D. It returns when ctx is done, ln is closed or the
+// connection to the server is lost.
+func (c *Client) SOCKS(ctx context.Context, ln net.Listener) error {
+	serveCtx, cancel := c.untilClosed(ctx)
+	defer cancel()
+
+	return c.checkLost(ctx, proxy.ServeSOCKS5(serveCtx, ln, c.DialContext))
+}
+
+// checkLost reports a lost connection to the server as the reason a
+// listener stopped, unless ctx was cancelled by the caller.
+func (c *Client) checkLost(ctx context.Context, err error) error {
+	if err != nil || ctx.Err() != nil {
+		return err
+	}
+	select {
+	case <-c.done:
+		return fmt.Errorf("SSH connection to %s was lost", c.host)
+	default:
+		return nil
+	}
+}
+
+// untilClosed returns a context that is also cancelled when the connection
+// to the server is lost.
+func (c *Client) untilClosed(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
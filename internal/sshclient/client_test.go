@@ -0,0 +1,262 @@
+package sshclient_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sshclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func dial(t *testing.T, cfg sshclient.Config) (*sshclient.Client, error) {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+	if cfg.KnownHostsFile == "" {
+		cfg.KnownHostsFile = filepath.Join(t.TempDir(), "known_hosts")
+	}
+	cfg.Timeout = 5 * time.Second
+
+	client, err := sshclient.Dial(context.Background(), cfg)
+	if client != nil {
+		t.Cleanup(func() { _ = client.Close() })
+	}
+	return client, err
+}
+
+func TestForward(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+	echo := newEchoServer(t)
+
+	client, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- client.Forward(ctx, ln, echo) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	assertEcho(t, conn, "ping")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestSOCKS(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+	echo := newEchoServer(t)
+
+	client, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = client.SOCKS(ctx, ln) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	host, portStr, _ := net.SplitHostPort(echo)
+	port, _ := strconv.Atoi(portStr)
+	request := []byte{5, 1, 0, 5, 1, 0, 1}
+	request = append(request, net.ParseIP(host).To4()...)
+	request = append(request, byte(port>>8), byte(port))
+	_, err = conn.Write(request)
+	require.NoError(t, err)
+
+	reply := make([]byte, 12)
+	_, err = io.ReadFull(conn, reply)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), reply[3], "SOCKS reply code")
+
+	assertEcho(t, conn, "ping")
+}
+
+func TestForward_ConnectionLost(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+
+	client, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- client.Forward(context.Background(), ln, "127.0.0.1:1") }()
+
+	require.NoError(t, client.Close())
+	select {
+	case err := <-done:
+		assert.EqualError(t, err, "SSH connection to 127.0.0.1 was lost")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Forward did not return after the connection was closed")
+	}
+}
+
+func TestShell(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+
+	client, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	err = client.Shell(context.Background(), strings.NewReader("ls\n"), &stdout, io.Discard)
+	require.NoError(t, err, "remote exit status should not be an error")
+	assert.Equal(t, "welcome\nls\n", stdout.String())
+	assert.Empty(t, srv.PTY(), "no PTY without a terminal")
+}
+
+func TestDial_AuthError(t *testing.T) {
+	keyPath, _ := writeKey(t)
+	_, other := writeKey(t)
+	srv := newTestServer(t, other)
+
+	_, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+
+	var authErr *sshclient.AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Contains(t, err.Error(), "SSH authentication failed: invalid SSH key at "+keyPath+" or key not authorized on server")
+}
+
+func TestDial_MissingKey(t *testing.T) {
+	_, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+
+	_, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: filepath.Join(t.TempDir(), "missing"), HostKeyPolicy: sshclient.HostKeyInsecure})
+
+	var authErr *sshclient.AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestDial_EncryptedKeyWithoutAgent(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte("secret"))
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(block), 0600))
+
+	_, err = dial(t, sshclient.Config{Host: "127.0.0.1:1", User: "ec2-user", KeyPath: keyPath})
+
+	var authErr *sshclient.AuthError
+	require.ErrorAs(t, err, &authErr)
+	assert.Contains(t, err.Error(), "passphrase protected")
+}
+
+func TestDial_HostKeyMismatch(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(srv.Addr)}, newSigner(t).PublicKey())
+	require.NoError(t, os.WriteFile(knownHosts, []byte(line+"\n"), 0600))
+
+	_, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, KnownHostsFile: knownHosts})
+
+	var hostKeyErr *sshclient.HostKeyError
+	require.ErrorAs(t, err, &hostKeyErr)
+	assert.False(t, hostKeyErr.Unknown)
+	assert.EqualError(t, err, "host key verification failed for 127.0.0.1 (try removing the host from known_hosts file)")
+}
+
+func TestDial_UnknownHost(t *testing.T) {
+	keyPath, pub := writeKey(t)
+	srv := newTestServer(t, pub)
+	knownHosts := filepath.Join(t.TempDir(), ".ssh", "known_hosts")
+
+	t.Run("rejected without confirmation", func(t *testing.T) {
+		_, err := dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, KnownHostsFile: knownHosts})
+
+		var hostKeyErr *sshclient.HostKeyError
+		require.ErrorAs(t, err, &hostKeyErr)
+		assert.True(t, hostKeyErr.Unknown)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		_, err := dial(t, sshclient.Config{
+			Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, KnownHostsFile: knownHosts,
+			ConfirmHostKey: func(string, string) (bool, error) { return false, nil },
+		})
+
+		var hostKeyErr *sshclient.HostKeyError
+		require.ErrorAs(t, err, &hostKeyErr)
+		assert.NoFileExists(t, knownHosts)
+	})
+
+	t.Run("accepted and remembered", func(t *testing.T) {
+		var fingerprint string
+		_, err := dial(t, sshclient.Config{
+			Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, KnownHostsFile: knownHosts,
+			ConfirmHostKey: func(host, fp string) (bool, error) {
+				assert.Equal(t, "127.0.0.1", host)
+				fingerprint = fp
+				return true, nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, ssh.FingerprintSHA256(srv.HostKey.PublicKey()), fingerprint)
+
+		_, err = dial(t, sshclient.Config{Host: srv.Addr, User: "ec2-user", KeyPath: keyPath, KnownHostsFile: knownHosts})
+		assert.NoError(t, err, "host should now be in known_hosts")
+	})
+}
+
+func TestDial_NetworkError(t *testing.T) {
+	keyPath, _ := writeKey(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	_, err = dial(t, sshclient.Config{Host: addr, User: "ec2-user", KeyPath: keyPath, HostKeyPolicy: sshclient.HostKeyInsecure})
+
+	var netErr *sshclient.NetworkError
+	require.ErrorAs(t, err, &netErr)
+	assert.Contains(t, err.Error(), "network connection failed: cannot reach host 127.0.0.1")
+}
+
+func TestNetworkError_UnresolvedHost(t *testing.T) {
+	err := &sshclient.NetworkError{Host: "bastion.invalid", Err: &net.DNSError{Err: "no such host", Name: "bastion.invalid", IsNotFound: true}}
+	assert.EqualError(t, err, "invalid hostname: bastion.invalid cannot be resolved")
+}
+
+func assertEcho(t *testing.T, conn net.Conn, msg string) {
+	t.Helper()
+
+	_, err := conn.Write([]byte(msg))
+	require.NoError(t, err)
+	buf := make([]byte, len(msg))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, msg, string(buf))
+}
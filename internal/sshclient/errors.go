@@ -0,0 +1,62 @@
+package sshclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// AuthError is returned when no key can be loaded or the server rejects every
+// offered key.
+type AuthError struct {
+	User    string
+	KeyPath string
+	Err     error
+}
+
+func (e *AuthError) Error() string {
+	msg := fmt.Sprintf("SSH authentication failed: invalid credentials for user %s", e.User)
+	if e.KeyPath != "" {
+		msg = fmt.Sprintf("SSH authentication failed: invalid SSH key at %s or key not authorized on server", e.KeyPath)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// HostKeyError is returned when the host key of the server does not match
+// known_hosts, or when an unknown host key was not accepted.
+type HostKeyError struct {
+	Host    string
+	Unknown bool
+	Err     error
+}
+
+func (e *HostKeyError) Error() string {
+	if e.Unknown {
+		return fmt.Sprintf("host key verification failed for %s (host key not accepted)", e.Host)
+	}
+	return fmt.Sprintf("host key verification failed for %s (try removing the host from known_hosts file)", e.Host)
+}
+
+func (e *HostKeyError) Unwrap() error { return e.Err }
+
+// NetworkError is returned when the server cannot be reached or the
+// connection drops during the handshake.
+type NetworkError struct {
+	Host string
+	Err  error
+}
+
+func (e *NetworkError) Error() string {
+	var dnsErr *net.DNSError
+	if errors.As(e.Err, &dnsErr) && dnsErr.IsNotFound {
+		return fmt.Sprintf("invalid hostname: %s cannot be resolved", e.Host)
+	}
+	return fmt.Sprintf("network connection failed: cannot reach host %s (check IP/network connectivity): %v", e.Host, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error { return e.Err }
@@ -0,0 +1,139 @@
+package sshclient
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// HostKeyPolicy decides how the host key of the server is verified.
+type HostKeyPolicy int
+
+const (
+	// HostKeyKnownHosts checks the key against KnownHostsFile and asks
+	// ConfirmHostKey about hosts that are not listed yet, like OpenSSH's
+	// StrictHostKeyChecking=ask.
+	HostKeyKnownHosts HostKeyPolicy = iota
+	// HostKeyInsecure accepts any host key. It is meant for EC2 Instance
+	// Connect, where the instance is identified through AWS and the key pair
+	// is thrown away after the session.
+	HostKeyInsecure
+)
+
+// hostKeyCallback returns the callback that verifies the server for addr and
+// the host key algorithms to offer. A verification failure is also stored in
+// failure, because the ssh package only reports it as text.
+func (c Config) hostKeyCallback(addr string, failure *error) (ssh.HostKeyCallback, []string, error) {
+	if c.HostKeyPolicy == HostKeyInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil, nil
+	}
+
+	check, err := c.knownHosts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	callback := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			*failure = &HostKeyError{Host: c.host(), Err: err}
+			return *failure
+		}
+
+		if err := c.acceptHostKey(hostname, key); err != nil {
+			*failure = err
+			return err
+		}
+		return nil
+	}
+
+	return callback, knownHostKeyAlgorithms(check, addr), nil
+}
+
+// knownHosts loads KnownHostsFile. A missing file is treated as empty.
+func (c Config) knownHosts() (ssh.HostKeyCallback, error) {
+	if _, err := os.Stat(c.KnownHostsFile); errors.Is(err, os.ErrNotExist) {
+		return func(string, net.Addr, ssh.PublicKey) error {
+			return &knownhosts.KeyError{}
+		}, nil
+	}
+
+	check, err := knownhosts.New(c.KnownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", c.KnownHostsFile, err)
+	}
+	return check, nil
+}
+
+// acceptHostKey asks ConfirmHostKey about an unknown host and records the
+// key in KnownHostsFile when it is accepted.
+func (c Config) acceptHostKey(hostname string, key ssh.PublicKey) error {
+	if c.ConfirmHostKey == nil {
+		return &HostKeyError{Host: c.host(), Unknown: true, Err: errors.New("host is not in known_hosts")}
+	}
+
+	ok, err := c.ConfirmHostKey(c.host(), ssh.FingerprintSHA256(key))
+	if err != nil {
+		return &HostKeyError{Host: c.host(), Unknown: true, Err: err}
+	}
+	if !ok {
+		return &HostKeyError{Host: c.host(), Unknown: true, Err: errors.New("host key rejected")}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.KnownHostsFile), 0700); err != nil {
+		return fmt.Errorf("failed to create known_hosts directory: %w", err)
+	}
+	f, err := os.OpenFile(c.KnownHostsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", c.KnownHostsFile, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+	if _, err := fmt.Fprintln(f, line); err != nil {
+		return fmt.Errorf("failed to update %s: %w", c.KnownHostsFile, err)
+	}
+	return nil
+}
+
+// knownHostKeyAlgorithms returns the host key algorithms matching the keys
+// known_hosts lists for addr, so that a server offering several key types
+// presents the one that can be verified. It returns nil for unknown hosts,
+// which leaves the default list in place.
+func knownHostKeyAlgorithms(check ssh.HostKeyCallback, addr string) []string {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil
+	}
+	probe, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		return nil
+	}
+
+	var keyErr *knownhosts.KeyError
+	err = check(addr, &net.TCPAddr{IP: net.IPv4zero}, probe.PublicKey())
+	if !errors.As(err, &keyErr) {
+		return nil
+	}
+
+	var algorithms []string
+	for _, known := range keyErr.Want {
+		if known.Key.Type() == ssh.KeyAlgoRSA {
+			algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+		}
+		algorithms = append(algorithms, known.Key.Type())
+	}
+	return algorithms
+}
@@ -0,0 +1,96 @@
+package sshclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+const (
+	defaultTerm   = "xterm-256color"
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Shell starts a login shell on the server and connects it to stdin, stdout
+// and stderr until the shell exits or ctx is done. When stdin is a terminal
+// a PTY is requested, the local terminal is put in raw mode and window size
+// changes are passed on. The exit status of the remote shell is not treated
+// as an error.
+func (c *Client) Shell(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		restore, err := requestPTY(session, int(f.Fd()))
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	if err := session.Shell(); err != nil {
+		return fmt.Errorf("failed to start remote shell: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-done:
+		var exitErr *ssh.ExitError
+		var missingErr *ssh.ExitMissingError
+		if errors.As(err, &exitErr) || errors.As(err, &missingErr) {
+			return nil
+		}
+		return err
+	}
+}
+
+// requestPTY asks for a terminal the size of fd and puts fd in raw mode. The
+// returned func restores the terminal.
+func requestPTY(session *ssh.Session, fd int) (func(), error) {
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = defaultWidth, defaultHeight
+	}
+
+	termType := os.Getenv("TERM")
+	if termType == "" {
+		termType = defaultTerm
+	}
+
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(termType, height, width, modes); err != nil {
+		return nil, fmt.Errorf("failed to request PTY: %w", err)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put terminal in raw mode: %w", err)
+	}
+	stopResize := watchWindowSize(fd, session)
+
+	return func() {
+		stopResize()
+		_ = term.Restore(fd, state)
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package sshclient
+
+import "golang.org/x/crypto/ssh"
+
+// watchWindowSize is a no-op on Windows, which has no SIGWINCH.
+func watchWindowSize(int, *ssh.Session) func() {
+	return func() {}
+}
@@ -0,0 +1,274 @@
+package ssmsession
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testToken = "token-value"
+
+// fakeAgent stands in for the Session Manager service and the SSM agent
+// behind it.
+type fakeAgent struct {
+	URL string
+
+	// Actions are the client actions requested in the handshake.
+	Actions []map[string]interface{}
+	// Paused pauses publication before the handshake starts.
+	Paused bool
+	// CustomerMessage is sent with the end of the handshake.
+	CustomerMessage string
+	// Script runs once the handshake is complete. The default echoes input.
+	Script func(s *agentSession)
+	// DropAck tells whether an input message goes unacknowledged.
+	DropAck func(m *message) bool
+
+	mu        sync.Mutex
+	handshake []byte
+	acked     []int64
+	sizes     []string
+	flags     []flag
+}
+
+// agentSession is one data channel connection to the fake agent.
+type agentSession struct {
+	agent   *fakeAgent
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	seq     int64
+	input   chan *message
+}
+
+func newFakeAgent(t *testing.T) *fakeAgent {
+	t.Helper()
+
+	a := &fakeAgent{
+		Actions: []map[string]interface{}{
+			{"ActionType": "SessionType", "ActionParameters": map[string]string{"SessionType": "Standard_Stream"}},
+		},
+	}
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		a.serve(conn)
+	}))
+	t.Cleanup(srv.Close)
+
+	a.URL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	return a
+}
+
+func (a *fakeAgent) serve(conn *websocket.Conn) {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return
+	}
+	var open struct {
+		TokenValue string
+	}
+	if json.Unmarshal(data, &open) != nil || open.TokenValue != testToken {
+		return
+	}
+
+	s := &agentSession{agent: a, conn: conn, input: make(chan *message, 64)}
+	go s.readLoop()
+
+	if a.Paused {
+		s.publication(false)
+	}
+
+	request, _ := json.Marshal(map[string]interface{}{
+		"AgentVersion":           "3.2.0.0",
+		"RequestedClientActions": a.Actions,
+	})
+	s.send(payloadHandshakeRequest, request)
+
+	response, ok := s.next()
+	if !ok || response.PayloadType != payloadHandshakeResponse {
+		return
+	}
+	a.mu.Lock()
+	a.handshake = response.Payload
+	a.mu.Unlock()
+
+	complete, _ := json.Marshal(map[string]interface{}{
+		"HandshakeTimeToComplete": 1000000,
+		"CustomerMessage":         a.CustomerMessage,
+	})
+	s.send(payloadHandshakeComplete, complete)
+
+	if a.Script != nil {
+		a.Script(s)
+		return
+	}
+	s.echo()
+}
+
+func (s *agentSession) readLoop() {
+	defer close(s.input)
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		msg, err := unmarshalMessage(data)
+		if err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case messageAcknowledge:
+			var ack struct {
+				AcknowledgedMessageSequenceNumber int64
+			}
+			_ = json.Unmarshal(msg.Payload, &ack)
+			s.agent.mu.Lock()
+			s.agent.acked = append(s.agent.acked, ack.AcknowledgedMessageSequenceNumber)
+			s.agent.mu.Unlock()
+		case messageInputStreamData:
+			if s.agent.DropAck == nil || !s.agent.DropAck(msg) {
+				s.ack(msg)
+			}
+			s.input <- msg
+		}
+	}
+}
+
+// next returns the next input message from the client.
+func (s *agentSession) next() (*message, bool) {
+	select {
+	case msg, ok := <-s.input:
+		return msg, ok
+	case <-time.After(5 * time.Second):
+		return nil, false
+	}
+}
+
+// echo sends stream input back as output until the client terminates the
+// session.
+func (s *agentSession) echo() {
+	for {
+		msg, ok := s.next()
+		if !ok {
+			return
+		}
+		switch msg.PayloadType {
+		case payloadOutput:
+			s.send(payloadOutput, msg.Payload)
+		case payloadSize:
+			var size struct {
+				Cols, Rows int
+			}
+			_ = json.Unmarshal(msg.Payload, &size)
+			s.agent.mu.Lock()
+			s.agent.sizes = append(s.agent.sizes, formatSize(size.Cols, size.Rows))
+			s.agent.mu.Unlock()
+		case payloadFlag:
+			f := flag(binary.BigEndian.Uint32(msg.Payload))
+			s.agent.mu.Lock()
+			s.agent.flags = append(s.agent.flags, f)
+			s.agent.mu.Unlock()
+			if f == flagTerminateSession {
+				s.close("")
+				return
+			}
+		}
+	}
+}
+
+func (s *agentSession) send(pt payloadType, payload []byte) {
+	s.sendSeq(s.seq, pt, payload)
+	s.seq++
+}
+
+// sendSeq sends an output message with an explicit sequence number.
+func (s *agentSession) sendSeq(seq int64, pt payloadType, payload []byte) {
+	s.write(&message{
+		Type:           messageOutputStreamData,
+		SchemaVersion:  messageSchemaVersion,
+		CreatedDate:    time.Now(),
+		SequenceNumber: seq,
+		ID:             newUUID(),
+		PayloadType:    pt,
+		Payload:        payload,
+	})
+}
+
+func (s *agentSession) sendFlag(f flag) {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(f))
+	s.send(payloadFlag, payload)
+}
+
+func (s *agentSession) ack(msg *message) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"AcknowledgedMessageType":           msg.Type,
+		"AcknowledgedMessageId":             msg.ID.String(),
+		"AcknowledgedMessageSequenceNumber": msg.SequenceNumber,
+		"IsSequentialMessage":               true,
+	})
+	s.write(&message{Type: messageAcknowledge, SchemaVersion: messageSchemaVersion, CreatedDate: time.Now(), ID: newUUID(), Payload: payload})
+}
+
+// publication sends start_publication, or pause_publication when start is
+// false.
+func (s *agentSession) publication(start bool) {
+	msgType := messagePausePublication
+	if start {
+		msgType = messageStartPublication
+	}
+	s.write(&message{Type: msgType, SchemaVersion: messageSchemaVersion, CreatedDate: time.Now(), ID: newUUID(), Payload: []byte("{}")})
+}
+
+// close sends channel_closed with output as the reason.
+func (s *agentSession) close(output string) {
+	payload, _ := json.Marshal(map[string]string{"SessionId": "sess-123", "Output": output})
+	s.write(&message{Type: messageChannelClosed, SchemaVersion: messageSchemaVersion, CreatedDate: time.Now(), ID: newUUID(), Payload: payload})
+}
+
+func (s *agentSession) write(msg *message) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.WriteMessage(websocket.BinaryMessage, msg.marshal())
+}
+
+func (a *fakeAgent) Handshake() []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.handshake
+}
+
+func (a *fakeAgent) Acked() []int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]int64(nil), a.acked...)
+}
+
+func (a *fakeAgent) Sizes() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.sizes...)
+}
+
+func (a *fakeAgent) Flags() []flag {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]flag(nil), a.flags...)
+}
+
+func formatSize(cols, rows int) string {
+	return fmt.Sprintf("%dx%d", cols, rows)
+}
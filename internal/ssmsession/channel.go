@@ -0,0 +1,566 @@
+// Package ssmsession speaks the Session Manager data channel protocol, so that
+// sessions started with ssm:StartSession can be used without
+// session-manager-plugin.
+package ssmsession
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// clientVersion is reported to the agent. It predates port multiplexing, so
+// a port session carries a single connection.
+const clientVersion = "1.1.61.0"
+
+var (
+	handshakeTimeout = 30 * time.Second
+	resendInterval   = time.Second
+	resendTimeout    = 3 * time.Second
+	pingInterval     = 5 * time.Minute
+)
+
+// ErrConnectToPort is returned when the agent cannot reach the destination of
+// a port forwarding session.
+var ErrConnectToPort = errors.New("the instance could not connect to the destination port")
+
+// ErrKMSEncryption is returned for sessions that require KMS encryption,
+// which the built-in client does not implement.
+var ErrKMSEncryption = errors.New("session requires KMS encryption, which is only supported by session-manager-plugin")
+
+// Channel is an open data channel of a Session Manager session. Reads return
+// the output of the session and writes send input to it.
+type Channel struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	paused   bool
+	nextSeq  int64
+	unacked  map[int64]*outgoing
+	expected int64
+	incoming map[int64]*message
+
+	sessionType     string
+	customerMessage string
+
+	output     *io.PipeReader
+	outputW    *io.PipeWriter
+	received   chan struct{}
+	outputOnce sync.Once
+	ready      chan struct{}
+	readyOnce  sync.Once
+	done       chan struct{}
+	doneOnce   sync.Once
+	err        error
+}
+
+type outgoing struct {
+	data   []byte
+	sentAt time.Time
+}
+
+// Dial opens the data channel at streamURL with the token returned by
+// ssm:StartSession and completes the handshake with the agent.
+func Dial(ctx context.Context, streamURL, token string) (*Channel, error) {
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: handshakeTimeout,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+	conn, _, err := dialer.Dial(streamURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Session Manager data channel: %w", err)
+	}
+
+	open, err := json.Marshal(map[string]string{
+		"MessageSchemaVersion": "1.0",
+		"RequestId":            newUUID().String(),
+		"TokenValue":           token,
+		"ClientId":             newUUID().String(),
+		"ClientVersion":        clientVersion,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, open); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open Session Manager data channel: %w", err)
+	}
+
+	c := newChannel(conn)
+	go c.readLoop()
+	go c.resendLoop(resendInterval, resendTimeout)
+	go c.pingLoop(pingInterval)
+
+	timer := time.NewTimer(handshakeTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.ready:
+		return c, nil
+	case <-c.done:
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, errors.New("data channel closed during handshake")
+	case <-ctx.Done():
+		_ = c.Close()
+		return nil, ctx.Err()
+	case <-timer.C:
+		_ = c.Close()
+		return nil, errors.New("timed out waiting for Session Manager handshake")
+	}
+}
+
+func newChannel(conn *websocket.Conn) *Channel {
+	r, w := io.Pipe()
+	c := &Channel{
+		conn:     conn,
+		unacked:  make(map[int64]*outgoing),
+		incoming: make(map[int64]*message),
+		output:   r,
+		outputW:  w,
+		received: make(chan struct{}),
+		ready:    make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// SessionType is the session type requested by the agent during the
+// handshake, such as "Standard_Stream" or "Port".
+func (c *Channel) SessionType() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionType
+}
+
+// CustomerMessage is the message the agent sent with the end of the
+// handshake, if any, such as the session preferences' notice to users.
+func (c *Channel) CustomerMessage() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.customerMessage
+}
+
+// Read reads output of the session. It returns io.EOF once the session has
+// ended normally.
+func (c *Channel) Read(p []byte) (int, error) {
+	return c.output.Read(p)
+}
+
+// Write sends p to the session as input.
+func (c *Channel) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > streamDataPayloadSize {
+			chunk = chunk[:streamDataPayloadSize]
+		}
+		if err := c.send(payloadOutput, chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// SetSize tells the agent the size of the local terminal.
+func (c *Channel) SetSize(cols, rows int) error {
+	payload, err := json.Marshal(struct {
+		Cols int `json:"cols"`
+		Rows int `json:"rows"`
+	}{cols, rows})
+	if err != nil {
+		return err
+	}
+	return c.send(payloadSize, payload)
+}
+
+func (c *Channel) sendFlag(f flag) error {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(f))
+	return c.send(payloadFlag, payload)
+}
+
+// Done is closed when the session has ended.
+func (c *Channel) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the session ended, or nil if it ended normally or is
+// still open.
+func (c *Channel) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close closes the data channel. The session itself is ended by the agent
+// once the channel is gone, or with ssm:TerminateSession.
+func (c *Channel) Close() error {
+	c.finish(nil)
+	return nil
+}
+
+// finish ends the channel with err as the reason. Only the first call has an
+// effect.
+func (c *Channel) finish(err error) {
+	c.doneOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		close(c.done)
+		c.cond.Broadcast()
+
+		_ = c.outputW.CloseWithError(err)
+		_ = c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		_ = c.conn.Close()
+	})
+}
+
+func (c *Channel) closedErr() error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+	return io.ErrClosedPipe
+}
+
+// send sends payload as the next input_stream_data message, waiting while
+// the agent has paused publication.
+func (c *Channel) send(pt payloadType, payload []byte) error {
+	c.mu.Lock()
+	for c.paused && !c.isDone() {
+		c.cond.Wait()
+	}
+	c.mu.Unlock()
+	return c.sendNow(pt, payload)
+}
+
+// sendNow sends payload as the next input_stream_data message without
+// waiting for publication to resume. readLoop uses it to answer the agent,
+// since it would otherwise block on the message that resumes publication.
+func (c *Channel) sendNow(pt payloadType, payload []byte) error {
+	if c.isDone() {
+		return c.closedErr()
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.mu.Lock()
+	msg := &message{
+		Type:           messageInputStreamData,
+		SchemaVersion:  messageSchemaVersion,
+		CreatedDate:    time.Now(),
+		SequenceNumber: c.nextSeq,
+		ID:             newUUID(),
+		PayloadType:    pt,
+		Payload:        payload,
+	}
+	c.nextSeq++
+	data := msg.marshal()
+	c.unacked[msg.SequenceNumber] = &outgoing{data: data, sentAt: time.Now()}
+	c.mu.Unlock()
+
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
+		c.finish(fmt.Errorf("failed to send to Session Manager: %w", err))
+		return c.closedErr()
+	}
+	return nil
+}
+
+func (c *Channel) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+func (c *Channel) isDone() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Channel) readLoop() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				err = nil
+			} else {
+				err = fmt.Errorf("data channel failed: %w", err)
+			}
+			c.finish(err)
+			return
+		}
+
+		msg, err := unmarshalMessage(data)
+		if err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case messageOutputStreamData:
+			// A failed acknowledgement means the connection is going away,
+			// which the next read reports. The message itself is still good.
+			_ = c.acknowledge(msg)
+			if err := c.receive(msg); err != nil {
+				c.finish(err)
+				return
+			}
+		case messageAcknowledge:
+			c.handleAcknowledge(msg)
+		case messageChannelClosed:
+			c.finish(channelClosedErr(msg))
+			return
+		case messageStartPublication, messagePausePublication:
+			c.mu.Lock()
+			c.paused = msg.Type == messagePausePublication
+			c.mu.Unlock()
+			c.cond.Broadcast()
+		}
+	}
+}
+
+func (c *Channel) acknowledge(msg *message) error {
+	payload, err := json.Marshal(struct {
+		AcknowledgedMessageType           string
+		AcknowledgedMessageId             string
+		AcknowledgedMessageSequenceNumber int64
+		IsSequentialMessage               bool
+	}{msg.Type, msg.ID.String(), msg.SequenceNumber, true})
+	if err != nil {
+		return err
+	}
+
+	ack := &message{
+		Type:          messageAcknowledge,
+		SchemaVersion: messageSchemaVersion,
+		CreatedDate:   time.Now(),
+		Flags:         acknowledgeFlags,
+		ID:            newUUID(),
+		Payload:       payload,
+	}
+	return c.write(ack.marshal())
+}
+
+func (c *Channel) handleAcknowledge(msg *message) {
+	var ack struct {
+		AcknowledgedMessageSequenceNumber int64
+	}
+	if err := json.Unmarshal(msg.Payload, &ack); err != nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.unacked, ack.AcknowledgedMessageSequenceNumber)
+	c.mu.Unlock()
+}
+
+// receive processes stream messages in sequence order. Messages that arrive
+// early are held until the gap is filled, and repeats are dropped.
+func (c *Channel) receive(msg *message) error {
+	c.mu.Lock()
+	if msg.SequenceNumber < c.expected {
+		c.mu.Unlock()
+		return nil
+	}
+	c.incoming[msg.SequenceNumber] = msg
+
+	var ordered []*message
+	for {
+		next, ok := c.incoming[c.expected]
+		if !ok {
+			break
+		}
+		ordered = append(ordered, next)
+		delete(c.incoming, c.expected)
+		c.expected++
+	}
+	c.mu.Unlock()
+
+	for _, m := range ordered {
+		if err := c.handle(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Channel) handle(msg *message) error {
+	switch msg.PayloadType {
+	case payloadOutput, payloadStdErr:
+		c.markReady()
+		c.outputOnce.Do(func() { close(c.received) })
+		if _, err := c.outputW.Write(msg.Payload); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			return err
+		}
+	case payloadHandshakeRequest:
+		return c.handleHandshakeRequest(msg.Payload)
+	case payloadHandshakeComplete:
+		var complete struct {
+			CustomerMessage string
+		}
+		if err := json.Unmarshal(msg.Payload, &complete); err == nil {
+			c.mu.Lock()
+			c.customerMessage = complete.CustomerMessage
+			c.mu.Unlock()
+		}
+		c.markReady()
+	case payloadEncChallengeReq:
+		return ErrKMSEncryption
+	case payloadFlag:
+		if len(msg.Payload) >= 4 && flag(binary.BigEndian.Uint32(msg.Payload)) == flagConnectToPortError {
+			return ErrConnectToPort
+		}
+	}
+	return nil
+}
+
+const (
+	actionStatusSuccess     = 1
+	actionStatusFailed      = 2
+	actionStatusUnsupported = 3
+)
+
+type processedClientAction struct {
+	ActionType   string
+	ActionStatus int
+	ActionResult json.RawMessage `json:",omitempty"`
+	Error        string
+}
+
+func (c *Channel) handleHandshakeRequest(payload []byte) error {
+	var request struct {
+		AgentVersion           string
+		RequestedClientActions []struct {
+			ActionType       string
+			ActionParameters json.RawMessage
+		}
+	}
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return fmt.Errorf("invalid handshake request: %w", err)
+	}
+
+	var handshakeErr error
+	processed := make([]processedClientAction, 0, len(request.RequestedClientActions))
+	for _, action := range request.RequestedClientActions {
+		result := processedClientAction{ActionType: action.ActionType}
+		switch action.ActionType {
+		case "SessionType":
+			var params struct {
+				SessionType string
+			}
+			_ = json.Unmarshal(action.ActionParameters, &params)
+			c.mu.Lock()
+			c.sessionType = params.SessionType
+			c.mu.Unlock()
+			result.ActionStatus = actionStatusSuccess
+		case "KMSEncryption":
+			result.ActionStatus = actionStatusFailed
+			result.Error = ErrKMSEncryption.Error()
+			handshakeErr = ErrKMSEncryption
+		default:
+			result.ActionStatus = actionStatusUnsupported
+			result.Error = fmt.Sprintf("unsupported action %s", action.ActionType)
+		}
+		processed = append(processed, result)
+	}
+
+	response, err := json.Marshal(struct {
+		ClientVersion          string
+		ProcessedClientActions []processedClientAction
+		Errors                 []string
+	}{clientVersion, processed, []string{}})
+	if err != nil {
+		return err
+	}
+	if err := c.sendNow(payloadHandshakeResponse, response); err != nil {
+		return err
+	}
+	return handshakeErr
+}
+
+func (c *Channel) markReady() {
+	c.readyOnce.Do(func() { close(c.ready) })
+}
+
+// channelClosedErr returns the reason the agent gave for closing the
+// channel, or nil when the session simply ended.
+func channelClosedErr(msg *message) error {
+	var closed struct {
+		SessionId string
+		Output    string
+	}
+	if err := json.Unmarshal(msg.Payload, &closed); err != nil || closed.Output == "" {
+		return nil
+	}
+	return fmt.Errorf("session %s closed: %s", closed.SessionId, closed.Output)
+}
+
+// resendLoop sends input messages again that the agent has not acknowledged
+// in time.
+func (c *Channel) resendLoop(interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		c.writeMu.Lock()
+		c.mu.Lock()
+		now := time.Now()
+		for _, out := range c.unacked {
+			if now.Sub(out.sentAt) < timeout {
+				continue
+			}
+			out.sentAt = now
+			if err := c.conn.WriteMessage(websocket.BinaryMessage, out.data); err != nil {
+				break
+			}
+		}
+		c.mu.Unlock()
+		c.writeMu.Unlock()
+	}
+}
+
+// pingLoop keeps the websocket open through idle periods.
+func (c *Channel) pingLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			_ = c.conn.WriteControl(websocket.PingMessage, []byte("keepalive"), time.Now().Add(10*time.Second))
+		}
+	}
+}
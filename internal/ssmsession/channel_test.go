@@ -0,0 +1,338 @@
+package ssmsession
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dial(t *testing.T, a *fakeAgent) *Channel {
+	t.Helper()
+
+	c, err := Dial(context.Background(), a.URL, testToken)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestMessage_RoundTrip(t *testing.T) {
+	msg := &message{
+		Type:           messageInputStreamData,
+		SchemaVersion:  messageSchemaVersion,
+		CreatedDate:    time.UnixMilli(1700000000123),
+		SequenceNumber: 42,
+		Flags:          acknowledgeFlags,
+		ID:             newUUID(),
+		PayloadType:    payloadSize,
+		Payload:        []byte(`{"cols":80,"rows":24}`),
+	}
+
+	data := msg.marshal()
+	assert.Len(t, data, offsetPayload+len(msg.Payload))
+	assert.Equal(t, "input_stream_data               ", string(data[offsetMessageType:offsetSchemaVersion]))
+	assert.Equal(t, msg.ID[8:], data[offsetMessageID:offsetMessageID+8], "least significant half comes first")
+
+	got, err := unmarshalMessage(data)
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+}
+
+func TestUnmarshalMessage_Invalid(t *testing.T) {
+	_, err := unmarshalMessage(make([]byte, 10))
+	assert.Error(t, err)
+
+	data := (&message{Type: messageAcknowledge, Payload: []byte("{}")}).marshal()
+	_, err = unmarshalMessage(data[:len(data)-1])
+	assert.EqualError(t, err, "payload length 2 exceeds message size")
+}
+
+func TestDial_Handshake(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Actions = append(a.Actions, map[string]interface{}{"ActionType": "SomethingNew"})
+
+	c := dial(t, a)
+	assert.Equal(t, "Standard_Stream", c.SessionType())
+
+	var response struct {
+		ClientVersion          string
+		ProcessedClientActions []processedClientAction
+	}
+	require.NoError(t, json.Unmarshal(a.Handshake(), &response))
+	assert.Equal(t, clientVersion, response.ClientVersion)
+	require.Len(t, response.ProcessedClientActions, 2)
+	assert.Equal(t, actionStatusSuccess, response.ProcessedClientActions[0].ActionStatus)
+	assert.Equal(t, actionStatusUnsupported, response.ProcessedClientActions[1].ActionStatus)
+}
+
+func TestDial_PausedPublication(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Paused = true
+	a.CustomerMessage = "Sessions on this instance are recorded."
+	a.Script = func(s *agentSession) {
+		time.Sleep(50 * time.Millisecond)
+		s.publication(true)
+		s.echo()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, a.URL, testToken)
+	require.NoError(t, err, "the handshake is answered while publication is paused")
+	defer func() { _ = c.Close() }()
+	assert.Equal(t, "Sessions on this instance are recorded.", c.CustomerMessage())
+
+	_, err = c.Write([]byte("ls"))
+	require.NoError(t, err)
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(c, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ls", string(buf))
+}
+
+func TestDial_InvalidToken(t *testing.T) {
+	a := newFakeAgent(t)
+
+	_, err := Dial(context.Background(), a.URL, "wrong")
+	assert.Error(t, err)
+}
+
+func TestDial_KMSEncryption(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Actions = append(a.Actions, map[string]interface{}{
+		"ActionType":       "KMSEncryption",
+		"ActionParameters": map[string]string{"KMSKeyId": "arn:aws:kms:us-east-1:111122223333:key/abc"},
+	})
+
+	_, err := Dial(context.Background(), a.URL, testToken)
+	assert.ErrorIs(t, err, ErrKMSEncryption)
+
+	require.Eventually(t, func() bool { return len(a.Handshake()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	var response struct {
+		ProcessedClientActions []processedClientAction
+	}
+	require.NoError(t, json.Unmarshal(a.Handshake(), &response))
+	assert.Equal(t, actionStatusFailed, response.ProcessedClientActions[1].ActionStatus)
+}
+
+func TestChannel_Acknowledgements(t *testing.T) {
+	a := newFakeAgent(t)
+	c := dial(t, a)
+
+	_, err := c.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(c, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]int64{0, 1, 2}, a.Acked())
+	}, 5*time.Second, 10*time.Millisecond, "handshake request, handshake complete and output are acknowledged")
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.unacked) == 0
+	}, 5*time.Second, 10*time.Millisecond, "input acknowledged by the agent is forgotten")
+}
+
+func TestChannel_Resend(t *testing.T) {
+	defer func(interval, timeout time.Duration) { resendInterval, resendTimeout = interval, timeout }(resendInterval, resendTimeout)
+	resendInterval, resendTimeout = 10*time.Millisecond, 20*time.Millisecond
+
+	a := newFakeAgent(t)
+	dropped := false
+	a.DropAck = func(m *message) bool {
+		if m.PayloadType != payloadOutput || dropped {
+			return false
+		}
+		dropped = true
+		return true
+	}
+	received := make(chan int64, 8)
+	a.Script = func(s *agentSession) {
+		for {
+			msg, ok := s.next()
+			if !ok {
+				return
+			}
+			received <- msg.SequenceNumber
+		}
+	}
+	c := dial(t, a)
+
+	_, err := c.Write([]byte("ls\n"))
+	require.NoError(t, err)
+
+	first := <-received
+	select {
+	case again := <-received:
+		assert.Equal(t, first, again)
+	case <-time.After(5 * time.Second):
+		t.Fatal("unacknowledged input was not sent again")
+	}
+}
+
+func TestChannel_OutOfOrderOutput(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Script = func(s *agentSession) {
+		s.sendSeq(3, payloadOutput, []byte("c"))
+		s.sendSeq(2, payloadOutput, []byte("b"))
+		s.sendSeq(2, payloadOutput, []byte("b"))
+		s.sendSeq(1, payloadOutput, []byte("a"))
+		s.sendSeq(4, payloadOutput, []byte("d"))
+		s.close("")
+	}
+	c := dial(t, a)
+
+	out, err := io.ReadAll(c)
+	require.NoError(t, err)
+	assert.Equal(t, "bcd", string(out), "output is delivered once and in sequence")
+	assert.NoError(t, c.Err())
+}
+
+func TestChannel_ChannelClosed(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Script = func(s *agentSession) { s.close("Session terminated by administrator") }
+	c := dial(t, a)
+
+	_, err := io.ReadAll(c)
+	assert.EqualError(t, err, "session sess-123 closed: Session terminated by administrator")
+	assert.Equal(t, err, c.Err())
+	_, err = c.Write([]byte("ls"))
+	assert.Error(t, err)
+}
+
+func TestShell(t *testing.T) {
+	a := newFakeAgent(t)
+	c := dial(t, a)
+
+	var stdout bytes.Buffer
+	stdin := &terminateAfter{Reader: strings.NewReader("ls\n"), c: c}
+	err := c.Shell(context.Background(), stdin, &stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "ls\n", stdout.String())
+	assert.Equal(t, []string{"80x24"}, a.Sizes(), "default size without a terminal")
+}
+
+// terminateAfter asks the agent to end the session once its input is used
+// up, and the echo is back.
+type terminateAfter struct {
+	io.Reader
+	c *Channel
+}
+
+func (r *terminateAfter) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		time.Sleep(100 * time.Millisecond)
+		_ = r.c.sendFlag(flagTerminateSession)
+	}
+	return n, err
+}
+
+func TestForward(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Actions[0]["ActionParameters"] = map[string]string{"SessionType": "Port"}
+	c := dial(t, a)
+	assert.Equal(t, "Port", c.SessionType())
+
+	local, remote := net.Pipe()
+	done := make(chan error, 1)
+	go func() { done <- c.Forward(context.Background(), remote) }()
+
+	_, err := local.Write([]byte("ping"))
+	require.NoError(t, err)
+	buf := make([]byte, 4)
+	_ = local.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = io.ReadFull(local, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	require.NoError(t, local.Close())
+	assert.NoError(t, <-done)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]flag{flagTerminateSession}, a.Flags())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestForward_ConnectToPortError(t *testing.T) {
+	a := newFakeAgent(t)
+	a.Script = func(s *agentSession) { s.sendFlag(flagConnectToPortError) }
+	c := dial(t, a)
+
+	local, remote := net.Pipe()
+	defer func() { _ = local.Close() }()
+
+	err := c.Forward(context.Background(), remote)
+	assert.ErrorIs(t, err, ErrConnectToPort)
+}
+
+func TestConn(t *testing.T) {
+	defer func(wait time.Duration) { connectWait = wait }(connectWait)
+	connectWait = 10 * time.Millisecond
+
+	a := newFakeAgent(t)
+	a.Actions[0]["ActionParameters"] = map[string]string{"SessionType": "Port"}
+	c := dial(t, a)
+
+	conn, err := c.Conn(context.Background())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	require.NoError(t, conn.(interface{ CloseWrite() error }).CloseWrite())
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err, "output keeps flowing after CloseWrite")
+	assert.Equal(t, "ping", string(buf))
+
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]flag{flagTerminateSession}, a.Flags())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConn_WaitsForOutput(t *testing.T) {
+	defer func(wait time.Duration) { connectWait = wait }(connectWait)
+	connectWait = time.Minute
+
+	a := newFakeAgent(t)
+	a.Script = func(s *agentSession) {
+		s.send(payloadOutput, []byte("SSH-2.0-OpenSSH_9.6\r\n"))
+		s.echo()
+	}
+	c := dial(t, a)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := c.Conn(ctx)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	line := make([]byte, 21)
+	_, err = io.ReadFull(conn, line)
+	require.NoError(t, err)
+	assert.Equal(t, "SSH-2.0-OpenSSH_9.6\r\n", string(line))
+}
+
+func TestConn_ConnectToPortError(t *testing.T) {
+	defer func(wait time.Duration) { connectWait = wait }(connectWait)
+	connectWait = time.Minute
+
+	a := newFakeAgent(t)
+	a.Script = func(s *agentSession) { s.sendFlag(flagConnectToPortError) }
+	c := dial(t, a)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := c.Conn(ctx)
+	assert.ErrorIs(t, err, ErrConnectToPort)
+}
@@ -0,0 +1,183 @@
+package ssmsession
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Message types of the data channel.
+const (
+	messageInputStreamData  = "input_stream_data"
+	messageOutputStreamData = "output_stream_data"
+	messageAcknowledge      = "acknowledge"
+	messageChannelClosed    = "channel_closed"
+	messageStartPublication = "start_publication"
+	messagePausePublication = "pause_publication"
+)
+
+// payloadType tells how the payload of a stream message is interpreted.
+type payloadType uint32
+
+const (
+	payloadOutput            payloadType = 1
+	payloadError             payloadType = 2
+	payloadSize              payloadType = 3
+	payloadParameter         payloadType = 4
+	payloadHandshakeRequest  payloadType = 5
+	payloadHandshakeResponse payloadType = 6
+	payloadHandshakeComplete payloadType = 7
+	payloadEncChallengeReq   payloadType = 8
+	payloadEncChallengeResp  payloadType = 9
+	payloadFlag              payloadType = 10
+	payloadStdErr            payloadType = 11
+	payloadExitCode          payloadType = 12
+)
+
+// flag is the payload of a payloadFlag message, used by port sessions.
+type flag uint32
+
+const (
+	flagDisconnectToPort   flag = 1
+	flagTerminateSession   flag = 2
+	flagConnectToPortError flag = 3
+)
+
+const (
+	messageSchemaVersion = 1
+	// acknowledgeFlags is what session-manager-plugin puts in the flags of
+	// acknowledge messages.
+	acknowledgeFlags = 3
+	// streamDataPayloadSize is the largest payload sent in one message.
+	streamDataPayloadSize = 1024
+
+	messageTypeLength   = 32
+	payloadDigestLength = 32
+	headerLength        = 116
+	uuidLength          = 16
+)
+
+// Field offsets of the binary message header. All integers are big-endian.
+const (
+	offsetHeaderLength   = 0
+	offsetMessageType    = 4
+	offsetSchemaVersion  = 36
+	offsetCreatedDate    = 40
+	offsetSequenceNumber = 48
+	offsetFlags          = 56
+	offsetMessageID      = 64
+	offsetPayloadDigest  = 80
+	offsetPayloadType    = 112
+	offsetPayloadLength  = 116
+	offsetPayload        = 120
+)
+
+// message is a binary frame exchanged on the data channel.
+type message struct {
+	Type           string
+	SchemaVersion  uint32
+	CreatedDate    time.Time
+	SequenceNumber int64
+	Flags          uint64
+	ID             uuid
+	PayloadType    payloadType
+	Payload        []byte
+}
+
+func (m *message) marshal() []byte {
+	b := make([]byte, offsetPayload+len(m.Payload))
+	binary.BigEndian.PutUint32(b[offsetHeaderLength:], headerLength)
+
+	typ := b[offsetMessageType : offsetMessageType+messageTypeLength]
+	for i := range typ {
+		typ[i] = ' '
+	}
+	copy(typ, m.Type)
+
+	binary.BigEndian.PutUint32(b[offsetSchemaVersion:], m.SchemaVersion)
+	binary.BigEndian.PutUint64(b[offsetCreatedDate:], uint64(m.CreatedDate.UnixMilli()))
+	binary.BigEndian.PutUint64(b[offsetSequenceNumber:], uint64(m.SequenceNumber))
+	binary.BigEndian.PutUint64(b[offsetFlags:], m.Flags)
+	m.ID.put(b[offsetMessageID:])
+
+	digest := sha256.Sum256(m.Payload)
+	copy(b[offsetPayloadDigest:offsetPayloadDigest+payloadDigestLength], digest[:])
+
+	binary.BigEndian.PutUint32(b[offsetPayloadType:], uint32(m.PayloadType))
+	binary.BigEndian.PutUint32(b[offsetPayloadLength:], uint32(len(m.Payload)))
+	copy(b[offsetPayload:], m.Payload)
+	return b
+}
+
+// unmarshalMessage parses a frame. Like session-manager-plugin, it finds the
+// payload through the header length of the frame and does not verify the
+// payload digest.
+func unmarshalMessage(b []byte) (*message, error) {
+	if len(b) < offsetPayload {
+		return nil, fmt.Errorf("message too short: %d bytes", len(b))
+	}
+
+	lengthOffset := int(binary.BigEndian.Uint32(b[offsetHeaderLength:]))
+	if lengthOffset < offsetPayloadLength || lengthOffset+4 > len(b) {
+		return nil, fmt.Errorf("invalid header length %d", lengthOffset)
+	}
+	length := int(binary.BigEndian.Uint32(b[lengthOffset:]))
+	start := lengthOffset + 4
+	if length > len(b)-start {
+		return nil, fmt.Errorf("payload length %d exceeds message size", length)
+	}
+
+	return &message{
+		Type:           string(bytes.TrimRight(b[offsetMessageType:offsetMessageType+messageTypeLength], " \x00")),
+		SchemaVersion:  binary.BigEndian.Uint32(b[offsetSchemaVersion:]),
+		CreatedDate:    time.UnixMilli(int64(binary.BigEndian.Uint64(b[offsetCreatedDate:]))),
+		SequenceNumber: int64(binary.BigEndian.Uint64(b[offsetSequenceNumber:])),
+		Flags:          binary.BigEndian.Uint64(b[offsetFlags:]),
+		ID:             getUUID(b[offsetMessageID:]),
+		PayloadType:    payloadType(binary.BigEndian.Uint32(b[offsetPayloadType:])),
+		Payload:        b[start : start+length],
+	}, nil
+}
+
+// uuid is a random RFC 4122 identifier.
+type uuid [uuidLength]byte
+
+func newUUID() uuid {
+	var u uuid
+	_, _ = rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u
+}
+
+// put writes u the way the data channel expects it: the least significant
+// half first.
+func (u uuid) put(b []byte) {
+	copy(b[0:8], u[8:])
+	copy(b[8:16], u[:8])
+}
+
+func getUUID(b []byte) uuid {
+	var u uuid
+	copy(u[8:], b[0:8])
+	copy(u[:8], b[8:16])
+	return u
+}
+
+func (u uuid) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,119 @@
+package ssmsession
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// connectWait is how long Conn waits for the agent to report that it cannot
+// reach the destination. The agent sends nothing once it is connected, so a
+// session that is still open after connectWait counts as connected.
+var connectWait = time.Second
+
+// Forward carries conn over a port forwarding session until either side
+// closes or ctx is done. The session is ended when Forward returns, since
+// it only serves one connection.
+func (c *Channel) Forward(ctx context.Context, conn net.Conn) error {
+	defer func() { _ = conn.Close() }()
+
+	finished := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(conn, c)
+		finished <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(c, conn)
+		finished <- struct{}{}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-finished:
+	}
+
+	if !c.isDone() {
+		_ = c.sendFlag(flagTerminateSession)
+	}
+	_ = c.Close()
+	return c.Err()
+}
+
+// Conn returns the port forwarding session as a net.Conn once its
+// destination has sent data or connectWait has passed. It fails with
+// ErrConnectToPort, or the reason the agent ended the session, when the
+// destination cannot be reached, so that callers can still refuse the
+// connection they are serving.
+//
+// A session cannot signal the end of its input, so CloseWrite of the
+// returned connection does nothing and output keeps flowing until the
+// destination closes. Close ends the session.
+func (c *Channel) Conn(ctx context.Context) (net.Conn, error) {
+	timer := time.NewTimer(connectWait)
+	defer timer.Stop()
+
+	select {
+	case <-c.received:
+	case <-timer.C:
+	case <-c.done:
+		// Output that came before the end of the session can still be read.
+		select {
+		case <-c.received:
+		default:
+			return nil, c.closedErr()
+		}
+	case <-ctx.Done():
+		_ = c.Close()
+		return nil, ctx.Err()
+	}
+	return &portConn{Channel: c}, nil
+}
+
+// portConn is a port forwarding session used as a net.Conn.
+type portConn struct {
+	*Channel
+	closeOnce sync.Once
+}
+
+func (c *portConn) Close() error {
+	c.closeOnce.Do(func() {
+		if !c.isDone() {
+			_ = c.sendFlag(flagTerminateSession)
+		}
+		_ = c.Channel.Close()
+	})
+	return nil
+}
+
+// CloseWrite is a no-op, see Conn.
+func (c *portConn) CloseWrite() error {
+	return nil
+}
+
+func (c *portConn) LocalAddr() net.Addr {
+	return sessionAddr{}
+}
+
+func (c *portConn) RemoteAddr() net.Addr {
+	return sessionAddr{}
+}
+
+func (c *portConn) SetDeadline(time.Time) error {
+	return errors.ErrUnsupported
+}
+
+func (c *portConn) SetReadDeadline(time.Time) error {
+	return errors.ErrUnsupported
+}
+
+func (c *portConn) SetWriteDeadline(time.Time) error {
+	return errors.ErrUnsupported
+}
+
+type sessionAddr struct{}
+
+func (sessionAddr) Network() string { return "ssm" }
+func (sessionAddr) String() string  { return "session-manager" }
@@ -0,0 +1,80 @@
+package ssmsession
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	defaultCols = 80
+	defaultRows = 24
+)
+
+// sizePollInterval is how often the terminal size is checked for changes.
+// Polling works the same on every platform, which is also how
+// session-manager-plugin does it.
+var sizePollInterval = 500 * time.Millisecond
+
+// Shell connects stdin and stdout to an interactive session until the
+// session ends or ctx is done. When stdin is a terminal it is put in raw mode
+// and size changes are sent to the agent.
+func (c *Channel) Shell(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	cols, rows := defaultCols, defaultRows
+	fd := -1
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd = int(f.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = term.Restore(fd, state) }()
+
+		if w, h, err := term.GetSize(fd); err == nil {
+			cols, rows = w, h
+		}
+	}
+	if err := c.SetSize(cols, rows); err != nil {
+		return err
+	}
+	if fd >= 0 {
+		go c.watchSize(fd, cols, rows)
+	}
+
+	go func() { _, _ = io.Copy(c, stdin) }()
+
+	output := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(stdout, c)
+		close(output)
+	}()
+
+	select {
+	case <-ctx.Done():
+		_ = c.Close()
+		return nil
+	case <-output:
+		return c.Err()
+	}
+}
+
+func (c *Channel) watchSize(fd, cols, rows int) {
+	ticker := time.NewTicker(sizePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			w, h, err := term.GetSize(fd)
+			if err != nil || (w == cols && h == rows) {
+				continue
+			}
+			cols, rows = w, h
+			_ = c.SetSize(cols, rows)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// defaultSTSRegion is used for STS calls when no profile in a role chain
+// sets a region.
+const defaultSTSRegion = "us-east-1"
+
+var validRoleARN = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+
+// AssumeRoleOptions describes a role assumed with the credentials of a source
+// profile, as set by role_arn, source_profile, external_id, duration_seconds
+// and role_session_name in ~/.aws/config.
+type AssumeRoleOptions struct {
+	RoleARN       string
+	SourceProfile string
+	ExternalID    string
+	// RoleSessionName defaults to awsctl-<unix time>.
+	RoleSessionName string
+	// DurationSeconds of 0 uses the STS default of one hour.
+	DurationSeconds int32
+}
+
+// IsValidRoleARN reports whether arn is an IAM role ARN.
+func IsValidRoleARN(arn string) bool {
+	return validRoleARN.MatchString(arn)
+}
+
+// NewSTSClient returns an STS client for the region that signs requests with
+// the given credentials.
+func NewSTSClient(region string, creds *models.AWSCredentials) STSAPI {
+	return sts.New(sts.Options{
+		Region:      region,
+		Credentials: credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+	})
+}
+
+func (c *RealSSOClient) stsClient(region string, creds *models.AWSCredentials) STSAPI {
+	if c.NewSTSClient != nil {
+		return c.NewSTSClient(region, creds)
+	}
+	return NewSTSClient(region, creds)
+}
+
+// resolveAssumeRoleProfile returns the assume-role settings of a profile, or
+// nil when it has no role_arn.
+func resolveAssumeRoleProfile(profile string, sections map[string]map[string]string) (*AssumeRoleOptions, error) {
+	values, ok := sections[awsconfig.ProfileSection(profile)]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found in ~/.aws/config", profile)
+	}
+	if values["role_arn"] == "" {
+		return nil, nil
+	}
+	if values["source_profile"] == "" {
+		return nil, fmt.Errorf("profile %s sets role_arn without source_profile; credential_source is not supported", profile)
+	}
+	if values["mfa_serial"] != "" {
+		return nil, fmt.Errorf("profile %s sets mfa_serial, which is not supported for role chaining", profile)
+	}
+
+	opts := &AssumeRoleOptions{
+		RoleARN:         values["role_arn"],
+		SourceProfile:   values["source_profile"],
+		ExternalID:      values["external_id"],
+		RoleSessionName: values["role_session_name"],
+	}
+	if duration := values["duration_seconds"]; duration != "" {
+		seconds, err := strconv.ParseInt(duration, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration_seconds %q in profile %s", duration, profile)
+		}
+		opts.DurationSeconds = int32(seconds)
+	}
+	return opts, nil
+}
+
+// AssumeRole returns credentials for a role assumed with the credentials of
+// opts.SourceProfile, which may itself be an SSO or assume-role profile.
+// Credentials are cached in ~/.aws/cli/cache like those of SSO profiles.
+func (c *RealSSOClient) AssumeRole(opts AssumeRoleOptions) (*models.AWSCredentials, error) {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return nil, err
+	}
+	return c.assumeRole(opts, sections, nil)
+}
+
+func (c *RealSSOClient) assumeRole(opts AssumeRoleOptions, sections map[string]map[string]string, chain []string) (*models.AWSCredentials, error) {
+	cacheDir, err := roleCredentialCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(cacheDir, assumeRoleCacheKey(opts)+".json")
+
+	if creds, err := readRoleCredentialCache(cachePath); err == nil {
+		if expiresAt, err := time.Parse(time.RFC3339, creds.Expiration); err == nil && time.Until(expiresAt) > credentialExpiryMargin {
+			return creds, nil
+		}
+	}
+
+	sourceCreds, err := c.profileCredentials(opts.SourceProfile, sections, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionName := opts.RoleSessionName
+	if sessionName == "" {
+		sessionName = fmt.Sprintf("awsctl-%d", time.Now().Unix())
+	}
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(opts.RoleARN),
+		RoleSessionName: aws.String(sessionName),
+	}
+	if opts.ExternalID != "" {
+		input.ExternalId = aws.String(opts.ExternalID)
+	}
+	if opts.DurationSeconds > 0 {
+		input.DurationSeconds = aws.Int32(opts.DurationSeconds)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	output, err := c.stsClient(chainRegion(opts.SourceProfile, sections), sourceCreds).AssumeRole(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %s: %w", opts.RoleARN, err)
+	}
+	if output.Credentials == nil {
+		return nil, fmt.Errorf("failed to assume role %s: empty response", opts.RoleARN)
+	}
+
+	creds := &models.AWSCredentials{
+		AccessKeyID:     aws.ToString(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(output.Credentials.SessionToken),
+		Expiration:      aws.ToTime(output.Credentials.Expiration).UTC().Format(time.RFC3339),
+	}
+
+	if err := writeRoleCredentialCache(cachePath, "assume-role", creds); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache role credentials: %v\n", err)
+	}
+	return creds, nil
+}
+
+// assumeRoleCacheKey names cached assume-role credentials in
+// ~/.aws/cli/cache like the AWS CLI does, but also hashes the source profile
+// and role session name, so that profiles assuming the same role from
+// different identities do not share credentials.
+func assumeRoleCacheKey(opts AssumeRoleOptions) string {
+	args := map[string]any{
+		"RoleArn":       opts.RoleARN,
+		"SourceProfile": opts.SourceProfile,
+	}
+	if opts.ExternalID != "" {
+		args["ExternalId"] = opts.ExternalID
+	}
+	if opts.DurationSeconds > 0 {
+		args["DurationSeconds"] = opts.DurationSeconds
+	}
+	if opts.RoleSessionName != "" {
+		args["RoleSessionName"] = opts.RoleSessionName
+	}
+	data, _ := json.Marshal(args)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainRegion returns the region STS is called in for a role assumed from
+// profile: the first region set along its source_profile chain.
+func chainRegion(profile string, sections map[string]map[string]string) string {
+	var seen []string
+	for profile != "" && !slices.Contains(seen, profile) {
+		seen = append(seen, profile)
+		values := sections[awsconfig.ProfileSection(profile)]
+		if values["region"] != "" {
+			return values["region"]
+		}
+		if values["role_arn"] == "" {
+			if values["sso_region"] != "" {
+				return values["sso_region"]
+			}
+			break
+		}
+		profile = values["source_profile"]
+	}
+	return defaultSTSRegion
+}
+
+// sourceSSOProfile follows the source_profile chain of an assume-role profile
+// to the SSO profile at its root. Other profiles are returned unchanged.
+func sourceSSOProfile(profile string, sections map[string]map[string]string) (string, error) {
+	var chain []string
+	for {
+		if slices.Contains(chain, profile) {
+			return "", roleChainLoopError(append(chain, profile))
+		}
+		chain = append(chain, profile)
+		opts, err := resolveAssumeRoleProfile(profile, sections)
+		if err != nil || opts == nil {
+			return profile, nil
+		}
+		profile = opts.SourceProfile
+	}
+}
+
+func roleChainLoopError(chain []string) error {
+	return fmt.Errorf("source_profile loop: %s", strings.Join(chain, " -> "))
+}
+
+// ConfigureAssumeRoleProfile writes an assume-role profile to ~/.aws/config.
+// The region of the source profile is copied when it has one. An existing
+// profile is only updated when it assumes the same role.
+func (c *RealSSOClient) ConfigureAssumeRoleProfile(profile string, opts AssumeRoleOptions) error {
+	if !validProfileName.MatchString(profile) {
+		return fmt.Errorf("invalid profile name %q", profile)
+	}
+	if !IsValidRoleARN(opts.RoleARN) {
+		return fmt.Errorf("invalid role ARN %q", opts.RoleARN)
+	}
+	if profile == opts.SourceProfile {
+		return fmt.Errorf("profile %s cannot be its own source profile", profile)
+	}
+
+	return c.updateConfig(func(f *awsconfig.File) error {
+		section := awsconfig.ProfileSection(profile)
+		sourceSection := awsconfig.ProfileSection(opts.SourceProfile)
+		if !f.HasSection(sourceSection) {
+			return fmt.Errorf("source profile %s not found in ~/.aws/config", opts.SourceProfile)
+		}
+		if values := f.Values(section); values != nil && values["role_arn"] != opts.RoleARN {
+			return fmt.Errorf("profile %s already exists and does not assume %s", profile, opts.RoleARN)
+		}
+
+		f.Set(section, "role_arn", opts.RoleARN)
+		f.Set(section, "source_profile", opts.SourceProfile)
+		optional := []struct{ key, value string }{
+			{"external_id", opts.ExternalID},
+			{"duration_seconds", durationString(opts.DurationSeconds)},
+			{"role_session_name", opts.RoleSessionName},
+		}
+		for _, setting := range optional {
+			if setting.value != "" {
+				f.Set(section, setting.key, setting.value)
+			} else {
+				f.Delete(section, setting.key)
+			}
+		}
+		if _, ok := f.Get(section, "region"); !ok {
+			if region, ok := f.Get(sourceSection, "region"); ok && region != "" {
+				f.Set(section, "region", region)
+			}
+		}
+		return nil
+	})
+}
+
+// SetupAssumeRoleProfile creates an assume-role profile, prompting for the
+// source profile and profile name when they are not given, and checks that
+// the role can be assumed.
+func (c *RealSSOClient) SetupAssumeRoleProfile(profile string, opts AssumeRoleOptions) error {
+	if opts.SourceProfile == "" {
+		profiles, err := c.ValidProfiles()
+		if err != nil {
+			return fmt.Errorf("failed to list profiles: %w", err)
+		}
+		if len(profiles) == 0 {
+			return errors.New("no profiles found to assume the role from; run `awsctl sso setup` first")
+		}
+		opts.SourceProfile, err = c.Prompter.SelectFromList("Select the source profile", profiles)
+		if err != nil {
+			return err
+		}
+	}
+	if profile == "" {
+		var err error
+		profile, err = c.Prompter.PromptWithDefault("Profile name", roleProfileName(opts.RoleARN))
+		if err != nil {
+			return fmt.Errorf("failed to prompt for profile name: %w", err)
+		}
+	}
+
+	if err := c.ConfigureAssumeRoleProfile(profile, opts); err != nil {
+		return fmt.Errorf("failed to configure profile %s: %w", profile, err)
+	}
+	fmt.Printf("Configured profile %s to assume %s from %s\n", profile, opts.RoleARN, opts.SourceProfile)
+
+	if _, err := c.ProfileCredentials(profile); err != nil {
+		if errors.Is(err, ErrLoginRequired) {
+			fmt.Printf("Log in with `awsctl sso init` to use profile %s.\n", profile)
+			return nil
+		}
+		return fmt.Errorf("failed to verify profile %s: %w", profile, err)
+	}
+	fmt.Printf("Successfully assumed %s with profile %s\n", opts.RoleARN, profile)
+	printUseHint(profile)
+	return nil
+}
+
+// roleProfileName suggests a profile name for a role ARN, e.g.
+// "123456789012-deploy" for arn:aws:iam::123456789012:role/ci/deploy.
+func roleProfileName(roleARN string) string {
+	accountID, role := roleARNParts(roleARN)
+	if accountID == "" {
+		return ""
+	}
+	return accountID + "-" + slugify(role)
+}
+
+// roleARNParts returns the account ID and role name of an IAM role ARN, e.g.
+// "123456789012" and "deploy" for arn:aws:iam::123456789012:role/ci/deploy.
+func roleARNParts(arn string) (accountID, role string) {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 {
+		return "", ""
+	}
+	return parts[4], parts[5][strings.LastIndex(parts[5], "/")+1:]
+}
+
+func durationString(seconds int32) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(seconds))
+}
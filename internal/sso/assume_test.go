@@ -0,0 +1,245 @@
+package sso_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const roleChainConfig = logoutConfig + `
+[profile hub]
+sso_session = team
+sso_account_id = 111111111111
+sso_role_name = Admin
+region = eu-central-1
+
+[profile prod-deploy]
+role_arn = arn:aws:iam::333333333333:role/deploy
+source_profile = hub
+external_id = ext-123
+duration_seconds = 1800
+
+[profile prod-readonly]
+role_arn = arn:aws:iam::444444444444:role/ReadOnly
+source_profile = prod-deploy
+role_session_name = audit
+
+[profile loop-a]
+role_arn = arn:aws:iam::555555555555:role/a
+source_profile = loop-b
+
+[profile loop-b]
+role_arn = arn:aws:iam::555555555555:role/b
+source_profile = loop-a
+
+[profile no-source]
+role_arn = arn:aws:iam::555555555555:role/a
+`
+
+type assumeCall struct {
+	region string
+	source string
+	input  *sts.AssumeRoleInput
+}
+
+// assumeRoleSTS returns an STS client factory whose AssumeRole returns
+// credentials derived from the role ARN and records each call.
+func assumeRoleSTS(ctrl *gomock.Controller, calls *[]assumeCall, expiration time.Time) func(string, *models.AWSCredentials) sso.STSAPI {
+	return func(region string, creds *models.AWSCredentials) sso.STSAPI {
+		m := mock_sso.NewMockSTSAPI(ctrl)
+		m.EXPECT().AssumeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+				*calls = append(*calls, assumeCall{region: region, source: creds.AccessKeyID, input: in})
+				return &sts.AssumeRoleOutput{
+					Credentials: &ststypes.Credentials{
+						AccessKeyId:     aws.String("ASIA-" + aws.ToString(in.RoleArn)),
+						SecretAccessKey: aws.String("secret"),
+						SessionToken:    aws.String("token"),
+						Expiration:      aws.Time(expiration),
+					},
+				}, nil
+			}).AnyTimes()
+		return m
+	}
+}
+
+func newRoleChainClient(t *testing.T, ctrl *gomock.Controller, calls *[]assumeCall, expiration time.Time) (*sso.RealSSOClient, logoutFixture) {
+	t.Helper()
+	f := writeLogoutFixtures(t)
+	writeAWSConfig(t, f.home, roleChainConfig)
+	portalCalls := 0
+	return &sso.RealSSOClient{
+		NewPortalClient: roleCredentialsPortal(ctrl, &portalCalls, time.Now().Add(time.Hour)),
+		NewSTSClient:    assumeRoleSTS(ctrl, calls, expiration),
+	}, f
+}
+
+func TestProfileCredentials_AssumeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []assumeCall
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client, f := newRoleChainClient(t, ctrl, &calls, expiration)
+
+	creds, err := client.ProfileCredentials("prod-deploy")
+	require.NoError(t, err)
+	assert.Equal(t, &models.AWSCredentials{
+		AccessKeyID:     "ASIA-arn:aws:iam::333333333333:role/deploy",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      expiration.Format(time.RFC3339),
+	}, creds)
+
+	require.Len(t, calls, 1)
+	assert.Equal(t, "eu-central-1", calls[0].region)
+	assert.Equal(t, "AKIA-111111111111", calls[0].source)
+	assert.Equal(t, "ext-123", aws.ToString(calls[0].input.ExternalId))
+	assert.Equal(t, int32(1800), aws.ToInt32(calls[0].input.DurationSeconds))
+	assert.Regexp(t, `^awsctl-\d+$`, aws.ToString(calls[0].input.RoleSessionName))
+
+	cachePath := filepath.Join(f.home, ".aws", "cli", "cache", sha1Hex(`{"DurationSeconds":1800,"ExternalId":"ext-123","RoleArn":"arn:aws:iam::333333333333:role/deploy","SourceProfile":"hub"}`)+".json")
+	data, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	var cache models.RoleCredentialCache
+	require.NoError(t, json.Unmarshal(data, &cache))
+	assert.Equal(t, "assume-role", cache.ProviderType)
+	assert.Equal(t, creds.AccessKeyID, cache.Credentials.AccessKeyID)
+
+	cached, err := client.ProfileCredentials("prod-deploy")
+	require.NoError(t, err)
+	assert.Equal(t, creds, cached)
+	assert.Len(t, calls, 1, "valid cached credentials must be reused")
+}
+
+func TestProfileCredentials_AssumeRoleCachePerSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []assumeCall
+	client, f := newRoleChainClient(t, ctrl, &calls, time.Now().Add(time.Hour))
+	writeAWSConfig(t, f.home, roleChainConfig+`
+[profile deploy-from-readonly]
+role_arn = arn:aws:iam::333333333333:role/deploy
+source_profile = prod-readonly
+external_id = ext-123
+duration_seconds = 1800
+`)
+
+	_, err := client.ProfileCredentials("prod-deploy")
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+
+	_, err = client.ProfileCredentials("deploy-from-readonly")
+	require.NoError(t, err)
+	require.Len(t, calls, 3, "the same role from another source profile must not reuse the cache")
+	assert.Equal(t, "arn:aws:iam::333333333333:role/deploy", aws.ToString(calls[2].input.RoleArn))
+	assert.Equal(t, "ASIA-arn:aws:iam::444444444444:role/ReadOnly", calls[2].source)
+}
+
+func TestProfileCredentials_RoleChain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []assumeCall
+	client, _ := newRoleChainClient(t, ctrl, &calls, time.Now().Add(time.Hour))
+
+	creds, err := client.ProfileCredentials("prod-readonly")
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA-arn:aws:iam::444444444444:role/ReadOnly", creds.AccessKeyID)
+
+	require.Len(t, calls, 2)
+	assert.Equal(t, "AKIA-111111111111", calls[0].source)
+	assert.Equal(t, "ASIA-arn:aws:iam::333333333333:role/deploy", calls[1].source)
+	assert.Equal(t, "audit", aws.ToString(calls[1].input.RoleSessionName))
+	assert.Nil(t, calls[1].input.ExternalId)
+}
+
+func TestProfileCredentials_RoleChainErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []assumeCall
+	client, _ := newRoleChainClient(t, ctrl, &calls, time.Now().Add(time.Hour))
+
+	_, err := client.ProfileCredentials("loop-a")
+	assert.EqualError(t, err, "source_profile loop: loop-a -> loop-b -> loop-a")
+
+	_, err = client.ProfileCredentials("no-source")
+	assert.EqualError(t, err, "profile no-source sets role_arn without source_profile; credential_source is not supported")
+
+	_, err = client.AssumeRole(sso.AssumeRoleOptions{RoleARN: "arn:aws:iam::555555555555:role/x", SourceProfile: "static"})
+	assert.EqualError(t, err, "profile static is not an SSO profile")
+	assert.Empty(t, calls)
+}
+
+func TestConfigureAssumeRoleProfile(t *testing.T) {
+	home := setTestHome(t)
+	writeAWSConfig(t, home, roleChainConfig)
+	client := &sso.RealSSOClient{}
+
+	opts := sso.AssumeRoleOptions{
+		RoleARN:         "arn:aws:iam::333333333333:role/deploy",
+		SourceProfile:   "hub",
+		RoleSessionName: "ci",
+	}
+	require.NoError(t, client.ConfigureAssumeRoleProfile("staging-deploy", opts))
+	assert.Equal(t, map[string]string{
+		"role_arn":          "arn:aws:iam::333333333333:role/deploy",
+		"source_profile":    "hub",
+		"role_session_name": "ci",
+		"region":            "eu-central-1",
+	}, readAWSConfig(t, home).Values("profile staging-deploy"))
+
+	opts.SourceProfile = "team-admin"
+	opts.DurationSeconds = 900
+	require.NoError(t, client.ConfigureAssumeRoleProfile("prod-deploy", opts))
+	assert.Equal(t, map[string]string{
+		"role_arn":          "arn:aws:iam::333333333333:role/deploy",
+		"source_profile":    "team-admin",
+		"duration_seconds":  "900",
+		"role_session_name": "ci",
+	}, readAWSConfig(t, home).Values("profile prod-deploy"))
+
+	err := client.ConfigureAssumeRoleProfile("hub", opts)
+	assert.EqualError(t, err, "profile hub already exists and does not assume arn:aws:iam::333333333333:role/deploy")
+
+	opts.SourceProfile = "missing"
+	err = client.ConfigureAssumeRoleProfile("new", opts)
+	assert.EqualError(t, err, "source profile missing not found in ~/.aws/config")
+
+	err = client.ConfigureAssumeRoleProfile("new", sso.AssumeRoleOptions{RoleARN: "deploy", SourceProfile: "hub"})
+	assert.EqualError(t, err, `invalid role ARN "deploy"`)
+}
+
+func TestSetupAssumeRoleProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []assumeCall
+	client, f := newRoleChainClient(t, ctrl, &calls, time.Now().Add(time.Hour))
+	prompter := mock_sso.NewMockPrompter(ctrl)
+	client.Prompter = prompter
+	prompter.EXPECT().SelectFromList("Select the source profile", gomock.Any()).Return("hub", nil)
+	prompter.EXPECT().PromptWithDefault("Profile name", "666666666666-deploy").Return("ops", nil)
+
+	require.NoError(t, client.SetupAssumeRoleProfile("", sso.AssumeRoleOptions{RoleARN: "arn:aws:iam::666666666666:role/ci/Deploy"}))
+
+	values := readAWSConfig(t, f.home).Values("profile ops")
+	assert.Equal(t, "hub", values["source_profile"])
+	require.Len(t, calls, 1)
+	assert.Equal(t, "arn:aws:iam::666666666666:role/ci/Deploy", aws.ToString(calls[0].input.RoleArn))
+}
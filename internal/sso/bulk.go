@@ -0,0 +1,261 @@
+package sso
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BerryBytes/awsctl/models"
+)
+
+const (
+	profileCreate    = "create"
+	profileUpdate    = "update"
+	profileUnchanged = "unchanged"
+	profileStale     = "stale"
+	profileConflict  = "conflict"
+)
+
+// profilePlanEntry is one row of the bulk setup plan.
+type profilePlanEntry struct {
+	Action      string
+	Profile     string
+	AccountID   string
+	AccountName string
+	Role        string
+}
+
+// profileFilter selects the account/role pairs bulk setup creates profiles
+// for. Nil patterns match everything.
+type profileFilter struct {
+	includeAccount *regexp.Regexp
+	excludeAccount *regexp.Regexp
+	includeRole    *regexp.Regexp
+	excludeRole    *regexp.Regexp
+}
+
+func compileFilterPattern(flag, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern %q: %w", flag, pattern, err)
+	}
+	return re, nil
+}
+
+func newProfileFilter(opts SSOFlagOptions) (*profileFilter, error) {
+	var f profileFilter
+	var err error
+	if f.includeAccount, err = compileFilterPattern("--include-account", opts.IncludeAccount); err != nil {
+		return nil, err
+	}
+	if f.excludeAccount, err = compileFilterPattern("--exclude-account", opts.ExcludeAccount); err != nil {
+		return nil, err
+	}
+	if f.includeRole, err = compileFilterPattern("--include-role", opts.IncludeRole); err != nil {
+		return nil, err
+	}
+	if f.excludeRole, err = compileFilterPattern("--exclude-role", opts.ExcludeRole); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (f *profileFilter) matches(accountName, role string) bool {
+	if f.includeAccount != nil && !f.includeAccount.MatchString(accountName) {
+		return false
+	}
+	if f.excludeAccount != nil && f.excludeAccount.MatchString(accountName) {
+		return false
+	}
+	if f.includeRole != nil && !f.includeRole.MatchString(role) {
+		return false
+	}
+	if f.excludeRole != nil && f.excludeRole.MatchString(role) {
+		return false
+	}
+	return true
+}
+
+// planProfiles compares the profiles bulk setup would write against the
+// existing ~/.aws/config sections. Profiles of the session whose account/role
+// is no longer visible are reported as stale, and names already used by a
+// profile for another account or role as conflicts.
+func (c *RealSSOClient) planProfiles(session *models.SSOSession, accounts []models.SSOAccount, filter *profileFilter, existing map[string]map[string]string) ([]profilePlanEntry, error) {
+	var plan []profilePlanEntry
+	visible := make(map[string]bool)
+	used := make(map[string]bool)
+
+	for _, account := range accounts {
+		for _, role := range account.Roles {
+			visible[account.AccountID+"/"+role] = true
+			if !filter.matches(account.AccountName, role) {
+				continue
+			}
+
+			profile, err := c.profileName(session, account, role)
+			if err != nil {
+				return nil, err
+			}
+			profile = uniqueProfileName(profile, account.AccountID, role, used)
+			used[profile] = true
+
+			action := profileCreate
+			if current, ok := existing["profile "+profile]; ok && !samePermissionSet(current, account.AccountID, role) {
+				action = profileConflict
+			} else if ok {
+				action = profileUnchanged
+				for key, value := range ssoProfileSettings(session.Region, account.AccountID, role, session.StartURL, session.Name) {
+					if current[key] != value {
+						action = profileUpdate
+						break
+					}
+				}
+			}
+
+			plan = append(plan, profilePlanEntry{
+				Action:      action,
+				Profile:     profile,
+				AccountID:   account.AccountID,
+				AccountName: account.AccountName,
+				Role:        role,
+			})
+		}
+	}
+
+	var stale []profilePlanEntry
+	for section, values := range existing {
+		profile, ok := strings.CutPrefix(section, "profile ")
+		if !ok || used[profile] || !belongsToSession(values, session) {
+			continue
+		}
+		if !visible[values["sso_account_id"]+"/"+values["sso_role_name"]] {
+			stale = append(stale, profilePlanEntry{
+				Action:    profileStale,
+				Profile:   profile,
+				AccountID: values["sso_account_id"],
+				Role:      values["sso_role_name"],
+			})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Profile < stale[j].Profile })
+
+	return append(plan, stale...), nil
+}
+
+var invalidProfileChars = regexp.MustCompile(`[^A-Za-z0-9._@+-]+`)
+
+// uniqueProfileName returns profile, or when an earlier pair of the plan uses
+// that name, profile suffixed with the account ID, then the role, then a
+// counter, whichever is first unused.
+func uniqueProfileName(profile, accountID, role string, used map[string]bool) string {
+	if !used[profile] {
+		return profile
+	}
+	profile += "-" + accountID
+	if !used[profile] {
+		return profile
+	}
+	profile += "-" + invalidProfileChars.ReplaceAllString(role, "-")
+	if !used[profile] {
+		return profile
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", profile, n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+func belongsToSession(values map[string]string, session *models.SSOSession) bool {
+	if values["sso_session"] != "" {
+		return values["sso_session"] == session.Name
+	}
+	return strings.TrimSuffix(values["sso_start_url"], "#") == session.StartURL
+}
+
+func printProfilePlan(plan []profilePlanEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ACTION\tPROFILE\tACCOUNT\tROLE")
+	for _, entry := range plan {
+		account := entry.AccountID
+		if entry.AccountName != "" {
+			account = fmt.Sprintf("%s (%s)", entry.AccountID, entry.AccountName)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Action, entry.Profile, account, entry.Role)
+	}
+	_ = w.Flush()
+}
+
+// setupAllProfiles writes one profile per visible account/role pair of the
+// session, updating profiles whose settings changed.
+func (c *RealSSOClient) setupAllProfiles(session *models.SSOSession, opts SSOFlagOptions) error {
+	filter, err := newProfileFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nFetching available AWS accounts and roles...")
+	accounts, err := c.ListSSOAccounts(session.Region, session.StartURL)
+	if err != nil {
+		return fmt.Errorf("error listing accounts: %w", err)
+	}
+
+	existing, err := c.readConfigSections()
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.planProfiles(session, accounts, filter, existing)
+	if err != nil {
+		return err
+	}
+	if len(plan) == 0 {
+		fmt.Println("No account/role pairs match the given filters.")
+		return nil
+	}
+
+	fmt.Println()
+	printProfilePlan(plan)
+
+	counts := make(map[string]int)
+	for _, entry := range plan {
+		counts[entry.Action]++
+	}
+
+	if opts.DryRun {
+		fmt.Printf("\nDry run: %d to create, %d to update, %d unchanged, %d stale. No profiles were written.\n",
+			counts[profileCreate], counts[profileUpdate], counts[profileUnchanged], counts[profileStale])
+		printConflicts(counts[profileConflict])
+		return nil
+	}
+
+	for _, entry := range plan {
+		if entry.Action != profileCreate && entry.Action != profileUpdate {
+			continue
+		}
+		if err := c.ConfigureSSOProfile(entry.Profile, session.Region, entry.AccountID, entry.Role, session.StartURL, session.Name); err != nil {
+			return fmt.Errorf("failed to configure SSO profile %s: %w", entry.Profile, err)
+		}
+	}
+
+	fmt.Printf("\nCreated %d, updated %d, unchanged %d profiles for sso-session %s.\n",
+		counts[profileCreate], counts[profileUpdate], counts[profileUnchanged], session.Name)
+	if counts[profileStale] > 0 {
+		fmt.Printf("%d profiles no longer have access and were left untouched; remove them if they are no longer needed.\n", counts[profileStale])
+	}
+	printConflicts(counts[profileConflict])
+	return nil
+}
+
+func printConflicts(count int) {
+	if count > 0 {
+		fmt.Printf("%d profiles were skipped because a profile with the same name exists for another account or role; set an alias under accounts or change profileNameTemplate.\n", count)
+	}
+}
@@ -0,0 +1,342 @@
+package sso_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(&buf, r)
+		close(done)
+	}()
+
+	defer func() {
+		os.Stdout = orig
+	}()
+	fn()
+	_ = w.Close()
+	<-done
+	return buf.String()
+}
+
+func bulkPortal(ctrl *gomock.Controller) *mock_sso.MockSSOPortalAPI {
+	m := mock_sso.NewMockSSOPortalAPI(ctrl)
+	m.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&awssso.ListAccountsOutput{
+			AccountList: []ssotypes.AccountInfo{
+				accountInfo("111111111111", "prod"),
+				accountInfo("222222222222", "sandbox"),
+			},
+		}, nil).AnyTimes()
+	m.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *awssso.ListAccountRolesInput, _ ...func(*awssso.Options)) (*awssso.ListAccountRolesOutput, error) {
+			roles := []ssotypes.RoleInfo{{RoleName: aws.String("Admin")}, {RoleName: aws.String("ReadOnly")}}
+			return &awssso.ListAccountRolesOutput{RoleList: roles}, nil
+		}).AnyTimes()
+	return m
+}
+
+// changedProfiles returns the profiles whose settings differ between before
+// and the config file now in home.
+func changedProfiles(t *testing.T, home, before string) []string {
+	t.Helper()
+	old := awsconfig.Parse([]byte(before))
+	current := readAWSConfig(t, home)
+
+	changed := []string{}
+	for _, profile := range current.Profiles() {
+		section := awsconfig.ProfileSection(profile)
+		if !maps.Equal(old.Values(section), current.Values(section)) {
+			changed = append(changed, profile)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func TestSetupSSO_All(t *testing.T) {
+	const existingConfig = `[sso-session team]
+sso_start_url = https://team.awsapps.com/start
+sso_region = us-east-1
+sso_registration_scopes = sso:account:access
+
+[profile team-prod-adm]
+sso_session = team
+sso_region = us-east-1
+sso_account_id = 111111111111
+sso_start_url = https://team.awsapps.com/start
+sso_role_name = Admin
+region = us-east-1
+output = json
+
+[profile team-prod-ro]
+sso_session = team
+sso_region = us-east-1
+sso_account_id = 111111111111
+sso_start_url = https://team.awsapps.com/start
+sso_role_name = ReadOnly
+region = eu-west-1
+output = json
+
+[profile team-legacy-adm]
+sso_session = team
+sso_account_id = 333333333333
+sso_role_name = Admin
+
+[profile unrelated]
+sso_session = other
+sso_account_id = 444444444444
+sso_role_name = Admin
+`
+
+	tests := []struct {
+		name         string
+		opts         sso.SSOFlagOptions
+		config       string
+		custom       models.Config
+		wantWritten  []string
+		wantOutput   []string
+		unwantOutput []string
+	}{
+		{
+			name:        "creates a profile per account and role",
+			opts:        sso.SSOFlagOptions{All: true},
+			wantWritten: []string{"team-prod-adm", "team-prod-ro", "team-sandbox-adm", "team-sandbox-ro"},
+			wantOutput:  []string{"Created 4, updated 0, unchanged 0"},
+		},
+		{
+			name:        "applies include and exclude filters",
+			opts:        sso.SSOFlagOptions{All: true, IncludeAccount: "^prod$", ExcludeRole: "^Read"},
+			wantWritten: []string{"team-prod-adm"},
+		},
+		{
+			name:         "is idempotent and reports stale profiles",
+			opts:         sso.SSOFlagOptions{All: true},
+			config:       existingConfig,
+			wantWritten:  []string{"team-prod-ro", "team-sandbox-adm", "team-sandbox-ro"},
+			wantOutput:   []string{"Created 2, updated 1, unchanged 1", "stale", "team-legacy-adm", "1 profiles no longer have access"},
+			unwantOutput: []string{"unrelated"},
+		},
+		{
+			name:        "dry run writes nothing",
+			opts:        sso.SSOFlagOptions{All: true, DryRun: true},
+			config:      existingConfig,
+			wantWritten: []string{},
+			wantOutput:  []string{"ACTION", "create", "team-sandbox-adm", "Dry run: 2 to create, 1 to update, 1 unchanged, 1 stale"},
+		},
+		{
+			name: "names profiles from the template",
+			opts: sso.SSOFlagOptions{All: true},
+			custom: models.Config{
+				ProfileNameTemplate: "{{.Tags.env}}-{{.Alias}}-{{.RoleShort}}",
+				Accounts: map[string]models.AccountSettings{
+					"111111111111": {Alias: "payments", Tags: map[string]string{"env": "prd"}},
+					"222222222222": {Tags: map[string]string{"env": "dev"}},
+				},
+			},
+			wantWritten: []string{"dev-sandbox-adm", "dev-sandbox-ro", "prd-payments-adm", "prd-payments-ro"},
+		},
+		{
+			name: "skips names used by another account or role",
+			opts: sso.SSOFlagOptions{All: true},
+			config: `[profile prd-payments-adm]
+region = us-east-1
+
+[profile dev-sandbox-adm]
+sso_account_id = 999999999999
+sso_role_name = Admin
+`,
+			custom: models.Config{
+				ProfileNameTemplate: "{{.Tags.env}}-{{.Alias}}-{{.RoleShort}}",
+				Accounts: map[string]models.AccountSettings{
+					"111111111111": {Alias: "payments", Tags: map[string]string{"env": "prd"}},
+					"222222222222": {Tags: map[string]string{"env": "dev"}},
+				},
+			},
+			wantWritten: []string{"dev-sandbox-ro", "prd-payments-ro"},
+			wantOutput:  []string{"conflict", "Created 2, updated 0, unchanged 0", "2 profiles were skipped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			home := setTestHome(t)
+			if tt.config != "" {
+				writeAWSConfig(t, home, tt.config)
+			}
+
+			server := newFakeOIDCServer(t)
+			mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+			portal := bulkPortal(ctrl)
+
+			client := &sso.RealSSOClient{
+				Executor:        mockExecutor,
+				NewOIDCClient:   server.clientFactory(),
+				NewPortalClient: func(string) sso.SSOPortalAPI { return portal },
+				Sleep:           func(time.Duration) {},
+				OpenBrowser:     func(string) error { return nil },
+			}
+			client.Config.AWSConfigDir = filepath.Join(home, ".config", "awsctl")
+			client.Config.RawCustomConfig = &tt.custom
+
+			opts := tt.opts
+			opts.Name = "team"
+			opts.StartURL = "https://team.awsapps.com/start"
+			opts.Region = "us-east-1"
+
+			var err error
+			output := captureStdout(t, func() {
+				err = client.SetupSSO(opts)
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantWritten, changedProfiles(t, home, tt.config))
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, output, want)
+			}
+			for _, unwanted := range tt.unwantOutput {
+				assert.NotContains(t, output, unwanted)
+			}
+
+			if profile := readAWSConfig(t, home).Values("profile team-sandbox-ro"); profile != nil {
+				assert.Equal(t, map[string]string{
+					"sso_session":    "team",
+					"sso_region":     "us-east-1",
+					"sso_account_id": "222222222222",
+					"sso_start_url":  "https://team.awsapps.com/start",
+					"sso_role_name":  "ReadOnly",
+					"region":         "us-east-1",
+					"output":         "json",
+				}, profile)
+			}
+		})
+	}
+}
+
+func TestSetupSSO_AllTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		errContains string
+	}{
+		{name: "missing tag", template: "{{.Tags.env}}-{{.RoleShort}}", errContains: "failed to build profile name for account 111111111111 role Admin"},
+		{name: "invalid name", template: "{{.AccountName}} {{.Role}}", errContains: `produced invalid profile name "prod Admin"`},
+		{name: "invalid template", template: "{{.Alias", errContains: "invalid profileNameTemplate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			home := setTestHome(t)
+			server := newFakeOIDCServer(t)
+			portal := bulkPortal(ctrl)
+
+			client := &sso.RealSSOClient{
+				Executor:        mock_awsctl.NewMockCommandExecutor(ctrl),
+				NewOIDCClient:   server.clientFactory(),
+				NewPortalClient: func(string) sso.SSOPortalAPI { return portal },
+				Sleep:           func(time.Duration) {},
+				OpenBrowser:     func(string) error { return nil },
+			}
+			client.Config.AWSConfigDir = filepath.Join(home, ".config", "awsctl")
+			client.Config.RawCustomConfig = &models.Config{ProfileNameTemplate: tt.template}
+
+			var err error
+			captureStdout(t, func() {
+				err = client.SetupSSO(sso.SSOFlagOptions{
+					Name:     "team",
+					StartURL: "https://team.awsapps.com/start",
+					Region:   "us-east-1",
+					All:      true,
+				})
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestSetupSSO_AllNameCollisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	home := setTestHome(t)
+	server := newFakeOIDCServer(t)
+	portal := mock_sso.NewMockSSOPortalAPI(ctrl)
+	portal.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&awssso.ListAccountsOutput{AccountList: []ssotypes.AccountInfo{accountInfo("111111111111", "prod")}}, nil).AnyTimes()
+	portal.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&awssso.ListAccountRolesOutput{RoleList: []ssotypes.RoleInfo{
+			{RoleName: aws.String("Admin")},
+			{RoleName: aws.String("ReadOnly")},
+			{RoleName: aws.String("Billing")},
+			{RoleName: aws.String("Ops Team")},
+			{RoleName: aws.String("Ops,Team")},
+		}}, nil).AnyTimes()
+
+	client := &sso.RealSSOClient{
+		Executor:        mock_awsctl.NewMockCommandExecutor(ctrl),
+		NewOIDCClient:   server.clientFactory(),
+		NewPortalClient: func(string) sso.SSOPortalAPI { return portal },
+		Sleep:           func(time.Duration) {},
+		OpenBrowser:     func(string) error { return nil },
+	}
+	client.Config.AWSConfigDir = filepath.Join(home, ".config", "awsctl")
+	client.Config.RawCustomConfig = &models.Config{ProfileNameTemplate: "{{.AccountName}}"}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = client.SetupSSO(sso.SSOFlagOptions{
+			Name:     "team",
+			StartURL: "https://team.awsapps.com/start",
+			Region:   "us-east-1",
+			All:      true,
+		})
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, "Created 5, updated 0, unchanged 0")
+
+	cfg := readAWSConfig(t, home)
+	for profile, role := range map[string]string{
+		"prod":                         "Admin",
+		"prod-111111111111":            "Billing",
+		"prod-111111111111-Ops-Team":   "Ops Team",
+		"prod-111111111111-Ops-Team-2": "Ops,Team",
+		"prod-111111111111-ReadOnly":   "ReadOnly",
+	} {
+		assert.Equal(t, role, cfg.Values("profile " + profile)["sso_role_name"], profile)
+	}
+}
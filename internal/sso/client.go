@@ -3,9 +3,10 @@ package sso
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
@@ -15,18 +16,40 @@ import (
 	"github.com/BerryBytes/awsctl/models"
 	"github.com/BerryBytes/awsctl/utils/common"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/spf13/afero"
 )
 
 type RealSSOClient struct {
-	TokenCache models.TokenCache
-	Config     config.Config
-	Prompter   Prompter
-	Executor   common.CommandExecutor
+	TokenCache      models.TokenCache
+	Config          config.Config
+	Fs              afero.Fs
+	Prompter        Prompter
+	Executor        common.CommandExecutor
+	NewOIDCClient   func(region string) OIDCAPI
+	NewPortalClient func(region string) SSOPortalAPI
+	NewSTSClient    func(region string, creds *models.AWSCredentials) STSAPI
+	OpenBrowser     func(url string) error
+	Sleep           func(d time.Duration)
 }
 type SSOFlagOptions struct {
 	StartURL string
 	Region   string
 	Name     string
+
+	// All creates a profile for every visible account and role instead of
+	// prompting for a single one.
+	All            bool
+	IncludeAccount string
+	ExcludeAccount string
+	IncludeRole    string
+	ExcludeRole    string
+	DryRun         bool
+
+	// SetDefault also writes the selected profile to [default].
+	SetDefault bool
 }
 
 func NewSSOClient(prompter Prompter, executor common.CommandExecutor) (SSOClient, error) {
@@ -41,34 +64,34 @@ func NewSSOClient(prompter Prompter, executor common.CommandExecutor) (SSOClient
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 	return &RealSSOClient{
-		Config:   *cfg,
-		Prompter: prompter,
-		Executor: executor,
+		Config:          *cfg,
+		Prompter:        prompter,
+		Executor:        executor,
+		NewOIDCClient:   NewOIDCClient,
+		NewPortalClient: NewSSOPortalClient,
+		NewSTSClient:    NewSTSClient,
 	}, nil
 }
 
-func (c *RealSSOClient) GetSsoAccessTokenFromCache(profile string) (*models.SSOCache, time.Time, error) {
-	startURL, err := c.ConfigureGet("sso_start_url", profile)
-	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to get sso_start_url for profile %s: %v", profile, err)
-	}
-	startURL = strings.TrimSuffix(startURL, "#")
-
+// findSSOCacheFile returns the most recently written SSO token cache entry for
+// the start URL and its path, or a nil cache when there is none.
+func findSSOCacheFile(startURL string) (*models.SSOCache, string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to get user home directory: %v", err)
+		return nil, "", fmt.Errorf("failed to get user home directory: %v", err)
 	}
 	cacheDir := filepath.Join(homeDir, ".aws", "sso", "cache")
 
 	files, err := os.ReadDir(cacheDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, time.Time{}, fmt.Errorf("no matching SSO cache file found")
+			return nil, "", nil
 		}
-		return nil, time.Time{}, fmt.Errorf("failed to read SSO cache directory: %v", err)
+		return nil, "", fmt.Errorf("failed to read SSO cache directory: %v", err)
 	}
 
 	var selectedCache *models.SSOCache
+	var selectedPath string
 	var latestModTime time.Time
 
 	for _, file := range files {
@@ -94,11 +117,26 @@ func (c *RealSSOClient) GetSsoAccessTokenFromCache(profile string) (*models.SSOC
 				if fileInfo.ModTime().After(latestModTime) {
 					latestModTime = fileInfo.ModTime()
 					selectedCache = &cache
+					selectedPath = cacheFilePath
 				}
 			}
 		}
 	}
 
+	return selectedCache, selectedPath, nil
+}
+
+func (c *RealSSOClient) GetSsoAccessTokenFromCache(profile string) (*models.SSOCache, time.Time, error) {
+	startURL, err := c.ConfigureGet("sso_start_url", profile)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get sso_start_url for profile %s: %v", profile, err)
+	}
+	startURL = strings.TrimSuffix(startURL, "#")
+
+	selectedCache, selectedPath, err := findSSOCacheFile(startURL)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
 	if selectedCache == nil {
 		return nil, time.Time{}, fmt.Errorf("no matching SSO cache file found for profile %s, start URL %s", profile, startURL)
 	}
@@ -111,6 +149,16 @@ func (c *RealSSOClient) GetSsoAccessTokenFromCache(profile string) (*models.SSOC
 		}
 	}
 
+	if time.Until(expiryTime) < c.tokenRefreshWindow() {
+		refreshed, refreshedExpiry, err := c.refreshCachedToken(selectedPath, selectedCache)
+		if err == nil {
+			return refreshed, refreshedExpiry, nil
+		}
+		if expiryTime.After(time.Now()) {
+			return selectedCache, expiryTime, nil
+		}
+	}
+
 	if expiryTime.Before(time.Now()) {
 		fmt.Println("Token expired. Re-login required.")
 		err := c.SSOLogin(profile, true, false)
@@ -127,14 +175,33 @@ func (c *RealSSOClient) GetSsoAccessTokenFromCache(profile string) (*models.SSOC
 	return selectedCache, expiryTime, nil
 }
 
+// tokenRefreshWindow returns how long before expiry a cached access token is
+// silently refreshed.
+func (c *RealSSOClient) tokenRefreshWindow() time.Duration {
+	if c.Config.RawCustomConfig != nil && c.Config.RawCustomConfig.TokenRefreshWindowMinutes > 0 {
+		return time.Duration(c.Config.RawCustomConfig.TokenRefreshWindowMinutes) * time.Minute
+	}
+	return defaultTokenRefreshWindow
+}
+
+// setsDefaultProfile reports whether the selected profile is written to
+// [default], either because it was asked for or because the config file keeps
+// the behaviour from before per-shell activation.
+func (c *RealSSOClient) setsDefaultProfile(requested bool) bool {
+	return requested || (c.Config.RawCustomConfig != nil && c.Config.RawCustomConfig.SetDefaultProfile)
+}
+
 func (c *RealSSOClient) GetCachedSsoAccessToken(profile string) (string, time.Time, error) {
 	c.TokenCache.Mu.Lock()
-	defer c.TokenCache.Mu.Unlock()
+	accessToken, expiry := c.TokenCache.AccessToken, c.TokenCache.Expiry
+	c.TokenCache.Mu.Unlock()
 
-	if c.TokenCache.AccessToken != "" && time.Now().Before(c.TokenCache.Expiry) {
-		return c.TokenCache.AccessToken, c.TokenCache.Expiry, nil
+	if accessToken != "" && time.Now().Add(c.tokenRefreshWindow()).Before(expiry) {
+		return accessToken, expiry, nil
 	}
 
+	// The lock is not held here, as an expired token starts a login, which
+	// clears the in-memory token.
 	cachedSSO, expiry, err := c.GetSsoAccessTokenFromCache(profile)
 	if err != nil {
 		return "", time.Time{}, err
@@ -144,8 +211,10 @@ func (c *RealSSOClient) GetCachedSsoAccessToken(profile string) (string, time.Ti
 		return "", time.Time{}, fmt.Errorf("no access token found in cache for profile %s", profile)
 	}
 
+	c.TokenCache.Mu.Lock()
 	c.TokenCache.AccessToken = *cachedSSO.AccessToken
 	c.TokenCache.Expiry = expiry
+	c.TokenCache.Mu.Unlock()
 
 	return *cachedSSO.AccessToken, expiry, nil
 }
@@ -156,19 +225,23 @@ func (c *RealSSOClient) GetSSOAccountName(accountID, profile string) (string, er
 		return "", fmt.Errorf("failed to retrieve SSO access token: %v", err)
 	}
 
-	output, err := c.Executor.RunCommand("aws", "sso", "list-accounts", "--access-token", accessToken, "--output", "json")
+	ssoRegion, err := c.ConfigureGet("sso_region", profile)
 	if err != nil {
-		return "", fmt.Errorf("failed to list AWS accounts: %v", err)
-	}
-
-	var response models.AccountNameResponse
-	if err := json.Unmarshal(output, &response); err != nil {
-		return "", fmt.Errorf("failed to unmarshal accounts: %v", err)
+		return "", fmt.Errorf("failed to get sso_region for profile %s: %w", profile, err)
 	}
 
-	for _, account := range response.AccountList {
-		if account.AccountID == accountID {
-			return account.AccountName, nil
+	paginator := awssso.NewListAccountsPaginator(c.portalClient(ssoRegion), &awssso.ListAccountsInput{
+		AccessToken: aws.String(accessToken),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return "", fmt.Errorf("failed to list AWS accounts: %w", err)
+		}
+		for _, account := range page.AccountList {
+			if aws.ToString(account.AccountId) == accountID {
+				return aws.ToString(account.AccountName), nil
+			}
 		}
 	}
 
@@ -176,51 +249,58 @@ func (c *RealSSOClient) GetSSOAccountName(accountID, profile string) (string, er
 }
 
 func (c *RealSSOClient) SSOLogin(awsProfile string, refresh, noBrowser bool) error {
-	args := []string{"sso", "login"}
-	if noBrowser {
-		args = append(args, "--no-browser")
+	if sections, err := c.readConfigSections(); err == nil {
+		if root, err := sourceSSOProfile(awsProfile, sections); err == nil {
+			awsProfile = root
+		}
+	}
+
+	startURL, err := c.ConfigureGet("sso_start_url", awsProfile)
+	if err != nil {
+		return fmt.Errorf("failed to get sso_start_url for profile %s: %w", awsProfile, err)
+	}
+	ssoRegion, err := c.ConfigureGet("sso_region", awsProfile)
+	if err != nil {
+		return fmt.Errorf("failed to get sso_region for profile %s: %w", awsProfile, err)
 	}
-	args = append(args, "--profile", awsProfile)
 
+	var scopes []string
+	sessionName, _ := c.ConfigureGet("sso_session", awsProfile)
+	if sessionName != "" {
+		section, err := c.readConfigSection(fmt.Sprintf("sso-session %s", sessionName))
+		if err == nil && section["sso_registration_scopes"] != "" {
+			scopes = parseScopes(section["sso_registration_scopes"])
+		} else {
+			scopes = []string{"sso:account:access"}
+		}
+	}
+
+	return c.runDeviceLogin(sessionName, strings.TrimSuffix(startURL, "#"), ssoRegion, scopes, noBrowser)
+}
+
+func (c *RealSSOClient) runDeviceLogin(sessionName, startURL, region string, scopes []string, noBrowser bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err := c.Executor.RunInteractiveCommand(ctx, "aws", args...)
+	_, err := c.deviceLogin(ctx, sessionName, startURL, region, scopes, noBrowser)
 	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
+		if errors.Is(err, context.DeadlineExceeded) {
 			return fmt.Errorf("SSO login timed out: the login flow was canceled or not completed")
 		}
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() == 130 {
-				return promptUtils.ErrInterrupted
-			}
-			return fmt.Errorf("SSO login failed: %v", exitErr)
+		if errors.Is(err, context.Canceled) {
+			return promptUtils.ErrInterrupted
 		}
-		return fmt.Errorf("error during SSO login: %v", err)
+		return fmt.Errorf("SSO login failed: %w", err)
 	}
-	return nil
-}
 
-func (c *RealSSOClient) GetRoleCredentials(accessToken, roleName, accountID string) (*models.AWSCredentials, error) {
-	output, err := c.Executor.RunCommand("aws", "sso", "get-role-credentials",
-		"--access-token", accessToken,
-		"--role-name", roleName,
-		"--account-id", accountID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get role credentials: %w, output: %s", err, string(output))
-	}
-
-	var response models.RoleCredentialsResponse
-	if err := json.Unmarshal(output, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials JSON: %w", err)
-	}
+	c.TokenCache.Mu.Lock()
+	c.TokenCache.AccessToken = ""
+	c.TokenCache.Expiry = time.Time{}
+	c.TokenCache.Mu.Unlock()
 
-	return &models.AWSCredentials{
-		AccessKeyID:     response.RoleCredentials.AccessKeyID,
-		SecretAccessKey: response.RoleCredentials.SecretAccessKey,
-		SessionToken:    response.RoleCredentials.SessionToken,
-		Expiration:      time.Unix(response.RoleCredentials.Expiration/1000, 0).Format(time.RFC3339),
-	}, nil
+	return nil
 }
 
 func (c *RealSSOClient) AwsSTSGetCallerIdentity(profile string) (string, error) {
@@ -237,18 +317,24 @@ func (c *RealSSOClient) AwsSTSGetCallerIdentity(profile string) (string, error)
 	return c.TryGetCallerIdentity(profile)
 }
 
+// TryGetCallerIdentity returns the ARN STS reports for the credentials of
+// profile, without starting a login.
 func (c *RealSSOClient) TryGetCallerIdentity(profile string) (string, error) {
-	output, err := c.Executor.RunCommand("aws", "sts", "get-caller-identity", "--profile", profile)
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return "", err
+	}
+	creds, err := c.profileCredentials(profile, sections, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to get caller identity: %w", err)
 	}
 
-	var identity struct {
-		Arn string `json:"Arn"`
-	}
-	if err := json.Unmarshal(output, &identity); err != nil {
-		return "", fmt.Errorf("failed to parse identity json: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
 
-	return identity.Arn, nil
+	output, err := c.stsClient(chainRegion(profile, sections), creds).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	return aws.ToString(output.Arn), nil
 }
@@ -14,7 +14,10 @@ import (
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
 	"github.com/BerryBytes/awsctl/utils/common"
-	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,8 +97,9 @@ func TestGetCachedSsoAccessToken(t *testing.T) {
 		err = os.WriteFile(cacheFile, data, 0644)
 		require.NoError(t, err)
 
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-			Return([]byte("https://example.awsapps.com/start"), nil)
+		writeAWSConfig(t, tempDir, `[profile test-profile]
+sso_start_url = https://example.awsapps.com/start
+`)
 
 		client := &sso.RealSSOClient{
 			Prompter: mockPrompter,
@@ -121,65 +125,67 @@ func TestGetCachedSsoAccessToken(t *testing.T) {
 }
 
 func TestSSOLogin(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockPrompter := mock_sso.NewMockPrompter(ctrl)
-	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
-
 	tests := []struct {
 		name        string
-		setup       func()
+		config      string
+		tokenErrors []string
 		profile     string
 		refresh     bool
 		noBrowser   bool
+		wantOpened  bool
 		expectError bool
 	}{
 		{
-			name: "successful login with browser",
-			setup: func() {
-				mockExecutor.EXPECT().RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--profile", "test-profile").
-					Return(nil)
-			},
+			name:        "successful login with browser",
+			config:      testProfileConfig,
 			profile:     "test-profile",
+			wantOpened:  true,
 			expectError: false,
 		},
 		{
-			name: "successful login without browser",
-			setup: func() {
-				mockExecutor.EXPECT().RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--no-browser", "--profile", "test-profile").
-					Return(nil)
-			},
+			name:        "successful login without browser",
+			config:      testProfileConfig,
 			profile:     "test-profile",
 			noBrowser:   true,
 			expectError: false,
 		},
 		{
-			name: "login timeout",
-			setup: func() {
-				mockExecutor.EXPECT().RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--profile", "test-profile").
-					Return(context.DeadlineExceeded)
-			},
+			name:        "missing start URL",
+			config:      "[profile test-profile]\nsso_region = us-east-1\n",
 			profile:     "test-profile",
 			expectError: true,
 		},
 		{
-			name: "login interrupted",
-			setup: func() {
-				mockExecutor.EXPECT().RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--profile", "test-profile").
-					Return(promptUtils.ErrInterrupted)
-			},
+			name:        "authorization denied",
+			config:      testProfileConfig,
+			tokenErrors: []string{"AccessDeniedException"},
 			profile:     "test-profile",
+			wantOpened:  true,
 			expectError: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.setup()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			home := setTestHome(t)
+			writeAWSConfig(t, home, tt.config)
+			server := newFakeOIDCServer(t, tt.tokenErrors...)
+
+			mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+
+			opened := false
 			client := &sso.RealSSOClient{
-				Prompter: mockPrompter,
-				Executor: mockExecutor,
+				Prompter:      mock_sso.NewMockPrompter(ctrl),
+				Executor:      mockExecutor,
+				NewOIDCClient: server.clientFactory(),
+				Sleep:         func(time.Duration) {},
+				OpenBrowser: func(string) error {
+					opened = true
+					return nil
+				},
 			}
 
 			err := client.SSOLogin(tt.profile, tt.refresh, tt.noBrowser)
@@ -188,16 +194,23 @@ func TestSSOLogin(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Equal(t, tt.wantOpened, opened)
 		})
 	}
 }
 
+const testProfileConfig = `[profile test-profile]
+sso_start_url = https://test.awsapps.com/start
+sso_region = us-east-1
+`
+
 func TestGetSSOAccountName(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockPrompter := mock_sso.NewMockPrompter(ctrl)
 	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+	mockPortal := mock_sso.NewMockSSOPortalAPI(ctrl)
 
 	tests := []struct {
 		name          string
@@ -214,15 +227,13 @@ func TestGetSSOAccountName(t *testing.T) {
 				client.TokenCache.AccessToken = "test-token"
 				client.TokenCache.Expiry = time.Now().Add(1 * time.Hour)
 
-				accountList := models.AccountNameResponse{
-					AccountList: []models.Account{
-						{AccountID: "123456789012", AccountName: "test-account"},
-					},
-				}
-				output, _ := json.Marshal(accountList)
-				mockExecutor.EXPECT().
-					RunCommand("aws", "sso", "list-accounts", "--access-token", "test-token", "--output", "json").
-					Return(output, nil)
+				mockPortal.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&awssso.ListAccountsOutput{
+						AccountList: []ssotypes.AccountInfo{
+							{AccountId: aws.String("123456789012"), AccountName: aws.String("test-account")},
+						},
+					}, nil)
 			},
 			accountID:    "123456789012",
 			profile:      "test-profile",
@@ -245,25 +256,21 @@ func TestGetSSOAccountName(t *testing.T) {
 				err := os.WriteFile(filepath.Join(cacheDir, "test.json"), data, 0644)
 				require.NoError(t, err)
 
-				oldHome := os.Getenv("HOME")
 				t.Setenv("HOME", tempDir)
-				t.Cleanup(func() {
-					_ = os.Setenv("HOME", oldHome)
-				})
-
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-					Return([]byte("https://example.awsapps.com/start"), nil)
-
-				accountList := models.AccountNameResponse{
-					AccountList: []models.Account{
-						{AccountID: "123456789012", AccountName: "test-account"},
-					},
-				}
-				output, _ := json.Marshal(accountList)
-				mockExecutor.EXPECT().
-					RunCommand("aws", "sso", "list-accounts", "--access-token", "test-token", "--output", "json").
-					Return(output, nil)
+				writeAWSConfig(t, tempDir, `[profile test-profile]
+sso_start_url = https://example.awsapps.com/start
+sso_region = us-east-1
+`)
+				mockPortal.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *awssso.ListAccountsInput, _ ...func(*awssso.Options)) (*awssso.ListAccountsOutput, error) {
+						assert.Equal(t, "test-token", aws.ToString(in.AccessToken))
+						return &awssso.ListAccountsOutput{
+							AccountList: []ssotypes.AccountInfo{
+								{AccountId: aws.String("123456789012"), AccountName: aws.String("test-account")},
+							},
+						}, nil
+					})
 
 				client.TokenCache = models.TokenCache{}
 			},
@@ -273,39 +280,46 @@ func TestGetSSOAccountName(t *testing.T) {
 			expectError:  false,
 		},
 		{
-			name: "account not found",
+			name: "account on a later page",
 			setup: func(client *sso.RealSSOClient) {
-				tempDir := t.TempDir()
-				cacheDir := filepath.Join(tempDir, ".aws", "sso", "cache")
-				require.NoError(t, os.MkdirAll(cacheDir, 0755))
-
-				cache := models.SSOCache{
-					StartURL:    stringPtr("https://example.awsapps.com/start"),
-					AccessToken: stringPtr("test-token"),
-					ExpiresAt:   stringPtr(time.Now().Add(1 * time.Hour).Format(time.RFC3339)),
-				}
-				data, _ := json.Marshal(cache)
-				require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "cache.json"), data, 0644))
-
-				oldHome := os.Getenv("HOME")
-				t.Setenv("HOME", tempDir)
-				t.Cleanup(func() {
-					_ = os.Setenv("HOME", oldHome)
-				})
-
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-					Return([]byte("https://example.awsapps.com/start"), nil)
+				client.TokenCache.AccessToken = "test-token"
+				client.TokenCache.Expiry = time.Now().Add(1 * time.Hour)
 
-				accountList := models.AccountNameResponse{
-					AccountList: []models.Account{},
-				}
-				output, _ := json.Marshal(accountList)
-				mockExecutor.EXPECT().
-					RunCommand("aws", "sso", "list-accounts", "--access-token", "test-token", "--output", "json").
-					Return(output, nil)
+				gomock.InOrder(
+					mockPortal.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(&awssso.ListAccountsOutput{
+							AccountList: []ssotypes.AccountInfo{
+								{AccountId: aws.String("111111111111"), AccountName: aws.String("other")},
+							},
+							NextToken: aws.String("page-2"),
+						}, nil),
+					mockPortal.EXPECT().
+						ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, in *awssso.ListAccountsInput, _ ...func(*awssso.Options)) (*awssso.ListAccountsOutput, error) {
+							assert.Equal(t, "page-2", aws.ToString(in.NextToken))
+							return &awssso.ListAccountsOutput{
+								AccountList: []ssotypes.AccountInfo{
+									{AccountId: aws.String("123456789012"), AccountName: aws.String("test-account")},
+								},
+							}, nil
+						}),
+				)
+			},
+			accountID:    "123456789012",
+			profile:      "test-profile",
+			expectedName: "test-account",
+			expectError:  false,
+		},
+		{
+			name: "account not found",
+			setup: func(client *sso.RealSSOClient) {
+				client.TokenCache.AccessToken = "test-token"
+				client.TokenCache.Expiry = time.Now().Add(1 * time.Hour)
 
-				client.TokenCache = models.TokenCache{}
+				mockPortal.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&awssso.ListAccountsOutput{}, nil)
 			},
 			accountID:     "123456789012",
 			profile:       "test-profile",
@@ -317,9 +331,11 @@ func TestGetSSOAccountName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			writeAWSConfig(t, setTestHome(t), testProfileConfig)
 			client := &sso.RealSSOClient{
-				Executor: mockExecutor,
-				Prompter: mockPrompter,
+				Executor:        mockExecutor,
+				Prompter:        mockPrompter,
+				NewPortalClient: func(string) sso.SSOPortalAPI { return mockPortal },
 			}
 			tt.setup(client)
 
@@ -338,81 +354,68 @@ func TestGetSSOAccountName(t *testing.T) {
 	}
 }
 
-func TestGetRoleCredentials(t *testing.T) {
+// callerIdentitySTS returns STS clients that report arn as the caller
+// identity and record the region and credentials they were made for.
+func callerIdentitySTS(ctrl *gomock.Controller, arn string, regions *[]string, creds *[]*models.AWSCredentials) func(string, *models.AWSCredentials) sso.STSAPI {
+	return func(region string, c *models.AWSCredentials) sso.STSAPI {
+		*regions = append(*regions, region)
+		*creds = append(*creds, c)
+		m := mock_sso.NewMockSTSAPI(ctrl)
+		m.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&sts.GetCallerIdentityOutput{Arn: aws.String(arn)}, nil)
+		return m
+	}
+}
+
+func TestTryGetCallerIdentity(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockPrompter := mock_sso.NewMockPrompter(ctrl)
-	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
-
-	t.Run("successful credentials retrieval", func(t *testing.T) {
-		client := &sso.RealSSOClient{
-			Prompter: mockPrompter,
-			Executor: mockExecutor,
-		}
-
-		expectedCreds := &models.AWSCredentials{
-			AccessKeyID:     "AKIA...",
-			SecretAccessKey: "secret...",
-			SessionToken:    "token...",
-			Expiration:      time.Now().Add(1 * time.Hour).Format(time.RFC3339),
-		}
-
-		response := models.RoleCredentialsResponse{
-			RoleCredentials: models.RoleCredentials{
-				AccessKeyID:     expectedCreds.AccessKeyID,
-				SecretAccessKey: expectedCreds.SecretAccessKey,
-				SessionToken:    expectedCreds.SessionToken,
-				Expiration:      time.Now().Add(1*time.Hour).Unix() * 1000,
-			},
-		}
-		responseData, err := json.Marshal(response)
-		require.NoError(t, err)
-
-		mockExecutor.EXPECT().RunCommand(
-			"aws", "sso", "get-role-credentials",
-			"--access-token", "test-token",
-			"--role-name", "test-role",
-			"--account-id", "123456789012",
-		).Return(responseData, nil)
+	writeLogoutFixtures(t)
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	calls := 0
+	var regions []string
+	var creds []*models.AWSCredentials
+	client := &sso.RealSSOClient{
+		NewPortalClient: roleCredentialsPortal(ctrl, &calls, expiration),
+		NewSTSClient:    callerIdentitySTS(ctrl, "arn:aws:sts::222222222222:assumed-role/ReadOnly/me", &regions, &creds),
+	}
 
-		creds, err := client.GetRoleCredentials("test-token", "test-role", "123456789012")
-		assert.NoError(t, err)
-		assert.Equal(t, expectedCreds, creds)
-	})
+	arn, err := client.TryGetCallerIdentity("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:sts::222222222222:assumed-role/ReadOnly/me", arn)
+	assert.Equal(t, []string{"eu-west-1"}, regions)
+	require.Len(t, creds, 1)
+	assert.Equal(t, "AKIA-222222222222", creds[0].AccessKeyID)
+}
 
-	t.Run("command failure", func(t *testing.T) {
-		client := &sso.RealSSOClient{
-			Prompter: mockPrompter,
-			Executor: mockExecutor,
-		}
+func TestTryGetCallerIdentity_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-		mockExecutor.EXPECT().RunCommand(
-			"aws", "sso", "get-role-credentials",
-			"--access-token", "test-token",
-			"--role-name", "test-role",
-			"--account-id", "123456789012",
-		).Return(nil, errors.New("command failed"))
+	writeLogoutFixtures(t)
 
-		_, err := client.GetRoleCredentials("test-token", "test-role", "123456789012")
-		assert.Error(t, err)
+	t.Run("no credentials", func(t *testing.T) {
+		client := &sso.RealSSOClient{}
+		_, err := client.TryGetCallerIdentity("static")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get caller identity")
 	})
 
-	t.Run("invalid JSON response", func(t *testing.T) {
+	t.Run("sts error", func(t *testing.T) {
+		calls := 0
 		client := &sso.RealSSOClient{
-			Prompter: mockPrompter,
-			Executor: mockExecutor,
+			NewPortalClient: roleCredentialsPortal(ctrl, &calls, time.Now().Add(time.Hour)),
+			NewSTSClient: func(string, *models.AWSCredentials) sso.STSAPI {
+				m := mock_sso.NewMockSTSAPI(ctrl)
+				m.EXPECT().GetCallerIdentity(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("ExpiredToken"))
+				return m
+			},
 		}
-
-		mockExecutor.EXPECT().RunCommand(
-			"aws", "sso", "get-role-credentials",
-			"--access-token", "test-token",
-			"--role-name", "test-role",
-			"--account-id", "123456789012",
-		).Return([]byte("invalid json"), nil)
-
-		_, err := client.GetRoleCredentials("test-token", "test-role", "123456789012")
-		assert.Error(t, err)
+		_, err := client.TryGetCallerIdentity("team-admin")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get caller identity: ExpiredToken")
 	})
 }
 
@@ -424,9 +427,7 @@ func TestGetCachedSsoAccessToken_ErrorCases(t *testing.T) {
 	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
 
 	t.Run("error from getSsoAccessTokenFromCache", func(t *testing.T) {
-		mockExecutor.EXPECT().
-			RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "bad-profile").
-			Return(nil, errors.New("config error"))
+		setTestHome(t)
 
 		client := &sso.RealSSOClient{
 			Prompter: mockPrompter,
@@ -452,9 +453,7 @@ func TestGetCachedSsoAccessToken_ErrorCases(t *testing.T) {
 		data, _ := json.Marshal(cacheData)
 		require.NoError(t, os.WriteFile(cacheFile, data, 0644))
 
-		mockExecutor.EXPECT().
-			RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-			Return([]byte("https://example.com"), nil)
+		writeAWSConfig(t, tempDir, "[profile test-profile]\nsso_start_url = https://example.com\n")
 
 		oldHome := os.Getenv("HOME")
 		if err := os.Setenv("HOME", tempDir); err != nil {
@@ -481,6 +480,7 @@ func TestGetSSOAccountName_ErrorCases(t *testing.T) {
 
 	mockPrompter := mock_sso.NewMockPrompter(ctrl)
 	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+	mockPortal := mock_sso.NewMockSSOPortalAPI(ctrl)
 
 	t.Run("error getting access token", func(t *testing.T) {
 		client := &sso.RealSSOClient{
@@ -488,16 +488,14 @@ func TestGetSSOAccountName_ErrorCases(t *testing.T) {
 			Executor: mockExecutor,
 		}
 
-		mockExecutor.EXPECT().
-			RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "bad-profile").
-			Return(nil, errors.New("config error"))
+		setTestHome(t)
 
 		_, err := client.GetSSOAccountName("123456789012", "bad-profile")
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to retrieve SSO access token")
 	})
 
-	t.Run("error listing accounts", func(t *testing.T) {
+	t.Run("error getting SSO region", func(t *testing.T) {
 		client := &sso.RealSSOClient{
 			Prompter: mockPrompter,
 			Executor: mockExecutor,
@@ -507,32 +505,32 @@ func TestGetSSOAccountName_ErrorCases(t *testing.T) {
 			},
 		}
 
-		mockExecutor.EXPECT().
-			RunCommand("aws", "sso", "list-accounts", "--access-token", "test-token", "--output", "json").
-			Return(nil, errors.New("command failed"))
+		writeAWSConfig(t, setTestHome(t), "[profile test-profile]\nregion = us-east-1\n")
 
 		_, err := client.GetSSOAccountName("123456789012", "test-profile")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to list AWS accounts")
+		assert.Contains(t, err.Error(), "failed to get sso_region")
 	})
 
-	t.Run("invalid accounts JSON", func(t *testing.T) {
+	t.Run("error listing accounts", func(t *testing.T) {
 		client := &sso.RealSSOClient{
-			Prompter: mockPrompter,
-			Executor: mockExecutor,
+			Prompter:        mockPrompter,
+			Executor:        mockExecutor,
+			NewPortalClient: func(string) sso.SSOPortalAPI { return mockPortal },
 			TokenCache: models.TokenCache{
 				AccessToken: "test-token",
 				Expiry:      time.Now().Add(1 * time.Hour),
 			},
 		}
 
-		mockExecutor.EXPECT().
-			RunCommand("aws", "sso", "list-accounts", "--access-token", "test-token", "--output", "json").
-			Return([]byte("invalid json"), nil)
+		writeAWSConfig(t, setTestHome(t), testProfileConfig)
+		mockPortal.EXPECT().
+			ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("request failed"))
 
 		_, err := client.GetSSOAccountName("123456789012", "test-profile")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to unmarshal accounts")
+		assert.Contains(t, err.Error(), "failed to list AWS accounts")
 	})
 }
 
@@ -553,9 +551,7 @@ func TestGetSsoAccessTokenFromCache_ErrorCases(t *testing.T) {
 		{
 			name: "error getting start URL",
 			setup: func(client *sso.RealSSOClient) {
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "bad-profile").
-					Return(nil, errors.New("config error"))
+				setTestHome(t)
 			},
 			profile:       "bad-profile",
 			expectedError: "failed to get sso_start_url",
@@ -564,9 +560,7 @@ func TestGetSsoAccessTokenFromCache_ErrorCases(t *testing.T) {
 		{
 			name: "error getting home directory",
 			setup: func(client *sso.RealSSOClient) {
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-					Return([]byte("https://example.com"), nil)
+				writeAWSConfig(t, setTestHome(t), "[profile test-profile]\nsso_start_url = https://example.com\n")
 
 				oldHome := os.Getenv("HOME")
 				if err := os.Unsetenv("HOME"); err != nil {
@@ -583,11 +577,8 @@ func TestGetSsoAccessTokenFromCache_ErrorCases(t *testing.T) {
 		{
 			name: "cache directory not exists",
 			setup: func(client *sso.RealSSOClient) {
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-					Return([]byte("https://example.com"), nil)
-
 				tempDir := t.TempDir()
+				writeAWSConfig(t, tempDir, "[profile test-profile]\nsso_start_url = https://example.com\n")
 				oldHome := os.Getenv("HOME")
 				if err := os.Setenv("HOME", tempDir); err != nil {
 					t.Fatalf("failed to set HOME to tempDir: %v", err)
@@ -603,11 +594,8 @@ func TestGetSsoAccessTokenFromCache_ErrorCases(t *testing.T) {
 		{
 			name: "no matching cache file",
 			setup: func(client *sso.RealSSOClient) {
-				mockExecutor.EXPECT().
-					RunCommand("aws", "configure", "get", "sso_start_url", "--profile", "test-profile").
-					Return([]byte("https://example.com"), nil)
-
 				tempDir := t.TempDir()
+				writeAWSConfig(t, tempDir, "[profile test-profile]\nsso_start_url = https://example.com\n")
 				cacheDir := filepath.Join(tempDir, ".aws", "sso", "cache")
 				require.NoError(t, os.MkdirAll(cacheDir, 0755))
 
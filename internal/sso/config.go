@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/spf13/afero"
 )
 
 func writeConfigFile(path, content string) error {
@@ -28,25 +31,111 @@ func writeConfigFile(path, content string) error {
 	return os.Rename(tmpFile.Name(), path)
 }
 
+// configStore returns the store for the shared AWS config file on the
+// client's file system.
+func (c *RealSSOClient) configStore() (*awsconfig.Store, error) {
+	path, err := awsconfig.ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return awsconfig.NewStore(c.fs(), path), nil
+}
+
+func (c *RealSSOClient) fs() afero.Fs {
+	if c.Fs != nil {
+		return c.Fs
+	}
+	return afero.NewOsFs()
+}
+
+func (c *RealSSOClient) loadConfig() (*awsconfig.File, error) {
+	store, err := c.configStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Load()
+}
+
+func (c *RealSSOClient) updateConfig(fn func(f *awsconfig.File) error) error {
+	store, err := c.configStore()
+	if err != nil {
+		return err
+	}
+	return store.Update(fn)
+}
+
+// loadCredentials parses the shared AWS credentials file. A missing file has
+// no sections.
+func (c *RealSSOClient) loadCredentials() (*awsconfig.File, error) {
+	path, err := awsconfig.CredentialsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return awsconfig.NewStore(c.fs(), path).Load()
+}
+
+// readConfigSections parses ~/.aws/config into its sections, keyed by the
+// section name without brackets (e.g. "profile dev" or "sso-session dev").
+// A missing file has no sections.
+func (c *RealSSOClient) readConfigSections() (map[string]map[string]string, error) {
+	f, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return f.Map(), nil
+}
+
+// readConfigSection returns the key/value pairs of a section of ~/.aws/config,
+// where header is the section name without brackets (e.g. "sso-session dev").
+func (c *RealSSOClient) readConfigSection(header string) (map[string]string, error) {
+	f, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	values := f.Values(header)
+	if values == nil {
+		return nil, fmt.Errorf("section [%s] not found in ~/.aws/config", header)
+	}
+	return values, nil
+}
+
 func (c *RealSSOClient) ConfigureSet(key, value, profile string) error {
-	_, err := c.Executor.RunCommand("aws", "configure", "set", key, value, "--profile", profile)
-	return err
+	return c.updateConfig(func(f *awsconfig.File) error {
+		f.Set(awsconfig.ProfileSection(profile), key, value)
+		return nil
+	})
 }
 
 func (c *RealSSOClient) ConfigureGet(key, profile string) (string, error) {
-	output, err := c.Executor.RunCommand("aws", "configure", "get", key, "--profile", profile)
+	f, err := c.loadConfig()
 	if err != nil {
 		return "", fmt.Errorf("failed to get %s: %w", key, err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	value, ok := f.Get(awsconfig.ProfileSection(profile), key)
+	if !ok {
+		return "", fmt.Errorf("failed to get %s: not set for profile %s", key, profile)
+	}
+	return value, nil
 }
 
+// ValidProfiles returns the non-default profiles of the config and
+// credentials files, like `aws configure list-profiles`.
 func (c *RealSSOClient) ValidProfiles() ([]string, error) {
-	output, err := c.Executor.RunCommand("aws", "configure", "list-profiles")
+	f, err := c.loadConfig()
 	if err != nil {
 		return nil, err
 	}
-	profiles := strings.Split(strings.TrimSpace(string(output)), "\n")
+	profiles := f.Profiles()
+
+	credentials, err := c.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range credentials.SectionNames() {
+		if !slices.Contains(profiles, name) {
+			profiles = append(profiles, name)
+		}
+	}
 
 	var validProfiles []string
 	for _, profile := range profiles {
@@ -71,24 +160,16 @@ func (c *RealSSOClient) ConfigureDefaultProfile(region, output string) error {
 }
 
 func (c *RealSSOClient) GetAWSRegion(profile string) (string, error) {
-	output, err := c.Executor.RunCommand("aws", "configure", "get", "region", "--profile", profile)
-	if err != nil {
-		return "", fmt.Errorf("failed to get AWS region: %v", err)
-	}
-	region := strings.TrimSpace(string(output))
-	if region == "" {
+	region, err := c.ConfigureGet("region", profile)
+	if err != nil || region == "" {
 		return "", fmt.Errorf("AWS region not found in profile %s", profile)
 	}
 	return region, nil
 }
 
 func (c *RealSSOClient) GetAWSOutput(profile string) (string, error) {
-	output, err := c.Executor.RunCommand("aws", "configure", "get", "output", "--profile", profile)
-	if err != nil {
-		return "", fmt.Errorf("failed to get AWS output: %v", err)
-	}
-	outputFormat := strings.TrimSpace(string(output))
-	if outputFormat == "" {
+	outputFormat, err := c.ConfigureGet("output", profile)
+	if err != nil || outputFormat == "" {
 		return "", fmt.Errorf("AWS output not found in profile %s", profile)
 	}
 	return outputFormat, nil
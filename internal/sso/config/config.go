@@ -1,11 +1,14 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
 
 	"github.com/BerryBytes/awsctl/models"
 	"gopkg.in/yaml.v3"
@@ -58,6 +61,11 @@ func loadConfigFile(cfg *Config) (*models.Config, error) {
 			return nil, fmt.Errorf("failed to parse config file: %w", err)
 		}
 	}
+	if parsedConfig.ProfileNameTemplate != "" {
+		if _, err := ParseProfileNameTemplate(parsedConfig.ProfileNameTemplate); err != nil {
+			return nil, fmt.Errorf("invalid profileNameTemplate: %w", err)
+		}
+	}
 
 	return &parsedConfig, nil
 }
@@ -81,6 +89,72 @@ func FindConfigFile(cfg *Config) (string, error) {
 	return "", ErrNoConfigFile
 }
 
+// SaveConfig writes cfg.RawCustomConfig to the awsctl config file it was
+// loaded from, or to config.yml in AWSConfigDir when there is none yet. The
+// file is replaced atomically; comments in it are not kept.
+func SaveConfig(cfg *Config) error {
+	configFilePath, err := FindConfigFile(cfg)
+	if errors.Is(err, ErrNoConfigFile) {
+		configFilePath = filepath.Join(cfg.AWSConfigDir, "config.yml")
+	} else if err != nil {
+		return err
+	}
+
+	var data []byte
+	if filepath.Ext(configFilePath) == ".json" {
+		data, err = json.MarshalIndent(cfg.RawCustomConfig, "", "  ")
+		data = append(data, '\n')
+	} else {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(cfg.RawCustomConfig)
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.AWSConfigDir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", cfg.AWSConfigDir, err)
+	}
+	tmpFile, err := os.CreateTemp(cfg.AWSConfigDir, ".config-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmpFile.Chmod(0600); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), configFilePath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// ParseProfileNameTemplate parses a profileNameTemplate. Referencing a
+// missing account tag is an error instead of rendering "<no value>".
+func ParseProfileNameTemplate(text string) (*template.Template, error) {
+	return template.New("profileNameTemplate").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"lower":   strings.ToLower,
+			"upper":   strings.ToUpper,
+			"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		}).
+		Parse(text)
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
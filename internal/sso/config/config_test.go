@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "awsctl")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte(content), 0600))
+}
+
+func TestNewConfig_ProfileNaming(t *testing.T) {
+	writeConfig(t, `profileNameTemplate: "{{.Tags.env}}-{{.Alias}}-{{.RoleShort}}"
+accounts:
+  "111111111111":
+    alias: payments
+    tags:
+      env: prod
+`)
+
+	cfg, err := config.NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "{{.Tags.env}}-{{.Alias}}-{{.RoleShort}}", cfg.RawCustomConfig.ProfileNameTemplate)
+	assert.Equal(t, "payments", cfg.RawCustomConfig.Accounts["111111111111"].Alias)
+	assert.Equal(t, "prod", cfg.RawCustomConfig.Accounts["111111111111"].Tags["env"])
+}
+
+func TestNewConfig_InvalidProfileNameTemplate(t *testing.T) {
+	writeConfig(t, `profileNameTemplate: "{{.Alias"`)
+
+	_, err := config.NewConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid profileNameTemplate")
+}
+
+func TestParseProfileNameTemplate(t *testing.T) {
+	tmpl, err := config.ParseProfileNameTemplate(`{{.Name | lower | replace " " "-"}}-{{upper .Env}}`)
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, tmpl.Execute(&b, map[string]string{"Name": "Data Lake", "Env": "prd"}))
+	assert.Equal(t, "data-lake-PRD", b.String())
+
+	b.Reset()
+	err = tmpl.Execute(&b, map[string]string{"Name": "x"})
+	assert.Error(t, err)
+}
+
+func TestSaveConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg, err := config.NewConfig()
+	require.NoError(t, err)
+	cfg.RawCustomConfig.SSOSessions = []models.SSOSession{{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1"}}
+	require.NoError(t, config.SaveConfig(cfg))
+
+	path := filepath.Join(home, ".config", "awsctl", "config.yml")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	reloaded, err := config.NewConfig()
+	require.NoError(t, err)
+	assert.Equal(t, cfg.RawCustomConfig, reloaded.RawCustomConfig)
+}
+
+func TestSaveConfig_KeepsJSONFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "awsctl")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"ssoSessions": []}`), 0600))
+
+	cfg, err := config.NewConfig()
+	require.NoError(t, err)
+	cfg.RawCustomConfig.SetDefaultProfile = true
+	require.NoError(t, config.SaveConfig(cfg))
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"setDefaultProfile": true`)
+	assert.NoFileExists(t, filepath.Join(dir, "config.yml"))
+}
@@ -0,0 +1,116 @@
+package sso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigClient(t *testing.T, config, credentials string) (*sso.RealSSOClient, afero.Fs, string) {
+	t.Helper()
+	home := setTestHome(t)
+	fs := afero.NewMemMapFs()
+	if config != "" {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(home, ".aws", "config"), []byte(config), 0600))
+	}
+	if credentials != "" {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(home, ".aws", "credentials"), []byte(credentials), 0600))
+	}
+	return &sso.RealSSOClient{Fs: fs}, fs, home
+}
+
+func TestConfigureGet(t *testing.T) {
+	client, _, _ := newConfigClient(t, `[default]
+region = eu-central-1
+
+[profile dev]
+region = us-east-1
+output = json
+`, "")
+
+	region, err := client.ConfigureGet("region", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", region)
+
+	region, err = client.GetAWSRegion("default")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central-1", region)
+
+	_, err = client.ConfigureGet("sso_region", "dev")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get sso_region")
+
+	_, err = client.GetAWSOutput("missing")
+	assert.EqualError(t, err, "AWS output not found in profile missing")
+}
+
+func TestConfigureSet_PreservesComments(t *testing.T) {
+	client, fs, home := newConfigClient(t, `# top comment
+[profile dev] # inline
+; keep me
+region = us-east-1
+
+[services local]
+s3 =
+  endpoint_url = http://localhost:4566
+`, "")
+
+	require.NoError(t, client.ConfigureSet("output", "yaml", "dev"))
+	require.NoError(t, client.ConfigureSet("region", "eu-west-1", "dev"))
+	require.NoError(t, client.ConfigureSet("region", "us-west-2", "new"))
+
+	data, err := afero.ReadFile(fs, filepath.Join(home, ".aws", "config"))
+	require.NoError(t, err)
+	assert.Equal(t, `# top comment
+[profile dev] # inline
+; keep me
+region = eu-west-1
+output = yaml
+
+[services local]
+s3 =
+  endpoint_url = http://localhost:4566
+
+[profile new]
+region = us-west-2
+`, string(data))
+}
+
+func TestValidProfiles(t *testing.T) {
+	client, _, _ := newConfigClient(t, `[default]
+region = us-east-1
+
+[profile dev]
+region = us-east-1
+
+[sso-session corp]
+sso_region = us-east-1
+
+[profile prod]
+region = us-east-1
+`, `[default]
+aws_access_key_id = AKIA
+
+[static]
+aws_access_key_id = AKIA
+
+[dev]
+aws_access_key_id = AKIA
+`)
+
+	profiles, err := client.ValidProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod", "static"}, profiles)
+}
+
+func TestValidProfiles_NoFiles(t *testing.T) {
+	client, _, _ := newConfigClient(t, "", "")
+
+	profiles, err := client.ValidProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
@@ -0,0 +1,295 @@
+package sso
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+)
+
+// credentialExpiryMargin is how long before their expiry cached role
+// credentials stop being reused, so callers never receive credentials that
+// expire mid-request.
+const credentialExpiryMargin = 5 * time.Minute
+
+// ErrLoginRequired is returned when role credentials cannot be fetched
+// without an interactive SSO login.
+var ErrLoginRequired = errors.New("SSO login required")
+
+// ssoProfile is the SSO configuration a profile resolves to, following its
+// sso_session reference when present.
+type ssoProfile struct {
+	Name        string
+	SessionName string
+	StartURL    string
+	Region      string
+	AccountID   string
+	RoleName    string
+}
+
+func resolveSSOProfile(profile string, sections map[string]map[string]string) (*ssoProfile, error) {
+	values, ok := sections[awsconfig.ProfileSection(profile)]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found in ~/.aws/config", profile)
+	}
+
+	p := &ssoProfile{
+		Name:        profile,
+		SessionName: values["sso_session"],
+		StartURL:    values["sso_start_url"],
+		Region:      values["sso_region"],
+		AccountID:   values["sso_account_id"],
+		RoleName:    values["sso_role_name"],
+	}
+	if p.SessionName != "" {
+		session, ok := sections["sso-session "+p.SessionName]
+		if !ok {
+			return nil, fmt.Errorf("sso-session %s of profile %s not found in ~/.aws/config", p.SessionName, profile)
+		}
+		p.StartURL = session["sso_start_url"]
+		p.Region = session["sso_region"]
+	}
+	p.StartURL = strings.TrimSuffix(p.StartURL, "#")
+
+	if p.StartURL == "" || p.AccountID == "" || p.RoleName == "" {
+		return nil, fmt.Errorf("profile %s is not an SSO profile", profile)
+	}
+	if p.Region == "" {
+		return nil, fmt.Errorf("sso_region is not set for profile %s", profile)
+	}
+	return p, nil
+}
+
+// ProfileCredentials returns role credentials for an SSO profile, or for an
+// assume-role profile whose source_profile chain ends in an SSO profile or an
+// MFA session profile. MFA session profiles return the cached credentials of
+// `awsctl iam session`.
+// Credentials are cached in ~/.aws/cli/cache, shared with the AWS CLI, and
+// reused until shortly before they expire. The cached SSO token is refreshed
+// silently if possible; an interactive login is never started.
+func (c *RealSSOClient) ProfileCredentials(profile string) (*models.AWSCredentials, error) {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return nil, err
+	}
+	return c.profileCredentials(profile, sections, nil)
+}
+
+// profileCredentials resolves the credentials of profile, where chain holds
+// the assume-role profiles already being resolved.
+func (c *RealSSOClient) profileCredentials(profile string, sections map[string]map[string]string, chain []string) (*models.AWSCredentials, error) {
+	if slices.Contains(chain, profile) {
+		return nil, roleChainLoopError(append(chain, profile))
+	}
+	if source := sections[awsconfig.ProfileSection(profile)][mfaSourceKey]; source != "" {
+		return c.mfaSessionCredentials(profile, source, sections)
+	}
+	opts, err := resolveAssumeRoleProfile(profile, sections)
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil {
+		return c.assumeRole(*opts, sections, append(chain, profile))
+	}
+
+	p, err := resolveSSOProfile(profile, sections)
+	if err != nil {
+		return nil, err
+	}
+	return c.ssoRoleCredentials(p)
+}
+
+func (c *RealSSOClient) ssoRoleCredentials(p *ssoProfile) (*models.AWSCredentials, error) {
+	cacheDir, err := roleCredentialCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(cacheDir, roleCredentialCacheKey(p.AccountID, p.RoleName, p.SessionName, p.StartURL)+".json")
+
+	if creds, err := readRoleCredentialCache(cachePath); err == nil {
+		if expiresAt, err := time.Parse(time.RFC3339, creds.Expiration); err == nil && time.Until(expiresAt) > credentialExpiryMargin {
+			return creds, nil
+		}
+	}
+
+	accessToken, err := c.nonInteractiveAccessToken(p)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	output, err := c.portalClient(p.Region).GetRoleCredentials(ctx, &sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(accessToken),
+		AccountId:   aws.String(p.AccountID),
+		RoleName:    aws.String(p.RoleName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role credentials: %w", err)
+	}
+	if output.RoleCredentials == nil {
+		return nil, errors.New("failed to get role credentials: empty response")
+	}
+
+	creds := &models.AWSCredentials{
+		AccessKeyID:     aws.ToString(output.RoleCredentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(output.RoleCredentials.SecretAccessKey),
+		SessionToken:    aws.ToString(output.RoleCredentials.SessionToken),
+		Expiration:      time.UnixMilli(output.RoleCredentials.Expiration).UTC().Format(time.RFC3339),
+	}
+
+	if err := writeRoleCredentialCache(cachePath, "sso", creds); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache role credentials: %v\n", err)
+	}
+	return creds, nil
+}
+
+// nonInteractiveAccessToken returns the cached SSO access token of the
+// profile, refreshing it inside the refresh window. Unlike
+// GetCachedSsoAccessToken it fails instead of starting a browser login,
+// because its callers write machine-readable output to stdout.
+func (c *RealSSOClient) nonInteractiveAccessToken(p *ssoProfile) (string, error) {
+	loginHint := fmt.Sprintf("run `awsctl sso init` and select profile %s to log in", p.Name)
+
+	cache, path, err := findSSOCacheFile(p.StartURL)
+	if err != nil {
+		return "", err
+	}
+	if cache == nil || aws.ToString(cache.AccessToken) == "" {
+		return "", fmt.Errorf("%w: no cached SSO token for %s; %s", ErrLoginRequired, p.StartURL, loginHint)
+	}
+
+	expiresAt, err := parseCacheTime(cache.ExpiresAt)
+	if err != nil {
+		return "", fmt.Errorf("invalid expiration time in SSO cache: %w", err)
+	}
+
+	if time.Until(expiresAt) < c.tokenRefreshWindow() {
+		if refreshed, _, err := c.refreshCachedToken(path, cache); err == nil {
+			return aws.ToString(refreshed.AccessToken), nil
+		}
+	}
+	if !time.Now().Before(expiresAt) {
+		return "", fmt.Errorf("%w: SSO token for %s has expired; %s", ErrLoginRequired, p.StartURL, loginHint)
+	}
+	return aws.ToString(cache.AccessToken), nil
+}
+
+func readRoleCredentialCache(path string) (*models.AWSCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache models.RoleCredentialCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse role credential cache %s: %w", path, err)
+	}
+	if cache.Credentials.AccessKeyID == "" || cache.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("role credential cache %s is incomplete", path)
+	}
+	return &models.AWSCredentials{
+		AccessKeyID:     cache.Credentials.AccessKeyID,
+		SecretAccessKey: cache.Credentials.SecretAccessKey,
+		SessionToken:    cache.Credentials.SessionToken,
+		Expiration:      cache.Credentials.Expiration,
+	}, nil
+}
+
+func writeRoleCredentialCache(path, providerType string, creds *models.AWSCredentials) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create role credential cache directory: %w", err)
+	}
+	cache := models.RoleCredentialCache{ProviderType: providerType}
+	cache.Credentials.AccessKeyID = creds.AccessKeyID
+	cache.Credentials.SecretAccessKey = creds.SecretAccessKey
+	cache.Credentials.SessionToken = creds.SessionToken
+	cache.Credentials.Expiration = creds.Expiration
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to encode role credential cache: %w", err)
+	}
+	return writeConfigFile(path, string(data))
+}
+
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9._@+/:=-]+$`)
+
+// CredentialProcessCommand returns the credential_process value that makes a
+// profile fetch its credentials through awsctl. The AWS CLI splits the value
+// like a shell and the SDKs run it through one, so its words are quoted.
+func CredentialProcessCommand(profile string) string {
+	return fmt.Sprintf("%s sso credentials --profile %s", shellQuote(awsctlCommand()), shellQuote(profile))
+}
+
+// awsctlCommand returns how credential_process runs this awsctl: as awsctl
+// when that is what PATH finds, so that upgrades which move the binary keep
+// working, and by its path otherwise.
+func awsctlCommand() string {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	if abs, err := filepath.Abs(exe); err == nil {
+		exe = abs
+	}
+
+	found, err := exec.LookPath("awsctl")
+	if err != nil {
+		return exe
+	}
+	foundInfo, err := os.Stat(found)
+	if err != nil {
+		return exe
+	}
+	if exeInfo, err := os.Stat(exe); err == nil && os.SameFile(foundInfo, exeInfo) {
+		return "awsctl"
+	}
+	return exe
+}
+
+// shellQuote returns s as a single shell word. Windows paths only need
+// quotes, as their backslashes are kept as they are there.
+func shellQuote(s string) string {
+	if safeShellWord.MatchString(s) {
+		return s
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + s + `"`
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s) + `"`
+}
+
+// ConfigureCredentialProcess adds a credential_process line for awsctl to the
+// SSO or assume-role profile, so SDKs and tools without SSO support can use
+// it.
+func (c *RealSSOClient) ConfigureCredentialProcess(profile string) error {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return err
+	}
+	root, err := sourceSSOProfile(profile, sections)
+	if err != nil {
+		return err
+	}
+	if _, err := resolveSSOProfile(root, sections); err != nil {
+		return err
+	}
+	if err := c.ConfigureSet("credential_process", CredentialProcessCommand(profile), profile); err != nil {
+		return fmt.Errorf("failed to set credential_process for profile %s: %w", profile, err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package sso_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roleCredentialsPortal(ctrl *gomock.Controller, calls *int, expiration time.Time) func(string) sso.SSOPortalAPI {
+	return func(region string) sso.SSOPortalAPI {
+		m := mock_sso.NewMockSSOPortalAPI(ctrl)
+		m.EXPECT().GetRoleCredentials(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *awssso.GetRoleCredentialsInput, _ ...func(*awssso.Options)) (*awssso.GetRoleCredentialsOutput, error) {
+				*calls++
+				return &awssso.GetRoleCredentialsOutput{
+					RoleCredentials: &ssotypes.RoleCredentials{
+						AccessKeyId:     aws.String("AKIA-" + aws.ToString(in.AccountId)),
+						SecretAccessKey: aws.String("secret-" + aws.ToString(in.RoleName)),
+						SessionToken:    aws.String("session-" + aws.ToString(in.AccessToken)),
+						Expiration:      expiration.UnixMilli(),
+					},
+				}, nil
+			}).AnyTimes()
+		return m
+	}
+}
+
+func TestProfileCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := writeLogoutFixtures(t)
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	calls := 0
+	client := &sso.RealSSOClient{NewPortalClient: roleCredentialsPortal(ctrl, &calls, expiration)}
+
+	creds, err := client.ProfileCredentials("team-admin")
+	require.NoError(t, err)
+	assert.Equal(t, &models.AWSCredentials{
+		AccessKeyID:     "AKIA-111111111111",
+		SecretAccessKey: "secret-Admin",
+		SessionToken:    "session-team-token",
+		Expiration:      expiration.Format(time.RFC3339),
+	}, creds)
+	assert.Equal(t, 1, calls)
+
+	data, err := os.ReadFile(f.teamCredentials)
+	require.NoError(t, err)
+	var cache models.RoleCredentialCache
+	require.NoError(t, json.Unmarshal(data, &cache))
+	assert.Equal(t, "sso", cache.ProviderType)
+	assert.Equal(t, "AKIA-111111111111", cache.Credentials.AccessKeyID)
+	assert.Equal(t, expiration.Format(time.RFC3339), cache.Credentials.Expiration)
+
+	cached, err := client.ProfileCredentials("team-admin")
+	require.NoError(t, err)
+	assert.Equal(t, creds, cached)
+	assert.Equal(t, 1, calls, "valid cached credentials must be reused")
+}
+
+func TestProfileCredentials_RefetchesNearExpiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := writeLogoutFixtures(t)
+	var cache models.RoleCredentialCache
+	cache.ProviderType = "sso"
+	cache.Credentials.AccessKeyID = "OLD"
+	cache.Credentials.SecretAccessKey = "old-secret"
+	cache.Credentials.Expiration = time.Now().Add(2 * time.Minute).UTC().Format(time.RFC3339)
+	data, err := json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(f.legacyCreds, data, 0600))
+
+	calls := 0
+	client := &sso.RealSSOClient{NewPortalClient: roleCredentialsPortal(ctrl, &calls, time.Now().Add(time.Hour))}
+
+	creds, err := client.ProfileCredentials("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "AKIA-222222222222", creds.AccessKeyID)
+	assert.Equal(t, "session-legacy-token", creds.SessionToken)
+	assert.Equal(t, 1, calls)
+}
+
+func TestProfileCredentials_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     string
+		setup       func(t *testing.T, f logoutFixture)
+		errContains string
+	}{
+		{name: "unknown profile", profile: "missing", errContains: "profile missing not found"},
+		{name: "non-SSO profile", profile: "static", errContains: "profile static is not an SSO profile"},
+		{
+			name:    "no cached token",
+			profile: "legacy",
+			setup: func(t *testing.T, f logoutFixture) {
+				require.NoError(t, os.Remove(f.legacyToken))
+			},
+			errContains: "no cached SSO token for https://legacy.awsapps.com/start",
+		},
+		{
+			name:    "expired token without refresh token",
+			profile: "legacy",
+			setup: func(t *testing.T, f logoutFixture) {
+				data, err := json.Marshal(models.SSOCache{
+					StartURL:    aws.String("https://legacy.awsapps.com/start"),
+					Region:      aws.String("eu-west-1"),
+					AccessToken: aws.String("legacy-token"),
+					ExpiresAt:   aws.String(time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)),
+				})
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(f.legacyToken, data, 0600))
+			},
+			errContains: "SSO token for https://legacy.awsapps.com/start has expired",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := writeLogoutFixtures(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+			client := &sso.RealSSOClient{NewPortalClient: func(string) sso.SSOPortalAPI { return mock_sso.NewMockSSOPortalAPI(ctrl) }}
+
+			_, err := client.ProfileCredentials(tt.profile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestCredentialProcessCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX quoting and symlinks")
+	}
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	t.Run("awsctl on PATH", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Symlink(exe, filepath.Join(dir, "awsctl")))
+		t.Setenv("PATH", dir)
+		assert.Equal(t, "awsctl sso credentials --profile dev-admin", sso.CredentialProcessCommand("dev-admin"))
+	})
+
+	t.Run("not on PATH", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		assert.Equal(t, exe+" sso credentials --profile dev-admin", sso.CredentialProcessCommand("dev-admin"))
+	})
+
+	t.Run("quoted profile", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Symlink(exe, filepath.Join(dir, "awsctl")))
+		t.Setenv("PATH", dir)
+		assert.Equal(t, `awsctl sso credentials --profile "my \"team\" \$HOME"`, sso.CredentialProcessCommand(`my "team" $HOME`))
+	})
+}
+
+func TestConfigureCredentialProcess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixture := writeLogoutFixtures(t)
+
+	client := &sso.RealSSOClient{Executor: mock_awsctl.NewMockCommandExecutor(ctrl)}
+	require.NoError(t, client.ConfigureCredentialProcess("team-admin"))
+
+	value, ok := readAWSConfig(t, fixture.home).Get("profile team-admin", "credential_process")
+	assert.True(t, ok)
+	assert.Equal(t, sso.CredentialProcessCommand("team-admin"), value)
+
+	err := client.ConfigureCredentialProcess("static")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile static is not an SSO profile")
+}
@@ -0,0 +1,146 @@
+package sso
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/models"
+)
+
+// EnvVar is an environment variable awsctl sets for role credentials.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// credentialEnvNames are the variables that select or hold AWS credentials.
+// They are removed before role credentials are injected, so a stale value or
+// profile cannot take precedence over them.
+var credentialEnvNames = []string{
+	"AWS_PROFILE",
+	"AWS_DEFAULT_PROFILE",
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"AWS_SECURITY_TOKEN",
+	"AWS_CREDENTIAL_EXPIRATION",
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+}
+
+// CredentialEnv returns the environment variables that make SDKs and tools
+// use creds. Region variables are only set when region is known.
+func CredentialEnv(creds *models.AWSCredentials, region string) []EnvVar {
+	env := []EnvVar{
+		{Name: "AWS_ACCESS_KEY_ID", Value: creds.AccessKeyID},
+		{Name: "AWS_SECRET_ACCESS_KEY", Value: creds.SecretAccessKey},
+		{Name: "AWS_SESSION_TOKEN", Value: creds.SessionToken},
+	}
+	if creds.Expiration != "" {
+		env = append(env, EnvVar{Name: "AWS_CREDENTIAL_EXPIRATION", Value: creds.Expiration})
+	}
+	if region != "" {
+		env = append(env,
+			EnvVar{Name: "AWS_REGION", Value: region},
+			EnvVar{Name: "AWS_DEFAULT_REGION", Value: region},
+		)
+	}
+	return env
+}
+
+// WithCredentialEnv returns environ, in os.Environ form, with every
+// credential variable replaced by vars.
+func WithCredentialEnv(environ []string, vars []EnvVar) []string {
+	result := make([]string, 0, len(environ)+len(vars))
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		if isCredentialEnvName(name) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	for _, v := range vars {
+		result = append(result, v.Name+"="+v.Value)
+	}
+	return result
+}
+
+func isCredentialEnvName(name string) bool {
+	for _, candidate := range credentialEnvNames {
+		if strings.EqualFold(name, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Shells FormatEnv can write statements for.
+var Shells = []string{"bash", "zsh", "fish", "powershell", "dotenv"}
+
+// FormatEnv renders vars as statements that set them in shell. The dotenv
+// format writes unquoted NAME=value lines as read by docker compose.
+func FormatEnv(shell string, vars []EnvVar) (string, error) {
+	if !slices.Contains(Shells, shell) {
+		return "", fmt.Errorf("unsupported shell %q: must be one of %s", shell, strings.Join(Shells, ", "))
+	}
+
+	var b strings.Builder
+	for _, v := range vars {
+		writeSet(&b, shell, v)
+	}
+	return b.String(), nil
+}
+
+// FormatProfileEnv renders statements that make shell use profile. Exported
+// credentials and regions are unset first, as they would take precedence over
+// AWS_PROFILE. The dotenv format cannot unset variables and only sets
+// AWS_PROFILE.
+func FormatProfileEnv(shell, profile string) (string, error) {
+	if !slices.Contains(Shells, shell) {
+		return "", fmt.Errorf("unsupported shell %q: must be one of %s", shell, strings.Join(Shells, ", "))
+	}
+
+	var b strings.Builder
+	for _, name := range credentialEnvNames {
+		if name == "AWS_PROFILE" {
+			continue
+		}
+		switch shell {
+		case "bash", "zsh":
+			fmt.Fprintf(&b, "unset %s\n", name)
+		case "fish":
+			fmt.Fprintf(&b, "set -e %s;\n", name)
+		case "powershell":
+			fmt.Fprintf(&b, "Remove-Item Env:%s -ErrorAction SilentlyContinue\n", name)
+		}
+	}
+	writeSet(&b, shell, EnvVar{Name: "AWS_PROFILE", Value: profile})
+	return b.String(), nil
+}
+
+func writeSet(b *strings.Builder, shell string, v EnvVar) {
+	switch shell {
+	case "bash", "zsh":
+		fmt.Fprintf(b, "export %s=%s\n", v.Name, quotePOSIX(v.Value))
+	case "fish":
+		fmt.Fprintf(b, "set -gx %s %s;\n", v.Name, quoteFish(v.Value))
+	case "powershell":
+		fmt.Fprintf(b, "$Env:%s = %s\n", v.Name, quotePowerShell(v.Value))
+	case "dotenv":
+		fmt.Fprintf(b, "%s=%s\n", v.Name, v.Value)
+	}
+}
+
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func quoteFish(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
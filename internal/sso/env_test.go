@@ -0,0 +1,53 @@
+package sso_test
+
+import (
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialEnv(t *testing.T) {
+	creds := &models.AWSCredentials{
+		AccessKeyID:     "AKIA",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Expiration:      "2030-01-01T00:00:00Z",
+	}
+
+	assert.Equal(t, []sso.EnvVar{
+		{Name: "AWS_ACCESS_KEY_ID", Value: "AKIA"},
+		{Name: "AWS_SECRET_ACCESS_KEY", Value: "secret"},
+		{Name: "AWS_SESSION_TOKEN", Value: "token"},
+		{Name: "AWS_CREDENTIAL_EXPIRATION", Value: "2030-01-01T00:00:00Z"},
+		{Name: "AWS_REGION", Value: "eu-west-1"},
+		{Name: "AWS_DEFAULT_REGION", Value: "eu-west-1"},
+	}, sso.CredentialEnv(creds, "eu-west-1"))
+
+	assert.Len(t, sso.CredentialEnv(creds, ""), 4)
+}
+
+func TestWithCredentialEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"AWS_PROFILE=prod",
+		"AWS_DEFAULT_PROFILE=prod",
+		"AWS_ACCESS_KEY_ID=OLD",
+		"AWS_SECURITY_TOKEN=old",
+		"AWS_REGION=us-east-1",
+		"AWS_CONFIG_FILE=/tmp/config",
+	}
+
+	got := sso.WithCredentialEnv(environ, []sso.EnvVar{{Name: "AWS_ACCESS_KEY_ID", Value: "NEW"}})
+	assert.Equal(t, []string{
+		"PATH=/usr/bin",
+		"AWS_CONFIG_FILE=/tmp/config",
+		"AWS_ACCESS_KEY_ID=NEW",
+	}, got)
+}
+
+func TestFormatEnv_UnsupportedShell(t *testing.T) {
+	_, err := sso.FormatEnv("tcsh", nil)
+	assert.EqualError(t, err, `unsupported shell "tcsh": must be one of bash, zsh, fish, powershell, dotenv`)
+}
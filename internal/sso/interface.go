@@ -1,14 +1,18 @@
 package sso
 
 import (
+	"context"
 	"time"
 
 	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type SSOClient interface {
 	SetupSSO(opts SSOFlagOptions) error
-	InitSSO(refresh, noBrowser bool) error
+	InitSSO(refresh, noBrowser, setDefault bool) error
 	ConfigureSet(key, value, profile string) error
 	ConfigureGet(key, profile string) (string, error)
 	ValidProfiles() ([]string, error)
@@ -19,9 +23,22 @@ type SSOClient interface {
 	GetCachedSsoAccessToken(profile string) (string, time.Time, error)
 	GetSSOAccountName(accountID, profile string) (string, error)
 	SSOLogin(awsProfile string, refresh, noBrowser bool) error
-	GetRoleCredentials(accessToken, roleName, accountID string) (*models.AWSCredentials, error)
 	AwsSTSGetCallerIdentity(profile string) (string, error)
 	TryGetCallerIdentity(profile string) (string, error)
+	ProfileStatuses(verify bool) ([]models.ProfileStatus, error)
+	PromptInfo(profile string) (*models.PromptInfo, error)
+	Logout(opts LogoutOptions) error
+	ProfileCredentials(profile string) (*models.AWSCredentials, error)
+	ConfigureCredentialProcess(profile string) error
+	SetDefaultProfile(profile string) error
+	SSOSessions() ([]models.SSOSessionInfo, error)
+	RenameSSOSession(oldName, newName string, yes bool) error
+	RemoveSSOSession(name string, yes bool) error
+	ImportSSOSessions() ([]string, error)
+	AssumeRole(opts AssumeRoleOptions) (*models.AWSCredentials, error)
+	ConfigureAssumeRoleProfile(profile string, opts AssumeRoleOptions) error
+	SetupAssumeRoleProfile(profile string, opts AssumeRoleOptions) error
+	MFASession(opts MFASessionOptions) (*models.AWSCredentials, error)
 }
 
 type Prompter interface {
@@ -36,3 +53,22 @@ type PromptRunner interface {
 	RunPrompt(label, defaultValue string, validate func(string) error) (string, error)
 	RunSelect(label string, items []string) (string, error)
 }
+
+type OIDCAPI interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+type STSAPI interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetSessionToken(ctx context.Context, params *sts.GetSessionTokenInput, optFns ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error)
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+type SSOPortalAPI interface {
+	ListAccounts(ctx context.Context, params *sso.ListAccountsInput, optFns ...func(*sso.Options)) (*sso.ListAccountsOutput, error)
+	ListAccountRoles(ctx context.Context, params *sso.ListAccountRolesInput, optFns ...func(*sso.Options)) (*sso.ListAccountRolesOutput, error)
+	GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error)
+	Logout(ctx context.Context, params *sso.LogoutInput, optFns ...func(*sso.Options)) (*sso.LogoutOutput, error)
+}
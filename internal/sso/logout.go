@@ -0,0 +1,330 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+)
+
+// LogoutOptions selects the SSO sessions to log out of. Exactly one of the
+// fields is expected to be set.
+type LogoutOptions struct {
+	Session string
+	Profile string
+	All     bool
+}
+
+// Logout revokes the cached SSO access tokens of the selected sessions and
+// removes them, together with the cached role credentials of their profiles.
+// Logging out of all sessions also ends every MFA session.
+func (c *RealSSOClient) Logout(opts LogoutOptions) error {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return err
+	}
+
+	caches, err := readSSOCacheFiles()
+	if err != nil {
+		return err
+	}
+
+	targets, err := logoutTargets(opts, sections, caches)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		c.logoutSession(target, sections, caches)
+	}
+	if opts.All {
+		if removed := removeMFASessionCaches(sections); removed > 0 {
+			fmt.Printf("Removed %d cached MFA session credential file(s)\n", removed)
+		}
+	}
+
+	c.TokenCache.Mu.Lock()
+	c.TokenCache.AccessToken = ""
+	c.TokenCache.Expiry = time.Time{}
+	c.TokenCache.Mu.Unlock()
+
+	return nil
+}
+
+func logoutTargets(opts LogoutOptions, sections map[string]map[string]string, caches map[string]*models.SSOCache) ([]models.SSOSession, error) {
+	switch {
+	case opts.All:
+		seen := make(map[string]bool)
+		var targets []models.SSOSession
+		for _, cache := range caches {
+			startURL := strings.TrimSuffix(aws.ToString(cache.StartURL), "#")
+			if startURL == "" || seen[startURL] {
+				continue
+			}
+			seen[startURL] = true
+			targets = append(targets, models.SSOSession{
+				Name:     aws.ToString(cache.SessionName),
+				StartURL: startURL,
+				Region:   aws.ToString(cache.Region),
+			})
+		}
+		sort.Slice(targets, func(i, j int) bool { return targets[i].StartURL < targets[j].StartURL })
+		if len(targets) == 0 {
+			fmt.Println("No cached SSO tokens found.")
+		}
+		return targets, nil
+
+	case opts.Session != "":
+		section, ok := sections["sso-session "+opts.Session]
+		if !ok {
+			return nil, fmt.Errorf("sso-session %s not found in ~/.aws/config", opts.Session)
+		}
+		return []models.SSOSession{{
+			Name:     opts.Session,
+			StartURL: strings.TrimSuffix(section["sso_start_url"], "#"),
+			Region:   section["sso_region"],
+		}}, nil
+
+	case opts.Profile != "":
+		section, ok := sections[awsconfig.ProfileSection(opts.Profile)]
+		if !ok {
+			return nil, fmt.Errorf("profile %s not found in ~/.aws/config", opts.Profile)
+		}
+		if sessionName := section["sso_session"]; sessionName != "" {
+			return logoutTargets(LogoutOptions{Session: sessionName}, sections, caches)
+		}
+		if section["sso_start_url"] == "" {
+			return nil, fmt.Errorf("profile %s is not an SSO profile", opts.Profile)
+		}
+		return []models.SSOSession{{
+			StartURL: strings.TrimSuffix(section["sso_start_url"], "#"),
+			Region:   section["sso_region"],
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("one of session, profile or all must be specified")
+}
+
+// logoutSession revokes and deletes every cached token of the session's start
+// URL and the role credentials cached for the session's profiles.
+func (c *RealSSOClient) logoutSession(session models.SSOSession, sections map[string]map[string]string, caches map[string]*models.SSOCache) {
+	label := session.StartURL
+	if session.Name != "" {
+		label = fmt.Sprintf("sso-session %s (%s)", session.Name, session.StartURL)
+	}
+
+	paths := make([]string, 0, len(caches))
+	for path := range caches {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	removedTokens := 0
+	for _, path := range paths {
+		cache := caches[path]
+		if strings.TrimSuffix(aws.ToString(cache.StartURL), "#") != session.StartURL {
+			continue
+		}
+
+		if expiresAt, err := parseCacheTime(cache.ExpiresAt); err == nil && time.Now().Before(expiresAt) && aws.ToString(cache.AccessToken) != "" {
+			region := aws.ToString(cache.Region)
+			if region == "" {
+				region = session.Region
+			}
+			if err := c.revokeAccessToken(region, aws.ToString(cache.AccessToken)); err != nil {
+				fmt.Printf("Warning: failed to revoke SSO token for %s: %v\n", label, err)
+			}
+		}
+
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Warning: failed to remove %s: %v\n", path, err)
+			continue
+		}
+		delete(caches, path)
+		removedTokens++
+	}
+
+	removedCredentials := removeRoleCredentialCaches(session, sections)
+
+	if removedTokens == 0 {
+		fmt.Printf("No cached SSO tokens found for %s\n", label)
+	} else {
+		fmt.Printf("Logged out of %s\n", label)
+	}
+	if removedCredentials > 0 {
+		fmt.Printf("Removed %d cached role credential file(s)\n", removedCredentials)
+	}
+}
+
+func (c *RealSSOClient) revokeAccessToken(region, accessToken string) error {
+	if region == "" {
+		return fmt.Errorf("SSO region is unknown")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+	_, err := c.portalClient(region).Logout(ctx, &sso.LogoutInput{AccessToken: aws.String(accessToken)})
+	return err
+}
+
+// readSSOCacheFiles returns all parseable SSO token cache files keyed by path.
+func readSSOCacheFiles() (map[string]*models.SSOCache, error) {
+	cacheDir, err := ssoCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*models.SSOCache{}, nil
+		}
+		return nil, fmt.Errorf("failed to read SSO cache directory: %w", err)
+	}
+
+	caches := make(map[string]*models.SSOCache)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(cacheDir, file.Name())
+		cache, err := readSSOCacheFile(path)
+		if err != nil || cache.StartURL == nil {
+			continue
+		}
+		caches[path] = cache
+	}
+	return caches, nil
+}
+
+// roleCredentialCacheKey mirrors the AWS CLI naming of cached SSO role
+// credentials in ~/.aws/cli/cache.
+func roleCredentialCacheKey(accountID, roleName, sessionName, startURL string) string {
+	args := map[string]string{
+		"accountId": accountID,
+		"roleName":  roleName,
+	}
+	if sessionName != "" {
+		args["sessionName"] = sessionName
+	} else {
+		args["startUrl"] = startURL
+	}
+	data, _ := json.Marshal(args)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func roleCredentialCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".aws", "cli", "cache"), nil
+}
+
+// removeRoleCredentialCaches deletes the cached role credentials of every
+// profile that belongs to the session, and of the assume-role profiles whose
+// source_profile chain leads to one of them. It returns how many were removed.
+func removeRoleCredentialCaches(session models.SSOSession, sections map[string]map[string]string) int {
+	cacheDir, err := roleCredentialCacheDir()
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	roots := make(map[string]bool)
+	for name, values := range sections {
+		if name != "default" && !strings.HasPrefix(name, "profile ") {
+			continue
+		}
+		if values["sso_account_id"] == "" || values["sso_role_name"] == "" {
+			continue
+		}
+
+		sessionName := values["sso_session"]
+		startURL := strings.TrimSuffix(values["sso_start_url"], "#")
+		if sessionName != "" {
+			if session.Name != "" && sessionName != session.Name {
+				continue
+			}
+			if session.Name == "" {
+				if s, ok := sections["sso-session "+sessionName]; !ok || strings.TrimSuffix(s["sso_start_url"], "#") != session.StartURL {
+					continue
+				}
+			}
+		} else if startURL != session.StartURL {
+			continue
+		}
+
+		roots[strings.TrimPrefix(name, "profile ")] = true
+		key := roleCredentialCacheKey(values["sso_account_id"], values["sso_role_name"], sessionName, startURL)
+		if err := os.Remove(filepath.Join(cacheDir, key+".json")); err == nil {
+			removed++
+		}
+	}
+	return removed + removeAssumeRoleCaches(cacheDir, roots, sections)
+}
+
+// removeMFASessionCaches deletes the cached credentials of every MFA session
+// profile, and of the assume-role profiles chained from them.
+func removeMFASessionCaches(sections map[string]map[string]string) int {
+	cacheDir, err := roleCredentialCacheDir()
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	roots := make(map[string]bool)
+	for name, values := range sections {
+		source := values[mfaSourceKey]
+		if source == "" || (name != "default" && !strings.HasPrefix(name, "profile ")) {
+			continue
+		}
+		roots[strings.TrimPrefix(name, "profile ")] = true
+
+		mfaSerial := sections[awsconfig.ProfileSection(source)]["mfa_serial"]
+		if mfaSerial == "" {
+			continue
+		}
+		path, err := mfaSessionCachePath(source, mfaSerial)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+	}
+	return removed + removeAssumeRoleCaches(cacheDir, roots, sections)
+}
+
+// removeAssumeRoleCaches deletes the cached credentials of the assume-role
+// profiles whose source_profile chain leads to one of roots.
+func removeAssumeRoleCaches(cacheDir string, roots map[string]bool, sections map[string]map[string]string) int {
+	removed := 0
+	for name := range sections {
+		if name != "default" && !strings.HasPrefix(name, "profile ") {
+			continue
+		}
+		profile := strings.TrimPrefix(name, "profile ")
+		opts, err := resolveAssumeRoleProfile(profile, sections)
+		if err != nil || opts == nil {
+			continue
+		}
+		if root, err := sourceSSOProfile(profile, sections); err != nil || !roots[root] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, assumeRoleCacheKey(*opts)+".json")); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
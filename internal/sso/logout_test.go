@@ -0,0 +1,296 @@
+package sso_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logoutConfig = `[sso-session team]
+sso_start_url = https://team.awsapps.com/start
+sso_region = us-east-1
+
+[profile team-admin]
+sso_session = team
+sso_account_id = 111111111111
+sso_role_name = Admin
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = eu-west-1
+sso_account_id = 222222222222
+sso_role_name = ReadOnly
+
+[profile static]
+region = us-east-1
+`
+
+type logoutFixture struct {
+	home            string
+	teamToken       string
+	teamStaleToken  string
+	legacyToken     string
+	teamCredentials string
+	legacyCreds     string
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeLogoutFixtures(t *testing.T) logoutFixture {
+	t.Helper()
+	home := setTestHome(t)
+	cacheDir := filepath.Join(home, ".aws", "sso", "cache")
+	cliCacheDir := filepath.Join(home, ".aws", "cli", "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0700))
+	require.NoError(t, os.MkdirAll(cliCacheDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".aws", "config"), []byte(logoutConfig), 0600))
+
+	write := func(path string, cache models.SSOCache) string {
+		data, err := json.Marshal(cache)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0600))
+		return path
+	}
+	valid := aws.String(time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	expired := aws.String(time.Now().Add(-time.Hour).UTC().Format(time.RFC3339))
+
+	f := logoutFixture{home: home}
+	f.teamToken = write(filepath.Join(cacheDir, sha1Hex("team")+".json"), models.SSOCache{
+		StartURL: aws.String("https://team.awsapps.com/start"), SessionName: aws.String("team"),
+		Region: aws.String("us-east-1"), AccessToken: aws.String("team-token"), ExpiresAt: valid,
+	})
+	f.teamStaleToken = write(filepath.Join(cacheDir, "old.json"), models.SSOCache{
+		StartURL: aws.String("https://team.awsapps.com/start"),
+		Region:   aws.String("us-east-1"), AccessToken: aws.String("old-token"), ExpiresAt: expired,
+	})
+	f.legacyToken = write(filepath.Join(cacheDir, sha1Hex("https://legacy.awsapps.com/start")+".json"), models.SSOCache{
+		StartURL: aws.String("https://legacy.awsapps.com/start"),
+		Region:   aws.String("eu-west-1"), AccessToken: aws.String("legacy-token"), ExpiresAt: valid,
+	})
+
+	f.teamCredentials = filepath.Join(cliCacheDir, sha1Hex(`{"accountId":"111111111111","roleName":"Admin","sessionName":"team"}`)+".json")
+	f.legacyCreds = filepath.Join(cliCacheDir, sha1Hex(`{"accountId":"222222222222","roleName":"ReadOnly","startUrl":"https://legacy.awsapps.com/start"}`)+".json")
+	require.NoError(t, os.WriteFile(f.teamCredentials, []byte("{}"), 0600))
+	require.NoError(t, os.WriteFile(f.legacyCreds, []byte("{}"), 0600))
+	return f
+}
+
+func assertExists(t *testing.T, path string, exists bool) {
+	t.Helper()
+	_, err := os.Stat(path)
+	if exists {
+		assert.NoError(t, err, path)
+	} else {
+		assert.True(t, os.IsNotExist(err), path)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        sso.LogoutOptions
+		revoked     map[string]string
+		removed     func(f logoutFixture) []string
+		kept        func(f logoutFixture) []string
+		logoutError error
+	}{
+		{
+			name:    "by session",
+			opts:    sso.LogoutOptions{Session: "team"},
+			revoked: map[string]string{"team-token": "us-east-1"},
+			removed: func(f logoutFixture) []string { return []string{f.teamToken, f.teamStaleToken, f.teamCredentials} },
+			kept:    func(f logoutFixture) []string { return []string{f.legacyToken, f.legacyCreds} },
+		},
+		{
+			name:    "by session profile",
+			opts:    sso.LogoutOptions{Profile: "team-admin"},
+			revoked: map[string]string{"team-token": "us-east-1"},
+			removed: func(f logoutFixture) []string { return []string{f.teamToken, f.teamStaleToken, f.teamCredentials} },
+			kept:    func(f logoutFixture) []string { return []string{f.legacyToken, f.legacyCreds} },
+		},
+		{
+			name:    "by legacy profile",
+			opts:    sso.LogoutOptions{Profile: "legacy"},
+			revoked: map[string]string{"legacy-token": "eu-west-1"},
+			removed: func(f logoutFixture) []string { return []string{f.legacyToken, f.legacyCreds} },
+			kept:    func(f logoutFixture) []string { return []string{f.teamToken, f.teamCredentials} },
+		},
+		{
+			name:    "all",
+			opts:    sso.LogoutOptions{All: true},
+			revoked: map[string]string{"team-token": "us-east-1", "legacy-token": "eu-west-1"},
+			removed: func(f logoutFixture) []string {
+				return []string{f.teamToken, f.teamStaleToken, f.legacyToken, f.teamCredentials, f.legacyCreds}
+			},
+			kept: func(f logoutFixture) []string { return nil },
+		},
+		{
+			name:        "revoke failure still clears cache",
+			opts:        sso.LogoutOptions{Session: "team"},
+			revoked:     map[string]string{"team-token": "us-east-1"},
+			logoutError: errors.New("unauthorized"),
+			removed:     func(f logoutFixture) []string { return []string{f.teamToken, f.teamStaleToken, f.teamCredentials} },
+			kept:        func(f logoutFixture) []string { return []string{f.legacyToken} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := writeLogoutFixtures(t)
+			revoked := make(map[string]string)
+
+			client := &sso.RealSSOClient{
+				TokenCache: models.TokenCache{AccessToken: "team-token", Expiry: time.Now().Add(time.Hour)},
+			}
+			client.NewPortalClient = func(region string) sso.SSOPortalAPI {
+				m := mock_sso.NewMockSSOPortalAPI(ctrl)
+				m.EXPECT().Logout(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, in *awssso.LogoutInput, _ ...func(*awssso.Options)) (*awssso.LogoutOutput, error) {
+						revoked[aws.ToString(in.AccessToken)] = region
+						return &awssso.LogoutOutput{}, tt.logoutError
+					})
+				return m
+			}
+
+			output := captureStdout(t, func() {
+				require.NoError(t, client.Logout(tt.opts))
+			})
+
+			assert.Equal(t, tt.revoked, revoked)
+			for _, path := range tt.removed(f) {
+				assertExists(t, path, false)
+			}
+			for _, path := range tt.kept(f) {
+				assertExists(t, path, true)
+			}
+			assert.Empty(t, client.TokenCache.AccessToken)
+			assert.Contains(t, output, "Logged out of")
+			if tt.logoutError != nil {
+				assert.Contains(t, output, "Warning: failed to revoke SSO token")
+			}
+		})
+	}
+}
+
+func TestLogout_ChainedCaches(t *testing.T) {
+	const config = roleChainConfig + `
+[profile legacy-deploy]
+role_arn = arn:aws:iam::333333333333:role/deploy
+source_profile = legacy
+
+[profile customer]
+mfa_serial = arn:aws:iam::123456789012:mfa/alice
+
+[profile customer-mfa]
+mfa_source_profile = customer
+
+[profile customer-deploy]
+role_arn = arn:aws:iam::210987654321:role/deploy
+source_profile = customer-mfa
+`
+	cacheFile := func(home, key string) string {
+		return filepath.Join(home, ".aws", "cli", "cache", sha1Hex(key)+".json")
+	}
+
+	tests := []struct {
+		name    string
+		opts    sso.LogoutOptions
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "by session",
+			opts:    sso.LogoutOptions{Session: "team"},
+			removed: []string{"prod-deploy", "prod-readonly"},
+			kept:    []string{"legacy-deploy", "customer-mfa", "customer-deploy"},
+		},
+		{
+			name:    "all",
+			opts:    sso.LogoutOptions{All: true},
+			removed: []string{"prod-deploy", "prod-readonly", "legacy-deploy", "customer-mfa", "customer-deploy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := writeLogoutFixtures(t)
+			writeAWSConfig(t, f.home, config)
+			paths := map[string]string{
+				"prod-deploy":     cacheFile(f.home, `{"DurationSeconds":1800,"ExternalId":"ext-123","RoleArn":"arn:aws:iam::333333333333:role/deploy","SourceProfile":"hub"}`),
+				"prod-readonly":   cacheFile(f.home, `{"RoleArn":"arn:aws:iam::444444444444:role/ReadOnly","RoleSessionName":"audit","SourceProfile":"prod-deploy"}`),
+				"legacy-deploy":   cacheFile(f.home, `{"RoleArn":"arn:aws:iam::333333333333:role/deploy","SourceProfile":"legacy"}`),
+				"customer-mfa":    cacheFile(f.home, `{"profile":"customer","serialNumber":"arn:aws:iam::123456789012:mfa/alice"}`),
+				"customer-deploy": cacheFile(f.home, `{"RoleArn":"arn:aws:iam::210987654321:role/deploy","SourceProfile":"customer-mfa"}`),
+			}
+			for _, path := range paths {
+				require.NoError(t, os.WriteFile(path, []byte("{}"), 0600))
+			}
+
+			client := &sso.RealSSOClient{}
+			client.NewPortalClient = func(region string) sso.SSOPortalAPI {
+				m := mock_sso.NewMockSSOPortalAPI(ctrl)
+				m.EXPECT().Logout(gomock.Any(), gomock.Any(), gomock.Any()).Return(&awssso.LogoutOutput{}, nil).AnyTimes()
+				return m
+			}
+
+			captureStdout(t, func() {
+				require.NoError(t, client.Logout(tt.opts))
+			})
+
+			for _, profile := range tt.removed {
+				assertExists(t, paths[profile], false)
+			}
+			for _, profile := range tt.kept {
+				assertExists(t, paths[profile], true)
+			}
+		})
+	}
+}
+
+func TestLogout_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        sso.LogoutOptions
+		errContains string
+	}{
+		{name: "unknown session", opts: sso.LogoutOptions{Session: "missing"}, errContains: "sso-session missing not found"},
+		{name: "unknown profile", opts: sso.LogoutOptions{Profile: "missing"}, errContains: "profile missing not found"},
+		{name: "non-SSO profile", opts: sso.LogoutOptions{Profile: "static"}, errContains: "profile static is not an SSO profile"},
+		{name: "no selector", opts: sso.LogoutOptions{}, errContains: "one of session, profile or all must be specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeLogoutFixtures(t)
+			client := &sso.RealSSOClient{}
+
+			err := client.Logout(tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
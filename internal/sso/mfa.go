@@ -0,0 +1,201 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// mfaSourceKey marks a profile in ~/.aws/config as the MFA session of the IAM
+// user profile it names. The AWS CLI and SDKs ignore it and use the
+// profile's credential_process instead.
+const mfaSourceKey = "mfa_source_profile"
+
+// ErrMFASessionExpired is returned when the credentials of an MFA session
+// profile have expired and a new MFA code is needed.
+var ErrMFASessionExpired = errors.New("MFA session expired")
+
+var validMFACode = regexp.MustCompile(`^\d{6}$`)
+
+// MFASessionOptions selects the IAM user profile to start an MFA session for
+// and the profile the temporary credentials are exposed as.
+type MFASessionOptions struct {
+	Profile        string
+	SessionProfile string
+	// TokenCode is prompted for when empty.
+	TokenCode string
+	// DurationSeconds of 0 uses the STS default of 12 hours.
+	DurationSeconds int32
+	// Force starts a new session even if the cached one is still valid.
+	Force bool
+}
+
+// MFASession gets temporary credentials for an IAM user profile with
+// mfa_serial through STS GetSessionToken, caches them and writes
+// opts.SessionProfile to ~/.aws/config. That profile gets its credentials
+// from the cache with credential_process, so every tool that reads the
+// shared config files can use it.
+func (c *RealSSOClient) MFASession(opts MFASessionOptions) (*models.AWSCredentials, error) {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return nil, err
+	}
+	mfaSerial := sections[awsconfig.ProfileSection(opts.Profile)]["mfa_serial"]
+	if mfaSerial == "" {
+		return nil, fmt.Errorf("profile %s has no mfa_serial in ~/.aws/config", opts.Profile)
+	}
+	longTerm, err := c.staticCredentials(opts.Profile, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	if !validProfileName.MatchString(opts.SessionProfile) {
+		return nil, fmt.Errorf("invalid profile name %q", opts.SessionProfile)
+	}
+	if opts.SessionProfile == opts.Profile {
+		return nil, fmt.Errorf("the MFA session profile must differ from %s", opts.Profile)
+	}
+	if values, ok := sections[awsconfig.ProfileSection(opts.SessionProfile)]; ok && values[mfaSourceKey] != opts.Profile {
+		return nil, fmt.Errorf("profile %s already exists and is not an MFA session of %s", opts.SessionProfile, opts.Profile)
+	}
+
+	cachePath, err := mfaSessionCachePath(opts.Profile, mfaSerial)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.Force {
+		if creds, err := readRoleCredentialCache(cachePath); err == nil {
+			if expiresAt, err := time.Parse(time.RFC3339, creds.Expiration); err == nil && time.Until(expiresAt) > credentialExpiryMargin {
+				return creds, c.configureMFASessionProfile(opts.SessionProfile, opts.Profile)
+			}
+		}
+	}
+
+	code := opts.TokenCode
+	if code == "" {
+		code, err = c.Prompter.PromptRequired(fmt.Sprintf("MFA code for %s", mfaSerial))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !validMFACode.MatchString(code) {
+		return nil, errors.New("invalid MFA code: must be 6 digits")
+	}
+
+	input := &sts.GetSessionTokenInput{
+		SerialNumber: aws.String(mfaSerial),
+		TokenCode:    aws.String(code),
+	}
+	if opts.DurationSeconds > 0 {
+		input.DurationSeconds = aws.Int32(opts.DurationSeconds)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	output, err := c.stsClient(chainRegion(opts.Profile, sections), longTerm).GetSessionToken(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session token: %w", err)
+	}
+	if output.Credentials == nil {
+		return nil, errors.New("failed to get session token: empty response")
+	}
+
+	creds := &models.AWSCredentials{
+		AccessKeyID:     aws.ToString(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.ToString(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.ToString(output.Credentials.SessionToken),
+		Expiration:      aws.ToTime(output.Credentials.Expiration).UTC().Format(time.RFC3339),
+	}
+	if err := writeRoleCredentialCache(cachePath, "mfa-session", creds); err != nil {
+		return nil, fmt.Errorf("failed to cache session credentials: %w", err)
+	}
+	return creds, c.configureMFASessionProfile(opts.SessionProfile, opts.Profile)
+}
+
+// mfaSessionCredentials returns the cached credentials of an MFA session
+// profile. New ones need an MFA code, so they are never requested here.
+func (c *RealSSOClient) mfaSessionCredentials(profile, source string, sections map[string]map[string]string) (*models.AWSCredentials, error) {
+	mfaSerial := sections[awsconfig.ProfileSection(source)]["mfa_serial"]
+	if mfaSerial == "" {
+		return nil, fmt.Errorf("profile %s of MFA session profile %s has no mfa_serial", source, profile)
+	}
+	cachePath, err := mfaSessionCachePath(source, mfaSerial)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := fmt.Sprintf("run `awsctl iam session --profile %s` to enter a new MFA code", source)
+	creds, err := readRoleCredentialCache(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: no MFA session for profile %s; %s", ErrMFASessionExpired, source, hint)
+	}
+	if expiresAt, err := time.Parse(time.RFC3339, creds.Expiration); err != nil || time.Until(expiresAt) <= credentialExpiryMargin {
+		return nil, fmt.Errorf("%w: MFA session for profile %s has expired; %s", ErrMFASessionExpired, source, hint)
+	}
+	return creds, nil
+}
+
+// staticCredentials returns the long-term access key of a profile from the
+// credentials file, or from ~/.aws/config if it is not there.
+func (c *RealSSOClient) staticCredentials(profile string, sections map[string]map[string]string) (*models.AWSCredentials, error) {
+	credentials, err := c.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	values := credentials.Values(profile)
+	if values["aws_access_key_id"] == "" {
+		values = sections[awsconfig.ProfileSection(profile)]
+	}
+	if values["aws_access_key_id"] == "" || values["aws_secret_access_key"] == "" {
+		return nil, fmt.Errorf("profile %s has no aws_access_key_id and aws_secret_access_key", profile)
+	}
+	return &models.AWSCredentials{
+		AccessKeyID:     values["aws_access_key_id"],
+		SecretAccessKey: values["aws_secret_access_key"],
+	}, nil
+}
+
+// configureMFASessionProfile writes the profile that exposes the MFA session
+// of source. The region of source is copied when it has one.
+func (c *RealSSOClient) configureMFASessionProfile(profile, source string) error {
+	err := c.updateConfig(func(f *awsconfig.File) error {
+		section := awsconfig.ProfileSection(profile)
+		if values := f.Values(section); values != nil && values[mfaSourceKey] != source {
+			return fmt.Errorf("profile %s already exists and is not an MFA session of %s", profile, source)
+		}
+		f.Set(section, mfaSourceKey, source)
+		f.Set(section, "credential_process", CredentialProcessCommand(profile))
+		if _, ok := f.Get(section, "region"); !ok {
+			if region, ok := f.Get(awsconfig.ProfileSection(source), "region"); ok && region != "" {
+				f.Set(section, "region", region)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure profile %s: %w", profile, err)
+	}
+	return nil
+}
+
+func mfaSessionCachePath(profile, mfaSerial string) (string, error) {
+	cacheDir, err := roleCredentialCacheDir()
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(map[string]string{"profile": profile, "serialNumber": mfaSerial})
+	sum := sha1.Sum(data)
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".json"), nil
+}
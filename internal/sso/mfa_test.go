@@ -0,0 +1,162 @@
+package sso_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mfaConfig = `[profile customer]
+region = eu-west-1
+mfa_serial = arn:aws:iam::123456789012:mfa/alice
+
+[profile nomfa]
+region = eu-west-1
+
+[profile customer-deploy]
+role_arn = arn:aws:iam::210987654321:role/deploy
+source_profile = customer-mfa
+`
+
+const mfaCredentials = `[customer]
+aws_access_key_id = AKIALONGTERM
+aws_secret_access_key = long-term-secret
+`
+
+type sessionTokenCall struct {
+	region string
+	source *models.AWSCredentials
+	input  *sts.GetSessionTokenInput
+}
+
+func newMFAClient(t *testing.T, ctrl *gomock.Controller, calls *[]sessionTokenCall, assumeCalls *[]assumeCall) (*sso.RealSSOClient, *mock_sso.MockPrompter, string) {
+	t.Helper()
+	home := setTestHome(t)
+	writeAWSConfig(t, home, mfaConfig)
+	require.NoError(t, afero.WriteFile(afero.NewOsFs(), filepath.Join(home, ".aws", "credentials"), []byte(mfaCredentials), 0600))
+
+	expiration := time.Now().Add(12 * time.Hour).UTC().Truncate(time.Second)
+	prompter := mock_sso.NewMockPrompter(ctrl)
+	return &sso.RealSSOClient{
+		Prompter: prompter,
+		NewSTSClient: func(region string, creds *models.AWSCredentials) sso.STSAPI {
+			m := mock_sso.NewMockSTSAPI(ctrl)
+			m.EXPECT().GetSessionToken(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, in *sts.GetSessionTokenInput, _ ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error) {
+					*calls = append(*calls, sessionTokenCall{region: region, source: creds, input: in})
+					return &sts.GetSessionTokenOutput{Credentials: &ststypes.Credentials{
+						AccessKeyId:     aws.String("ASIAMFA"),
+						SecretAccessKey: aws.String("mfa-secret"),
+						SessionToken:    aws.String("mfa-token"),
+						Expiration:      aws.Time(expiration),
+					}}, nil
+				}).AnyTimes()
+			m.EXPECT().AssumeRole(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, in *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+					*assumeCalls = append(*assumeCalls, assumeCall{region: region, source: creds.AccessKeyID, input: in})
+					return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+						AccessKeyId:     aws.String("ASIAROLE"),
+						SecretAccessKey: aws.String("role-secret"),
+						SessionToken:    aws.String("role-token"),
+						Expiration:      aws.Time(expiration),
+					}}, nil
+				}).AnyTimes()
+			return m
+		},
+	}, prompter, home
+}
+
+func TestMFASession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []sessionTokenCall
+	var assumeCalls []assumeCall
+	client, prompter, home := newMFAClient(t, ctrl, &calls, &assumeCalls)
+	prompter.EXPECT().PromptRequired("MFA code for arn:aws:iam::123456789012:mfa/alice").Return("123456", nil)
+
+	opts := sso.MFASessionOptions{Profile: "customer", SessionProfile: "customer-mfa", DurationSeconds: 3600}
+	creds, err := client.MFASession(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAMFA", creds.AccessKeyID)
+
+	require.Len(t, calls, 1)
+	assert.Equal(t, "eu-west-1", calls[0].region)
+	assert.Equal(t, &models.AWSCredentials{AccessKeyID: "AKIALONGTERM", SecretAccessKey: "long-term-secret"}, calls[0].source)
+	assert.Equal(t, "arn:aws:iam::123456789012:mfa/alice", aws.ToString(calls[0].input.SerialNumber))
+	assert.Equal(t, "123456", aws.ToString(calls[0].input.TokenCode))
+	assert.Equal(t, int32(3600), aws.ToInt32(calls[0].input.DurationSeconds))
+
+	assert.Equal(t, map[string]string{
+		"mfa_source_profile": "customer",
+		"credential_process": sso.CredentialProcessCommand("customer-mfa"),
+		"region":             "eu-west-1",
+	}, readAWSConfig(t, home).Values("profile customer-mfa"))
+
+	sessionCreds, err := client.ProfileCredentials("customer-mfa")
+	require.NoError(t, err)
+	assert.Equal(t, creds, sessionCreds)
+
+	roleCreds, err := client.ProfileCredentials("customer-deploy")
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAROLE", roleCreds.AccessKeyID)
+	require.Len(t, assumeCalls, 1)
+	assert.Equal(t, "ASIAMFA", assumeCalls[0].source)
+
+	cached, err := client.MFASession(opts)
+	require.NoError(t, err)
+	assert.Equal(t, creds, cached)
+	assert.Len(t, calls, 1, "a valid session must be reused without prompting")
+
+	opts.Force = true
+	opts.TokenCode = "654321"
+	_, err = client.MFASession(opts)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "654321", aws.ToString(calls[1].input.TokenCode))
+}
+
+func TestMFASession_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var calls []sessionTokenCall
+	var assumeCalls []assumeCall
+	client, prompter, home := newMFAClient(t, ctrl, &calls, &assumeCalls)
+	writeAWSConfig(t, home, mfaConfig+`
+[profile customer-mfa]
+mfa_source_profile = customer
+credential_process = awsctl sso credentials --profile customer-mfa
+`)
+
+	_, err := client.MFASession(sso.MFASessionOptions{Profile: "nomfa", SessionProfile: "nomfa-mfa"})
+	assert.EqualError(t, err, "profile nomfa has no mfa_serial in ~/.aws/config")
+
+	_, err = client.MFASession(sso.MFASessionOptions{Profile: "customer", SessionProfile: "nomfa"})
+	assert.EqualError(t, err, "profile nomfa already exists and is not an MFA session of customer")
+
+	_, err = client.MFASession(sso.MFASessionOptions{Profile: "customer", SessionProfile: "customer-mfa", TokenCode: "12345"})
+	assert.EqualError(t, err, "invalid MFA code: must be 6 digits")
+
+	prompter.EXPECT().PromptRequired(gomock.Any()).Return("", errors.New("interrupted"))
+	_, err = client.MFASession(sso.MFASessionOptions{Profile: "customer", SessionProfile: "customer-mfa"})
+	assert.EqualError(t, err, "interrupted")
+	assert.Empty(t, calls)
+
+	_, err = client.ProfileCredentials("customer-deploy")
+	assert.ErrorIs(t, err, sso.ErrMFASessionExpired)
+	assert.Contains(t, err.Error(), "run `awsctl iam session --profile customer`")
+}
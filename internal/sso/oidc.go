@@ -0,0 +1,326 @@
+package sso
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/common"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+const (
+	deviceCodeGrantType   = "urn:ietf:params:oauth:grant-type:device_code"
+	refreshTokenGrantType = "refresh_token"
+	oidcClientType        = "public"
+	defaultPollInterval   = 5 * time.Second
+	slowDownIncrement     = 5 * time.Second
+	tokenRefreshTimeout   = 30 * time.Second
+
+	// defaultTokenRefreshWindow is used when tokenRefreshWindowMinutes is not
+	// set in the awsctl config.
+	defaultTokenRefreshWindow = 15 * time.Minute
+
+	// registrationRenewWindow re-registers the OIDC client at login when the
+	// cached registration expires within this window, so new refresh tokens
+	// are not tied to a client that is about to expire.
+	registrationRenewWindow = 7 * 24 * time.Hour
+)
+
+// NewOIDCClient returns an SSO OIDC client for the given region. The OIDC
+// operations used by awsctl are unauthenticated, so no credentials are needed.
+func NewOIDCClient(region string) OIDCAPI {
+	return ssooidc.New(ssooidc.Options{Region: region})
+}
+
+func (c *RealSSOClient) oidcClient(region string) OIDCAPI {
+	if c.NewOIDCClient != nil {
+		return c.NewOIDCClient(region)
+	}
+	return NewOIDCClient(region)
+}
+
+func (c *RealSSOClient) wait(ctx context.Context, d time.Duration) error {
+	if c.Sleep != nil {
+		c.Sleep(d)
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *RealSSOClient) openBrowser(url string) error {
+	if c.OpenBrowser != nil {
+		return c.OpenBrowser(url)
+	}
+	return common.OpenURL(url)
+}
+
+// ssoCacheKey mirrors the AWS CLI naming of token cache files: the SHA-1 of
+// the sso-session name, or of the start URL for legacy profiles.
+func ssoCacheKey(sessionName, startURL string) string {
+	input := startURL
+	if sessionName != "" {
+		input = sessionName
+	}
+	sum := sha1.Sum([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+func ssoCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".aws", "sso", "cache"), nil
+}
+
+func ssoCachePath(sessionName, startURL string) (string, error) {
+	cacheDir, err := ssoCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, ssoCacheKey(sessionName, startURL)+".json"), nil
+}
+
+func readSSOCacheFile(path string) (*models.SSOCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache models.SSOCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse SSO cache file %s: %w", path, err)
+	}
+	return &cache, nil
+}
+
+func writeSSOCacheFile(path string, cache *models.SSOCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create SSO cache directory: %w", err)
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to encode SSO cache: %w", err)
+	}
+	if err := writeConfigFile(path, string(data)); err != nil {
+		return fmt.Errorf("failed to write SSO cache file %s: %w", path, err)
+	}
+	return nil
+}
+
+func parseCacheTime(value *string) (time.Time, error) {
+	if value == nil || *value == "" {
+		return time.Time{}, errors.New("missing timestamp")
+	}
+	return time.Parse(time.RFC3339, *value)
+}
+
+// clientRegistration returns an OIDC client registration for the cache file,
+// reusing the one stored there while it is still valid.
+func (c *RealSSOClient) clientRegistration(ctx context.Context, client OIDCAPI, cachePath, sessionName string, scopes []string) (*models.SSOCache, error) {
+	if cached, err := readSSOCacheFile(cachePath); err == nil && cached.ClientID != nil && cached.ClientSecret != nil {
+		if expiresAt, err := parseCacheTime(cached.RegistrationExpiresAt); err == nil && time.Now().Add(registrationRenewWindow).Before(expiresAt) {
+			return &models.SSOCache{
+				ClientID:              cached.ClientID,
+				ClientSecret:          cached.ClientSecret,
+				RegistrationExpiresAt: cached.RegistrationExpiresAt,
+			}, nil
+		}
+	}
+
+	clientName := "awsctl"
+	if sessionName != "" {
+		clientName = "awsctl-" + sessionName
+	}
+
+	out, err := client.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String(clientName),
+		ClientType: aws.String(oidcClientType),
+		Scopes:     scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register SSO OIDC client: %w", err)
+	}
+
+	return &models.SSOCache{
+		ClientID:              out.ClientId,
+		ClientSecret:          out.ClientSecret,
+		RegistrationExpiresAt: aws.String(time.Unix(out.ClientSecretExpiresAt, 0).UTC().Format(time.RFC3339)),
+	}, nil
+}
+
+// deviceLogin runs the OIDC device authorization flow against the SSO region
+// and writes the resulting token to the SSO cache in the AWS CLI format.
+func (c *RealSSOClient) deviceLogin(ctx context.Context, sessionName, startURL, region string, scopes []string, noBrowser bool) (*models.SSOCache, error) {
+	if startURL == "" || region == "" {
+		return nil, fmt.Errorf("SSO start URL and region are required for login")
+	}
+
+	cachePath, err := ssoCachePath(sessionName, startURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.oidcClient(region)
+
+	registration, err := c.clientRegistration(ctx, client, cachePath, sessionName, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     registration.ClientID,
+		ClientSecret: registration.ClientSecret,
+		StartUrl:     aws.String(startURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	verificationURL := aws.ToString(auth.VerificationUriComplete)
+	if verificationURL == "" {
+		verificationURL = aws.ToString(auth.VerificationUri)
+	}
+
+	if noBrowser {
+		fmt.Println("\nBrowser will not be automatically opened.")
+		fmt.Println("Please visit the following URL:")
+	} else {
+		fmt.Println("\nAttempting to automatically open the SSO authorization page in your default browser.")
+		fmt.Println("If the browser does not open or you wish to use a different device to authorize this request, open the following URL:")
+	}
+	fmt.Printf("\n%s\n\nThen enter the code:\n\n%s\n\n", verificationURL, aws.ToString(auth.UserCode))
+
+	if !noBrowser {
+		if err := c.openBrowser(verificationURL); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
+
+	interval := defaultPollInterval
+	if auth.Interval > 0 {
+		interval = time.Duration(auth.Interval) * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+
+	for {
+		if err := c.wait(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		token, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     registration.ClientID,
+			ClientSecret: registration.ClientSecret,
+			GrantType:    aws.String(deviceCodeGrantType),
+			DeviceCode:   auth.DeviceCode,
+		})
+		if err == nil {
+			cache := &models.SSOCache{
+				AccessToken:           token.AccessToken,
+				ExpiresAt:             aws.String(time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UTC().Format(time.RFC3339)),
+				StartURL:              aws.String(startURL),
+				Region:                aws.String(region),
+				ClientID:              registration.ClientID,
+				ClientSecret:          registration.ClientSecret,
+				RegistrationExpiresAt: registration.RegistrationExpiresAt,
+			}
+			if sessionName != "" {
+				cache.SessionName = aws.String(sessionName)
+			}
+			if aws.ToString(token.RefreshToken) != "" {
+				cache.RefreshToken = token.RefreshToken
+			}
+			if err := writeSSOCacheFile(cachePath, cache); err != nil {
+				return nil, err
+			}
+			return cache, nil
+		}
+
+		var pending *types.AuthorizationPendingException
+		var slowDown *types.SlowDownException
+		var expired *types.ExpiredTokenException
+		var denied *types.AccessDeniedException
+		switch {
+		case errors.As(err, &pending):
+		case errors.As(err, &slowDown):
+			interval += slowDownIncrement
+		case errors.As(err, &expired):
+			return nil, fmt.Errorf("device authorization expired before it was approved")
+		case errors.As(err, &denied):
+			return nil, fmt.Errorf("device authorization was denied")
+		default:
+			return nil, fmt.Errorf("failed to create SSO token: %w", err)
+		}
+
+		if auth.ExpiresIn > 0 && time.Now().After(deadline) {
+			return nil, fmt.Errorf("device authorization expired before it was approved")
+		}
+	}
+}
+
+// refreshCachedToken renews the access token stored in the cache file at path
+// using the refresh_token grant, without any user interaction.
+func (c *RealSSOClient) refreshCachedToken(path string, cache *models.SSOCache) (*models.SSOCache, time.Time, error) {
+	if aws.ToString(cache.RefreshToken) == "" || cache.ClientID == nil || cache.ClientSecret == nil || aws.ToString(cache.Region) == "" {
+		return nil, time.Time{}, errors.New("SSO cache does not contain a refresh token")
+	}
+
+	registrationExpiry, err := parseCacheTime(cache.RegistrationExpiresAt)
+	if err != nil || !time.Now().Before(registrationExpiry) {
+		return nil, time.Time{}, errors.New("SSO client registration has expired")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+	defer cancel()
+
+	token, err := c.oidcClient(*cache.Region).CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     cache.ClientID,
+		ClientSecret: cache.ClientSecret,
+		GrantType:    aws.String(refreshTokenGrantType),
+		RefreshToken: cache.RefreshToken,
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to refresh SSO token: %w", err)
+	}
+
+	expiry := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UTC()
+	refreshed := *cache
+	refreshed.AccessToken = token.AccessToken
+	refreshed.ExpiresAt = aws.String(expiry.Format(time.RFC3339))
+	if aws.ToString(token.RefreshToken) != "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	if err := writeSSOCacheFile(path, &refreshed); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return &refreshed, expiry.Truncate(time.Second), nil
+}
+
+func parseScopes(scopes string) []string {
+	var result []string
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,445 @@
+package sso_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCServer is a local stand-in for the SSO OIDC endpoint. Token
+// requests are answered from tokenErrors in order; once exhausted a token is
+// issued.
+type fakeOIDCServer struct {
+	*httptest.Server
+	mu            sync.Mutex
+	tokenErrors   []string
+	registrations int
+	tokenRequests []map[string]interface{}
+}
+
+func newFakeOIDCServer(t *testing.T, tokenErrors ...string) *fakeOIDCServer {
+	t.Helper()
+	f := &fakeOIDCServer{tokenErrors: tokenErrors}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/client/register", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.registrations++
+		f.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"clientId":              "client-id",
+			"clientSecret":          "client-secret",
+			"clientIdIssuedAt":      time.Now().Unix(),
+			"clientSecretExpiresAt": time.Now().Add(90 * 24 * time.Hour).Unix(),
+		})
+	})
+	mux.HandleFunc("/device_authorization", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"deviceCode":              "device-code",
+			"userCode":                "ABCD-EFGH",
+			"verificationUri":         "https://device.sso.example.com/",
+			"verificationUriComplete": "https://device.sso.example.com/?user_code=ABCD-EFGH",
+			"expiresIn":               600,
+			"interval":                1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		f.mu.Lock()
+		f.tokenRequests = append(f.tokenRequests, body)
+		var errCode string
+		if len(f.tokenErrors) > 0 {
+			errCode = f.tokenErrors[0]
+			f.tokenErrors = f.tokenErrors[1:]
+		}
+		f.mu.Unlock()
+
+		if errCode != "" {
+			w.Header().Set("X-Amzn-ErrorType", errCode)
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": errCode})
+			return
+		}
+
+		resp := map[string]interface{}{
+			"accessToken": "access-token",
+			"expiresIn":   28800,
+			"tokenType":   "Bearer",
+		}
+		if body["grantType"] == "refresh_token" {
+			resp["accessToken"] = "refreshed-token"
+		}
+		resp["refreshToken"] = "refresh-token"
+		writeJSON(w, http.StatusOK, resp)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeOIDCServer) clientFactory() func(region string) sso.OIDCAPI {
+	return func(region string) sso.OIDCAPI {
+		return ssooidc.New(ssooidc.Options{
+			Region:       region,
+			BaseEndpoint: aws.String(f.URL),
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func setTestHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "")
+	return home
+}
+
+// writeAWSConfig writes content as ~/.aws/config under home.
+func writeAWSConfig(t *testing.T, home, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".aws"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".aws", "config"), []byte(content), 0600))
+}
+
+// readAWSConfig parses ~/.aws/config under home.
+func readAWSConfig(t *testing.T, home string) *awsconfig.File {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(home, ".aws", "config"))
+	require.NoError(t, err)
+	return awsconfig.Parse(data)
+}
+
+func cacheFileFor(home, key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(home, ".aws", "sso", "cache", hex.EncodeToString(sum[:])+".json")
+}
+
+func writeSessionConfig(t *testing.T, home string) {
+	t.Helper()
+	writeAWSConfig(t, home, `[sso-session test-session]
+sso_start_url = https://test.awsapps.com/start
+sso_region = us-west-2
+sso_registration_scopes = sso:account:access
+`)
+}
+
+func TestRunSSOLogin_DeviceAuthorization(t *testing.T) {
+	home := setTestHome(t)
+	writeSessionConfig(t, home)
+
+	server := newFakeOIDCServer(t, "AuthorizationPendingException", "SlowDownException")
+
+	var sleeps []time.Duration
+	var opened string
+	client := &sso.RealSSOClient{
+		NewOIDCClient: server.clientFactory(),
+		Sleep:         func(d time.Duration) { sleeps = append(sleeps, d) },
+		OpenBrowser: func(url string) error {
+			opened = url
+			return nil
+		},
+	}
+
+	require.NoError(t, client.RunSSOLogin("test-session"))
+
+	assert.Equal(t, "https://device.sso.example.com/?user_code=ABCD-EFGH", opened)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, 6 * time.Second}, sleeps)
+	assert.Len(t, server.tokenRequests, 3)
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", server.tokenRequests[0]["grantType"])
+
+	data, err := os.ReadFile(cacheFileFor(home, "test-session"))
+	require.NoError(t, err)
+
+	var cache models.SSOCache
+	require.NoError(t, json.Unmarshal(data, &cache))
+	assert.Equal(t, "access-token", *cache.AccessToken)
+	assert.Equal(t, "refresh-token", *cache.RefreshToken)
+	assert.Equal(t, "https://test.awsapps.com/start", *cache.StartURL)
+	assert.Equal(t, "us-west-2", *cache.Region)
+	assert.Equal(t, "test-session", *cache.SessionName)
+	assert.Equal(t, "client-id", *cache.ClientID)
+
+	expiresAt, err := time.Parse(time.RFC3339, *cache.ExpiresAt)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(8*time.Hour), expiresAt, time.Minute)
+}
+
+func TestRunSSOLogin_ReusesClientRegistration(t *testing.T) {
+	home := setTestHome(t)
+	writeSessionConfig(t, home)
+
+	server := newFakeOIDCServer(t)
+	client := &sso.RealSSOClient{
+		NewOIDCClient: server.clientFactory(),
+		Sleep:         func(time.Duration) {},
+		OpenBrowser:   func(string) error { return nil },
+	}
+
+	require.NoError(t, client.RunSSOLogin("test-session"))
+	require.NoError(t, client.RunSSOLogin("test-session"))
+	assert.Equal(t, 1, server.registrations)
+}
+
+func TestRunSSOLogin_DeviceAuthorizationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenErrors []string
+		errContains string
+	}{
+		{
+			name:        "authorization expired",
+			tokenErrors: []string{"ExpiredTokenException"},
+			errContains: "device authorization expired",
+		},
+		{
+			name:        "authorization denied",
+			tokenErrors: []string{"AuthorizationPendingException", "AccessDeniedException"},
+			errContains: "device authorization was denied",
+		},
+		{
+			name:        "unexpected error",
+			tokenErrors: []string{"InvalidClientException"},
+			errContains: "failed to create SSO token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setTestHome(t)
+			writeSessionConfig(t, home)
+
+			server := newFakeOIDCServer(t, tt.tokenErrors...)
+			client := &sso.RealSSOClient{
+				NewOIDCClient: server.clientFactory(),
+				Sleep:         func(time.Duration) {},
+				OpenBrowser:   func(string) error { return nil },
+			}
+
+			err := client.RunSSOLogin("test-session")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+
+			_, statErr := os.Stat(cacheFileFor(home, "test-session"))
+			assert.True(t, os.IsNotExist(statErr))
+		})
+	}
+}
+
+func TestSSOLogin_LegacyProfileUsesStartURLCacheKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	home := setTestHome(t)
+	server := newFakeOIDCServer(t)
+
+	writeAWSConfig(t, home, `[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = eu-west-1
+`)
+
+	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+
+	client := &sso.RealSSOClient{
+		Executor:      mockExecutor,
+		NewOIDCClient: server.clientFactory(),
+		Sleep:         func(time.Duration) {},
+	}
+
+	require.NoError(t, client.SSOLogin("legacy", false, true))
+
+	data, err := os.ReadFile(cacheFileFor(home, "https://legacy.awsapps.com/start"))
+	require.NoError(t, err)
+	var cache models.SSOCache
+	require.NoError(t, json.Unmarshal(data, &cache))
+	assert.Nil(t, cache.SessionName)
+	assert.Equal(t, "eu-west-1", *cache.Region)
+}
+
+func writeRefreshableCache(t *testing.T, home string, expiresIn, registrationExpiresIn time.Duration) string {
+	t.Helper()
+	path := cacheFileFor(home, "test-session")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	cache := models.SSOCache{
+		AccessToken:           aws.String("old-token"),
+		RefreshToken:          aws.String("refresh-token"),
+		ExpiresAt:             aws.String(time.Now().Add(expiresIn).UTC().Format(time.RFC3339)),
+		StartURL:              aws.String("https://test.awsapps.com/start"),
+		SessionName:           aws.String("test-session"),
+		Region:                aws.String("us-west-2"),
+		ClientID:              aws.String("client-id"),
+		ClientSecret:          aws.String("client-secret"),
+		RegistrationExpiresAt: aws.String(time.Now().Add(registrationExpiresIn).UTC().Format(time.RFC3339)),
+	}
+	data, err := json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestGetSsoAccessTokenFromCache_SilentRefresh(t *testing.T) {
+	tests := []struct {
+		name                  string
+		windowMinutes         int
+		expiresIn             time.Duration
+		registrationExpiresIn time.Duration
+		tokenErrors           []string
+		wantToken             string
+		wantRefreshRequests   int
+	}{
+		{
+			name:                  "refreshes token inside default window",
+			expiresIn:             5 * time.Minute,
+			registrationExpiresIn: 30 * 24 * time.Hour,
+			wantToken:             "refreshed-token",
+			wantRefreshRequests:   1,
+		},
+		{
+			name:                  "refreshes expired token",
+			expiresIn:             -time.Hour,
+			registrationExpiresIn: 30 * 24 * time.Hour,
+			wantToken:             "refreshed-token",
+			wantRefreshRequests:   1,
+		},
+		{
+			name:                  "keeps token outside default window",
+			expiresIn:             30 * time.Minute,
+			registrationExpiresIn: 30 * 24 * time.Hour,
+			wantToken:             "old-token",
+		},
+		{
+			name:                  "honors configured window",
+			windowMinutes:         60,
+			expiresIn:             30 * time.Minute,
+			registrationExpiresIn: 30 * 24 * time.Hour,
+			wantToken:             "refreshed-token",
+			wantRefreshRequests:   1,
+		},
+		{
+			name:                  "keeps valid token when registration expired",
+			expiresIn:             5 * time.Minute,
+			registrationExpiresIn: -time.Hour,
+			wantToken:             "old-token",
+		},
+		{
+			name:                  "keeps valid token when refresh fails",
+			expiresIn:             5 * time.Minute,
+			registrationExpiresIn: 30 * 24 * time.Hour,
+			tokenErrors:           []string{"InvalidGrantException"},
+			wantToken:             "old-token",
+			wantRefreshRequests:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			home := setTestHome(t)
+			path := writeRefreshableCache(t, home, tt.expiresIn, tt.registrationExpiresIn)
+			server := newFakeOIDCServer(t, tt.tokenErrors...)
+
+			writeAWSConfig(t, home, "[profile dev]\nsso_start_url = https://test.awsapps.com/start\n")
+
+			mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
+
+			client := &sso.RealSSOClient{
+				Executor:      mockExecutor,
+				NewOIDCClient: server.clientFactory(),
+			}
+			client.Config.RawCustomConfig = &models.Config{TokenRefreshWindowMinutes: tt.windowMinutes}
+
+			cache, expiry, err := client.GetSsoAccessTokenFromCache("dev")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantToken, *cache.AccessToken)
+			assert.Len(t, server.tokenRequests, tt.wantRefreshRequests)
+
+			if tt.wantToken == "refreshed-token" {
+				assert.Equal(t, "refresh_token", server.tokenRequests[0]["grantType"])
+				assert.Equal(t, "refresh-token", server.tokenRequests[0]["refreshToken"])
+				assert.WithinDuration(t, time.Now().Add(8*time.Hour), expiry, time.Minute)
+
+				stored, err := os.ReadFile(path)
+				require.NoError(t, err)
+				var onDisk models.SSOCache
+				require.NoError(t, json.Unmarshal(stored, &onDisk))
+				assert.Equal(t, "refreshed-token", *onDisk.AccessToken)
+				assert.Equal(t, "client-id", *onDisk.ClientID)
+			}
+		})
+	}
+}
+
+func TestGetCachedSsoAccessToken_LoginAfterFailedRefresh(t *testing.T) {
+	home := setTestHome(t)
+	writeAWSConfig(t, home, "[profile dev]\nsso_start_url = https://test.awsapps.com/start\nsso_region = us-west-2\n")
+	writeRefreshableCache(t, home, -time.Hour, 30*24*time.Hour)
+
+	server := newFakeOIDCServer(t, "InvalidGrantException")
+	client := &sso.RealSSOClient{
+		NewOIDCClient: server.clientFactory(),
+		Sleep:         func(time.Duration) {},
+		OpenBrowser:   func(string) error { return nil },
+	}
+
+	type result struct {
+		token string
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		token, _, err := client.GetCachedSsoAccessToken("dev")
+		done <- result{token, err}
+	}()
+
+	select {
+	case r := <-done:
+		require.NoError(t, r.err)
+		assert.Equal(t, "access-token", r.token)
+	case <-time.After(10 * time.Second):
+		t.Fatal("GetCachedSsoAccessToken did not return after logging in again")
+	}
+	require.Len(t, server.tokenRequests, 2)
+	assert.Equal(t, "refresh_token", server.tokenRequests[0]["grantType"])
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", server.tokenRequests[1]["grantType"])
+}
+
+func TestRunSSOLogin_ReregistersExpiringClient(t *testing.T) {
+	home := setTestHome(t)
+	writeSessionConfig(t, home)
+	writeRefreshableCache(t, home, -time.Hour, 24*time.Hour)
+
+	server := newFakeOIDCServer(t)
+	client := &sso.RealSSOClient{
+		NewOIDCClient: server.clientFactory(),
+		Sleep:         func(time.Duration) {},
+		OpenBrowser:   func(string) error { return nil },
+	}
+
+	require.NoError(t, client.RunSSOLogin("test-session"))
+	assert.Equal(t, 1, server.registrations)
+}
@@ -0,0 +1,147 @@
+package sso
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+)
+
+// maxConcurrentRoleListings bounds the ListAccountRoles calls in flight while
+// fetching roles for every account, to stay clear of portal API throttling.
+const maxConcurrentRoleListings = 8
+
+// NewSSOPortalClient returns an AWS SSO portal client for the given region.
+// Portal operations authenticate with the SSO access token, not credentials.
+func NewSSOPortalClient(region string) SSOPortalAPI {
+	return sso.New(sso.Options{Region: region})
+}
+
+func (c *RealSSOClient) portalClient(region string) SSOPortalAPI {
+	if c.NewPortalClient != nil {
+		return c.NewPortalClient(region)
+	}
+	return NewSSOPortalClient(region)
+}
+
+// fetchAccounts returns every account the token can access, following
+// pagination.
+func fetchAccounts(ctx context.Context, client SSOPortalAPI, accessToken, region string) ([]models.SSOAccount, error) {
+	var accounts []models.SSOAccount
+	paginator := sso.NewListAccountsPaginator(client, &sso.ListAccountsInput{
+		AccessToken: aws.String(accessToken),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, acc := range page.AccountList {
+			if aws.ToString(acc.AccountId) == "" {
+				continue
+			}
+			accounts = append(accounts, models.SSOAccount{
+				AccountID:   aws.ToString(acc.AccountId),
+				AccountName: aws.ToString(acc.AccountName),
+				Email:       aws.ToString(acc.EmailAddress),
+				SSORegion:   region,
+			})
+		}
+	}
+	return accounts, nil
+}
+
+// fetchAccountRoles returns every role name available in the account,
+// following pagination.
+func fetchAccountRoles(ctx context.Context, client SSOPortalAPI, accessToken, accountID string) ([]string, error) {
+	var roles []string
+	paginator := sso.NewListAccountRolesPaginator(client, &sso.ListAccountRolesInput{
+		AccessToken: aws.String(accessToken),
+		AccountId:   aws.String(accountID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range page.RoleList {
+			if name := aws.ToString(role.RoleName); name != "" {
+				roles = append(roles, name)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles, nil
+}
+
+// fillAccountRoles lists the roles of all accounts concurrently and stores
+// them on each account.
+func fillAccountRoles(ctx context.Context, client SSOPortalAPI, accessToken string, accounts []models.SSOAccount) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, maxConcurrentRoleListings)
+	)
+
+	for i := range accounts {
+		wg.Add(1)
+		go func(account *models.SSOAccount) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			roles, err := fetchAccountRoles(ctx, client, accessToken, account.AccountID)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to list roles for account %s: %w", account.AccountID, err)
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			account.Roles = roles
+		}(&accounts[i])
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// ListSSOAccounts returns all accounts available for the SSO start URL with
+// their roles, sorted by account name.
+func (c *RealSSOClient) ListSSOAccounts(region, startURL string) ([]models.SSOAccount, error) {
+	accessToken, err := c.GetAccessToken(startURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	ctx := context.Background()
+	client := c.portalClient(region)
+
+	accounts, err := fetchAccounts(ctx, client, accessToken, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts found")
+	}
+
+	if err := fillAccountRoles(ctx, client, accessToken, accounts); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return strings.ToLower(accounts[i].AccountName) < strings.ToLower(accounts[j].AccountName)
+	})
+	return accounts, nil
+}
@@ -0,0 +1,231 @@
+package sso_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const portalStartURL = "https://portal.awsapps.com/start"
+
+func writePortalToken(t *testing.T) {
+	t.Helper()
+	home := setTestHome(t)
+	cacheDir := filepath.Join(home, ".aws", "sso", "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0700))
+	data, err := json.Marshal(models.SSOCache{
+		StartURL:    aws.String(portalStartURL),
+		AccessToken: aws.String("portal-token"),
+		ExpiresAt:   aws.String(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "portal.json"), data, 0600))
+}
+
+func accountInfo(id, name string) ssotypes.AccountInfo {
+	return ssotypes.AccountInfo{
+		AccountId:    aws.String(id),
+		AccountName:  aws.String(name),
+		EmailAddress: aws.String(name + "@example.com"),
+	}
+}
+
+func TestListSSOAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writePortalToken(t)
+	mockPortal := mock_sso.NewMockSSOPortalAPI(ctrl)
+
+	gomock.InOrder(
+		mockPortal.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *awssso.ListAccountsInput, _ ...func(*awssso.Options)) (*awssso.ListAccountsOutput, error) {
+				assert.Equal(t, "portal-token", aws.ToString(in.AccessToken))
+				assert.Nil(t, in.NextToken)
+				return &awssso.ListAccountsOutput{
+					AccountList: []ssotypes.AccountInfo{accountInfo("222222222222", "staging"), {AccountId: aws.String("")}},
+					NextToken:   aws.String("accounts-2"),
+				}, nil
+			}),
+		mockPortal.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *awssso.ListAccountsInput, _ ...func(*awssso.Options)) (*awssso.ListAccountsOutput, error) {
+				assert.Equal(t, "accounts-2", aws.ToString(in.NextToken))
+				return &awssso.ListAccountsOutput{
+					AccountList: []ssotypes.AccountInfo{accountInfo("111111111111", "Production")},
+				}, nil
+			}),
+	)
+
+	mockPortal.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *awssso.ListAccountRolesInput, _ ...func(*awssso.Options)) (*awssso.ListAccountRolesOutput, error) {
+			switch {
+			case aws.ToString(in.AccountId) == "111111111111":
+				return &awssso.ListAccountRolesOutput{
+					RoleList: []ssotypes.RoleInfo{{RoleName: aws.String("ReadOnly")}, {RoleName: aws.String("Admin")}},
+				}, nil
+			case in.NextToken == nil:
+				return &awssso.ListAccountRolesOutput{
+					RoleList:  []ssotypes.RoleInfo{{RoleName: aws.String("Developer")}},
+					NextToken: aws.String("roles-2"),
+				}, nil
+			default:
+				return &awssso.ListAccountRolesOutput{
+					RoleList: []ssotypes.RoleInfo{{RoleName: aws.String("Billing")}},
+				}, nil
+			}
+		}).Times(3)
+
+	var region string
+	client := &sso.RealSSOClient{
+		NewPortalClient: func(r string) sso.SSOPortalAPI {
+			region = r
+			return mockPortal
+		},
+	}
+
+	accounts, err := client.ListSSOAccounts("eu-central-1", portalStartURL)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central-1", region)
+	assert.Equal(t, []models.SSOAccount{
+		{
+			AccountID:   "111111111111",
+			AccountName: "Production",
+			SSORegion:   "eu-central-1",
+			Email:       "Production@example.com",
+			Roles:       []string{"Admin", "ReadOnly"},
+		},
+		{
+			AccountID:   "222222222222",
+			AccountName: "staging",
+			SSORegion:   "eu-central-1",
+			Email:       "staging@example.com",
+			Roles:       []string{"Billing", "Developer"},
+		},
+	}, accounts)
+}
+
+func TestListSSOAccounts_ManyAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writePortalToken(t)
+	mockPortal := mock_sso.NewMockSSOPortalAPI(ctrl)
+
+	const total = 320
+	const pageSize = 20
+	mockPortal.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, in *awssso.ListAccountsInput, _ ...func(*awssso.Options)) (*awssso.ListAccountsOutput, error) {
+			start := 0
+			if in.NextToken != nil {
+				_, _ = fmt.Sscanf(*in.NextToken, "%d", &start)
+			}
+			out := &awssso.ListAccountsOutput{}
+			for i := start; i < start+pageSize && i < total; i++ {
+				out.AccountList = append(out.AccountList, accountInfo(fmt.Sprintf("%012d", i), fmt.Sprintf("account-%03d", i)))
+			}
+			if start+pageSize < total {
+				out.NextToken = aws.String(fmt.Sprintf("%d", start+pageSize))
+			}
+			return out, nil
+		}).Times(total / pageSize)
+
+	var inFlight, maxInFlight int32
+	mockPortal.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *awssso.ListAccountRolesInput, _ ...func(*awssso.Options)) (*awssso.ListAccountRolesOutput, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return &awssso.ListAccountRolesOutput{RoleList: []ssotypes.RoleInfo{{RoleName: aws.String("Admin")}}}, nil
+		}).Times(total)
+
+	client := &sso.RealSSOClient{
+		NewPortalClient: func(string) sso.SSOPortalAPI { return mockPortal },
+	}
+
+	accounts, err := client.ListSSOAccounts("us-east-1", portalStartURL)
+	require.NoError(t, err)
+	require.Len(t, accounts, total)
+	for _, account := range accounts {
+		assert.Equal(t, []string{"Admin"}, account.Roles)
+	}
+	assert.Greater(t, maxInFlight, int32(1))
+	assert.LessOrEqual(t, maxInFlight, int32(8))
+}
+
+func TestListSSOAccounts_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(m *mock_sso.MockSSOPortalAPI)
+		errContains string
+	}{
+		{
+			name: "list accounts fails",
+			setup: func(m *mock_sso.MockSSOPortalAPI) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("unauthorized"))
+			},
+			errContains: "failed to list accounts",
+		},
+		{
+			name: "no accounts",
+			setup: func(m *mock_sso.MockSSOPortalAPI) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&awssso.ListAccountsOutput{}, nil)
+			},
+			errContains: "no accounts found",
+		},
+		{
+			name: "list roles fails",
+			setup: func(m *mock_sso.MockSSOPortalAPI) {
+				m.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&awssso.ListAccountsOutput{
+						AccountList: []ssotypes.AccountInfo{accountInfo("111111111111", "prod")},
+					}, nil)
+				m.EXPECT().ListAccountRoles(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("throttled"))
+			},
+			errContains: "failed to list roles for account 111111111111",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			writePortalToken(t)
+			mockPortal := mock_sso.NewMockSSOPortalAPI(ctrl)
+			tt.setup(mockPortal)
+
+			client := &sso.RealSSOClient{
+				NewPortalClient: func(string) sso.SSOPortalAPI { return mockPortal },
+			}
+
+			_, err := client.ListSSOAccounts("us-east-1", portalStartURL)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
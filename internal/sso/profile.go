@@ -2,14 +2,19 @@ package sso
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
 )
 
-func (c *RealSSOClient) ConfigureSSOProfile(profile, region, accountID, role, ssoStartUrl, ssoSession string) error {
-	configs := map[string]string{
+// ssoProfileKeys lists the ~/.aws/config keys of an SSO profile in the order
+// they are written.
+var ssoProfileKeys = []string{"sso_session", "sso_region", "sso_account_id", "sso_start_url", "sso_role_name", "region", "output"}
+
+// ssoProfileSettings returns the ~/.aws/config keys written for an SSO profile.
+func ssoProfileSettings(region, accountID, role, ssoStartUrl, ssoSession string) map[string]string {
+	return map[string]string{
 		"sso_session":    ssoSession,
 		"sso_region":     region,
 		"sso_account_id": accountID,
@@ -18,14 +23,17 @@ func (c *RealSSOClient) ConfigureSSOProfile(profile, region, accountID, role, ss
 		"region":         region,
 		"output":         "json",
 	}
+}
+
+func (c *RealSSOClient) ConfigureSSOProfile(profile, region, accountID, role, ssoStartUrl, ssoSession string) error {
+	configs := ssoProfileSettings(region, accountID, role, ssoStartUrl, ssoSession)
 
-	for key, value := range configs {
-		if err := c.ConfigureSet(key, value, profile); err != nil {
-			fmt.Printf("Error setting %s: %v\n", key, err)
-			return err
+	return c.updateConfig(func(f *awsconfig.File) error {
+		for _, key := range ssoProfileKeys {
+			f.Set(awsconfig.ProfileSection(profile), key, configs[key])
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 func (c *RealSSOClient) ConfigureAWSProfile(profileName, sessionName, ssoRegion, ssoStartURL, accountID, roleName, region string) error {
@@ -45,109 +53,40 @@ func (c *RealSSOClient) ConfigureAWSProfile(profileName, sessionName, ssoRegion,
 		return nil
 	}
 
-	homeDir, err := os.UserHomeDir()
+	replaced, err := c.writeDefaultProfile(sessionName, ssoRegion, ssoStartURL, accountID, roleName, region)
 	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
-	}
-	configFile := filepath.Join(homeDir, ".aws", "config")
-	configDir := filepath.Dir(configFile)
-
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", configDir, err)
-	}
-
-	var configContent strings.Builder
-	if _, err := os.Stat(configFile); err == nil {
-		data, err := os.ReadFile(configFile)
-		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", configFile, err)
-		}
-		configContent.Write(data)
-		if len(data) > 0 && data[len(data)-1] != '\n' {
-			configContent.WriteString("\n")
-		}
-	}
-
-	lines := strings.Split(configContent.String(), "\n")
-	var newLines []string
-	inDefault := false
-	for _, line := range lines {
-		if strings.HasPrefix(line, "[default]") {
-			inDefault = true
-			fmt.Println("Existing default profile found, overwriting...")
-			continue
-		}
-		if inDefault && (strings.HasPrefix(line, "[") || line == "") {
-			inDefault = false
-		}
-		if !inDefault {
-			newLines = append(newLines, line)
-		}
-	}
-	configContent.Reset()
-	configContent.WriteString(strings.Join(newLines, "\n"))
-	configContent.WriteString("\n")
-
-	configContent.WriteString("[default]\n")
-	configContent.WriteString(fmt.Sprintf("sso_session = %s\n", sessionName))
-	configContent.WriteString(fmt.Sprintf("sso_account_id = %s\n", accountID))
-	configContent.WriteString(fmt.Sprintf("sso_role_name = %s\n", roleName))
-	configContent.WriteString(fmt.Sprintf("region = %s\n", region))
-	configContent.WriteString(fmt.Sprintf("sso_region = %s\n", ssoRegion))
-	configContent.WriteString(fmt.Sprintf("sso_start_url = %s\n", ssoStartURL))
-	configContent.WriteString("output = json\n")
-
-	tempFile := configFile + ".tmp"
-	if err := writeConfigFile(tempFile, configContent.String()); err != nil {
-		return fmt.Errorf("failed to write temporary config: %w", err)
+		return fmt.Errorf("failed to configure AWS default profile: %w", err)
 	}
-
-	if err := os.Rename(tempFile, configFile); err != nil {
-		_ = os.Remove(tempFile)
-		return fmt.Errorf("failed to update config file: %w", err)
+	if replaced {
+		fmt.Println("Existing default profile found, overwriting...")
 	}
 
 	fmt.Println("Configured AWS default profile")
+	return nil
+}
 
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return fmt.Errorf("failed to verify %s: %w", configFile, err)
-	}
-	configText := string(data)
-	lines = strings.Split(configText, "\n")
-	inDefaultSection := false
-	requiredFields := map[string]string{
-		"sso_session":    sessionName,
-		"sso_account_id": accountID,
-		"sso_role_name":  roleName,
-	}
-	foundFields := make(map[string]bool)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "[default]" {
-			inDefaultSection = true
-			continue
-		}
-		if inDefaultSection && strings.HasPrefix(line, "[") {
-			break
-		}
-		if inDefaultSection {
-			for key, value := range requiredFields {
-				if line == fmt.Sprintf("%s = %s", key, value) {
-					foundFields[key] = true
-				}
+// writeDefaultProfile replaces the settings of the default profile and reports
+// whether it existed. The section is replaced as a whole, so settings of the
+// previously selected profile do not leak into the new one. Comments in the
+// section are kept.
+func (c *RealSSOClient) writeDefaultProfile(sessionName, ssoRegion, ssoStartURL, accountID, roleName, region string) (bool, error) {
+	settings := ssoProfileSettings(ssoRegion, accountID, roleName, ssoStartURL, sessionName)
+	settings["region"] = region
+
+	replaced := false
+	err := c.updateConfig(func(f *awsconfig.File) error {
+		if existing := f.Values("default"); existing != nil {
+			replaced = true
+			for key := range existing {
+				f.Delete("default", key)
 			}
 		}
-	}
-
-	for key := range requiredFields {
-		if !foundFields[key] {
-			return fmt.Errorf("failed to configure default profile: missing %s", key)
+		for _, key := range ssoProfileKeys {
+			f.Set("default", key, settings[key])
 		}
-	}
-
-	return nil
+		return nil
+	})
+	return replaced, err
 }
 
 func (c *RealSSOClient) PromptProfileDetails(ssoRegion string) (string, string, error) {
@@ -162,7 +101,10 @@ func (c *RealSSOClient) PromptProfileDetails(ssoRegion string) (string, string,
 	return profileName, region, nil
 }
 
-func (c *RealSSOClient) setProfileAsDefault(profile string) error {
+// SetDefaultProfile copies the SSO settings of profile into the default
+// profile, which every shell and tool without AWS_PROFILE uses. It prints
+// nothing, so it can run while a shell snippet is written to stdout.
+func (c *RealSSOClient) SetDefaultProfile(profile string) error {
 	sessionName, err := c.ConfigureGet("sso_session", profile)
 	if err != nil {
 		return fmt.Errorf("failed to get sso_session: %w", err)
@@ -193,10 +135,17 @@ func (c *RealSSOClient) setProfileAsDefault(profile string) error {
 		region = ssoRegion
 	}
 
-	if err := c.ConfigureAWSProfile("default", sessionName, ssoRegion, ssoStartURL, accountID, roleName, region); err != nil {
+	ssoStartURL = strings.TrimSuffix(ssoStartURL, "#")
+	if err := ValidateStartURL(ssoStartURL); err != nil {
+		return fmt.Errorf("invalid start URL: %w", err)
+	}
+	if err := ValidateAccountID(accountID); err != nil {
+		return fmt.Errorf("invalid account ID: %w", err)
+	}
+
+	if _, err := c.writeDefaultProfile(sessionName, ssoRegion, ssoStartURL, accountID, roleName, region); err != nil {
 		return fmt.Errorf("failed to configure AWS default profile: %w", err)
 	}
-	fmt.Println("Successfully set this profile as default!")
 	return nil
 }
 
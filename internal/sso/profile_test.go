@@ -6,10 +6,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
 	"github.com/BerryBytes/awsctl/internal/sso"
 	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
 	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,40 +24,49 @@ func TestConfigureSSOProfile(t *testing.T) {
 	mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
 
 	t.Run("successful configuration", func(t *testing.T) {
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_session", "test-session", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_region", "us-west-2", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_account_id", "123456789012", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_start_url", "https://example.awsapps.com/start", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_role_name", "Admin", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "region", "us-west-2", "--profile", "test-profile").Return(nil, nil)
-		mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "output", "json", "--profile", "test-profile").Return(nil, nil)
+		home := setTestHome(t)
+		fs := afero.NewMemMapFs()
+		configPath := filepath.Join(home, ".aws", "config")
+		require.NoError(t, afero.WriteFile(fs, configPath, []byte("# managed by hand\n[profile test-profile]\nregion = eu-west-1 ; old\n"), 0600))
 
 		client := &sso.RealSSOClient{
 			Prompter: mockPrompter,
 			Executor: mockExecutor,
+			Fs:       fs,
 		}
 
 		err := client.ConfigureSSOProfile("test-profile", "us-west-2", "123456789012", "Admin", "https://example.awsapps.com/start", "test-session")
-		assert.NoError(t, err)
-	})
+		require.NoError(t, err)
 
-	t.Run("error setting first config value", func(t *testing.T) {
-		mockExecutor.EXPECT().
-			RunCommand("aws", "configure", "set", "sso_session", "test-session", "--profile", "test-profile").
-			Return(nil, errors.New("config error"))
+		data, err := afero.ReadFile(fs, configPath)
+		require.NoError(t, err)
+		assert.Equal(t, `# managed by hand
+[profile test-profile]
+region = us-west-2
+sso_session = test-session
+sso_region = us-west-2
+sso_account_id = 123456789012
+sso_start_url = https://example.awsapps.com/start
+sso_role_name = Admin
+output = json
+`, string(data))
+	})
 
-		mockExecutor.EXPECT().RunCommand(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	t.Run("error writing config", func(t *testing.T) {
+		setTestHome(t)
 
 		client := &sso.RealSSOClient{
 			Prompter: mockPrompter,
 			Executor: mockExecutor,
+			Fs:       afero.NewReadOnlyFs(afero.NewMemMapFs()),
 		}
 
 		err := client.ConfigureSSOProfile("test-profile", "us-west-2", "123456789012", "Admin", "https://example.awsapps.com/start", "test-session")
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "config error")
+		assert.Contains(t, err.Error(), "failed to create directory")
 	})
 }
+
 func TestConfigureAWSProfile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -78,23 +89,15 @@ func TestConfigureAWSProfile(t *testing.T) {
 		expectedOutput string
 	}{
 		{
-			name:        "successful non-default profile configuration",
-			profileName: "test-profile",
-			sessionName: "test-session",
-			ssoRegion:   "us-west-2",
-			ssoStartURL: "https://example.awsapps.com/start",
-			accountID:   "123456789012",
-			roleName:    "Admin",
-			region:      "us-west-2",
-			setup: func() {
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_session", "test-session", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_region", "us-west-2", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_account_id", "123456789012", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_start_url", "https://example.awsapps.com/start", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "sso_role_name", "Admin", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "region", "us-west-2", "--profile", "test-profile").Return(nil, nil)
-				mockExecutor.EXPECT().RunCommand("aws", "configure", "set", "output", "json", "--profile", "test-profile").Return(nil, nil)
-			},
+			name:           "successful non-default profile configuration",
+			profileName:    "test-profile",
+			sessionName:    "test-session",
+			ssoRegion:      "us-west-2",
+			ssoStartURL:    "https://example.awsapps.com/start",
+			accountID:      "123456789012",
+			roleName:       "Admin",
+			region:         "us-west-2",
+			setup:          func() {},
 			expectError:    false,
 			expectedOutput: "Configured AWS profile 'test-profile'",
 		},
@@ -180,6 +183,7 @@ func TestConfigureAWSProfile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			setTestHome(t)
 			tt.setup()
 			client := &sso.RealSSOClient{
 				Prompter: mockPrompter,
@@ -196,10 +200,52 @@ func TestConfigureAWSProfile(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 
+				home, err := os.UserHomeDir()
+				require.NoError(t, err)
+				values := readAWSConfig(t, home).Values(awsconfig.ProfileSection(tt.profileName))
+				assert.Equal(t, tt.accountID, values["sso_account_id"])
+				assert.Equal(t, tt.roleName, values["sso_role_name"])
+				assert.Equal(t, tt.region, values["region"])
 			}
 		})
 	}
 }
+
+func TestConfigureAWSProfile_ReplacesDefault(t *testing.T) {
+	home := setTestHome(t)
+	writeAWSConfig(t, home, `# shared settings
+[default]
+region = eu-west-1
+credential_process = old-helper
+
+[profile keep]
+# not touched
+region = ap-south-1
+`)
+
+	client := &sso.RealSSOClient{}
+	output := captureStdout(t, func() {
+		require.NoError(t, client.ConfigureAWSProfile("default", "test-session", "us-west-2", "https://example.awsapps.com/start", "123456789012", "Admin", "us-east-1"))
+	})
+	assert.Contains(t, output, "Existing default profile found, overwriting")
+
+	data, err := os.ReadFile(filepath.Join(home, ".aws", "config"))
+	require.NoError(t, err)
+	assert.Equal(t, `# shared settings
+[default]
+sso_session = test-session
+sso_region = us-west-2
+sso_account_id = 123456789012
+sso_start_url = https://example.awsapps.com/start
+sso_role_name = Admin
+region = us-east-1
+output = json
+
+[profile keep]
+# not touched
+region = ap-south-1
+`, string(data))
+}
 func TestPromptProfileDetails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,113 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+)
+
+// ErrProfileConflict is returned when a generated profile name is already
+// used by a profile for another account or role.
+var ErrProfileConflict = errors.New("profile name already in use")
+
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@+-]*$`)
+
+// ProfileNameData is what profileNameTemplate is executed with.
+type ProfileNameData struct {
+	Session     string
+	AccountID   string
+	AccountName string
+	// Alias is the configured account alias, or a slug of the account name.
+	Alias string
+	Role  string
+	// RoleShort is the abbreviated role, e.g. "ro" for ReadOnlyAccess.
+	RoleShort string
+	Region    string
+	Tags      map[string]string
+}
+
+// profileName returns the name of the profile for an account/role pair of
+// session, built from profileNameTemplate when one is configured.
+func (c *RealSSOClient) profileName(session *models.SSOSession, account models.SSOAccount, role string) (string, error) {
+	var settings models.AccountSettings
+	var text string
+	if cfg := c.Config.RawCustomConfig; cfg != nil {
+		settings = cfg.Accounts[account.AccountID]
+		text = cfg.ProfileNameTemplate
+	}
+
+	alias := settings.Alias
+	if alias == "" {
+		alias = accountSlug(account.AccountName)
+	}
+
+	if text == "" {
+		return slugify(session.Name) + "-" + alias + "-" + roleShort(role), nil
+	}
+
+	tmpl, err := config.ParseProfileNameTemplate(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid profileNameTemplate: %w", err)
+	}
+	tags := settings.Tags
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, ProfileNameData{
+		Session:     session.Name,
+		AccountID:   account.AccountID,
+		AccountName: account.AccountName,
+		Alias:       alias,
+		Role:        role,
+		RoleShort:   roleShort(role),
+		Region:      session.Region,
+		Tags:        tags,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build profile name for account %s role %s: %w", account.AccountID, role, err)
+	}
+
+	name := strings.TrimSpace(b.String())
+	if !validProfileName.MatchString(name) {
+		return "", fmt.Errorf("profileNameTemplate produced invalid profile name %q for account %s role %s", name, account.AccountID, role)
+	}
+	return name, nil
+}
+
+// samePermissionSet reports whether an existing profile section points to
+// accountID and role, so rewriting it cannot lose an unrelated profile.
+func samePermissionSet(values map[string]string, accountID, role string) bool {
+	return values["sso_account_id"] == accountID && values["sso_role_name"] == role
+}
+
+// conflictError returns an ErrProfileConflict error describing the
+// existing profile that a profile for accountID and role would overwrite.
+func conflictError(profile string, values map[string]string, accountID, role string) error {
+	target := "a profile that is not for AWS SSO"
+	if values["sso_account_id"] != "" {
+		target = fmt.Sprintf("account %s role %s", values["sso_account_id"], values["sso_role_name"])
+	}
+	return fmt.Errorf("%w: profile %s is for %s, not account %s role %s; set an alias under accounts or change profileNameTemplate in the awsctl config",
+		ErrProfileConflict, profile, target, accountID, role)
+}
+
+// checkProfileConflict refuses to overwrite an existing profile for another
+// account or role.
+func (c *RealSSOClient) checkProfileConflict(profile, accountID, role string) error {
+	f, err := c.loadConfig()
+	if err != nil {
+		return err
+	}
+	values := f.Values(awsconfig.ProfileSection(profile))
+	if values == nil || samePermissionSet(values, accountID, role) {
+		return nil
+	}
+	return conflictError(profile, values, accountID, role)
+}
@@ -0,0 +1,83 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+)
+
+// ErrProfileNotFound is returned by PromptInfo for profiles that are not in
+// ~/.aws/config.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// PromptInfo describes a profile for a shell prompt. Like ProfileStatuses it
+// only reads ~/.aws/config and the token and credential caches, so it never
+// prompts or calls AWS. Assume-role profiles report the SSO token of the
+// profile at the root of their chain, and MFA session profiles the expiry of
+// the cached session.
+func (c *RealSSOClient) PromptInfo(profile string) (*models.PromptInfo, error) {
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := sections[awsconfig.ProfileSection(profile)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not in ~/.aws/config", ErrProfileNotFound, profile)
+	}
+	info := &models.PromptInfo{
+		Profile:   profile,
+		AccountID: values["sso_account_id"],
+		Role:      values["sso_role_name"],
+		Region:    values["region"],
+		Status:    StatusNotSSO,
+	}
+	if arn := values["role_arn"]; arn != "" {
+		info.AccountID, info.Role = roleARNParts(arn)
+	}
+
+	if source := values[mfaSourceKey]; source != "" {
+		info.Status = StatusNoToken
+		mfaSerial := sections[awsconfig.ProfileSection(source)]["mfa_serial"]
+		if cachePath, err := mfaSessionCachePath(source, mfaSerial); err == nil && mfaSerial != "" {
+			if creds, err := readRoleCredentialCache(cachePath); err == nil {
+				setPromptExpiry(info, creds.Expiration)
+			}
+		}
+	} else if root, err := sourceSSOProfile(profile, sections); err == nil {
+		status := profileStatus(root, sections)
+		info.SSOSession = status.SSOSession
+		info.Status = status.Status
+		if info.AccountID == "" {
+			info.AccountID = status.AccountID
+			info.Role = status.Role
+		}
+		if info.Region == "" {
+			info.Region = status.Region
+		}
+		setPromptExpiry(info, status.ExpiresAt)
+	}
+
+	if c.Config.RawCustomConfig != nil && info.AccountID != "" {
+		info.Alias = c.Config.RawCustomConfig.Accounts[info.AccountID].Alias
+	}
+	return info, nil
+}
+
+func setPromptExpiry(info *models.PromptInfo, expiration string) {
+	expiresAt, err := time.Parse(time.RFC3339, expiration)
+	if err != nil {
+		return
+	}
+	info.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	if remaining := time.Until(expiresAt); remaining > 0 {
+		info.Status = StatusValid
+		info.Valid = true
+		info.MinutesLeft = int(remaining / time.Minute)
+	} else {
+		info.Status = StatusExpired
+	}
+}
@@ -0,0 +1,79 @@
+package sso_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const promptInfoConfig = roleChainConfig + `
+[profile customer]
+mfa_serial = arn:aws:iam::123456789012:mfa/alice
+
+[profile customer-mfa]
+mfa_source_profile = customer
+region = eu-west-2
+`
+
+func TestPromptInfo(t *testing.T) {
+	f := writeLogoutFixtures(t)
+	writeAWSConfig(t, f.home, promptInfoConfig)
+	client := &sso.RealSSOClient{Config: config.Config{RawCustomConfig: &models.Config{
+		Accounts: map[string]models.AccountSettings{"111111111111": {Alias: "team"}},
+	}}}
+
+	info, err := client.PromptInfo("team-admin")
+	require.NoError(t, err)
+	assert.Equal(t, "team", info.Alias)
+	assert.Equal(t, "111111111111", info.AccountID)
+	assert.Equal(t, "Admin", info.Role)
+	assert.Equal(t, "team", info.SSOSession)
+	assert.Equal(t, sso.StatusValid, info.Status)
+	assert.True(t, info.Valid)
+	assert.InDelta(t, 59, info.MinutesLeft, 1)
+
+	info, err = client.PromptInfo("prod-readonly")
+	require.NoError(t, err)
+	assert.Equal(t, "444444444444", info.AccountID)
+	assert.Equal(t, "ReadOnly", info.Role)
+	assert.Equal(t, "", info.Alias)
+	assert.Equal(t, "eu-central-1", info.Region)
+	assert.True(t, info.Valid, "assume-role profiles report the token of the SSO profile at the root of the chain")
+
+	info, err = client.PromptInfo("static")
+	require.NoError(t, err)
+	assert.Equal(t, &models.PromptInfo{Profile: "static", Region: "us-east-1", Status: sso.StatusNotSSO}, info)
+
+	info, err = client.PromptInfo("customer-mfa")
+	require.NoError(t, err)
+	assert.Equal(t, sso.StatusNoToken, info.Status)
+
+	expiration := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+	cachePath := filepath.Join(f.home, ".aws", "cli", "cache", sha1Hex(`{"profile":"customer","serialNumber":"arn:aws:iam::123456789012:mfa/alice"}`)+".json")
+	var cache models.RoleCredentialCache
+	cache.ProviderType = "mfa-session"
+	cache.Credentials.AccessKeyID = "ASIA"
+	cache.Credentials.SecretAccessKey = "secret"
+	cache.Credentials.Expiration = expiration.Format(time.RFC3339)
+	data, err := json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cachePath, data, 0600))
+
+	info, err = client.PromptInfo("customer-mfa")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-2", info.Region)
+	assert.Equal(t, expiration.Format(time.RFC3339), info.ExpiresAt)
+	assert.InDelta(t, 29, info.MinutesLeft, 1)
+	assert.True(t, info.Valid)
+
+	_, err = client.PromptInfo("missing")
+	assert.ErrorIs(t, err, sso.ErrProfileNotFound)
+}
@@ -1,7 +1,6 @@
 package sso
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
 	"github.com/BerryBytes/awsctl/internal/sso/config"
 	"github.com/BerryBytes/awsctl/models"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
@@ -122,101 +122,34 @@ func (c *RealSSOClient) ConfigureSSOSession(sessionName, startURL, region, scope
 	fmt.Println("\nConfiguring AWS SSO session in ~/.aws/config...")
 	startURL = strings.TrimSuffix(startURL, "#")
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
+	section := fmt.Sprintf("sso-session %s", sessionName)
+	settings := []struct{ key, value string }{
+		{"sso_start_url", startURL},
+		{"sso_region", region},
+		{"sso_registration_scopes", scopes},
 	}
-	configFile := filepath.Join(homeDir, ".aws", "config")
-	configDir := filepath.Dir(configFile)
 
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", configDir, err)
-	}
-
-	existingSession := make(map[string]string)
-	fileExists := false
-	if data, err := os.ReadFile(configFile); err == nil {
-		fileExists = true
-		lines := strings.Split(string(data), "\n")
-		inSession := false
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == fmt.Sprintf("[sso-session %s]", sessionName) {
-				inSession = true
-				continue
+	changed := false
+	err := c.updateConfig(func(f *awsconfig.File) error {
+		for _, setting := range settings {
+			if current, ok := f.Get(section, setting.key); !ok || current != setting.value {
+				f.Set(section, setting.key, setting.value)
+				changed = true
 			}
-			if inSession && (strings.HasPrefix(line, "[") || line == "") {
-				inSession = false
-				continue
-			}
-			if inSession && strings.Contains(line, "=") {
-				parts := strings.SplitN(line, "=", 2)
-				if len(parts) == 2 {
-					key := strings.TrimSpace(parts[0])
-					value := strings.TrimSpace(parts[1])
-					existingSession[key] = value
-				}
-			}
-		}
-	}
-
-	newConfig := map[string]string{
-		"sso_start_url":           startURL,
-		"sso_region":              region,
-		"sso_registration_scopes": scopes,
-	}
-	matches := true
-	for key, newValue := range newConfig {
-		if existingValue, exists := existingSession[key]; !exists || existingValue != newValue {
-			matches = false
-			break
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write ~/.aws/config: %w", err)
 	}
 
-	if len(existingSession) > 0 && matches {
+	if !changed {
 		fmt.Printf("sso-session %s already configured with identical values, skipping write\n", sessionName)
 		return nil
 	}
-
-	var newContent strings.Builder
-
-	if fileExists {
-		data, err := os.ReadFile(configFile)
-		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", configFile, err)
-		}
-
-		lines := strings.Split(string(data), "\n")
-		inSession := false
-		for _, line := range lines {
-			if strings.HasPrefix(line, fmt.Sprintf("[sso-session %s]", sessionName)) {
-				inSession = true
-				continue
-			}
-			if inSession && (strings.HasPrefix(line, "[") || line == "") {
-				inSession = false
-			}
-			if !inSession {
-				newContent.WriteString(line)
-				newContent.WriteString("\n")
-			}
-		}
-	}
-
-	newContent.WriteString(fmt.Sprintf("[sso-session %s]\n", sessionName))
-	newContent.WriteString(fmt.Sprintf("sso_start_url = %s\n", startURL))
-	newContent.WriteString(fmt.Sprintf("sso_region = %s\n", region))
-	newContent.WriteString(fmt.Sprintf("sso_registration_scopes = %s\n", scopes))
-	newContent.WriteString("\n")
-
-	if err := writeConfigFile(configFile, newContent.String()); err != nil {
-		return fmt.Errorf("failed to write %s: %w", configFile, err)
+	for _, setting := range settings {
+		fmt.Printf("Set sso-session.%s.%s = %s\n", sessionName, setting.key, setting.value)
 	}
-
-	fmt.Printf("Set sso-session.%s.sso_start_url = %s\n", sessionName, startURL)
-	fmt.Printf("Set sso-session.%s.sso_region = %s\n", sessionName, region)
-	fmt.Printf("Set sso-session.%s.sso_registration_scopes = %s\n", sessionName, scopes)
-
 	return nil
 }
 
@@ -225,13 +158,22 @@ func (c *RealSSOClient) RunSSOLogin(sessionName string) error {
 		return fmt.Errorf("invalid SSO configuration: %w", err)
 	}
 
-	fmt.Println("\nInitiating AWS SSO login... (this may open a browser window)")
+	section, err := c.readConfigSection(fmt.Sprintf("sso-session %s", sessionName))
+	if err != nil {
+		return fmt.Errorf("invalid SSO configuration: %w", err)
+	}
+	scopes := parseScopes(section["sso_registration_scopes"])
+	if len(scopes) == 0 {
+		scopes = []string{"sso:account:access"}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	fmt.Println("\nInitiating AWS SSO login... (this may open a browser window)")
 
-	if err := c.Executor.RunInteractiveCommand(ctx, "aws", "sso", "login", "--sso-session", sessionName); err != nil {
-		return fmt.Errorf("error during SSO login: %w; ensure AWS CLI is updated (run 'aws --version'), verify the SSO Start URL, region, and network connectivity", err)
+	if err := c.runDeviceLogin(sessionName, strings.TrimSuffix(section["sso_start_url"], "#"), section["sso_region"], scopes, false); err != nil {
+		if errors.Is(err, promptUtils.ErrInterrupted) {
+			return err
+		}
+		return fmt.Errorf("error during SSO login: %w; verify the SSO Start URL, region, and network connectivity", err)
 	}
 
 	fmt.Println("AWS SSO login successful")
@@ -239,17 +181,12 @@ func (c *RealSSOClient) RunSSOLogin(sessionName string) error {
 }
 
 func (c *RealSSOClient) validateAWSConfig(sessionName string) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get user home directory: %w", err)
-	}
-	configFile := filepath.Join(homeDir, ".aws", "config")
-	data, err := os.ReadFile(configFile)
+	f, err := c.loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to read %s: %w", configFile, err)
+		return err
 	}
-	if !strings.Contains(string(data), fmt.Sprintf("[sso-session %s]", sessionName)) {
-		return fmt.Errorf("sso-session %s not found in %s; check the configuration", sessionName, configFile)
+	if !f.HasSection(fmt.Sprintf("sso-session %s", sessionName)) {
+		return fmt.Errorf("sso-session %s not found in ~/.aws/config; check the configuration", sessionName)
 	}
 	return nil
 }
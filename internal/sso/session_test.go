@@ -11,7 +11,6 @@ import (
 	"github.com/BerryBytes/awsctl/internal/sso"
 	"github.com/BerryBytes/awsctl/internal/sso/config"
 	"github.com/BerryBytes/awsctl/models"
-	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
 	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -271,76 +270,28 @@ func TestSelectSSOSession(t *testing.T) {
 func TestRunSSOLogin(t *testing.T) {
 	tests := []struct {
 		name        string
-		setup       func(t *testing.T) string
+		writeConfig bool
 		sessionName string
-		mockExec    func(*mock_awsctl.MockCommandExecutor)
+		tokenErrors []string
 		wantErr     bool
 		errContains string
 	}{
 		{
-			name: "Successful login",
-			setup: func(t *testing.T) string {
-				dir, err := os.MkdirTemp("", "awsctl-test")
-				require.NoError(t, err)
-
-				awsDir := filepath.Join(dir, ".aws")
-				err = os.Mkdir(awsDir, 0700)
-				require.NoError(t, err)
-
-				configPath := filepath.Join(awsDir, "config")
-				content := `[sso-session test-session]
-sso_start_url = https://test.awsapps.com/start
-sso_region = us-west-2
-`
-				err = os.WriteFile(configPath, []byte(content), 0600)
-				require.NoError(t, err)
-
-				return dir
-			},
+			name:        "Successful login",
+			writeConfig: true,
 			sessionName: "test-session",
-			mockExec: func(m *mock_awsctl.MockCommandExecutor) {
-				m.EXPECT().
-					RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--sso-session", "test-session").
-					Return(nil)
-			},
 		},
 		{
-			name: "Invalid configuration",
-			setup: func(t *testing.T) string {
-				dir, err := os.MkdirTemp("", "awsctl-test")
-				require.NoError(t, err)
-				return dir
-			},
+			name:        "Invalid configuration",
 			sessionName: "missing-session",
 			wantErr:     true,
 			errContains: "invalid SSO configuration",
 		},
 		{
-			name: "Login command fails",
-			setup: func(t *testing.T) string {
-				dir, err := os.MkdirTemp("", "awsctl-test")
-				require.NoError(t, err)
-
-				awsDir := filepath.Join(dir, ".aws")
-				err = os.Mkdir(awsDir, 0700)
-				require.NoError(t, err)
-
-				configPath := filepath.Join(awsDir, "config")
-				content := `[sso-session test-session]
-sso_start_url = https://test.awsapps.com/start
-sso_region = us-west-2
-`
-				err = os.WriteFile(configPath, []byte(content), 0600)
-				require.NoError(t, err)
-
-				return dir
-			},
+			name:        "Login fails",
+			writeConfig: true,
 			sessionName: "test-session",
-			mockExec: func(m *mock_awsctl.MockCommandExecutor) {
-				m.EXPECT().
-					RunInteractiveCommand(gomock.Any(), "aws", "sso", "login", "--sso-session", "test-session").
-					Return(errors.New("login failed"))
-			},
+			tokenErrors: []string{"InvalidGrantException"},
 			wantErr:     true,
 			errContains: "error during SSO login",
 		},
@@ -348,33 +299,16 @@ sso_region = us-west-2
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			tempHome := tt.setup(t)
-			defer func() {
-				if err := os.RemoveAll(tempHome); err != nil {
-					t.Logf("failed to remove temp home: %v", err)
-				}
-			}()
-
-			originalHome := os.Getenv("HOME")
-			if err := os.Setenv("HOME", tempHome); err != nil {
-				t.Fatalf("failed to set HOME to tempDir: %v", err)
-			}
-			defer func() {
-				if err := os.Setenv("HOME", originalHome); err != nil {
-					t.Logf("warning: failed to reset HOME environment variable: %v", err)
-				}
-			}()
-
-			mockExecutor := mock_awsctl.NewMockCommandExecutor(ctrl)
-			if tt.mockExec != nil {
-				tt.mockExec(mockExecutor)
+			home := setTestHome(t)
+			if tt.writeConfig {
+				writeSessionConfig(t, home)
 			}
 
+			server := newFakeOIDCServer(t, tt.tokenErrors...)
 			client := &sso.RealSSOClient{
-				Executor: mockExecutor,
+				NewOIDCClient: server.clientFactory(),
+				Sleep:         func(time.Duration) {},
+				OpenBrowser:   func(string) error { return nil },
 			}
 
 			err := client.RunSSOLogin(tt.sessionName)
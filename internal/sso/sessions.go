@@ -0,0 +1,298 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const ssoSessionPrefix = "sso-session "
+
+// SSOSessions lists the SSO sessions of the awsctl config file and
+// ~/.aws/config, sorted by name, with the profiles that reference them.
+func (c *RealSSOClient) SSOSessions() ([]models.SSOSessionInfo, error) {
+	f, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*models.SSOSessionInfo)
+	get := func(name string) *models.SSOSessionInfo {
+		info, ok := byName[name]
+		if !ok {
+			info = &models.SSOSessionInfo{Name: name, Profiles: []string{}}
+			byName[name] = info
+		}
+		return info
+	}
+
+	for _, session := range c.customSessions() {
+		info := get(session.Name)
+		info.StartURL = strings.TrimSuffix(session.StartURL, "#")
+		info.Region = session.Region
+		info.Scopes = session.Scopes
+		info.InConfig = true
+	}
+
+	sections := f.Map()
+	for _, section := range f.SectionNames() {
+		name, ok := strings.CutPrefix(section, ssoSessionPrefix)
+		if !ok {
+			continue
+		}
+		info := get(name)
+		info.InAWSConfig = true
+		values := sections[section]
+		if info.StartURL == "" {
+			info.StartURL = strings.TrimSuffix(values["sso_start_url"], "#")
+		}
+		if info.Region == "" {
+			info.Region = values["sso_region"]
+		}
+		if info.Scopes == "" {
+			info.Scopes = values["sso_registration_scopes"]
+		}
+	}
+
+	for _, profile := range f.Profiles() {
+		if name, ok := sections[awsconfig.ProfileSection(profile)]["sso_session"]; ok {
+			if info, known := byName[name]; known {
+				info.Profiles = append(info.Profiles, profile)
+			}
+		}
+	}
+
+	sessions := make([]models.SSOSessionInfo, 0, len(byName))
+	for _, info := range byName {
+		sessions = append(sessions, *info)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
+	return sessions, nil
+}
+
+// RenameSSOSession renames an SSO session in the awsctl config file and
+// ~/.aws/config, points its profiles at the new name and moves its cached
+// token. Unless yes is set, the user confirms the change first.
+func (c *RealSSOClient) RenameSSOSession(oldName, newName string, yes bool) error {
+	session, err := c.findSSOSession(oldName)
+	if err != nil {
+		return err
+	}
+	if _, err := c.findSSOSession(newName); err == nil {
+		return fmt.Errorf("SSO session %s already exists", newName)
+	}
+
+	if !yes {
+		fmt.Printf("Renaming SSO session %s to %s updates %s.\n", oldName, newName, describeProfiles(session.Profiles))
+		confirmed, err := c.Prompter.PromptYesNo("Continue?", false)
+		if err != nil {
+			return fmt.Errorf("failed to confirm rename: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Rename cancelled.")
+			return nil
+		}
+	}
+
+	if session.InAWSConfig {
+		err := c.updateConfig(func(f *awsconfig.File) error {
+			if err := f.RenameSection(ssoSessionPrefix+oldName, ssoSessionPrefix+newName); err != nil {
+				return err
+			}
+			for _, profile := range session.Profiles {
+				f.Set(awsconfig.ProfileSection(profile), "sso_session", newName)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update ~/.aws/config: %w", err)
+		}
+	}
+
+	if session.InConfig {
+		for i := range c.Config.RawCustomConfig.SSOSessions {
+			if c.Config.RawCustomConfig.SSOSessions[i].Name == oldName {
+				c.Config.RawCustomConfig.SSOSessions[i].Name = newName
+			}
+		}
+		if err := config.SaveConfig(&c.Config); err != nil {
+			return fmt.Errorf("failed to save awsctl config: %w", err)
+		}
+	}
+
+	if err := moveSSOCacheFile(oldName, newName); err != nil {
+		fmt.Printf("Warning: failed to move cached SSO token: %v\n", err)
+	}
+
+	fmt.Printf("Renamed SSO session %s to %s (%s updated)\n", oldName, newName, describeProfiles(session.Profiles))
+	return nil
+}
+
+// RemoveSSOSession removes an SSO session from the awsctl config file and
+// ~/.aws/config together with the profiles that reference it. Unless yes is
+// set, the user confirms the removal first.
+func (c *RealSSOClient) RemoveSSOSession(name string, yes bool) error {
+	session, err := c.findSSOSession(name)
+	if err != nil {
+		return err
+	}
+
+	if !yes {
+		fmt.Printf("Removing SSO session %s also removes %s.\n", name, describeProfiles(session.Profiles))
+		confirmed, err := c.Prompter.PromptYesNo("Continue?", false)
+		if err != nil {
+			return fmt.Errorf("failed to confirm removal: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Removal cancelled.")
+			return nil
+		}
+	}
+
+	if session.InAWSConfig || len(session.Profiles) > 0 {
+		err := c.updateConfig(func(f *awsconfig.File) error {
+			f.DeleteSection(ssoSessionPrefix + name)
+			for _, profile := range session.Profiles {
+				f.DeleteSection(awsconfig.ProfileSection(profile))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update ~/.aws/config: %w", err)
+		}
+	}
+
+	if session.InConfig {
+		c.Config.RawCustomConfig.SSOSessions = slices.DeleteFunc(c.Config.RawCustomConfig.SSOSessions, func(s models.SSOSession) bool {
+			return s.Name == name
+		})
+		if err := config.SaveConfig(&c.Config); err != nil {
+			return fmt.Errorf("failed to save awsctl config: %w", err)
+		}
+	}
+
+	fmt.Printf("Removed SSO session %s (%s removed)\n", name, describeProfiles(session.Profiles))
+	return nil
+}
+
+// ImportSSOSessions copies the [sso-session] blocks of ~/.aws/config that are
+// missing from the awsctl config file into it and returns their names.
+func (c *RealSSOClient) ImportSSOSessions() ([]string, error) {
+	f, err := c.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]models.SSOSession)
+	for _, session := range c.customSessions() {
+		existing[session.Name] = session
+	}
+
+	var imported []string
+	sections := f.Map()
+	for _, section := range f.SectionNames() {
+		name, ok := strings.CutPrefix(section, ssoSessionPrefix)
+		if !ok {
+			continue
+		}
+		values := sections[section]
+		startURL := strings.TrimSuffix(values["sso_start_url"], "#")
+		region := values["sso_region"]
+		if startURL == "" || region == "" {
+			fmt.Printf("Skipping sso-session %s: sso_start_url and sso_region are required\n", name)
+			continue
+		}
+		if current, ok := existing[name]; ok {
+			if strings.TrimSuffix(current.StartURL, "#") != startURL {
+				fmt.Printf("Skipping sso-session %s: a session with that name and start URL %s already exists\n", name, current.StartURL)
+			}
+			continue
+		}
+
+		session := models.SSOSession{
+			Name:     name,
+			StartURL: startURL,
+			Region:   region,
+			Scopes:   values["sso_registration_scopes"],
+		}
+		if c.Config.RawCustomConfig == nil {
+			c.Config.RawCustomConfig = &models.Config{}
+		}
+		c.Config.RawCustomConfig.SSOSessions = append(c.Config.RawCustomConfig.SSOSessions, session)
+		existing[name] = session
+		imported = append(imported, name)
+	}
+
+	if len(imported) > 0 {
+		if err := config.SaveConfig(&c.Config); err != nil {
+			return nil, fmt.Errorf("failed to save awsctl config: %w", err)
+		}
+	}
+	return imported, nil
+}
+
+func (c *RealSSOClient) customSessions() []models.SSOSession {
+	if c.Config.RawCustomConfig == nil {
+		return nil
+	}
+	return c.Config.RawCustomConfig.SSOSessions
+}
+
+func (c *RealSSOClient) findSSOSession(name string) (*models.SSOSessionInfo, error) {
+	sessions, err := c.SSOSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSO sessions: %w", err)
+	}
+	for i := range sessions {
+		if sessions[i].Name == name {
+			return &sessions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("SSO session %s not found", name)
+}
+
+// moveSSOCacheFile renames the token cache file of a session, which is keyed
+// by the session name. A session without a cached token is left alone.
+func moveSSOCacheFile(oldName, newName string) error {
+	oldPath, err := ssoCachePath(oldName, "")
+	if err != nil {
+		return err
+	}
+	cache, err := readSSOCacheFile(oldPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	newPath, err := ssoCachePath(newName, "")
+	if err != nil {
+		return err
+	}
+	if cache.SessionName != nil {
+		cache.SessionName = aws.String(newName)
+	}
+	if err := writeSSOCacheFile(newPath, cache); err != nil {
+		return err
+	}
+	return os.Remove(oldPath)
+}
+
+func describeProfiles(profiles []string) string {
+	switch len(profiles) {
+	case 0:
+		return "no profiles"
+	case 1:
+		return "profile " + profiles[0]
+	default:
+		return fmt.Sprintf("%d profiles: %s", len(profiles), strings.Join(profiles, ", "))
+	}
+}
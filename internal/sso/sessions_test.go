@@ -0,0 +1,158 @@
+package sso_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionsAWSConfig = `[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = us-east-1
+sso_registration_scopes = sso:account:access
+
+[profile corp-dev]
+sso_session = corp
+sso_account_id = 111111111111
+sso_role_name = Admin
+
+[default]
+sso_session = corp
+region = us-east-1
+
+[sso-session legacy]
+sso_start_url = https://legacy.awsapps.com/start#
+sso_region = eu-west-1
+
+[sso-session broken]
+sso_region = eu-west-1
+
+[profile static]
+region = us-west-2
+`
+
+func newSessionsClient(t *testing.T, sessions ...models.SSOSession) (*sso.RealSSOClient, *mock_sso.MockPrompter, string) {
+	t.Helper()
+	home := setTestHome(t)
+	writeAWSConfig(t, home, sessionsAWSConfig)
+	ctrl := gomock.NewController(t)
+	prompter := mock_sso.NewMockPrompter(ctrl)
+	client := &sso.RealSSOClient{
+		Prompter: prompter,
+		Config: config.Config{
+			AWSConfigDir:    filepath.Join(home, ".config", "awsctl"),
+			RawCustomConfig: &models.Config{SSOSessions: sessions},
+		},
+	}
+	return client, prompter, home
+}
+
+func TestSSOSessions(t *testing.T) {
+	client, _, _ := newSessionsClient(t,
+		models.SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1", Scopes: "sso:account:access"},
+		models.SSOSession{Name: "local", StartURL: "https://local.awsapps.com/start", Region: "us-west-2"},
+	)
+
+	sessions, err := client.SSOSessions()
+	require.NoError(t, err)
+	assert.Equal(t, []models.SSOSessionInfo{
+		{Name: "broken", Region: "eu-west-1", InAWSConfig: true, Profiles: []string{}},
+		{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1", Scopes: "sso:account:access", InConfig: true, InAWSConfig: true, Profiles: []string{"corp-dev", "default"}},
+		{Name: "legacy", StartURL: "https://legacy.awsapps.com/start", Region: "eu-west-1", InAWSConfig: true, Profiles: []string{}},
+		{Name: "local", StartURL: "https://local.awsapps.com/start", Region: "us-west-2", InConfig: true, Profiles: []string{}},
+	}, sessions)
+}
+
+func TestRenameSSOSession(t *testing.T) {
+	client, _, home := newSessionsClient(t,
+		models.SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1"},
+	)
+	oldCache := cacheFileFor(home, "corp")
+	require.NoError(t, os.MkdirAll(filepath.Dir(oldCache), 0700))
+	require.NoError(t, os.WriteFile(oldCache, []byte(`{"accessToken":"token","sessionName":"corp","startUrl":"https://corp.awsapps.com/start"}`), 0600))
+
+	require.NoError(t, client.RenameSSOSession("corp", "acme", true))
+
+	f := readAWSConfig(t, home)
+	assert.False(t, f.HasSection("sso-session corp"))
+	url, _ := f.Get("sso-session acme", "sso_start_url")
+	assert.Equal(t, "https://corp.awsapps.com/start", url)
+	for _, section := range []string{"profile corp-dev", "default"} {
+		session, _ := f.Get(section, "sso_session")
+		assert.Equal(t, "acme", session, section)
+	}
+	assert.Equal(t, "acme", client.Config.RawCustomConfig.SSOSessions[0].Name)
+
+	saved, err := os.ReadFile(filepath.Join(home, ".config", "awsctl", "config.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "name: acme")
+
+	assert.NoFileExists(t, oldCache)
+	cache, err := os.ReadFile(cacheFileFor(home, "acme"))
+	require.NoError(t, err)
+	assert.Contains(t, string(cache), `"sessionName":"acme"`)
+}
+
+func TestRenameSSOSession_Errors(t *testing.T) {
+	client, _, _ := newSessionsClient(t)
+
+	err := client.RenameSSOSession("missing", "other", true)
+	assert.EqualError(t, err, "SSO session missing not found")
+
+	err = client.RenameSSOSession("corp", "legacy", true)
+	assert.EqualError(t, err, "SSO session legacy already exists")
+}
+
+func TestRenameSSOSession_Declined(t *testing.T) {
+	client, prompter, home := newSessionsClient(t)
+	prompter.EXPECT().PromptYesNo("Continue?", false).Return(false, nil)
+
+	require.NoError(t, client.RenameSSOSession("corp", "acme", false))
+
+	f := readAWSConfig(t, home)
+	assert.True(t, f.HasSection("sso-session corp"))
+	assert.NoDirExists(t, filepath.Join(home, ".config", "awsctl"))
+}
+
+func TestRemoveSSOSession(t *testing.T) {
+	client, prompter, home := newSessionsClient(t,
+		models.SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1"},
+		models.SSOSession{Name: "local", StartURL: "https://local.awsapps.com/start", Region: "us-west-2"},
+	)
+	prompter.EXPECT().PromptYesNo("Continue?", false).Return(true, nil)
+
+	require.NoError(t, client.RemoveSSOSession("corp", false))
+
+	f := readAWSConfig(t, home)
+	assert.Equal(t, []string{"sso-session legacy", "sso-session broken", "profile static"}, f.SectionNames())
+	assert.Equal(t, []models.SSOSession{{Name: "local", StartURL: "https://local.awsapps.com/start", Region: "us-west-2"}}, client.Config.RawCustomConfig.SSOSessions)
+	assert.FileExists(t, filepath.Join(home, ".config", "awsctl", "config.yml"))
+}
+
+func TestImportSSOSessions(t *testing.T) {
+	client, _, _ := newSessionsClient(t,
+		models.SSOSession{Name: "corp", StartURL: "https://corp.awsapps.com/start", Region: "us-east-1"},
+	)
+
+	imported, err := client.ImportSSOSessions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy"}, imported)
+	assert.Equal(t, models.SSOSession{Name: "legacy", StartURL: "https://legacy.awsapps.com/start", Region: "eu-west-1"}, client.Config.RawCustomConfig.SSOSessions[1])
+
+	cfg, err := config.NewConfig()
+	require.NoError(t, err)
+	assert.Len(t, cfg.RawCustomConfig.SSOSessions, 2)
+
+	client.Config = *cfg
+	imported, err = client.ImportSSOSessions()
+	require.NoError(t, err)
+	assert.Empty(t, imported)
+}
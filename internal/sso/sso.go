@@ -28,24 +28,35 @@ func (c *RealSSOClient) SetupSSO(opts SSOFlagOptions) error {
 		return fmt.Errorf("failed to run SSO login: %w", err)
 	}
 
-	accountID, accountName, err := c.selectAccount(ssoSession.Region, ssoSession.StartURL)
+	if opts.All {
+		return c.setupAllProfiles(ssoSession, opts)
+	}
+
+	account, err := c.selectAccount(ssoSession.Region, ssoSession.StartURL)
 	if err != nil {
 		if errors.Is(err, promptUtils.ErrInterrupted) {
 			return nil
 		}
 		return fmt.Errorf("failed to select account: %w", err)
 	}
+	accountID := account.AccountID
 
-	role, err := c.selectRole(ssoSession.Region, ssoSession.StartURL, accountID)
-	fmt.Printf("Selected role: %s\n", role)
+	role, err := c.selectRole(account)
 	if err != nil {
 		if errors.Is(err, promptUtils.ErrInterrupted) {
 			return nil
 		}
 		return fmt.Errorf("failed to select role: %w", err)
 	}
+	fmt.Printf("Selected role: %s\n", role)
 
-	profileName := c.generateProfileName(ssoSession.Name, accountName, role)
+	profileName, err := c.profileName(ssoSession, account, role)
+	if err != nil {
+		return err
+	}
+	if err := c.checkProfileConflict(profileName, accountID, role); err != nil {
+		return err
+	}
 
 	if err := c.ConfigureAWSProfile(profileName, ssoSession.Name, ssoSession.Region, ssoSession.StartURL, accountID, role, ssoSession.Region); err != nil {
 		return fmt.Errorf("failed to configure AWS profile: %w", err)
@@ -53,7 +64,7 @@ func (c *RealSSOClient) SetupSSO(opts SSOFlagOptions) error {
 
 	defaultConfigured := profileName == "default"
 
-	if !defaultConfigured {
+	if !defaultConfigured && c.setsDefaultProfile(opts.SetDefault) {
 		if err := c.ConfigureAWSProfile("default", ssoSession.Name, ssoSession.Region, ssoSession.StartURL, accountID, role, ssoSession.Region); err != nil {
 			return fmt.Errorf("failed to configure AWS default profile: %w", err)
 		}
@@ -67,12 +78,19 @@ func (c *RealSSOClient) SetupSSO(opts SSOFlagOptions) error {
 		fmt.Println("You can now use AWS CLI commands without specifying --profile")
 	} else {
 		fmt.Printf("You can now use this profile with AWS CLI commands using: --profile %s\n", profileName)
+		printUseHint(profileName)
 	}
 
 	return nil
 }
 
-func (c *RealSSOClient) InitSSO(refresh, noBrowser bool) error {
+// printUseHint tells how to activate profile in the current shell only.
+func printUseHint(profile string) {
+	fmt.Printf("To use it in this shell, run: eval \"$(awsctl sso use %s)\"\n", profile)
+	fmt.Println("Pass --set-default to make it the default profile for every shell.")
+}
+
+func (c *RealSSOClient) InitSSO(refresh, noBrowser, setDefault bool) error {
 	fmt.Println("Initializing AWS SSO...")
 
 	profiles, err := c.ValidProfiles()
@@ -94,18 +112,20 @@ func (c *RealSSOClient) InitSSO(refresh, noBrowser bool) error {
 		if err != nil {
 			return fmt.Errorf("failed to select profile: %w", err)
 		}
-
-		if awsProfile != "default" {
-			if err := c.setProfileAsDefault(awsProfile); err != nil {
-				return err
-			}
-		}
 	}
 
 	if !slices.Contains(profiles, awsProfile) {
 		return fmt.Errorf("invalid profile: %s", awsProfile)
 	}
 
+	defaultSet := c.setsDefaultProfile(setDefault)
+	if defaultSet {
+		if err := c.SetDefaultProfile(awsProfile); err != nil {
+			return err
+		}
+		fmt.Println("Successfully set this profile as default!")
+	}
+
 	// Handle refresh flag
 	if refresh {
 		fmt.Printf("Refresh flag set. Forcing re-login for profile %s...\n", awsProfile)
@@ -130,5 +150,12 @@ func (c *RealSSOClient) InitSSO(refresh, noBrowser bool) error {
 
 	fmt.Printf("SSO token validated for profile %s\n", awsProfile)
 
-	return c.printProfileSummary(awsProfile)
+	if err := c.printProfileSummary(awsProfile); err != nil {
+		return err
+	}
+	if !defaultSet && c.Config.AWSProfile != awsProfile {
+		fmt.Println()
+		printUseHint(awsProfile)
+	}
+	return nil
 }
@@ -0,0 +1,116 @@
+package sso_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_sso "github.com/BerryBytes/awsctl/tests/mock/sso"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssso "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const initConfig = `[default]
+region = eu-west-1
+
+[sso-session team]
+sso_start_url = https://team.awsapps.com/start
+sso_region = us-east-1
+
+[profile team-admin]
+sso_session = team
+sso_start_url = https://team.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 111111111111
+sso_role_name = Admin
+region = us-east-1
+output = json
+`
+
+func TestInitSSO_DefaultProfile(t *testing.T) {
+	tests := []struct {
+		name          string
+		setDefault    bool
+		configFlag    bool
+		wantDefault   bool
+		wantUseHint   bool
+		activeProfile string
+	}{
+		{name: "per shell by default", wantUseHint: true},
+		{name: "set default flag", setDefault: true, wantDefault: true},
+		{name: "set default config", configFlag: true, wantDefault: true},
+		{name: "already active in shell", activeProfile: "team-admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			home := setTestHome(t)
+			writeAWSConfig(t, home, initConfig)
+
+			prompter := mock_sso.NewMockPrompter(ctrl)
+			if tt.activeProfile == "" {
+				prompter.EXPECT().SelectFromList("Select AWS profile", []string{"team-admin"}).Return("team-admin", nil)
+			}
+
+			cacheDir := filepath.Join(home, ".aws", "sso", "cache")
+			require.NoError(t, os.MkdirAll(cacheDir, 0700))
+			data, err := json.Marshal(models.SSOCache{
+				StartURL:    aws.String("https://team.awsapps.com/start"),
+				AccessToken: aws.String("token"),
+				ExpiresAt:   aws.String(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)),
+			})
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "team.json"), data, 0600))
+
+			calls := 0
+			var regions []string
+			var creds []*models.AWSCredentials
+
+			client := &sso.RealSSOClient{
+				Prompter: prompter,
+				NewPortalClient: func(region string) sso.SSOPortalAPI {
+					m := roleCredentialsPortal(ctrl, &calls, time.Now().Add(time.Hour))(region).(*mock_sso.MockSSOPortalAPI)
+					m.EXPECT().ListAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(&awssso.ListAccountsOutput{AccountList: []ssotypes.AccountInfo{accountInfo("111111111111", "team")}}, nil).AnyTimes()
+					return m
+				},
+				NewSTSClient: callerIdentitySTS(ctrl, "arn:aws:sts::111111111111:assumed-role/Admin/me", &regions, &creds),
+				TokenCache: models.TokenCache{
+					AccessToken: "token",
+					Expiry:      time.Now().Add(time.Hour),
+				},
+			}
+			client.Config.AWSProfile = tt.activeProfile
+			client.Config.RawCustomConfig = &models.Config{SetDefaultProfile: tt.configFlag}
+
+			output := captureStdout(t, func() {
+				err = client.InitSSO(false, false, tt.setDefault)
+			})
+			require.NoError(t, err)
+
+			defaults := readAWSConfig(t, home).Values("default")
+			if tt.wantDefault {
+				assert.Equal(t, "111111111111", defaults["sso_account_id"])
+				assert.Contains(t, output, "Successfully set this profile as default!")
+			} else {
+				assert.Equal(t, map[string]string{"region": "eu-west-1"}, defaults)
+			}
+			if tt.wantUseHint {
+				assert.Contains(t, output, `eval "$(awsctl sso use team-admin)"`)
+			} else {
+				assert.NotContains(t, output, "awsctl sso use")
+			}
+		})
+	}
+}
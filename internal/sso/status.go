@@ -0,0 +1,93 @@
+package sso
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/awsconfig"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	StatusValid   = "valid"
+	StatusExpired = "expired"
+	StatusNoToken = "no token"
+	StatusNotSSO  = "not sso"
+)
+
+// ProfileStatuses reports the cached SSO token state of every profile. It
+// only reads local files unless verify is set, in which case the identity of
+// each profile with a valid token is checked with STS.
+func (c *RealSSOClient) ProfileStatuses(verify bool) ([]models.ProfileStatus, error) {
+	profiles, err := c.ValidProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	sections, err := c.readConfigSections()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.ProfileStatus, 0, len(profiles))
+	for _, profile := range profiles {
+		status := profileStatus(profile, sections)
+
+		if verify && status.Valid {
+			identity, err := c.TryGetCallerIdentity(profile)
+			if err != nil {
+				status.IdentityError = err.Error()
+			} else {
+				status.Identity = identity
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func profileStatus(profile string, sections map[string]map[string]string) models.ProfileStatus {
+	values := sections[awsconfig.ProfileSection(profile)]
+
+	status := models.ProfileStatus{
+		Profile:    profile,
+		SSOSession: values["sso_session"],
+		StartURL:   values["sso_start_url"],
+		AccountID:  values["sso_account_id"],
+		Role:       values["sso_role_name"],
+		Region:     values["region"],
+	}
+	if session, ok := sections["sso-session "+status.SSOSession]; ok && status.SSOSession != "" {
+		status.StartURL = session["sso_start_url"]
+	}
+	status.StartURL = strings.TrimSuffix(status.StartURL, "#")
+
+	if status.StartURL == "" {
+		status.Status = StatusNotSSO
+		return status
+	}
+
+	cache, _, err := findSSOCacheFile(status.StartURL)
+	if err != nil || cache == nil || aws.ToString(cache.AccessToken) == "" {
+		status.Status = StatusNoToken
+		return status
+	}
+
+	expiresAt, err := parseCacheTime(cache.ExpiresAt)
+	if err != nil {
+		status.Status = StatusNoToken
+		return status
+	}
+	status.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+
+	if time.Now().Before(expiresAt) {
+		status.Status = StatusValid
+		status.Valid = true
+	} else {
+		status.Status = StatusExpired
+	}
+	return status
+}
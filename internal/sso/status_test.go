@@ -0,0 +1,130 @@
+package sso_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const statusConfig = `[sso-session team]
+sso_start_url = https://team.awsapps.com/start
+sso_region = us-east-1
+
+[profile team-admin]
+sso_session = team
+sso_account_id = 111111111111
+sso_role_name = Admin
+region = eu-west-1
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start#
+sso_account_id = 222222222222
+sso_role_name = ReadOnly
+region = us-west-2
+
+[profile other]
+sso_start_url = https://other.awsapps.com/start
+sso_account_id = 333333333333
+sso_role_name = ReadOnly
+
+[profile static]
+region = us-east-1
+`
+
+func writeStatusFixtures(t *testing.T) {
+	t.Helper()
+	home := setTestHome(t)
+	writeAWSConfig(t, home, statusConfig)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".aws", "sso", "cache"), 0700))
+
+	for name, cache := range map[string]models.SSOCache{
+		"team.json": {
+			StartURL:    aws.String("https://team.awsapps.com/start"),
+			AccessToken: aws.String("token"),
+			ExpiresAt:   aws.String(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)),
+		},
+		"legacy.json": {
+			StartURL:    aws.String("https://legacy.awsapps.com/start"),
+			AccessToken: aws.String("token"),
+			ExpiresAt:   aws.String(time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)),
+		},
+	} {
+		data, err := json.Marshal(cache)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".aws", "sso", "cache", name), data, 0600))
+	}
+}
+
+func TestProfileStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writeStatusFixtures(t)
+
+	client := &sso.RealSSOClient{Executor: mock_awsctl.NewMockCommandExecutor(ctrl)}
+	statuses, err := client.ProfileStatuses(false)
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+
+	assert.Equal(t, "team-admin", statuses[0].Profile)
+	assert.Equal(t, "team", statuses[0].SSOSession)
+	assert.Equal(t, "https://team.awsapps.com/start", statuses[0].StartURL)
+	assert.Equal(t, "111111111111", statuses[0].AccountID)
+	assert.Equal(t, "Admin", statuses[0].Role)
+	assert.Equal(t, "eu-west-1", statuses[0].Region)
+	assert.Equal(t, sso.StatusValid, statuses[0].Status)
+	assert.True(t, statuses[0].Valid)
+	assert.NotEmpty(t, statuses[0].ExpiresAt)
+	assert.Empty(t, statuses[0].Identity)
+
+	assert.Equal(t, sso.StatusExpired, statuses[1].Status)
+	assert.False(t, statuses[1].Valid)
+	assert.Equal(t, sso.StatusNoToken, statuses[2].Status)
+	assert.Equal(t, sso.StatusNotSSO, statuses[3].Status)
+}
+
+func TestProfileStatuses_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	writeStatusFixtures(t)
+
+	calls := 0
+	var regions []string
+	var creds []*models.AWSCredentials
+	client := &sso.RealSSOClient{
+		NewPortalClient: roleCredentialsPortal(ctrl, &calls, time.Now().Add(time.Hour)),
+		NewSTSClient:    callerIdentitySTS(ctrl, "arn:aws:sts::111111111111:assumed-role/Admin/me", &regions, &creds),
+	}
+	statuses, err := client.ProfileStatuses(true)
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+	assert.Equal(t, "arn:aws:sts::111111111111:assumed-role/Admin/me", statuses[0].Identity)
+	assert.Equal(t, []string{"eu-west-1"}, regions)
+	for _, status := range statuses[1:] {
+		assert.Empty(t, status.Identity)
+	}
+}
+
+func TestProfileStatuses_ListProfilesError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	setTestHome(t)
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir())
+
+	client := &sso.RealSSOClient{Executor: mock_awsctl.NewMockCommandExecutor(ctrl)}
+	_, err := client.ProfileStatuses(false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list profiles")
+}
@@ -1,10 +1,11 @@
 package sso
 
 import (
-	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/BerryBytes/awsctl/models"
 )
 
 func ValidateAccountID(accountID string) error {
@@ -40,151 +41,65 @@ func PrintSummary(profileName, sessionName, ssoStartURL, ssoRegion, accountID, r
 	}
 }
 
-func (c *RealSSOClient) listSSOAccounts(region, startURL string) ([]string, error) {
-	accessToken, err := c.GetAccessToken(startURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get access token: %w", err)
-	}
-
-	output, err := c.Executor.RunCommand(
-		"aws", "sso", "list-accounts",
-		"--region", region,
-		"--access-token", accessToken,
-		"--output", "json",
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list accounts: %v: %s", err, string(output))
-	}
-
-	type account struct {
-		AccountId   string `json:"accountId"`
-		AccountName string `json:"accountName"`
-	}
-	type accountList struct {
-		AccountList []account `json:"accountList"`
-	}
-	var result accountList
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse accounts JSON: %v", err)
-	}
-
-	if len(result.AccountList) == 0 {
-		return nil, fmt.Errorf("no accounts found")
-	}
-
-	accounts := make([]string, 0, len(result.AccountList))
-	for _, acc := range result.AccountList {
-		if acc.AccountId == "" {
-			continue
-		}
-		name := acc.AccountName
-		if name == "" {
-			name = "Unnamed"
-		}
-		accounts = append(accounts, fmt.Sprintf("%s (%s)", acc.AccountId, name))
-	}
-
-	if len(accounts) == 0 {
-		return nil, fmt.Errorf("no valid accounts found")
-	}
-	return accounts, nil
-}
-
-func (c *RealSSOClient) listSSORoles(region, startURL, accountID string) ([]string, error) {
-	accessToken, err := c.GetAccessToken(startURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get access token: %w", err)
-	}
-
-	output, err := c.Executor.RunCommand("aws", "sso", "list-account-roles", "--region", region, "--account-id", accountID, "--access-token", accessToken)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list roles: %v: %s", err, string(output))
-	}
-
-	type role struct {
-		RoleName string `json:"roleName"`
-	}
-	type roleList struct {
-		RoleList []role `json:"roleList"`
-	}
-	var result roleList
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse roles JSON: %v", err)
-	}
-
-	if len(result.RoleList) == 0 {
-		return nil, fmt.Errorf("no roles found for account %s", accountID)
-	}
-
-	roles := make([]string, 0, len(result.RoleList))
-	for _, role := range result.RoleList {
-		if role.RoleName == "" {
-			continue
-		}
-		roles = append(roles, role.RoleName)
+func accountLabel(account models.SSOAccount) string {
+	name := account.AccountName
+	if name == "" {
+		name = "Unnamed"
 	}
-
-	if len(roles) == 0 {
-		return nil, fmt.Errorf("no valid roles found for account %s", accountID)
-	}
-	return roles, nil
+	return fmt.Sprintf("%s (%s)", account.AccountID, name)
 }
 
-func (c *RealSSOClient) selectAccount(region, startURL string) (string, string, error) {
+func (c *RealSSOClient) selectAccount(region, startURL string) (models.SSOAccount, error) {
 	fmt.Println("\nFetching available AWS accounts...")
-	accounts, err := c.listSSOAccounts(region, startURL)
+	accounts, err := c.ListSSOAccounts(region, startURL)
 	if err != nil {
-		return "", "", fmt.Errorf("error listing accounts: %w", err)
+		return models.SSOAccount{}, fmt.Errorf("error listing accounts: %w", err)
 	}
-	if len(accounts) == 0 {
-		return "", "", fmt.Errorf("no AWS accounts found")
+
+	labels := make([]string, 0, len(accounts))
+	byLabel := make(map[string]models.SSOAccount, len(accounts))
+	for _, account := range accounts {
+		label := accountLabel(account)
+		labels = append(labels, label)
+		byLabel[label] = account
 	}
 
-	selectedAccount, err := c.Prompter.SelectFromList("Select an AWS account:", accounts)
+	selected, err := c.Prompter.SelectFromList("Select an AWS account:", labels)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to select account: %w", err)
+		return models.SSOAccount{}, fmt.Errorf("failed to select account: %w", err)
 	}
 
-	parts := strings.SplitN(selectedAccount, " ", 2)
-	accountID := parts[0]
-	accountName := ""
-	if len(parts) > 1 {
-		accountName = parts[1]
+	account, ok := byLabel[selected]
+	if !ok {
+		return models.SSOAccount{}, fmt.Errorf("invalid account selection: %s", selected)
 	}
-	accountName = strings.Trim(accountName, "()")
-
-	if err := ValidateAccountID(accountID); err != nil {
-		return "", "", err
+	if err := ValidateAccountID(account.AccountID); err != nil {
+		return models.SSOAccount{}, err
 	}
 
-	return accountID, accountName, nil
+	return account, nil
 }
 
-func (c *RealSSOClient) selectRole(region, startURL, accountID string) (string, error) {
-	fmt.Printf("\nFetching available roles for account %s...\n", accountID)
-	roles, err := c.listSSORoles(region, startURL, accountID)
-	if err != nil {
-		return "", fmt.Errorf("error listing roles: %w", err)
-	}
-	if len(roles) == 0 {
-		return "", fmt.Errorf("no roles found for account %s", accountID)
+func (c *RealSSOClient) selectRole(account models.SSOAccount) (string, error) {
+	if len(account.Roles) == 0 {
+		return "", fmt.Errorf("no roles found for account %s", account.AccountID)
 	}
 
-	role, err := c.Prompter.SelectFromList("Select a role:", roles)
+	role, err := c.Prompter.SelectFromList("Select a role:", account.Roles)
 	if err != nil {
 		return "", fmt.Errorf("failed to select role: %w", err)
 	}
 	return role, nil
 }
 
-func (c *RealSSOClient) generateProfileName(session, account, role string) string {
-	sessionSlug := strings.ToLower(strings.ReplaceAll(session, " ", "-"))
-	sessionSlug = strings.Trim(sessionSlug, "-")
-
-	accountSlug := strings.ToLower(strings.ReplaceAll(account, " ", "-"))
-	accountSlug = strings.Trim(accountSlug, "-")
+// slugify lowercases s and joins its words with dashes.
+func slugify(s string) string {
+	return strings.Trim(strings.ToLower(strings.ReplaceAll(s, " ", "-")), "-")
+}
 
-	accountParts := strings.Split(accountSlug, "-")
+// accountSlug shortens an account name to at most three meaningful words.
+func accountSlug(accountName string) string {
+	accountParts := strings.Split(slugify(accountName), "-")
 	meaningfulParts := []string{}
 	for _, part := range accountParts {
 		if part != "" && part != "and" && part != "the" && part != "of" {
@@ -195,8 +110,12 @@ func (c *RealSSOClient) generateProfileName(session, account, role string) strin
 		}
 	}
 
-	acctSlug := strings.Join(meaningfulParts, "-")
+	return strings.Join(meaningfulParts, "-")
+}
 
+// roleShort abbreviates a permission set name, e.g. "adm" for
+// AdministratorAccess.
+func roleShort(role string) string {
 	roleWords := strings.FieldsFunc(strings.ToLower(role), func(r rune) bool {
 		return r == ' ' || r == '-'
 	})
@@ -244,9 +163,5 @@ func (c *RealSSOClient) generateProfileName(session, account, role string) strin
 		}
 	}
 
-	roleSlug := strings.Join(roleParts, "-")
-
-	profileName := sessionSlug + "-" + acctSlug + "-" + roleSlug
-
-	return profileName
+	return strings.Join(roleParts, "-")
 }
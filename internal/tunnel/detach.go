@@ -0,0 +1,147 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/shirou/gopsutil/net"
+)
+
+const (
+	// logFileEnv tells a tunnel set started by Detach where its output goes.
+	logFileEnv = "AWSCTL_TUNNEL_LOG"
+	// detachTimeout is how long Detach waits for a tunnel set to come up.
+	detachTimeout = time.Minute
+)
+
+// selfCommand runs the awsctl executable with args.
+func selfCommand(args ...string) *exec.Cmd {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	return exec.Command(exe, args...)
+}
+
+// Detach brings up the tunnel set name in a background `awsctl tunnel up`
+// process and returns once all of its local ports are listening. The
+// process writes its output to a log file in the state directory.
+func (s *TunnelService) Detach(name string, opts connection.AWSOptions) error {
+	set, err := s.tunnelSet(name)
+	if err != nil {
+		return err
+	}
+	if _, err := connectOptions(set); err != nil {
+		return fmt.Errorf("tunnel set %s: %w", name, err)
+	}
+	if s.StateDir == "" {
+		return errors.New("no tunnel state directory")
+	}
+	if old, err := s.readState(name); err == nil && running(old) {
+		return fmt.Errorf("tunnel set %s is already up (pid %d)", name, old.PID)
+	}
+
+	ports := make([]int, 0, len(set.Forwards))
+	for _, f := range set.Forwards {
+		ports = append(ports, f.Local)
+	}
+	if port, ok := anyListening(ports); ok {
+		return fmt.Errorf("tunnel set %s: local port %d is already in use", name, port)
+	}
+
+	if err := os.MkdirAll(s.StateDir, 0700); err != nil {
+		return fmt.Errorf("failed to create tunnel state directory: %w", err)
+	}
+	logPath := s.logPath(name)
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create tunnel log: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	args := []string{"tunnel", "up", name}
+	if opts.Profile != "" {
+		args = append(args, "--profile", opts.Profile)
+	}
+	if opts.Region != "" {
+		args = append(args, "--region", opts.Region)
+	}
+	cmd := s.Command(args...)
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, logFileEnv+"="+logPath)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start tunnel set %s: %w", name, err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(detachTimeout)
+	for {
+		select {
+		case <-exited:
+			return fmt.Errorf("tunnel set %s failed to start:\n%s", name, tail(logPath, 20))
+		case <-timeout:
+			fmt.Printf("Tunnel set %s is still starting (pid %d). Check on it with: awsctl tunnel logs %s\n", name, cmd.Process.Pid, name)
+			return nil
+		case <-ticker.C:
+		}
+
+		st, err := s.readState(name)
+		if err != nil || st.PID != cmd.Process.Pid || !allListening(ports) {
+			continue
+		}
+		fmt.Printf("Tunnel set %s is up in the background (pid %d).\n", name, st.PID)
+		fmt.Printf("Logs: awsctl tunnel logs %s\nStop: awsctl tunnel stop %s\n", name, name)
+		return nil
+	}
+}
+
+// listeningPorts returns the local TCP ports that are being listened on.
+func listeningPorts() map[int]bool {
+	conns, err := net.Connections("tcp")
+	if err != nil {
+		return nil
+	}
+	ports := make(map[int]bool)
+	for _, conn := range conns {
+		if conn.Status == "LISTEN" {
+			ports[int(conn.Laddr.Port)] = true
+		}
+	}
+	return ports
+}
+
+func anyListening(ports []int) (int, bool) {
+	listening := listeningPorts()
+	for _, port := range ports {
+		if listening[port] {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+func allListening(ports []int) bool {
+	listening := listeningPorts()
+	for _, port := range ports {
+		if !listening[port] {
+			return false
+		}
+	}
+	return true
+}
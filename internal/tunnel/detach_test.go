@@ -0,0 +1,177 @@
+package tunnel_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/tunnel"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment of the supervisor that the detach tests run in the background.
+const (
+	supervisorStateEnv = "AWSCTL_TEST_SUPERVISOR_STATE"
+	supervisorPortsEnv = "AWSCTL_TEST_SUPERVISOR_PORTS"
+	supervisorFailEnv  = "AWSCTL_TEST_SUPERVISOR_FAIL"
+)
+
+func detachSets(ports []int) map[string]models.TunnelSet {
+	set := models.TunnelSet{Bastion: "i-0abc", Method: "ssm"}
+	for i, port := range ports {
+		set.Forwards = append(set.Forwards, models.TunnelForward{Local: port, Remote: fmt.Sprintf("10.0.1.%d:80", i+5)})
+	}
+	return map[string]models.TunnelSet{"dev": set}
+}
+
+// TestHelperSupervisor stands in for `awsctl tunnel up` when it is run by
+// Detach in the tests below.
+func TestHelperSupervisor(t *testing.T) {
+	stateDir := os.Getenv(supervisorStateEnv)
+	if stateDir == "" {
+		t.Skip("run by the detach tests")
+	}
+
+	var ports []int
+	for _, p := range strings.Split(os.Getenv(supervisorPortsEnv), ",") {
+		port, _ := strconv.Atoi(p)
+		ports = append(ports, port)
+	}
+	service, conn := newService(t, detachSets(ports))
+	service.StateDir = stateDir
+
+	conn.EXPECT().UseConnectOptions(gomock.Any())
+	conn.EXPECT().StartTunnels(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, forwards []connection.Forward) error {
+		if os.Getenv(supervisorFailEnv) != "" {
+			return errors.New("failed to connect to bastion: connection refused")
+		}
+		for _, f := range forwards {
+			ln, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(f.LocalPort)))
+			if err != nil {
+				return err
+			}
+			defer func() { _ = ln.Close() }()
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	args := flag.Args()
+	if err := service.Up(ctx, args[len(args)-1], connection.AWSOptions{}); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func freePorts(t *testing.T, n int) []int {
+	t.Helper()
+	ports := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		ports = append(ports, ln.Addr().(*net.TCPAddr).Port)
+		defer func() { _ = ln.Close() }()
+	}
+	return ports
+}
+
+func detachService(t *testing.T, fail bool) (*tunnel.TunnelService, []int) {
+	t.Helper()
+	ports := freePorts(t, 2)
+	service, _ := newService(t, detachSets(ports))
+
+	portList := make([]string, len(ports))
+	for i, port := range ports {
+		portList[i] = strconv.Itoa(port)
+	}
+	service.Command = func(args ...string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], append([]string{"-test.run=^TestHelperSupervisor$", "--"}, args...)...)
+		cmd.Env = append(os.Environ(),
+			supervisorStateEnv+"="+service.StateDir,
+			supervisorPortsEnv+"="+strings.Join(portList, ","),
+		)
+		if fail {
+			cmd.Env = append(cmd.Env, supervisorFailEnv+"=1")
+		}
+		return cmd
+	}
+	t.Cleanup(func() { _ = service.StopAll() })
+	return service, ports
+}
+
+func TestDetach(t *testing.T) {
+	service, ports := detachService(t, false)
+
+	require.NoError(t, service.Detach("dev", connection.AWSOptions{}))
+
+	tunnels, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, tunnels, 1)
+	assert.Equal(t, "dev", tunnels[0].ID)
+	assert.Equal(t, "ssm", tunnels[0].Method)
+	assert.Equal(t, "i-0abc", tunnels[0].Bastion)
+	assert.Equal(t, []models.TunnelForward{
+		{Local: ports[0], Remote: "10.0.1.5:80"},
+		{Local: ports[1], Remote: "10.0.1.6:80"},
+	}, tunnels[0].Forwards)
+	assert.NotEqual(t, os.Getpid(), tunnels[0].PID)
+	assert.NotEmpty(t, tunnels[0].LogFile)
+
+	err = service.Detach("dev", connection.AWSOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tunnel set dev is already up")
+
+	var logs bytes.Buffer
+	require.NoError(t, service.Logs(context.Background(), "dev", &logs, false))
+	assert.Contains(t, logs.String(), "Bringing up tunnel set dev...")
+
+	require.NoError(t, service.Stop("dev"))
+	tunnels, err = service.List()
+	require.NoError(t, err)
+	assert.Empty(t, tunnels)
+	for _, port := range ports {
+		ln, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+		require.NoError(t, err, "port %d should be released", port)
+		_ = ln.Close()
+	}
+}
+
+func TestDetach_Fails(t *testing.T) {
+	service, _ := detachService(t, true)
+
+	err := service.Detach("dev", connection.AWSOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tunnel set dev failed to start")
+	assert.Contains(t, err.Error(), "connection refused")
+
+	tunnels, err := service.List()
+	require.NoError(t, err)
+	assert.Empty(t, tunnels)
+}
+
+func TestDetach_PortInUse(t *testing.T) {
+	service, ports := detachService(t, false)
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(ports[1])))
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	err = service.Detach("dev", connection.AWSOptions{})
+	assert.EqualError(t, err, fmt.Sprintf("tunnel set dev: local port %d is already in use", ports[1]))
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package tunnel
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in a new session, so that it outlives the terminal.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
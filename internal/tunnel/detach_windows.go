@@ -0,0 +1,18 @@
+//go:build windows
+
+package tunnel
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachedProcess is DETACHED_PROCESS: the process gets no console.
+const detachedProcess = 0x00000008
+
+// detach starts cmd without a console, so that it outlives the terminal.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess,
+	}
+}
@@ -0,0 +1,18 @@
+package tunnel
+
+import (
+	"context"
+	"io"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/models"
+)
+
+type TunnelServiceInterface interface {
+	Up(ctx context.Context, name string, opts connection.AWSOptions) error
+	Detach(name string, opts connection.AWSOptions) error
+	List() ([]models.TunnelProcess, error)
+	Stop(id string) error
+	StopAll() error
+	Logs(ctx context.Context, id string, w io.Writer, follow bool) error
+}
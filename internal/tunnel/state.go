@@ -0,0 +1,321 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	// stopTimeout is how long Stop waits for a tunnel set to exit.
+	stopTimeout = 5 * time.Second
+
+	lockTimeout       = 5 * time.Second
+	lockRetryInterval = 50 * time.Millisecond
+	staleLockAge      = 30 * time.Second
+)
+
+// state is the record of a tunnel set that is up. ProcessStart tells the
+// process that wrote it from a later one that reuses its PID.
+type state struct {
+	models.TunnelProcess
+	ProcessStart int64 `json:"processStart"`
+}
+
+func (s *TunnelService) statePath(name string) string {
+	return filepath.Join(s.StateDir, name+".json")
+}
+
+func (s *TunnelService) logPath(name string) string {
+	return filepath.Join(s.StateDir, name+".log")
+}
+
+// acquire records that the tunnel set of record is up in this process. It
+// fails if another live process holds the set already.
+func (s *TunnelService) acquire(record models.TunnelProcess) (release func(), err error) {
+	if s.StateDir == "" {
+		return func() {}, nil
+	}
+	if err := os.MkdirAll(s.StateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create tunnel state directory: %w", err)
+	}
+
+	unlock, err := s.lockState(record.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if old, err := s.readState(record.ID); err == nil && running(old) {
+		return nil, fmt.Errorf("tunnel set %s is already up (pid %d)", record.ID, old.PID)
+	}
+
+	current := state{TunnelProcess: record}
+	current.PID = os.Getpid()
+	if p, err := process.NewProcess(int32(current.PID)); err == nil {
+		current.ProcessStart, _ = p.CreateTime()
+	}
+	if err := s.writeState(current); err != nil {
+		return nil, fmt.Errorf("failed to write tunnel state: %w", err)
+	}
+
+	return func() { s.removeState(current) }, nil
+}
+
+// lockState acquires an exclusive lock file for the state of the tunnel set
+// name, so that checking whether it is up and recording it happen as one
+// step. Lock files older than staleLockAge are assumed to be left behind and
+// are taken over.
+func (s *TunnelService) lockState(name string) (func(), error) {
+	lockPath := filepath.Join(s.StateDir, name+".lock")
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = lockFile.WriteString(strconv.Itoa(os.Getpid()))
+			_ = lockFile.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock tunnel state of %s: %w", name, err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock on tunnel set %s; remove %s if no other awsctl process is running", name, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// writeState replaces the state file through a temporary file, so that
+// readers never observe a partially written one.
+func (s *TunnelService) writeState(st state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.StateDir, "."+st.ID+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.statePath(st.ID))
+}
+
+// removeState removes the state of a tunnel set if it still is st, and not
+// the record of a process that has brought the set up since.
+func (s *TunnelService) removeState(st state) {
+	unlock, err := s.lockState(st.ID)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	if current, err := s.readState(st.ID); err == nil && (current.PID != st.PID || current.ProcessStart != st.ProcessStart) {
+		return
+	}
+	_ = os.Remove(s.statePath(st.ID))
+}
+
+func (s *TunnelService) readState(name string) (state, error) {
+	var st state
+	data, err := os.ReadFile(s.statePath(name))
+	if err != nil {
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("invalid tunnel state of %s: %w", name, err)
+	}
+	st.ID = name
+	return st, nil
+}
+
+// running reports whether the process that wrote st is still alive.
+func running(st state) bool {
+	p, err := process.NewProcess(int32(st.PID))
+	if err != nil {
+		return false
+	}
+	created, err := p.CreateTime()
+	return err == nil && created == st.ProcessStart
+}
+
+// List returns the tunnel sets that are up, sorted by ID. Records left by
+// processes that are gone are removed.
+func (s *TunnelService) List() ([]models.TunnelProcess, error) {
+	if s.StateDir == "" {
+		return nil, errors.New("no tunnel state directory")
+	}
+	paths, err := filepath.Glob(filepath.Join(s.StateDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	tunnels := []models.TunnelProcess{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		st, err := s.readState(name)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			}
+			continue
+		}
+		if !running(st) {
+			s.removeState(st)
+			continue
+		}
+		tunnels = append(tunnels, st.TunnelProcess)
+	}
+	sort.Slice(tunnels, func(i, j int) bool { return tunnels[i].ID < tunnels[j].ID })
+	return tunnels, nil
+}
+
+// Stop stops the process that brought up the tunnel set id, along with the
+// ssh or session-manager-plugin processes it started.
+func (s *TunnelService) Stop(id string) error {
+	if s.StateDir == "" {
+		return errors.New("no tunnel state directory")
+	}
+	st, err := s.readState(id)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("tunnel set %s is not up", id)
+	}
+	if err != nil {
+		return err
+	}
+	if !running(st) {
+		s.removeState(st)
+		return fmt.Errorf("tunnel set %s is not up (removed stale state of pid %d)", id, st.PID)
+	}
+
+	p, err := process.NewProcess(int32(st.PID))
+	if err != nil {
+		return fmt.Errorf("failed to find tunnel process %d: %w", st.PID, err)
+	}
+	children, _ := p.Children()
+	if err := p.Terminate(); err != nil {
+		return fmt.Errorf("failed to stop tunnel set %s (pid %d): %w", id, st.PID, err)
+	}
+	for _, child := range children {
+		_ = child.Terminate()
+	}
+
+	// Wait for the ports to be released, so that the set can be brought up
+	// again right away.
+	deadline := time.Now().Add(stopTimeout)
+	for running(st) {
+		if time.Now().After(deadline) {
+			_ = p.Kill()
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	// A killed process leaves its state behind.
+	s.removeState(st)
+
+	fmt.Printf("Stopped tunnel set %s (pid %d)\n", id, st.PID)
+	return nil
+}
+
+// StopAll stops every tunnel set that is up.
+func (s *TunnelService) StopAll() error {
+	tunnels, err := s.List()
+	if err != nil {
+		return err
+	}
+	if len(tunnels) == 0 {
+		fmt.Println("No tunnel sets are up.")
+		return nil
+	}
+
+	var errs []error
+	for _, t := range tunnels {
+		if err := s.Stop(t.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Logs writes the output of the tunnel set id, which must have been started
+// with --detach, to w. With follow, new output is written until ctx is done
+// or the tunnel set stops.
+func (s *TunnelService) Logs(ctx context.Context, id string, w io.Writer, follow bool) error {
+	if s.StateDir == "" {
+		return errors.New("no tunnel state directory")
+	}
+	f, err := os.Open(s.logPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no logs for tunnel set %s: logs are kept for sets started with --detach", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open tunnel log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+	if !follow {
+		return nil
+	}
+
+	st, err := s.readState(id)
+	if err != nil {
+		return nil
+	}
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		alive := running(st)
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		if !alive {
+			return nil
+		}
+	}
+}
+
+// tail returns the last n lines of the file at path.
+func tail(path string, n int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
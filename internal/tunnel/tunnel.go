@@ -0,0 +1,316 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/sso/config"
+	"github.com/BerryBytes/awsctl/models"
+	"github.com/BerryBytes/awsctl/utils/common"
+)
+
+// Tunnel set methods as written in the awsctl config.
+const (
+	MethodSSH = "ssh"
+	MethodSSM = "ssm"
+	MethodEIC = "eic"
+)
+
+// TunnelService brings up the tunnel sets defined in the awsctl config file.
+type TunnelService struct {
+	ConnServices connection.ServicesInterface
+	// ConfigureAWS switches to the profile and region of a tunnel set.
+	ConfigureAWS func(opts connection.AWSOptions) error
+	// LoadConfig returns the awsctl config holding the tunnel sets.
+	LoadConfig func() (*models.Config, error)
+	// LookupRDS returns host:port of an RDS instance or cluster.
+	LookupRDS func(ctx context.Context, identifier string) (string, error)
+	// LookupEKS returns the API server endpoint of an EKS cluster.
+	LookupEKS func(ctx context.Context, cluster string) (string, error)
+	// StateDir records which tunnel sets are up, so that they can be listed
+	// and stopped from another terminal, and holds the logs of detached sets.
+	StateDir string
+	// Command returns the awsctl command that Detach runs in the background.
+	Command func(args ...string) *exec.Cmd
+}
+
+func NewTunnelService(
+	connServices connection.ServicesInterface,
+	opts ...func(*TunnelService),
+) *TunnelService {
+	service := &TunnelService{
+		ConnServices: connServices,
+		LoadConfig:   loadConfig,
+		Command:      selfCommand,
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		service.StateDir = filepath.Join(home, ".config", "awsctl", "tunnels")
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+func loadConfig() (*models.Config, error) {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.RawCustomConfig, nil
+}
+
+// Up brings up every forward of the tunnel set name until ctx is done or
+// one of them fails. Profile and region given in opts take precedence over
+// those of the set.
+func (s *TunnelService) Up(ctx context.Context, name string, opts connection.AWSOptions) error {
+	set, err := s.tunnelSet(name)
+	if err != nil {
+		return err
+	}
+	connect, err := connectOptions(set)
+	if err != nil {
+		return fmt.Errorf("tunnel set %s: %w", name, err)
+	}
+
+	if (opts.Profile == "" && set.Profile != "") || (opts.Region == "" && set.Region != "") {
+		if opts.Profile == "" {
+			opts.Profile = set.Profile
+		}
+		if opts.Region == "" {
+			opts.Region = set.Region
+		}
+		if s.ConfigureAWS != nil {
+			if err := s.ConfigureAWS(opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	if connect.Host, err = s.resolveBastion(ctx, set.Bastion); err != nil {
+		return fmt.Errorf("tunnel set %s: %w", name, err)
+	}
+	forwards, err := s.resolveForwards(ctx, set.Forwards)
+	if err != nil {
+		return fmt.Errorf("tunnel set %s: %w", name, err)
+	}
+
+	record := models.TunnelProcess{
+		ID:        name,
+		Method:    strings.ToLower(set.Method),
+		Bastion:   connect.Host,
+		Profile:   opts.Profile,
+		Region:    opts.Region,
+		StartedAt: time.Now(),
+		LogFile:   os.Getenv(logFileEnv),
+	}
+	if record.Profile == "" {
+		record.Profile = os.Getenv("AWS_PROFILE")
+	}
+	for _, f := range forwards {
+		record.Forwards = append(record.Forwards, models.TunnelForward{
+			Local:  f.LocalPort,
+			Remote: net.JoinHostPort(f.RemoteHost, strconv.Itoa(f.RemotePort)),
+		})
+	}
+	release, err := s.acquire(record)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fmt.Printf("Bringing up tunnel set %s...\n", name)
+	s.ConnServices.UseConnectOptions(connect)
+	return s.ConnServices.StartTunnels(ctx, forwards)
+}
+
+func (s *TunnelService) tunnelSet(name string) (models.TunnelSet, error) {
+	cfg, err := s.LoadConfig()
+	if err != nil {
+		return models.TunnelSet{}, fmt.Errorf("failed to load awsctl config: %w", err)
+	}
+
+	set, ok := cfg.Tunnels[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Tunnels))
+		for n := range cfg.Tunnels {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return models.TunnelSet{}, fmt.Errorf("tunnel set %q not found: no tunnels are defined in the awsctl config", name)
+		}
+		return models.TunnelSet{}, fmt.Errorf("tunnel set %q not found, defined sets: %s", name, strings.Join(names, ", "))
+	}
+	return set, nil
+}
+
+// connectOptions returns the bastion connection settings of set, without
+// resolving its bastion selector.
+func connectOptions(set models.TunnelSet) (connection.ConnectOptions, error) {
+	if set.Bastion == "" {
+		return connection.ConnectOptions{}, errors.New("bastion is required")
+	}
+	if len(set.Forwards) == 0 {
+		return connection.ConnectOptions{}, errors.New("no forwards defined")
+	}
+
+	opts := connection.ConnectOptions{
+		User:    set.User,
+		KeyPath: set.Key,
+	}
+	if opts.User == "" {
+		opts.User = "ec2-user"
+	}
+	if opts.KeyPath == "" {
+		opts.KeyPath = "~/.ssh/id_ed25519"
+	}
+
+	method := strings.ToLower(set.Method)
+	switch method {
+	case MethodSSH:
+		opts.Method = connection.MethodSSH
+	case MethodSSM:
+		opts.Method = connection.MethodSSM
+	case MethodEIC:
+		// SSH to an instance ID goes through EC2 Instance Connect.
+		opts.Method = connection.MethodSSH
+	default:
+		return connection.ConnectOptions{}, fmt.Errorf("invalid method %q: must be ssh, ssm or eic", set.Method)
+	}
+	if method != MethodSSH && !isInstanceSelector(set.Bastion) {
+		return connection.ConnectOptions{}, fmt.Errorf("method %s needs an instance ID or name:<Name tag> as bastion", method)
+	}
+	// SSH to an instance goes through EC2 Instance Connect, which has to be
+	// asked for explicitly.
+	if method == MethodSSH && isInstanceSelector(set.Bastion) {
+		return connection.ConnectOptions{}, fmt.Errorf("method ssh needs a host name or IP address as bastion; use eic or ssm for %s", set.Bastion)
+	}
+	return opts, nil
+}
+
+func isInstanceSelector(bastion string) bool {
+	return strings.HasPrefix(bastion, "i-") || strings.HasPrefix(bastion, "name:")
+}
+
+// resolveBastion returns the instance ID of a name:<Name tag> selector, and
+// other bastions unchanged.
+func (s *TunnelService) resolveBastion(ctx context.Context, bastion string) (string, error) {
+	name, ok := strings.CutPrefix(bastion, "name:")
+	if !ok {
+		return bastion, nil
+	}
+	if !s.ConnServices.IsAWSConfigured() {
+		return "", fmt.Errorf("AWS configuration required to find bastion %s", bastion)
+	}
+
+	instances, err := s.ConnServices.ListBastionInstances(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list bastion instances: %w", err)
+	}
+	var matches []string
+	for _, instance := range instances {
+		if instance.Name == name {
+			matches = append(matches, instance.InstanceID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no bastion instance named %s found", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("bastion name %s matches several instances: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+func (s *TunnelService) resolveForwards(ctx context.Context, forwards []models.TunnelForward) ([]connection.Forward, error) {
+	resolved := make([]connection.Forward, 0, len(forwards))
+	seen := make(map[int]bool, len(forwards))
+	for _, f := range forwards {
+		if err := common.ValidatePort(f.Local); err != nil {
+			return nil, fmt.Errorf("invalid local port of %s: %w", f.Remote, err)
+		}
+		if seen[f.Local] {
+			return nil, fmt.Errorf("local port %d is used by more than one forward", f.Local)
+		}
+		seen[f.Local] = true
+
+		host, port, err := s.resolveRemote(ctx, f.Remote)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, connection.Forward{LocalPort: f.Local, RemoteHost: host, RemotePort: port})
+	}
+	return resolved, nil
+}
+
+// resolveRemote returns the host and port of a forward target: host:port,
+// rds:<instance or cluster> or eks:<cluster>.
+func (s *TunnelService) resolveRemote(ctx context.Context, remote string) (string, int, error) {
+	if id, ok := strings.CutPrefix(remote, "rds:"); ok {
+		if s.LookupRDS == nil {
+			return "", 0, fmt.Errorf("cannot look up %s", remote)
+		}
+		endpoint, err := s.LookupRDS(ctx, id)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get endpoint of %s: %w", remote, err)
+		}
+		return splitHostPort(endpoint)
+	}
+
+	if cluster, ok := strings.CutPrefix(remote, "eks:"); ok {
+		if s.LookupEKS == nil {
+			return "", 0, fmt.Errorf("cannot look up %s", remote)
+		}
+		endpoint, err := s.LookupEKS(ctx, cluster)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to get endpoint of %s: %w", remote, err)
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Hostname() == "" {
+			return "", 0, fmt.Errorf("invalid endpoint %q of %s", endpoint, remote)
+		}
+		if u.Port() == "" {
+			return u.Hostname(), 443, nil
+		}
+		return splitHostPort(u.Host)
+	}
+
+	host, port, err := splitHostPort(remote)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid remote %q: %w", remote, err)
+	}
+	return host, port, nil
+}
+
+func splitHostPort(value string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(value)
+	if err != nil {
+		return "", 0, err
+	}
+	if host == "" {
+		return "", 0, fmt.Errorf("missing host in %q", value)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port %q", portStr)
+	}
+	if err := common.ValidatePort(port); err != nil {
+		return "", 0, err
+	}
+	return host, port, nil
+}
@@ -0,0 +1,330 @@
+package tunnel_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	"github.com/BerryBytes/awsctl/internal/tunnel"
+	"github.com/BerryBytes/awsctl/models"
+	mock_awsctl "github.com/BerryBytes/awsctl/tests/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/shirou/gopsutil/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T, sets map[string]models.TunnelSet) (*tunnel.TunnelService, *mock_awsctl.MockServicesInterface) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	conn := mock_awsctl.NewMockServicesInterface(ctrl)
+
+	service := tunnel.NewTunnelService(conn, func(s *tunnel.TunnelService) {
+		s.LoadConfig = func() (*models.Config, error) {
+			return &models.Config{Tunnels: sets}, nil
+		}
+		s.StateDir = t.TempDir()
+	})
+	return service, conn
+}
+
+func TestUp(t *testing.T) {
+	service, conn := newService(t, map[string]models.TunnelSet{
+		"prod-db": {
+			Bastion: "name:prod-bastion",
+			Method:  "ssm",
+			Profile: "prod",
+			Forwards: []models.TunnelForward{
+				{Local: 15432, Remote: "rds:orders"},
+				{Local: 16443, Remote: "eks:prod"},
+				{Local: 16379, Remote: "cache.internal:6379"},
+			},
+		},
+	})
+
+	var configured connection.AWSOptions
+	service.ConfigureAWS = func(opts connection.AWSOptions) error {
+		configured = opts
+		return nil
+	}
+	service.LookupRDS = func(_ context.Context, id string) (string, error) {
+		assert.Equal(t, "orders", id)
+		return "orders.abc.us-east-1.rds.amazonaws.com:5432", nil
+	}
+	service.LookupEKS = func(_ context.Context, cluster string) (string, error) {
+		assert.Equal(t, "prod", cluster)
+		return "https://ABC.gr7.us-east-1.eks.amazonaws.com", nil
+	}
+
+	conn.EXPECT().IsAWSConfigured().Return(true)
+	conn.EXPECT().ListBastionInstances(gomock.Any()).Return([]models.EC2Instance{
+		{InstanceID: "i-0aaa", Name: "dev-bastion"},
+		{InstanceID: "i-0bbb", Name: "prod-bastion"},
+	}, nil)
+	conn.EXPECT().UseConnectOptions(connection.ConnectOptions{
+		Method:  connection.MethodSSM,
+		Host:    "i-0bbb",
+		User:    "ec2-user",
+		KeyPath: "~/.ssh/id_ed25519",
+	})
+	conn.EXPECT().StartTunnels(gomock.Any(), []connection.Forward{
+		{LocalPort: 15432, RemoteHost: "orders.abc.us-east-1.rds.amazonaws.com", RemotePort: 5432},
+		{LocalPort: 16443, RemoteHost: "ABC.gr7.us-east-1.eks.amazonaws.com", RemotePort: 443},
+		{LocalPort: 16379, RemoteHost: "cache.internal", RemotePort: 6379},
+	}).DoAndReturn(func(context.Context, []connection.Forward) error {
+		_, err := os.Stat(filepath.Join(service.StateDir, "prod-db.json"))
+		assert.NoError(t, err, "state should be recorded while the tunnels are up")
+		return nil
+	})
+
+	err := service.Up(context.Background(), "prod-db", connection.AWSOptions{Region: "eu-west-1"})
+	require.NoError(t, err)
+	assert.Equal(t, connection.AWSOptions{Profile: "prod", Region: "eu-west-1"}, configured)
+
+	_, err = os.Stat(filepath.Join(service.StateDir, "prod-db.json"))
+	assert.True(t, errors.Is(err, os.ErrNotExist), "state should be removed when the tunnels stop")
+}
+
+func TestUp_EIC(t *testing.T) {
+	service, conn := newService(t, map[string]models.TunnelSet{
+		"dev": {
+			Bastion:  "i-0abc",
+			Method:   "eic",
+			User:     "ubuntu",
+			Key:      "~/.ssh/dev",
+			Forwards: []models.TunnelForward{{Local: 8080, Remote: "10.0.1.5:80"}},
+		},
+	})
+
+	conn.EXPECT().UseConnectOptions(connection.ConnectOptions{
+		Method:  connection.MethodSSH,
+		Host:    "i-0abc",
+		User:    "ubuntu",
+		KeyPath: "~/.ssh/dev",
+	})
+	conn.EXPECT().StartTunnels(gomock.Any(), []connection.Forward{
+		{LocalPort: 8080, RemoteHost: "10.0.1.5", RemotePort: 80},
+	}).Return(nil)
+
+	assert.NoError(t, service.Up(context.Background(), "dev", connection.AWSOptions{}))
+}
+
+func TestUp_Errors(t *testing.T) {
+	fwd := []models.TunnelForward{{Local: 8080, Remote: "10.0.1.5:80"}}
+
+	tests := []struct {
+		name    string
+		set     models.TunnelSet
+		setup   func(*mock_awsctl.MockServicesInterface)
+		wantErr string
+	}{
+		{
+			name:    "invalid method",
+			set:     models.TunnelSet{Bastion: "bastion.example.com", Method: "telnet", Forwards: fwd},
+			wantErr: `invalid method "telnet"`,
+		},
+		{
+			name:    "ssm with host",
+			set:     models.TunnelSet{Bastion: "bastion.example.com", Method: "ssm", Forwards: fwd},
+			wantErr: "method ssm needs an instance ID",
+		},
+		{
+			name:    "ssh with instance",
+			set:     models.TunnelSet{Bastion: "name:bastion", Method: "ssh", Forwards: fwd},
+			wantErr: "method ssh needs a host name or IP address as bastion; use eic or ssm for name:bastion",
+		},
+		{
+			name:    "no forwards",
+			set:     models.TunnelSet{Bastion: "i-0abc", Method: "ssm"},
+			wantErr: "no forwards defined",
+		},
+		{
+			name: "duplicate local port",
+			set: models.TunnelSet{Bastion: "i-0abc", Method: "ssm", Forwards: []models.TunnelForward{
+				{Local: 8080, Remote: "10.0.1.5:80"},
+				{Local: 8080, Remote: "10.0.1.6:80"},
+			}},
+			wantErr: "local port 8080 is used by more than one forward",
+		},
+		{
+			name:    "invalid remote",
+			set:     models.TunnelSet{Bastion: "i-0abc", Method: "ssm", Forwards: []models.TunnelForward{{Local: 8080, Remote: "10.0.1.5"}}},
+			wantErr: `invalid remote "10.0.1.5"`,
+		},
+		{
+			name: "ambiguous bastion name",
+			set:  models.TunnelSet{Bastion: "name:bastion", Method: "ssm", Forwards: fwd},
+			setup: func(conn *mock_awsctl.MockServicesInterface) {
+				conn.EXPECT().IsAWSConfigured().Return(true)
+				conn.EXPECT().ListBastionInstances(gomock.Any()).Return([]models.EC2Instance{
+					{InstanceID: "i-0aaa", Name: "bastion"},
+					{InstanceID: "i-0bbb", Name: "bastion"},
+				}, nil)
+			},
+			wantErr: "bastion name bastion matches several instances: i-0aaa, i-0bbb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, conn := newService(t, map[string]models.TunnelSet{"set": tt.set})
+			if tt.setup != nil {
+				tt.setup(conn)
+			}
+
+			err := service.Up(context.Background(), "set", connection.AWSOptions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestUp_UnknownSet(t *testing.T) {
+	service, _ := newService(t, map[string]models.TunnelSet{"b": {}, "a": {}})
+
+	err := service.Up(context.Background(), "c", connection.AWSOptions{})
+	assert.EqualError(t, err, `tunnel set "c" not found, defined sets: a, b`)
+}
+
+// startSleeper starts a process standing in for a running tunnel set and
+// records it as the owner of name. The returned channel is closed when the
+// process exits.
+func startSleeper(t *testing.T, service *tunnel.TunnelService, name string) <-chan struct{} {
+	t.Helper()
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	p, err := process.NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+	created, err := p.CreateTime()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]int64{"pid": int64(cmd.Process.Pid), "processStart": created})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(service.StateDir, name+".json"), data, 0600))
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return exited
+}
+
+func TestUp_AlreadyUp(t *testing.T) {
+	service, _ := newService(t, map[string]models.TunnelSet{
+		"dev": {Bastion: "i-0abc", Method: "ssm", Forwards: []models.TunnelForward{{Local: 8080, Remote: "10.0.1.5:80"}}},
+	})
+	startSleeper(t, service, "dev")
+
+	err := service.Up(context.Background(), "dev", connection.AWSOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tunnel set dev is already up")
+}
+
+func TestUp_Concurrent(t *testing.T) {
+	service, conn := newService(t, map[string]models.TunnelSet{
+		"dev": {Bastion: "i-0abc", Method: "ssm", Forwards: []models.TunnelForward{{Local: 8080, Remote: "10.0.1.5:80"}}},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	conn.EXPECT().UseConnectOptions(gomock.Any())
+	conn.EXPECT().StartTunnels(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ []connection.Forward) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+	for range attempts {
+		go func() { errs <- service.Up(ctx, "dev", connection.AWSOptions{}) }()
+	}
+
+	for range attempts - 1 {
+		err := <-errs
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tunnel set dev is already up")
+	}
+	cancel()
+	assert.NoError(t, <-errs)
+}
+
+func TestStop(t *testing.T) {
+	service, _ := newService(t, nil)
+	exited := startSleeper(t, service, "dev")
+
+	require.NoError(t, service.Stop("dev"))
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tunnel process was not stopped")
+	}
+	_, err := os.Stat(filepath.Join(service.StateDir, "dev.json"))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestStop_NotUp(t *testing.T) {
+	service, _ := newService(t, nil)
+
+	err := service.Stop("dev")
+	assert.EqualError(t, err, "tunnel set dev is not up")
+
+	stale := filepath.Join(service.StateDir, "dev.json")
+	require.NoError(t, os.WriteFile(stale, []byte(`{"pid":1,"processStart":1}`), 0600))
+	err = service.Stop("dev")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "removed stale state")
+	_, err = os.Stat(stale)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestList(t *testing.T) {
+	service, _ := newService(t, nil)
+	startSleeper(t, service, "prod")
+	startSleeper(t, service, "dev")
+	stale := filepath.Join(service.StateDir, "old.json")
+	require.NoError(t, os.WriteFile(stale, []byte(`{"pid":1,"processStart":1}`), 0600))
+
+	tunnels, err := service.List()
+	require.NoError(t, err)
+	require.Len(t, tunnels, 2)
+	assert.Equal(t, "dev", tunnels[0].ID)
+	assert.Equal(t, "prod", tunnels[1].ID)
+
+	_, err = os.Stat(stale)
+	assert.True(t, errors.Is(err, os.ErrNotExist), "stale state should be removed")
+}
+
+func TestStopAll(t *testing.T) {
+	service, _ := newService(t, nil)
+	startSleeper(t, service, "prod")
+	startSleeper(t, service, "dev")
+
+	require.NoError(t, service.StopAll())
+	tunnels, err := service.List()
+	require.NoError(t, err)
+	assert.Empty(t, tunnels)
+}
+
+func TestLogs(t *testing.T) {
+	service, _ := newService(t, nil)
+
+	var out bytes.Buffer
+	err := service.Logs(context.Background(), "dev", &out, false)
+	assert.EqualError(t, err, "no logs for tunnel set dev: logs are kept for sets started with --detach")
+
+	require.NoError(t, os.WriteFile(filepath.Join(service.StateDir, "dev.log"), []byte("Bringing up tunnel set dev...\n"), 0600))
+	require.NoError(t, service.Logs(context.Background(), "dev", &out, true))
+	assert.Equal(t, "Bringing up tunnel set dev...\n", out.String())
+}
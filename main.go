@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	promptInfoCmd "github.com/BerryBytes/awsctl/cmd/promptinfo"
 	"github.com/BerryBytes/awsctl/cmd/root"
 	"github.com/BerryBytes/awsctl/internal/bastion"
 	connection "github.com/BerryBytes/awsctl/internal/common"
@@ -12,6 +14,7 @@ import (
 	"github.com/BerryBytes/awsctl/internal/eks"
 	"github.com/BerryBytes/awsctl/internal/rds"
 	"github.com/BerryBytes/awsctl/internal/sso"
+	"github.com/BerryBytes/awsctl/internal/tunnel"
 	"github.com/BerryBytes/awsctl/utils/common"
 	generalutils "github.com/BerryBytes/awsctl/utils/general"
 	promptUtils "github.com/BerryBytes/awsctl/utils/prompt"
@@ -19,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/spf13/cobra"
 )
 
 var Version = "0.2.0"
@@ -31,6 +35,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	// prompt-info runs on every shell prompt and only reads local files, so
+	// the AWS SDK and service setup below is skipped for it.
+	if len(os.Args) > 1 && os.Args[1] == promptInfoCmd.CommandName {
+		execute(root.NewRootCmd(root.RootDependencies{
+			SSOSetupClient: ssoSetupClient,
+			Version:        Version,
+		}))
+		return
+	}
+
 	generalManager := generalutils.NewGeneralUtilsManager()
 	fileSystem := &common.RealFileSystem{}
 	gPrompter := promptUtils.NewPrompt()
@@ -89,6 +103,34 @@ func main() {
 			s.Prompt = gPrompter
 		},
 	)
+	configureAWS := func(opts connection.AWSOptions) error {
+		if err := services.UseAWSOptions(ctx, opts); err != nil {
+			return err
+		}
+		rdsSvc.AWSOptions = opts
+		eksSvc.AWSOptions = opts
+		ecrSvc.AWSOptions = opts
+		eksSvc.EKSClient = eks.NewEKSClient(provider.AwsConfig, fileSystem)
+		return nil
+	}
+
+	tunnelSvc := tunnel.NewTunnelService(
+		services,
+		func(s *tunnel.TunnelService) {
+			s.ConfigureAWS = configureAWS
+			s.LookupRDS = func(ctx context.Context, identifier string) (string, error) {
+				return rds.NewRDSClient(provider.AwsConfig, &common.RealCommandExecutor{}).GetConnectionEndpoint(ctx, identifier)
+			}
+			s.LookupEKS = func(ctx context.Context, cluster string) (string, error) {
+				details, err := eks.NewEKSClient(provider.AwsConfig, fileSystem).GetClusterDetails(ctx, cluster)
+				if err != nil {
+					return "", err
+				}
+				return details.Endpoint, nil
+			}
+		},
+	)
+
 	rootCmd := root.NewRootCmd(root.RootDependencies{
 		SSOSetupClient: ssoSetupClient,
 		BastionService: bastionSvc,
@@ -97,9 +139,19 @@ func main() {
 		RDSService:     rdsSvc,
 		EKSService:     eksSvc,
 		ECRService:     ecrSvc,
+		TunnelService:  tunnelSvc,
 		Version:        Version,
+		ConfigureAWS:   configureAWS,
 	})
+	execute(rootCmd)
+}
+
+func execute(rootCmd *cobra.Command) {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *common.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
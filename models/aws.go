@@ -8,12 +8,24 @@ type AWSCredentials struct {
 	Expiration      string `json:"expiration" yaml:"expiration"`
 }
 
-// RoleCredentialsResponse is the structure to parse the role credentials response from AWS SSO.
-type RoleCredentialsResponse struct {
-	RoleCredentials struct {
-		AccessKeyID     string `json:"accessKeyId"`
-		SecretAccessKey string `json:"secretAccessKey"`
-		SessionToken    string `json:"sessionToken"`
-		Expiration      int64  `json:"expiration"`
-	} `json:"roleCredentials"`
+// CredentialProcessOutput is the JSON document a credential_process prints,
+// see https://docs.aws.amazon.com/sdkref/latest/guide/feature-process-credentials.html.
+type CredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken,omitempty"`
+	Expiration      string `json:"Expiration,omitempty"`
+}
+
+// RoleCredentialCache is the ~/.aws/cli/cache file format the AWS CLI uses for
+// SSO role credentials.
+type RoleCredentialCache struct {
+	ProviderType string `json:"ProviderType"`
+	Credentials  struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string `json:"SecretAccessKey"`
+		SessionToken    string `json:"SessionToken"`
+		Expiration      string `json:"Expiration"`
+	} `json:"Credentials"`
 }
@@ -14,6 +14,7 @@ type TokenCache struct {
 
 type SSOCache struct {
 	AccessToken           *string `json:"accessToken"`
+	RefreshToken          *string `json:"refreshToken,omitempty"`
 	ExpiresAt             *string `json:"expiresAt"`
 	StartURL              *string `json:"startUrl"`
 	SessionName           *string `json:"sessionName,omitempty"`
@@ -1,12 +1,12 @@
 package models
 
 type EC2Instance struct {
-	InstanceID       string
-	Name             string
-	PublicIPAddress  string
-	PrivateIPAddress string
-	State            string
-	InstanceType     string
-	AZ               string
-	Tags             map[string]string
+	InstanceID       string            `json:"instanceId" yaml:"instanceId"`
+	Name             string            `json:"name" yaml:"name"`
+	PublicIPAddress  string            `json:"publicIpAddress" yaml:"publicIpAddress"`
+	PrivateIPAddress string            `json:"privateIpAddress" yaml:"privateIpAddress"`
+	State            string            `json:"state" yaml:"state"`
+	InstanceType     string            `json:"instanceType" yaml:"instanceType"`
+	AZ               string            `json:"availabilityZone" yaml:"availabilityZone"`
+	Tags             map[string]string `json:"tags" yaml:"tags"`
 }
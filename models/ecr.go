@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ECRRepository is an ECR repository of a registry.
+type ECRRepository struct {
+	RepositoryName     string    `json:"repositoryName" yaml:"repositoryName"`
+	RepositoryURI      string    `json:"repositoryUri" yaml:"repositoryUri"`
+	RegistryID         string    `json:"registryId" yaml:"registryId"`
+	ImageTagMutability string    `json:"imageTagMutability" yaml:"imageTagMutability"`
+	CreatedAt          time.Time `json:"createdAt" yaml:"createdAt"`
+}
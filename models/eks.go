@@ -1,8 +1,8 @@
 package models
 
 type EKSCluster struct {
-	ClusterName              string
-	Endpoint                 string
-	Region                   string
-	CertificateAuthorityData string
+	ClusterName              string `json:"clusterName" yaml:"clusterName"`
+	Endpoint                 string `json:"endpoint" yaml:"endpoint"`
+	Region                   string `json:"region" yaml:"region"`
+	CertificateAuthorityData string `json:"certificateAuthorityData" yaml:"certificateAuthorityData"`
 }
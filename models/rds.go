@@ -1,7 +1,7 @@
 package models
 
 type RDSInstance struct {
-	DBInstanceIdentifier string
-	Engine               string
-	Endpoint             string
+	DBInstanceIdentifier string `json:"dbInstanceIdentifier" yaml:"dbInstanceIdentifier"`
+	Engine               string `json:"engine" yaml:"engine"`
+	Endpoint             string `json:"endpoint" yaml:"endpoint"`
 }
@@ -21,6 +21,27 @@ type SSOProfile struct {
 
 type Config struct {
 	SSOSessions []SSOSession `yaml:"ssoSessions" json:"ssoSessions"`
+	// TokenRefreshWindowMinutes refreshes cached SSO tokens once fewer than
+	// this many minutes remain before they expire.
+	TokenRefreshWindowMinutes int `yaml:"tokenRefreshWindowMinutes,omitempty" json:"tokenRefreshWindowMinutes,omitempty"`
+	// SetDefaultProfile makes `sso init` and `sso setup` write the selected
+	// profile to [default], as they did before `sso use` existed.
+	SetDefaultProfile bool `yaml:"setDefaultProfile,omitempty" json:"setDefaultProfile,omitempty"`
+	// ProfileNameTemplate is a text/template for the names of profiles
+	// created by `sso setup`. Empty keeps <session>-<account>-<role> names.
+	ProfileNameTemplate string `yaml:"profileNameTemplate,omitempty" json:"profileNameTemplate,omitempty"`
+	// Accounts holds per-account naming settings keyed by account ID.
+	Accounts map[string]AccountSettings `yaml:"accounts,omitempty" json:"accounts,omitempty"`
+	// Tunnels holds the tunnel sets of `awsctl tunnel up`, keyed by name.
+	Tunnels map[string]TunnelSet `yaml:"tunnels,omitempty" json:"tunnels,omitempty"`
+}
+
+// AccountSettings overrides how the profiles of an account are named.
+type AccountSettings struct {
+	// Alias replaces the account name in profile names.
+	Alias string `yaml:"alias,omitempty" json:"alias,omitempty"`
+	// Tags are available to profileNameTemplate as .Tags, e.g. {{.Tags.env}}.
+	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
 // SSOSession represents an AWS SSO session configuration.
@@ -31,9 +52,47 @@ type SSOSession struct {
 	Scopes   string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 }
 
-type RoleCredentials struct {
-	AccessKeyID     string `json:"accessKeyId"`
-	SecretAccessKey string `json:"secretAccessKey"`
-	SessionToken    string `json:"sessionToken"`
-	Expiration      int64  `json:"expiration"`
+// SSOSessionInfo describes an SSO session of the awsctl config file or
+// ~/.aws/config, and the profiles that use it.
+type SSOSessionInfo struct {
+	Name     string `json:"name" yaml:"name"`
+	StartURL string `json:"startUrl" yaml:"startUrl"`
+	Region   string `json:"region" yaml:"region"`
+	Scopes   string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// InConfig is set when the session is in the awsctl config file.
+	InConfig bool `json:"inConfig" yaml:"inConfig"`
+	// InAWSConfig is set when ~/.aws/config has an [sso-session] block for it.
+	InAWSConfig bool     `json:"inAwsConfig" yaml:"inAwsConfig"`
+	Profiles    []string `json:"profiles" yaml:"profiles"`
+}
+
+// ProfileStatus describes the SSO token state of an AWS profile.
+type ProfileStatus struct {
+	Profile       string `json:"profile" yaml:"profile"`
+	SSOSession    string `json:"ssoSession,omitempty" yaml:"ssoSession,omitempty"`
+	StartURL      string `json:"startUrl,omitempty" yaml:"startUrl,omitempty"`
+	AccountID     string `json:"accountId,omitempty" yaml:"accountId,omitempty"`
+	Role          string `json:"role,omitempty" yaml:"role,omitempty"`
+	Region        string `json:"region,omitempty" yaml:"region,omitempty"`
+	ExpiresAt     string `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	Status        string `json:"status" yaml:"status"`
+	Valid         bool   `json:"valid" yaml:"valid"`
+	Identity      string `json:"identity,omitempty" yaml:"identity,omitempty"`
+	IdentityError string `json:"identityError,omitempty" yaml:"identityError,omitempty"`
+}
+
+// PromptInfo describes the active profile for a shell prompt segment.
+type PromptInfo struct {
+	Profile    string `json:"profile" yaml:"profile"`
+	AccountID  string `json:"accountId,omitempty" yaml:"accountId,omitempty"`
+	Alias      string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Role       string `json:"role,omitempty" yaml:"role,omitempty"`
+	Region     string `json:"region,omitempty" yaml:"region,omitempty"`
+	SSOSession string `json:"ssoSession,omitempty" yaml:"ssoSession,omitempty"`
+	ExpiresAt  string `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	// MinutesLeft is the number of whole minutes until ExpiresAt, or 0 once
+	// it has passed.
+	MinutesLeft int    `json:"minutesLeft" yaml:"minutesLeft"`
+	Status      string `json:"status" yaml:"status"`
+	Valid       bool   `json:"valid" yaml:"valid"`
 }
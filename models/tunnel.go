@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// TunnelSet is a named group of port forwards through one bastion host.
+type TunnelSet struct {
+	// Bastion is an instance ID, a host name or IP, or name:<Name tag> of a
+	// bastion instance.
+	Bastion string `yaml:"bastion" json:"bastion"`
+	// Method is ssh, ssm or eic (SSH through EC2 Instance Connect).
+	Method  string `yaml:"method" json:"method"`
+	User    string `yaml:"user,omitempty" json:"user,omitempty"`
+	Key     string `yaml:"key,omitempty" json:"key,omitempty"`
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Region  string `yaml:"region,omitempty" json:"region,omitempty"`
+
+	Forwards []TunnelForward `yaml:"forwards" json:"forwards"`
+}
+
+// TunnelForward forwards a local port to Remote, which is host:port,
+// rds:<instance or cluster> or eks:<cluster>.
+type TunnelForward struct {
+	Local  int    `yaml:"local" json:"local"`
+	Remote string `yaml:"remote" json:"remote"`
+}
+
+// TunnelProcess is a tunnel set that is up, as recorded in the tunnel state
+// directory.
+type TunnelProcess struct {
+	// ID is the name of the tunnel set.
+	ID      string `yaml:"id" json:"id"`
+	PID     int    `yaml:"pid" json:"pid"`
+	Method  string `yaml:"method" json:"method"`
+	Bastion string `yaml:"bastion" json:"bastion"`
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Region  string `yaml:"region,omitempty" json:"region,omitempty"`
+	// Forwards holds the remotes as resolved host:port.
+	Forwards  []TunnelForward `yaml:"forwards" json:"forwards"`
+	StartedAt time.Time       `yaml:"startedAt" json:"startedAt"`
+	// LogFile is the output of a tunnel set started with --detach.
+	LogFile string `yaml:"logFile,omitempty" json:"logFile,omitempty"`
+}
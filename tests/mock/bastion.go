@@ -8,6 +8,8 @@ import (
 	context "context"
 	reflect "reflect"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	models "github.com/BerryBytes/awsctl/models"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,6 +36,21 @@ func (m *MockBastionServiceInterface) EXPECT() *MockBastionServiceInterfaceMockR
 	return m.recorder
 }
 
+// ListInstances mocks base method.
+func (m *MockBastionServiceInterface) ListInstances(ctx context.Context) ([]models.EC2Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances", ctx)
+	ret0, _ := ret[0].([]models.EC2Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockBastionServiceInterfaceMockRecorder) ListInstances(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockBastionServiceInterface)(nil).ListInstances), ctx)
+}
+
 // Run mocks base method.
 func (m *MockBastionServiceInterface) Run(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -47,3 +64,45 @@ func (mr *MockBastionServiceInterfaceMockRecorder) Run(ctx interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockBastionServiceInterface)(nil).Run), ctx)
 }
+
+// SSHIntoBastion mocks base method.
+func (m *MockBastionServiceInterface) SSHIntoBastion(ctx context.Context, connect connection.ConnectOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSHIntoBastion", ctx, connect)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SSHIntoBastion indicates an expected call of SSHIntoBastion.
+func (mr *MockBastionServiceInterfaceMockRecorder) SSHIntoBastion(ctx, connect interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSHIntoBastion", reflect.TypeOf((*MockBastionServiceInterface)(nil).SSHIntoBastion), ctx, connect)
+}
+
+// StartPortForwarding mocks base method.
+func (m *MockBastionServiceInterface) StartPortForwarding(ctx context.Context, connect connection.ConnectOptions, localPort int, remoteHost string, remotePort int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartPortForwarding", ctx, connect, localPort, remoteHost, remotePort)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartPortForwarding indicates an expected call of StartPortForwarding.
+func (mr *MockBastionServiceInterfaceMockRecorder) StartPortForwarding(ctx, connect, localPort, remoteHost, remotePort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartPortForwarding", reflect.TypeOf((*MockBastionServiceInterface)(nil).StartPortForwarding), ctx, connect, localPort, remoteHost, remotePort)
+}
+
+// StartSOCKSProxy mocks base method.
+func (m *MockBastionServiceInterface) StartSOCKSProxy(ctx context.Context, connect connection.ConnectOptions, port int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSOCKSProxy", ctx, connect, port)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartSOCKSProxy indicates an expected call of StartSOCKSProxy.
+func (mr *MockBastionServiceInterfaceMockRecorder) StartSOCKSProxy(ctx, connect, port interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSOCKSProxy", reflect.TypeOf((*MockBastionServiceInterface)(nil).StartSOCKSProxy), ctx, connect, port)
+}
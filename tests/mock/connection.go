@@ -6,8 +6,11 @@ package mock_awsctl
 
 import (
 	context "context"
+	io "io"
+	net "net"
 	reflect "reflect"
 
+	connection "github.com/BerryBytes/awsctl/internal/common"
 	models "github.com/BerryBytes/awsctl/models"
 	aws "github.com/aws/aws-sdk-go-v2/aws"
 	ec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -461,6 +464,21 @@ func (mr *MockServicesInterfaceMockRecorder) IsAWSConfigured() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAWSConfigured", reflect.TypeOf((*MockServicesInterface)(nil).IsAWSConfigured))
 }
 
+// ListBastionInstances mocks base method.
+func (m *MockServicesInterface) ListBastionInstances(ctx context.Context) ([]models.EC2Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBastionInstances", ctx)
+	ret0, _ := ret[0].([]models.EC2Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBastionInstances indicates an expected call of ListBastionInstances.
+func (mr *MockServicesInterfaceMockRecorder) ListBastionInstances(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBastionInstances", reflect.TypeOf((*MockServicesInterface)(nil).ListBastionInstances), ctx)
+}
+
 // SSHIntoBastion mocks base method.
 func (m *MockServicesInterface) SSHIntoBastion(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -505,6 +523,219 @@ func (mr *MockServicesInterfaceMockRecorder) StartSOCKSProxy(ctx, port interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSOCKSProxy", reflect.TypeOf((*MockServicesInterface)(nil).StartSOCKSProxy), ctx, port)
 }
 
+// StartTunnels mocks base method.
+func (m *MockServicesInterface) StartTunnels(ctx context.Context, forwards []connection.Forward) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartTunnels", ctx, forwards)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartTunnels indicates an expected call of StartTunnels.
+func (mr *MockServicesInterfaceMockRecorder) StartTunnels(ctx, forwards interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTunnels", reflect.TypeOf((*MockServicesInterface)(nil).StartTunnels), ctx, forwards)
+}
+
+// UseConnectOptions mocks base method.
+func (m *MockServicesInterface) UseConnectOptions(opts connection.ConnectOptions) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UseConnectOptions", opts)
+}
+
+// UseConnectOptions indicates an expected call of UseConnectOptions.
+func (mr *MockServicesInterfaceMockRecorder) UseConnectOptions(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseConnectOptions", reflect.TypeOf((*MockServicesInterface)(nil).UseConnectOptions), opts)
+}
+
+// MockSSHClient is a mock of SSHClient interface.
+type MockSSHClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSHClientMockRecorder
+}
+
+// MockSSHClientMockRecorder is the mock recorder for MockSSHClient.
+type MockSSHClientMockRecorder struct {
+	mock *MockSSHClient
+}
+
+// NewMockSSHClient creates a new mock instance.
+func NewMockSSHClient(ctrl *gomock.Controller) *MockSSHClient {
+	mock := &MockSSHClient{ctrl: ctrl}
+	mock.recorder = &MockSSHClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSHClient) EXPECT() *MockSSHClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSSHClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSSHClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSSHClient)(nil).Close))
+}
+
+// Forward mocks base method.
+func (m *MockSSHClient) Forward(ctx context.Context, ln net.Listener, remoteAddr string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, ln, remoteAddr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockSSHClientMockRecorder) Forward(ctx, ln, remoteAddr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockSSHClient)(nil).Forward), ctx, ln, remoteAddr)
+}
+
+// SOCKS mocks base method.
+func (m *MockSSHClient) SOCKS(ctx context.Context, ln net.Listener) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SOCKS", ctx, ln)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SOCKS indicates an expected call of SOCKS.
+func (mr *MockSSHClientMockRecorder) SOCKS(ctx, ln interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SOCKS", reflect.TypeOf((*MockSSHClient)(nil).SOCKS), ctx, ln)
+}
+
+// Shell mocks base method.
+func (m *MockSSHClient) Shell(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shell", ctx, stdin, stdout, stderr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shell indicates an expected call of Shell.
+func (mr *MockSSHClientMockRecorder) Shell(ctx, stdin, stdout, stderr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shell", reflect.TypeOf((*MockSSHClient)(nil).Shell), ctx, stdin, stdout, stderr)
+}
+
+// MockSSMDataChannel is a mock of SSMDataChannel interface.
+type MockSSMDataChannel struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSMDataChannelMockRecorder
+}
+
+// MockSSMDataChannelMockRecorder is the mock recorder for MockSSMDataChannel.
+type MockSSMDataChannelMockRecorder struct {
+	mock *MockSSMDataChannel
+}
+
+// NewMockSSMDataChannel creates a new mock instance.
+func NewMockSSMDataChannel(ctrl *gomock.Controller) *MockSSMDataChannel {
+	mock := &MockSSMDataChannel{ctrl: ctrl}
+	mock.recorder = &MockSSMDataChannelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSMDataChannel) EXPECT() *MockSSMDataChannelMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSSMDataChannel) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSSMDataChannelMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSSMDataChannel)(nil).Close))
+}
+
+// Conn mocks base method.
+func (m *MockSSMDataChannel) Conn(ctx context.Context) (net.Conn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Conn", ctx)
+	ret0, _ := ret[0].(net.Conn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Conn indicates an expected call of Conn.
+func (mr *MockSSMDataChannelMockRecorder) Conn(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conn", reflect.TypeOf((*MockSSMDataChannel)(nil).Conn), ctx)
+}
+
+// CustomerMessage mocks base method.
+func (m *MockSSMDataChannel) CustomerMessage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CustomerMessage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CustomerMessage indicates an expected call of CustomerMessage.
+func (mr *MockSSMDataChannelMockRecorder) CustomerMessage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomerMessage", reflect.TypeOf((*MockSSMDataChannel)(nil).CustomerMessage))
+}
+
+// Done mocks base method.
+func (m *MockSSMDataChannel) Done() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Done")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Done indicates an expected call of Done.
+func (mr *MockSSMDataChannelMockRecorder) Done() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Done", reflect.TypeOf((*MockSSMDataChannel)(nil).Done))
+}
+
+// Forward mocks base method.
+func (m *MockSSMDataChannel) Forward(ctx context.Context, conn net.Conn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, conn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockSSMDataChannelMockRecorder) Forward(ctx, conn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockSSMDataChannel)(nil).Forward), ctx, conn)
+}
+
+// Shell mocks base method.
+func (m *MockSSMDataChannel) Shell(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shell", ctx, stdin, stdout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Shell indicates an expected call of Shell.
+func (mr *MockSSMDataChannelMockRecorder) Shell(ctx, stdin, stdout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shell", reflect.TypeOf((*MockSSMDataChannel)(nil).Shell), ctx, stdin, stdout)
+}
+
 // MockAWSConfigLoader is a mock of AWSConfigLoader interface.
 type MockAWSConfigLoader struct {
 	ctrl     *gomock.Controller
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	ecr "github.com/BerryBytes/awsctl/internal/ecr"
+	models "github.com/BerryBytes/awsctl/models"
 	common "github.com/BerryBytes/awsctl/utils/common"
 	aws "github.com/aws/aws-sdk-go-v2/aws"
 	config "github.com/aws/aws-sdk-go-v2/config"
@@ -39,6 +40,26 @@ func (m *MockECRAPI) EXPECT() *MockECRAPIMockRecorder {
 	return m.recorder
 }
 
+// DescribeRepositories mocks base method.
+func (m *MockECRAPI) DescribeRepositories(ctx context.Context, params *ecr0.DescribeRepositoriesInput, optFns ...func(*ecr0.Options)) (*ecr0.DescribeRepositoriesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeRepositories", varargs...)
+	ret0, _ := ret[0].(*ecr0.DescribeRepositoriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeRepositories indicates an expected call of DescribeRepositories.
+func (mr *MockECRAPIMockRecorder) DescribeRepositories(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRepositories", reflect.TypeOf((*MockECRAPI)(nil).DescribeRepositories), varargs...)
+}
+
 // GetAuthorizationToken mocks base method.
 func (m *MockECRAPI) GetAuthorizationToken(ctx context.Context, params *ecr0.GetAuthorizationTokenInput, optFns ...func(*ecr0.Options)) (*ecr0.GetAuthorizationTokenOutput, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +103,21 @@ func (m *MockECRAdapterInterface) EXPECT() *MockECRAdapterInterfaceMockRecorder
 	return m.recorder
 }
 
+// ListRepositories mocks base method.
+func (m *MockECRAdapterInterface) ListRepositories(ctx context.Context) ([]models.ECRRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRepositories", ctx)
+	ret0, _ := ret[0].([]models.ECRRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRepositories indicates an expected call of ListRepositories.
+func (mr *MockECRAdapterInterfaceMockRecorder) ListRepositories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepositories", reflect.TypeOf((*MockECRAdapterInterface)(nil).ListRepositories), ctx)
+}
+
 // Login mocks base method.
 func (m *MockECRAdapterInterface) Login(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -96,6 +132,20 @@ func (mr *MockECRAdapterInterfaceMockRecorder) Login(ctx interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockECRAdapterInterface)(nil).Login), ctx)
 }
 
+// LoginRegistry mocks base method.
+func (m *MockECRAdapterInterface) LoginRegistry(ctx context.Context, registry string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginRegistry", ctx, registry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoginRegistry indicates an expected call of LoginRegistry.
+func (mr *MockECRAdapterInterfaceMockRecorder) LoginRegistry(ctx, registry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginRegistry", reflect.TypeOf((*MockECRAdapterInterface)(nil).LoginRegistry), ctx, registry)
+}
+
 // MockConfigLoader is a mock of ConfigLoader interface.
 type MockConfigLoader struct {
 	ctrl     *gomock.Controller
@@ -162,6 +212,35 @@ func (m *MockECRServiceInterface) EXPECT() *MockECRServiceInterfaceMockRecorder
 	return m.recorder
 }
 
+// ListRepositories mocks base method.
+func (m *MockECRServiceInterface) ListRepositories() ([]models.ECRRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRepositories")
+	ret0, _ := ret[0].([]models.ECRRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRepositories indicates an expected call of ListRepositories.
+func (mr *MockECRServiceInterfaceMockRecorder) ListRepositories() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepositories", reflect.TypeOf((*MockECRServiceInterface)(nil).ListRepositories))
+}
+
+// Login mocks base method.
+func (m *MockECRServiceInterface) Login(opts ecr.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockECRServiceInterfaceMockRecorder) Login(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockECRServiceInterface)(nil).Login), opts)
+}
+
 // Run mocks base method.
 func (m *MockECRServiceInterface) Run() error {
 	m.ctrl.T.Helper()
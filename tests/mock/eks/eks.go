@@ -40,6 +40,21 @@ func (m *MockEKSServiceInterface) EXPECT() *MockEKSServiceInterfaceMockRecorder
 	return m.recorder
 }
 
+// ListClusters mocks base method.
+func (m *MockEKSServiceInterface) ListClusters() ([]models.EKSCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusters")
+	ret0, _ := ret[0].([]models.EKSCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusters indicates an expected call of ListClusters.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListClusters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListClusters))
+}
+
 // Run mocks base method.
 func (m *MockEKSServiceInterface) Run() error {
 	m.ctrl.T.Helper()
@@ -54,6 +69,20 @@ func (mr *MockEKSServiceInterfaceMockRecorder) Run() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockEKSServiceInterface)(nil).Run))
 }
 
+// UpdateKubeconfig mocks base method.
+func (m *MockEKSServiceInterface) UpdateKubeconfig(opts eks.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateKubeconfig", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateKubeconfig indicates an expected call of UpdateKubeconfig.
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateKubeconfig(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateKubeconfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateKubeconfig), opts)
+}
+
 // MockEKSAPI is a mock of EKSAPI interface.
 type MockEKSAPI struct {
 	ctrl     *gomock.Controller
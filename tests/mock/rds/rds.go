@@ -40,6 +40,35 @@ func (m *MockRDSServiceInterface) EXPECT() *MockRDSServiceInterfaceMockRecorder
 	return m.recorder
 }
 
+// Connect mocks base method.
+func (m *MockRDSServiceInterface) Connect(opts rds.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Connect", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Connect indicates an expected call of Connect.
+func (mr *MockRDSServiceInterfaceMockRecorder) Connect(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Connect", reflect.TypeOf((*MockRDSServiceInterface)(nil).Connect), opts)
+}
+
+// ListInstances mocks base method.
+func (m *MockRDSServiceInterface) ListInstances() ([]models.RDSInstance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances")
+	ret0, _ := ret[0].([]models.RDSInstance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockRDSServiceInterfaceMockRecorder) ListInstances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockRDSServiceInterface)(nil).ListInstances))
+}
+
 // Run mocks base method.
 func (m *MockRDSServiceInterface) Run() error {
 	m.ctrl.T.Helper()
@@ -54,6 +83,20 @@ func (mr *MockRDSServiceInterfaceMockRecorder) Run() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockRDSServiceInterface)(nil).Run))
 }
 
+// Tunnel mocks base method.
+func (m *MockRDSServiceInterface) Tunnel(opts rds.Options) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tunnel", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tunnel indicates an expected call of Tunnel.
+func (mr *MockRDSServiceInterfaceMockRecorder) Tunnel(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tunnel", reflect.TypeOf((*MockRDSServiceInterface)(nil).Tunnel), opts)
+}
+
 // MockRDSAPI is a mock of RDSAPI interface.
 type MockRDSAPI struct {
 	ctrl     *gomock.Controller
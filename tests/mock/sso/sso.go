@@ -1,20 +1,19 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: ./internal/sso/interface.go
-//
-// Generated by this command:
-//
-//	mockgen -source=./internal/sso/interface.go -destination=./tests/mock/sso/sso.go -package=mock_sso
-//
 
 // Package mock_sso is a generated GoMock package.
 package mock_sso
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
 	sso "github.com/BerryBytes/awsctl/internal/sso"
 	models "github.com/BerryBytes/awsctl/models"
+	sso0 "github.com/aws/aws-sdk-go-v2/service/sso"
+	ssooidc "github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	sts "github.com/aws/aws-sdk-go-v2/service/sts"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -22,7 +21,6 @@ import (
 type MockSSOClient struct {
 	ctrl     *gomock.Controller
 	recorder *MockSSOClientMockRecorder
-	isgomock struct{}
 }
 
 // MockSSOClientMockRecorder is the mock recorder for MockSSOClient.
@@ -42,6 +40,21 @@ func (m *MockSSOClient) EXPECT() *MockSSOClientMockRecorder {
 	return m.recorder
 }
 
+// AssumeRole mocks base method.
+func (m *MockSSOClient) AssumeRole(opts sso.AssumeRoleOptions) (*models.AWSCredentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssumeRole", opts)
+	ret0, _ := ret[0].(*models.AWSCredentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssumeRole indicates an expected call of AssumeRole.
+func (mr *MockSSOClientMockRecorder) AssumeRole(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssumeRole", reflect.TypeOf((*MockSSOClient)(nil).AssumeRole), opts)
+}
+
 // AwsSTSGetCallerIdentity mocks base method.
 func (m *MockSSOClient) AwsSTSGetCallerIdentity(profile string) (string, error) {
 	m.ctrl.T.Helper()
@@ -52,11 +65,39 @@ func (m *MockSSOClient) AwsSTSGetCallerIdentity(profile string) (string, error)
 }
 
 // AwsSTSGetCallerIdentity indicates an expected call of AwsSTSGetCallerIdentity.
-func (mr *MockSSOClientMockRecorder) AwsSTSGetCallerIdentity(profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) AwsSTSGetCallerIdentity(profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AwsSTSGetCallerIdentity", reflect.TypeOf((*MockSSOClient)(nil).AwsSTSGetCallerIdentity), profile)
 }
 
+// ConfigureAssumeRoleProfile mocks base method.
+func (m *MockSSOClient) ConfigureAssumeRoleProfile(profile string, opts sso.AssumeRoleOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureAssumeRoleProfile", profile, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureAssumeRoleProfile indicates an expected call of ConfigureAssumeRoleProfile.
+func (mr *MockSSOClientMockRecorder) ConfigureAssumeRoleProfile(profile, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureAssumeRoleProfile", reflect.TypeOf((*MockSSOClient)(nil).ConfigureAssumeRoleProfile), profile, opts)
+}
+
+// ConfigureCredentialProcess mocks base method.
+func (m *MockSSOClient) ConfigureCredentialProcess(profile string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfigureCredentialProcess", profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfigureCredentialProcess indicates an expected call of ConfigureCredentialProcess.
+func (mr *MockSSOClientMockRecorder) ConfigureCredentialProcess(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureCredentialProcess", reflect.TypeOf((*MockSSOClient)(nil).ConfigureCredentialProcess), profile)
+}
+
 // ConfigureDefaultProfile mocks base method.
 func (m *MockSSOClient) ConfigureDefaultProfile(region, output string) error {
 	m.ctrl.T.Helper()
@@ -66,7 +107,7 @@ func (m *MockSSOClient) ConfigureDefaultProfile(region, output string) error {
 }
 
 // ConfigureDefaultProfile indicates an expected call of ConfigureDefaultProfile.
-func (mr *MockSSOClientMockRecorder) ConfigureDefaultProfile(region, output any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) ConfigureDefaultProfile(region, output interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureDefaultProfile", reflect.TypeOf((*MockSSOClient)(nil).ConfigureDefaultProfile), region, output)
 }
@@ -81,7 +122,7 @@ func (m *MockSSOClient) ConfigureGet(key, profile string) (string, error) {
 }
 
 // ConfigureGet indicates an expected call of ConfigureGet.
-func (mr *MockSSOClientMockRecorder) ConfigureGet(key, profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) ConfigureGet(key, profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureGet", reflect.TypeOf((*MockSSOClient)(nil).ConfigureGet), key, profile)
 }
@@ -95,7 +136,7 @@ func (m *MockSSOClient) ConfigureSSOProfile(profile, region, accountID, role, ss
 }
 
 // ConfigureSSOProfile indicates an expected call of ConfigureSSOProfile.
-func (mr *MockSSOClientMockRecorder) ConfigureSSOProfile(profile, region, accountID, role, ssoStartUrl, ssoSession any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) ConfigureSSOProfile(profile, region, accountID, role, ssoStartUrl, ssoSession interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureSSOProfile", reflect.TypeOf((*MockSSOClient)(nil).ConfigureSSOProfile), profile, region, accountID, role, ssoStartUrl, ssoSession)
 }
@@ -109,7 +150,7 @@ func (m *MockSSOClient) ConfigureSet(key, value, profile string) error {
 }
 
 // ConfigureSet indicates an expected call of ConfigureSet.
-func (mr *MockSSOClientMockRecorder) ConfigureSet(key, value, profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) ConfigureSet(key, value, profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigureSet", reflect.TypeOf((*MockSSOClient)(nil).ConfigureSet), key, value, profile)
 }
@@ -124,7 +165,7 @@ func (m *MockSSOClient) GetAWSOutput(profile string) (string, error) {
 }
 
 // GetAWSOutput indicates an expected call of GetAWSOutput.
-func (mr *MockSSOClientMockRecorder) GetAWSOutput(profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) GetAWSOutput(profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSOutput", reflect.TypeOf((*MockSSOClient)(nil).GetAWSOutput), profile)
 }
@@ -139,7 +180,7 @@ func (m *MockSSOClient) GetAWSRegion(profile string) (string, error) {
 }
 
 // GetAWSRegion indicates an expected call of GetAWSRegion.
-func (mr *MockSSOClientMockRecorder) GetAWSRegion(profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) GetAWSRegion(profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSRegion", reflect.TypeOf((*MockSSOClient)(nil).GetAWSRegion), profile)
 }
@@ -155,53 +196,155 @@ func (m *MockSSOClient) GetCachedSsoAccessToken(profile string) (string, time.Ti
 }
 
 // GetCachedSsoAccessToken indicates an expected call of GetCachedSsoAccessToken.
-func (mr *MockSSOClientMockRecorder) GetCachedSsoAccessToken(profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) GetCachedSsoAccessToken(profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedSsoAccessToken", reflect.TypeOf((*MockSSOClient)(nil).GetCachedSsoAccessToken), profile)
 }
 
-// GetRoleCredentials mocks base method.
-func (m *MockSSOClient) GetRoleCredentials(accessToken, roleName, accountID string) (*models.AWSCredentials, error) {
+// GetSSOAccountName mocks base method.
+func (m *MockSSOClient) GetSSOAccountName(accountID, profile string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRoleCredentials", accessToken, roleName, accountID)
-	ret0, _ := ret[0].(*models.AWSCredentials)
+	ret := m.ctrl.Call(m, "GetSSOAccountName", accountID, profile)
+	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetRoleCredentials indicates an expected call of GetRoleCredentials.
-func (mr *MockSSOClientMockRecorder) GetRoleCredentials(accessToken, roleName, accountID any) *gomock.Call {
+// GetSSOAccountName indicates an expected call of GetSSOAccountName.
+func (mr *MockSSOClientMockRecorder) GetSSOAccountName(accountID, profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleCredentials", reflect.TypeOf((*MockSSOClient)(nil).GetRoleCredentials), accessToken, roleName, accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSOAccountName", reflect.TypeOf((*MockSSOClient)(nil).GetSSOAccountName), accountID, profile)
 }
 
-// GetSSOAccountName mocks base method.
-func (m *MockSSOClient) GetSSOAccountName(accountID, profile string) (string, error) {
+// ImportSSOSessions mocks base method.
+func (m *MockSSOClient) ImportSSOSessions() ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSSOAccountName", accountID, profile)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "ImportSSOSessions")
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSSOAccountName indicates an expected call of GetSSOAccountName.
-func (mr *MockSSOClientMockRecorder) GetSSOAccountName(accountID, profile any) *gomock.Call {
+// ImportSSOSessions indicates an expected call of ImportSSOSessions.
+func (mr *MockSSOClientMockRecorder) ImportSSOSessions() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSOAccountName", reflect.TypeOf((*MockSSOClient)(nil).GetSSOAccountName), accountID, profile)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSSOSessions", reflect.TypeOf((*MockSSOClient)(nil).ImportSSOSessions))
 }
 
 // InitSSO mocks base method.
-func (m *MockSSOClient) InitSSO(refresh, noBrowser bool) error {
+func (m *MockSSOClient) InitSSO(refresh, noBrowser, setDefault bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InitSSO", refresh, noBrowser)
+	ret := m.ctrl.Call(m, "InitSSO", refresh, noBrowser, setDefault)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // InitSSO indicates an expected call of InitSSO.
-func (mr *MockSSOClientMockRecorder) InitSSO(refresh, noBrowser any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) InitSSO(refresh, noBrowser, setDefault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitSSO", reflect.TypeOf((*MockSSOClient)(nil).InitSSO), refresh, noBrowser, setDefault)
+}
+
+// Logout mocks base method.
+func (m *MockSSOClient) Logout(opts sso.LogoutOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockSSOClientMockRecorder) Logout(opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitSSO", reflect.TypeOf((*MockSSOClient)(nil).InitSSO), refresh, noBrowser)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockSSOClient)(nil).Logout), opts)
+}
+
+// MFASession mocks base method.
+func (m *MockSSOClient) MFASession(opts sso.MFASessionOptions) (*models.AWSCredentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MFASession", opts)
+	ret0, _ := ret[0].(*models.AWSCredentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MFASession indicates an expected call of MFASession.
+func (mr *MockSSOClientMockRecorder) MFASession(opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MFASession", reflect.TypeOf((*MockSSOClient)(nil).MFASession), opts)
+}
+
+// ProfileCredentials mocks base method.
+func (m *MockSSOClient) ProfileCredentials(profile string) (*models.AWSCredentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileCredentials", profile)
+	ret0, _ := ret[0].(*models.AWSCredentials)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfileCredentials indicates an expected call of ProfileCredentials.
+func (mr *MockSSOClientMockRecorder) ProfileCredentials(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileCredentials", reflect.TypeOf((*MockSSOClient)(nil).ProfileCredentials), profile)
+}
+
+// ProfileStatuses mocks base method.
+func (m *MockSSOClient) ProfileStatuses(verify bool) ([]models.ProfileStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProfileStatuses", verify)
+	ret0, _ := ret[0].([]models.ProfileStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProfileStatuses indicates an expected call of ProfileStatuses.
+func (mr *MockSSOClientMockRecorder) ProfileStatuses(verify interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProfileStatuses", reflect.TypeOf((*MockSSOClient)(nil).ProfileStatuses), verify)
+}
+
+// PromptInfo mocks base method.
+func (m *MockSSOClient) PromptInfo(profile string) (*models.PromptInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromptInfo", profile)
+	ret0, _ := ret[0].(*models.PromptInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromptInfo indicates an expected call of PromptInfo.
+func (mr *MockSSOClientMockRecorder) PromptInfo(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptInfo", reflect.TypeOf((*MockSSOClient)(nil).PromptInfo), profile)
+}
+
+// RemoveSSOSession mocks base method.
+func (m *MockSSOClient) RemoveSSOSession(name string, yes bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSSOSession", name, yes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSSOSession indicates an expected call of RemoveSSOSession.
+func (mr *MockSSOClientMockRecorder) RemoveSSOSession(name, yes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSSOSession", reflect.TypeOf((*MockSSOClient)(nil).RemoveSSOSession), name, yes)
+}
+
+// RenameSSOSession mocks base method.
+func (m *MockSSOClient) RenameSSOSession(oldName, newName string, yes bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameSSOSession", oldName, newName, yes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameSSOSession indicates an expected call of RenameSSOSession.
+func (mr *MockSSOClientMockRecorder) RenameSSOSession(oldName, newName, yes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameSSOSession", reflect.TypeOf((*MockSSOClient)(nil).RenameSSOSession), oldName, newName, yes)
 }
 
 // SSOLogin mocks base method.
@@ -213,11 +356,54 @@ func (m *MockSSOClient) SSOLogin(awsProfile string, refresh, noBrowser bool) err
 }
 
 // SSOLogin indicates an expected call of SSOLogin.
-func (mr *MockSSOClientMockRecorder) SSOLogin(awsProfile, refresh, noBrowser any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) SSOLogin(awsProfile, refresh, noBrowser interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSOLogin", reflect.TypeOf((*MockSSOClient)(nil).SSOLogin), awsProfile, refresh, noBrowser)
 }
 
+// SSOSessions mocks base method.
+func (m *MockSSOClient) SSOSessions() ([]models.SSOSessionInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SSOSessions")
+	ret0, _ := ret[0].([]models.SSOSessionInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SSOSessions indicates an expected call of SSOSessions.
+func (mr *MockSSOClientMockRecorder) SSOSessions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SSOSessions", reflect.TypeOf((*MockSSOClient)(nil).SSOSessions))
+}
+
+// SetDefaultProfile mocks base method.
+func (m *MockSSOClient) SetDefaultProfile(profile string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultProfile", profile)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultProfile indicates an expected call of SetDefaultProfile.
+func (mr *MockSSOClientMockRecorder) SetDefaultProfile(profile interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultProfile", reflect.TypeOf((*MockSSOClient)(nil).SetDefaultProfile), profile)
+}
+
+// SetupAssumeRoleProfile mocks base method.
+func (m *MockSSOClient) SetupAssumeRoleProfile(profile string, opts sso.AssumeRoleOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupAssumeRoleProfile", profile, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetupAssumeRoleProfile indicates an expected call of SetupAssumeRoleProfile.
+func (mr *MockSSOClientMockRecorder) SetupAssumeRoleProfile(profile, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupAssumeRoleProfile", reflect.TypeOf((*MockSSOClient)(nil).SetupAssumeRoleProfile), profile, opts)
+}
+
 // SetupSSO mocks base method.
 func (m *MockSSOClient) SetupSSO(opts sso.SSOFlagOptions) error {
 	m.ctrl.T.Helper()
@@ -227,7 +413,7 @@ func (m *MockSSOClient) SetupSSO(opts sso.SSOFlagOptions) error {
 }
 
 // SetupSSO indicates an expected call of SetupSSO.
-func (mr *MockSSOClientMockRecorder) SetupSSO(opts any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) SetupSSO(opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupSSO", reflect.TypeOf((*MockSSOClient)(nil).SetupSSO), opts)
 }
@@ -242,7 +428,7 @@ func (m *MockSSOClient) TryGetCallerIdentity(profile string) (string, error) {
 }
 
 // TryGetCallerIdentity indicates an expected call of TryGetCallerIdentity.
-func (mr *MockSSOClientMockRecorder) TryGetCallerIdentity(profile any) *gomock.Call {
+func (mr *MockSSOClientMockRecorder) TryGetCallerIdentity(profile interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryGetCallerIdentity", reflect.TypeOf((*MockSSOClient)(nil).TryGetCallerIdentity), profile)
 }
@@ -266,7 +452,6 @@ func (mr *MockSSOClientMockRecorder) ValidProfiles() *gomock.Call {
 type MockPrompter struct {
 	ctrl     *gomock.Controller
 	recorder *MockPrompterMockRecorder
-	isgomock struct{}
 }
 
 // MockPrompterMockRecorder is the mock recorder for MockPrompter.
@@ -296,7 +481,7 @@ func (m *MockPrompter) PromptForRegion(defaultRegion string) (string, error) {
 }
 
 // PromptForRegion indicates an expected call of PromptForRegion.
-func (mr *MockPrompterMockRecorder) PromptForRegion(defaultRegion any) *gomock.Call {
+func (mr *MockPrompterMockRecorder) PromptForRegion(defaultRegion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptForRegion", reflect.TypeOf((*MockPrompter)(nil).PromptForRegion), defaultRegion)
 }
@@ -311,7 +496,7 @@ func (m *MockPrompter) PromptRequired(label string) (string, error) {
 }
 
 // PromptRequired indicates an expected call of PromptRequired.
-func (mr *MockPrompterMockRecorder) PromptRequired(label any) *gomock.Call {
+func (mr *MockPrompterMockRecorder) PromptRequired(label interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptRequired", reflect.TypeOf((*MockPrompter)(nil).PromptRequired), label)
 }
@@ -326,7 +511,7 @@ func (m *MockPrompter) PromptWithDefault(label, defaultValue string) (string, er
 }
 
 // PromptWithDefault indicates an expected call of PromptWithDefault.
-func (mr *MockPrompterMockRecorder) PromptWithDefault(label, defaultValue any) *gomock.Call {
+func (mr *MockPrompterMockRecorder) PromptWithDefault(label, defaultValue interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptWithDefault", reflect.TypeOf((*MockPrompter)(nil).PromptWithDefault), label, defaultValue)
 }
@@ -341,7 +526,7 @@ func (m *MockPrompter) PromptYesNo(label string, defaultValue bool) (bool, error
 }
 
 // PromptYesNo indicates an expected call of PromptYesNo.
-func (mr *MockPrompterMockRecorder) PromptYesNo(label, defaultValue any) *gomock.Call {
+func (mr *MockPrompterMockRecorder) PromptYesNo(label, defaultValue interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromptYesNo", reflect.TypeOf((*MockPrompter)(nil).PromptYesNo), label, defaultValue)
 }
@@ -356,7 +541,7 @@ func (m *MockPrompter) SelectFromList(label string, items []string) (string, err
 }
 
 // SelectFromList indicates an expected call of SelectFromList.
-func (mr *MockPrompterMockRecorder) SelectFromList(label, items any) *gomock.Call {
+func (mr *MockPrompterMockRecorder) SelectFromList(label, items interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectFromList", reflect.TypeOf((*MockPrompter)(nil).SelectFromList), label, items)
 }
@@ -365,7 +550,6 @@ func (mr *MockPrompterMockRecorder) SelectFromList(label, items any) *gomock.Cal
 type MockPromptRunner struct {
 	ctrl     *gomock.Controller
 	recorder *MockPromptRunnerMockRecorder
-	isgomock struct{}
 }
 
 // MockPromptRunnerMockRecorder is the mock recorder for MockPromptRunner.
@@ -395,7 +579,7 @@ func (m *MockPromptRunner) RunPrompt(label, defaultValue string, validate func(s
 }
 
 // RunPrompt indicates an expected call of RunPrompt.
-func (mr *MockPromptRunnerMockRecorder) RunPrompt(label, defaultValue, validate any) *gomock.Call {
+func (mr *MockPromptRunnerMockRecorder) RunPrompt(label, defaultValue, validate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunPrompt", reflect.TypeOf((*MockPromptRunner)(nil).RunPrompt), label, defaultValue, validate)
 }
@@ -410,7 +594,276 @@ func (m *MockPromptRunner) RunSelect(label string, items []string) (string, erro
 }
 
 // RunSelect indicates an expected call of RunSelect.
-func (mr *MockPromptRunnerMockRecorder) RunSelect(label, items any) *gomock.Call {
+func (mr *MockPromptRunnerMockRecorder) RunSelect(label, items interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSelect", reflect.TypeOf((*MockPromptRunner)(nil).RunSelect), label, items)
 }
+
+// MockOIDCAPI is a mock of OIDCAPI interface.
+type MockOIDCAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCAPIMockRecorder
+}
+
+// MockOIDCAPIMockRecorder is the mock recorder for MockOIDCAPI.
+type MockOIDCAPIMockRecorder struct {
+	mock *MockOIDCAPI
+}
+
+// NewMockOIDCAPI creates a new mock instance.
+func NewMockOIDCAPI(ctrl *gomock.Controller) *MockOIDCAPI {
+	mock := &MockOIDCAPI{ctrl: ctrl}
+	mock.recorder = &MockOIDCAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCAPI) EXPECT() *MockOIDCAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateToken mocks base method.
+func (m *MockOIDCAPI) CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateToken", varargs...)
+	ret0, _ := ret[0].(*ssooidc.CreateTokenOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateToken indicates an expected call of CreateToken.
+func (mr *MockOIDCAPIMockRecorder) CreateToken(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockOIDCAPI)(nil).CreateToken), varargs...)
+}
+
+// RegisterClient mocks base method.
+func (m *MockOIDCAPI) RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterClient", varargs...)
+	ret0, _ := ret[0].(*ssooidc.RegisterClientOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockOIDCAPIMockRecorder) RegisterClient(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockOIDCAPI)(nil).RegisterClient), varargs...)
+}
+
+// StartDeviceAuthorization mocks base method.
+func (m *MockOIDCAPI) StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartDeviceAuthorization", varargs...)
+	ret0, _ := ret[0].(*ssooidc.StartDeviceAuthorizationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartDeviceAuthorization indicates an expected call of StartDeviceAuthorization.
+func (mr *MockOIDCAPIMockRecorder) StartDeviceAuthorization(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDeviceAuthorization", reflect.TypeOf((*MockOIDCAPI)(nil).StartDeviceAuthorization), varargs...)
+}
+
+// MockSTSAPI is a mock of STSAPI interface.
+type MockSTSAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockSTSAPIMockRecorder
+}
+
+// MockSTSAPIMockRecorder is the mock recorder for MockSTSAPI.
+type MockSTSAPIMockRecorder struct {
+	mock *MockSTSAPI
+}
+
+// NewMockSTSAPI creates a new mock instance.
+func NewMockSTSAPI(ctrl *gomock.Controller) *MockSTSAPI {
+	mock := &MockSTSAPI{ctrl: ctrl}
+	mock.recorder = &MockSTSAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSTSAPI) EXPECT() *MockSTSAPIMockRecorder {
+	return m.recorder
+}
+
+// AssumeRole mocks base method.
+func (m *MockSTSAPI) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AssumeRole", varargs...)
+	ret0, _ := ret[0].(*sts.AssumeRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssumeRole indicates an expected call of AssumeRole.
+func (mr *MockSTSAPIMockRecorder) AssumeRole(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssumeRole", reflect.TypeOf((*MockSTSAPI)(nil).AssumeRole), varargs...)
+}
+
+// GetCallerIdentity mocks base method.
+func (m *MockSTSAPI) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCallerIdentity", varargs...)
+	ret0, _ := ret[0].(*sts.GetCallerIdentityOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCallerIdentity indicates an expected call of GetCallerIdentity.
+func (mr *MockSTSAPIMockRecorder) GetCallerIdentity(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCallerIdentity", reflect.TypeOf((*MockSTSAPI)(nil).GetCallerIdentity), varargs...)
+}
+
+// GetSessionToken mocks base method.
+func (m *MockSTSAPI) GetSessionToken(ctx context.Context, params *sts.GetSessionTokenInput, optFns ...func(*sts.Options)) (*sts.GetSessionTokenOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSessionToken", varargs...)
+	ret0, _ := ret[0].(*sts.GetSessionTokenOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionToken indicates an expected call of GetSessionToken.
+func (mr *MockSTSAPIMockRecorder) GetSessionToken(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionToken", reflect.TypeOf((*MockSTSAPI)(nil).GetSessionToken), varargs...)
+}
+
+// MockSSOPortalAPI is a mock of SSOPortalAPI interface.
+type MockSSOPortalAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockSSOPortalAPIMockRecorder
+}
+
+// MockSSOPortalAPIMockRecorder is the mock recorder for MockSSOPortalAPI.
+type MockSSOPortalAPIMockRecorder struct {
+	mock *MockSSOPortalAPI
+}
+
+// NewMockSSOPortalAPI creates a new mock instance.
+func NewMockSSOPortalAPI(ctrl *gomock.Controller) *MockSSOPortalAPI {
+	mock := &MockSSOPortalAPI{ctrl: ctrl}
+	mock.recorder = &MockSSOPortalAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSSOPortalAPI) EXPECT() *MockSSOPortalAPIMockRecorder {
+	return m.recorder
+}
+
+// GetRoleCredentials mocks base method.
+func (m *MockSSOPortalAPI) GetRoleCredentials(ctx context.Context, params *sso0.GetRoleCredentialsInput, optFns ...func(*sso0.Options)) (*sso0.GetRoleCredentialsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRoleCredentials", varargs...)
+	ret0, _ := ret[0].(*sso0.GetRoleCredentialsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleCredentials indicates an expected call of GetRoleCredentials.
+func (mr *MockSSOPortalAPIMockRecorder) GetRoleCredentials(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleCredentials", reflect.TypeOf((*MockSSOPortalAPI)(nil).GetRoleCredentials), varargs...)
+}
+
+// ListAccountRoles mocks base method.
+func (m *MockSSOPortalAPI) ListAccountRoles(ctx context.Context, params *sso0.ListAccountRolesInput, optFns ...func(*sso0.Options)) (*sso0.ListAccountRolesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccountRoles", varargs...)
+	ret0, _ := ret[0].(*sso0.ListAccountRolesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountRoles indicates an expected call of ListAccountRoles.
+func (mr *MockSSOPortalAPIMockRecorder) ListAccountRoles(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountRoles", reflect.TypeOf((*MockSSOPortalAPI)(nil).ListAccountRoles), varargs...)
+}
+
+// ListAccounts mocks base method.
+func (m *MockSSOPortalAPI) ListAccounts(ctx context.Context, params *sso0.ListAccountsInput, optFns ...func(*sso0.Options)) (*sso0.ListAccountsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccounts", varargs...)
+	ret0, _ := ret[0].(*sso0.ListAccountsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockSSOPortalAPIMockRecorder) ListAccounts(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockSSOPortalAPI)(nil).ListAccounts), varargs...)
+}
+
+// Logout mocks base method.
+func (m *MockSSOPortalAPI) Logout(ctx context.Context, params *sso0.LogoutInput, optFns ...func(*sso0.Options)) (*sso0.LogoutOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Logout", varargs...)
+	ret0, _ := ret[0].(*sso0.LogoutOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockSSOPortalAPIMockRecorder) Logout(ctx, params interface{}, optFns ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockSSOPortalAPI)(nil).Logout), varargs...)
+}
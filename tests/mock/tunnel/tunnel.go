@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/tunnel/interface.go
+
+// Package mock_tunnel is a generated GoMock package.
+package mock_tunnel
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	connection "github.com/BerryBytes/awsctl/internal/common"
+	models "github.com/BerryBytes/awsctl/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTunnelServiceInterface is a mock of TunnelServiceInterface interface.
+type MockTunnelServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTunnelServiceInterfaceMockRecorder
+}
+
+// MockTunnelServiceInterfaceMockRecorder is the mock recorder for MockTunnelServiceInterface.
+type MockTunnelServiceInterfaceMockRecorder struct {
+	mock *MockTunnelServiceInterface
+}
+
+// NewMockTunnelServiceInterface creates a new mock instance.
+func NewMockTunnelServiceInterface(ctrl *gomock.Controller) *MockTunnelServiceInterface {
+	mock := &MockTunnelServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTunnelServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTunnelServiceInterface) EXPECT() *MockTunnelServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Detach mocks base method.
+func (m *MockTunnelServiceInterface) Detach(name string, opts connection.AWSOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detach", name, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockTunnelServiceInterfaceMockRecorder) Detach(name, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockTunnelServiceInterface)(nil).Detach), name, opts)
+}
+
+// List mocks base method.
+func (m *MockTunnelServiceInterface) List() ([]models.TunnelProcess, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]models.TunnelProcess)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTunnelServiceInterfaceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTunnelServiceInterface)(nil).List))
+}
+
+// Logs mocks base method.
+func (m *MockTunnelServiceInterface) Logs(ctx context.Context, id string, w io.Writer, follow bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logs", ctx, id, w, follow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logs indicates an expected call of Logs.
+func (mr *MockTunnelServiceInterfaceMockRecorder) Logs(ctx, id, w, follow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logs", reflect.TypeOf((*MockTunnelServiceInterface)(nil).Logs), ctx, id, w, follow)
+}
+
+// Stop mocks base method.
+func (m *MockTunnelServiceInterface) Stop(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockTunnelServiceInterfaceMockRecorder) Stop(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockTunnelServiceInterface)(nil).Stop), id)
+}
+
+// StopAll mocks base method.
+func (m *MockTunnelServiceInterface) StopAll() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopAll")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopAll indicates an expected call of StopAll.
+func (mr *MockTunnelServiceInterfaceMockRecorder) StopAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopAll", reflect.TypeOf((*MockTunnelServiceInterface)(nil).StopAll))
+}
+
+// Up mocks base method.
+func (m *MockTunnelServiceInterface) Up(ctx context.Context, name string, opts connection.AWSOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Up", ctx, name, opts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Up indicates an expected call of Up.
+func (mr *MockTunnelServiceInterfaceMockRecorder) Up(ctx, name, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Up", reflect.TypeOf((*MockTunnelServiceInterface)(nil).Up), ctx, name, opts)
+}
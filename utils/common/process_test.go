@@ -0,0 +1,48 @@
+package common_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/utils/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	tests := []struct {
+		name     string
+		command  string
+		args     []string
+		env      []string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "success", command: "sh", args: []string{"-c", "exit 0"}},
+		{name: "exit code is returned", command: "sh", args: []string{"-c", "exit 3"}, wantCode: 3},
+		{name: "environment is passed", command: "sh", args: []string{"-c", `test "$FOO" = bar`}, env: []string{"FOO=bar"}},
+		{name: "killed by signal", command: "sh", args: []string{"-c", "kill -TERM $$"}, wantCode: 143},
+		{name: "command not found", command: "awsctl-no-such-command", wantErr: "command not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := common.RunProcess(tt.command, tt.args, tt.env)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
+
+func TestExitCodeError(t *testing.T) {
+	assert.EqualError(t, &common.ExitCodeError{Code: 2}, "exit status 2")
+}
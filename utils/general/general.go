@@ -65,9 +65,11 @@ func NewGeneralUtilsManager() GeneralUtilsInterface {
 	return &DefaultGeneralUtilsManager{}
 }
 
-func isValidRegionFormat(region string) bool {
-	// Matches patterns like us-east-1, ap-southeast-2
-	return regexp.MustCompile(`^[a-z]{2}-[a-z]+-\d+$`).MatchString(region)
+// IsValidRegionFormat reports whether region looks like an AWS region name,
+// without checking that the region exists.
+func IsValidRegionFormat(region string) bool {
+	// Matches patterns like us-east-1, ap-southeast-2, us-gov-west-1
+	return regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`).MatchString(region)
 }
 
 var (
@@ -113,7 +115,7 @@ func IsRegionValid(region string) bool {
 		}
 	}
 
-	return isValidRegionFormat(region)
+	return IsValidRegionFormat(region)
 }
 
 var validNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-_]{0,126}[a-zA-Z0-9]$`)
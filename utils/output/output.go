@@ -0,0 +1,149 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats are the values accepted by --output. A Go template is given as
+// template=<template>.
+var Formats = []string{"table", "json", "yaml", "text", "template=<template>"}
+
+const templatePrefix = "template="
+
+// Column is a column of table and text output.
+type Column[T any] struct {
+	Header string
+	Value  func(T) string
+}
+
+// Validate returns an error when format is not one of Formats. An empty format
+// is valid and means table.
+func Validate(format string) error {
+	switch {
+	case format == "", format == "table", format == "json", format == "yaml", format == "text":
+		return nil
+	case strings.HasPrefix(format, templatePrefix):
+		if _, err := parseTemplate(format); err != nil {
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid --output %q: must be one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Format returns the value of the global --output flag of cmd, or "" when the
+// command has none.
+func Format(cmd *cobra.Command) string {
+	flag := cmd.Flag("output")
+	if flag == nil {
+		return ""
+	}
+	return flag.Value.String()
+}
+
+// Print writes items to w in format:
+//
+//   - table (or "") prints an aligned table with a header row.
+//   - text prints the columns separated by tabs, without a header.
+//   - json and yaml print the items as a list, using their json and yaml
+//     field names.
+//   - template=<template> executes the Go template once per item, followed by
+//     a newline. The template sees the item's JSON fields, e.g. {{.instanceId}}.
+func Print[T any](w io.Writer, format string, items []T, columns []Column[T]) error {
+	if items == nil {
+		items = []T{}
+	}
+
+	switch {
+	case format == "" || format == "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = c.Header
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(headers, "\t"))
+		for _, item := range items {
+			_, _ = fmt.Fprintln(tw, strings.Join(row(item, columns, "-"), "\t"))
+		}
+		return tw.Flush()
+	case format == "text":
+		for _, item := range items {
+			if _, err := fmt.Fprintln(w, strings.Join(row(item, columns, ""), "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case format == "json":
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case format == "yaml":
+		data, err := yaml.Marshal(items)
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	case strings.HasPrefix(format, templatePrefix):
+		return printTemplate(w, format, items)
+	}
+	return Validate(format)
+}
+
+func row[T any](item T, columns []Column[T], empty string) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = c.Value(item)
+		if values[i] == "" {
+			values[i] = empty
+		}
+	}
+	return values
+}
+
+func parseTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, templatePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func printTemplate[T any](w io.Writer, format string, items []T) error {
+	tmpl, err := parseTemplate(format)
+	if err != nil {
+		return err
+	}
+
+	// Round trip through JSON so that templates use the same field names as
+	// the json output.
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	var fields []map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	for _, item := range fields {
+		if err := tmpl.Execute(w, item); err != nil {
+			return fmt.Errorf("failed to execute --output template: %w", err)
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/BerryBytes/awsctl/utils/output"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	Name string `json:"name" yaml:"name"`
+	Size int    `json:"size" yaml:"size"`
+	Note string `json:"note" yaml:"note"`
+}
+
+var columns = []output.Column[item]{
+	{Header: "NAME", Value: func(i item) string { return i.Name }},
+	{Header: "NOTE", Value: func(i item) string { return i.Note }},
+}
+
+var items = []item{
+	{Name: "alpha", Size: 1, Note: "first"},
+	{Name: "b", Size: 2},
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		items  []item
+		want   string
+	}{
+		{
+			name:   "default table",
+			format: "",
+			items:  items,
+			want:   "NAME   NOTE\nalpha  first\nb      -\n",
+		},
+		{
+			name:   "table",
+			format: "table",
+			items:  nil,
+			want:   "NAME  NOTE\n",
+		},
+		{
+			name:   "text",
+			format: "text",
+			items:  items,
+			want:   "alpha\tfirst\nb\t\n",
+		},
+		{
+			name:   "json",
+			format: "json",
+			items:  items[1:],
+			want:   "[\n  {\n    \"name\": \"b\",\n    \"size\": 2,\n    \"note\": \"\"\n  }\n]\n",
+		},
+		{
+			name:   "empty json",
+			format: "json",
+			items:  nil,
+			want:   "[]\n",
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			items:  items[:1],
+			want:   "- name: alpha\n  size: 1\n  note: first\n",
+		},
+		{
+			name:   "template",
+			format: "template={{.name}}={{.size}}",
+			items:  items,
+			want:   "alpha=1\nb=2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, output.Print(&buf, tt.format, tt.items, columns))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestPrint_InvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := output.Print(&buf, "xml", items, columns)
+	assert.EqualError(t, err, `invalid --output "xml": must be one of table, json, yaml, text, template=<template>`)
+	assert.Empty(t, buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	for _, format := range []string{"", "table", "json", "yaml", "text", "template={{.name}}"} {
+		assert.NoError(t, output.Validate(format), format)
+	}
+
+	assert.Error(t, output.Validate("xml"))
+
+	err := output.Validate("template={{.name")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --output template")
+}
+
+func TestFormat(t *testing.T) {
+	cmd := &cobra.Command{Use: "list"}
+	assert.Equal(t, "", output.Format(cmd))
+
+	root := &cobra.Command{Use: "awsctl"}
+	root.PersistentFlags().StringP("output", "o", "", "")
+	root.AddCommand(cmd)
+	require.NoError(t, root.PersistentFlags().Set("output", "json"))
+	assert.Equal(t, "json", output.Format(cmd))
+}
@@ -3,8 +3,11 @@ package promptUtils
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 type Prompter interface {
@@ -17,6 +20,15 @@ type RealPrompter struct{}
 
 var ErrInterrupted = errors.New("operation interrupted")
 
+// IgnoreInterrupt returns nil for ErrInterrupted, so that a command ends
+// quietly when a prompt is left with Ctrl+C, and err otherwise.
+func IgnoreInterrupt(err error) error {
+	if errors.Is(err, ErrInterrupted) {
+		return nil
+	}
+	return err
+}
+
 func handlePromptError(err error) error {
 	if err != nil {
 		if errors.Is(err, promptui.ErrInterrupt) {
@@ -87,3 +99,38 @@ func (p *RealPrompter) PromptForInputWithValidation(prompt, defaultValue string,
 func NewPrompt() Prompter {
 	return &RealPrompter{}
 }
+
+// IsInteractive reports whether stdin is a terminal that missing values can
+// be prompted for. It is a variable so that tests can replace it.
+var IsInteractive = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// SetInteractive makes IsInteractive report interactive until the returned
+// func is called, e.g. with t.Cleanup in tests.
+func SetInteractive(interactive bool) (restore func()) {
+	orig := IsInteractive
+	IsInteractive = func() bool { return interactive }
+	return func() { IsInteractive = orig }
+}
+
+// Errors returned instead of prompting for the AWS region or profile when
+// stdin is not a terminal.
+var (
+	ErrRegionRequired  = errors.New("no AWS region set: pass --region or set AWS_REGION when stdin is not a terminal")
+	ErrProfileRequired = errors.New("no AWS profile set: pass --profile or set AWS_PROFILE when stdin is not a terminal")
+)
+
+// RequireFlags returns an error for the first of the named flags that was not
+// given when stdin is not a terminal, since its value cannot be prompted for.
+func RequireFlags(cmd *cobra.Command, names ...string) error {
+	if IsInteractive() {
+		return nil
+	}
+	for _, name := range names {
+		if !cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s is required when stdin is not a terminal", name)
+		}
+	}
+	return nil
+}